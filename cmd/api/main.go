@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"flag"
 	"log"
 	"net/http"
 	"os"
@@ -10,14 +9,12 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/darmawguna/tirtaapp.git/utils"
-
 	"github.com/darmawguna/tirtaapp.git/config"
 	"github.com/darmawguna/tirtaapp.git/handlers"
-	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/darmawguna/tirtaapp.git/repositories"
 	"github.com/darmawguna/tirtaapp.git/routes"
 	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 	"gorm.io/gorm"
@@ -27,29 +24,23 @@ var db *gorm.DB
 
 func main() {
 	// --- Tahap 1: Inisialisasi Konfigurasi & Koneksi ---
-	clearDB := flag.Bool("clear-db", false, "Set this flag to delete all data from the database before starting")
-	flag.Parse()
 	config.LoadConfig()
 	db = config.ConnectDB()
-	config.RunMigration(db,
-		&models.User{}, &models.Quiz{}, &models.Education{}, &models.ComplaintLog{},
-		&models.DrugSchedule{}, &models.ControlSchedule{}, &models.HemodialysisSchedule{},
-		&models.Device{}, &models.FluidBalanceLog{}, &models.HemodialysisMonitoring{},
-		&models.MedicationRefillSchedule{},
-	)
+	config.RequireMigratedSchema(db)
+	if err := storage.CheckSigningSecret(); err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
 
 	// Inisialisasi Queue Service (RabbitMQ)
 	queueService := services.NewQueueService()
 	if err := queueService.Connect(); err != nil {
 		log.Fatalf("Could not connect to RabbitMQ: %s", err)
 	}
-	if *clearDB {
-		err := utils.ClearAllData(db) // Call the clear function
-		if err != nil {
-			log.Fatalf("FATAL: Could not clear database: %v", err)
-		}
-		log.Println("Database cleared successfully via flag.")
-		// Optional: os.Exit(0) if you only want to clear and not start the server
+
+	// Penyimpanan file upload (lokal atau S3-compatible, lihat STORAGE_DRIVER)
+	blobStore, err := storage.NewBlobStoreFromConfig()
+	if err != nil {
+		log.Fatalf("FATAL: Could not initialize storage: %v", err)
 	}
 
 	// --- Tahap 2: Dependency Injection ---
@@ -65,41 +56,82 @@ func main() {
 	hemodialysisMonitoringRepo := repositories.NewHemodialysisMonitoringRepository(db)
 	complaintRepository := repositories.NewComplaintRepository(db)
 	medicationRefillStory := repositories.NewMedicationRefillRepository(db)
+	jobRunRepository := repositories.NewJobRunRepository(db)
+	careTeamRepo := repositories.NewCareTeamRepository(db)
+	intradialyticReadingRepo := repositories.NewIntradialyticReadingRepository(db)
+	fluidPrescriptionRepo := repositories.NewFluidPrescriptionRepository(db)
+	fluidEntryRepo := repositories.NewFluidEntryRepository(db)
+	fluidContainerPresetRepo := repositories.NewFluidContainerPresetRepository(db)
+	labTestRepo := repositories.NewLabTestRepository(db)
+	labResultRepo := repositories.NewLabResultRepository(db)
+	vascularAccessRepo := repositories.NewVascularAccessRepository(db)
+	symptomRepo := repositories.NewSymptomRepository(db)
+	triageRuleRepo := repositories.NewTriageRuleRepository(db)
+	educationEngagementRepo := repositories.NewEducationEngagementRepository(db)
+	educationTriggerRepo := repositories.NewEducationTriggerRepository(db)
+	educationMediaUploadRepo := repositories.NewEducationMediaUploadRepository(db)
 	// (Tambahkan repository lain di sini jika ada)
 
 	deviceService := services.NewDeviceService(deviceRepository)
-	quizService := services.NewQuizService(quizRepository)
-	educationService := services.NewEducationService(educationRepository)
+	quizService := services.NewQuizService(quizRepository, careTeamRepo)
+	educationService := services.NewEducationService(educationRepository, blobStore)
+	imageService := services.NewImageService(blobStore)
+	educationMediaService := services.NewEducationMediaService(educationMediaUploadRepo, imageService, blobStore)
 	authService := services.NewAuthService(userRepository, deviceService)
 	drugScheduleService := services.NewDrugScheduleService(drugScheduleRepository, queueService)
 	controlScheduleService := services.NewControlScheduleService(controlScheduleRepo, queueService)
 	hemodialysisScheduleService := services.NewHemodialysisScheduleService(hemodialysisScheduleRepo, queueService)
-	fluidBalanceService := services.NewFluidBalanceService(fluidBalanceRepo, userRepository)
-	hemodialysisMonitoringService := services.NewHemodialysisMonitoringService(hemodialysisMonitoringRepo, userRepository)
-	profileService := services.NewProfileService(userRepository)
-	complaintService := services.NewComplaintService(complaintRepository)
+	fluidBalanceService := services.NewFluidBalanceService(fluidBalanceRepo, userRepository, fluidPrescriptionRepo, fluidEntryRepo, fluidContainerPresetRepo, careTeamRepo, queueService)
+	hemodialysisMonitoringService := services.NewHemodialysisMonitoringService(hemodialysisMonitoringRepo, userRepository, hemodialysisScheduleRepo, careTeamRepo, queueService)
+	profileService := services.NewProfileService(userRepository, blobStore)
+	symptomService := services.NewSymptomService(symptomRepo)
+	triageService := services.NewTriageService(triageRuleRepo, symptomRepo)
+	complaintService := services.NewComplaintService(complaintRepository, triageService, careTeamRepo, queueService)
 	medicationReffilService := services.NewMedicationRefillService( medicationRefillStory, queueService)
+	jobService := services.NewJobService(jobRunRepository, queueService)
+	careTeamService := services.NewCareTeamService(careTeamRepo, userRepository)
+	intradialyticReadingService := services.NewIntradialyticReadingService(intradialyticReadingRepo, hemodialysisMonitoringRepo, userRepository, careTeamRepo)
+	fluidPrescriptionService := services.NewFluidPrescriptionService(fluidPrescriptionRepo, userRepository, careTeamRepo)
+	fluidContainerPresetService := services.NewFluidContainerPresetService(fluidContainerPresetRepo)
+	labTestService := services.NewLabTestService(labTestRepo)
+	labResultService := services.NewLabResultService(labResultRepo, labTestRepo, hemodialysisMonitoringRepo, userRepository, careTeamRepo)
+	vascularAccessService := services.NewVascularAccessService(vascularAccessRepo, userRepository, careTeamRepo, queueService)
+	educationEngagementService := services.NewEducationEngagementService(educationEngagementRepo, educationRepository, careTeamRepo)
+	educationRecommendationService := services.NewEducationRecommendationService(educationTriggerRepo, educationRepository, educationEngagementRepo, userRepository, fluidBalanceRepo, hemodialysisMonitoringRepo, complaintRepository)
 	// (Tambahkan service lain di sini jika ada)
 
 	authHandler := handlers.NewAuthHandler(authService)
 	drugScheduleHandler := handlers.NewDrugScheduleHandler(drugScheduleService)
 	quizHandler := handlers.NewQuizHandler(quizService)
-	educationHandler := handlers.NewEducationHandler(educationService)
+	educationHandler := handlers.NewEducationHandler(educationService, educationMediaService, imageService, blobStore)
 	controlScheduleHandler := handlers.NewControlScheduleHandler(controlScheduleService)
 	hemodialysisScheduleHandler := handlers.NewHemodialysisScheduleHandler(hemodialysisScheduleService)
-	fluidBalanceHandler := handlers.NewFluidBalanceHandler(fluidBalanceService)
-	hemodialysisMonitoringHandler := handlers.NewHemodialysisMonitoringHandler(hemodialysisMonitoringService)
-	profileHandler := handlers.NewProfileHandler(profileService)
-	complaintHandler := handlers.NewComplaintHandler(complaintService)
+	fluidBalanceHandler := handlers.NewFluidBalanceHandler(fluidBalanceService, educationRecommendationService)
+	hemodialysisMonitoringHandler := handlers.NewHemodialysisMonitoringHandler(hemodialysisMonitoringService, educationRecommendationService)
+	profileHandler := handlers.NewProfileHandler(profileService, imageService, blobStore)
+	complaintHandler := handlers.NewComplaintHandler(complaintService, educationRecommendationService, blobStore)
 	medicationReffilHandler := handlers.NewMedicationRefillHandler(medicationReffilService)
+	jobHandler := handlers.NewJobHandler(jobService)
+	careTeamHandler := handlers.NewCareTeamHandler(careTeamService)
+	intradialyticReadingHandler := handlers.NewIntradialyticReadingHandler(intradialyticReadingService)
+	fluidPrescriptionHandler := handlers.NewFluidPrescriptionHandler(fluidPrescriptionService)
+	fluidContainerPresetHandler := handlers.NewFluidContainerPresetHandler(fluidContainerPresetService)
+	labTestHandler := handlers.NewLabTestHandler(labTestService)
+	labResultHandler := handlers.NewLabResultHandler(labResultService)
+	vascularAccessHandler := handlers.NewVascularAccessHandler(vascularAccessService)
+	symptomHandler := handlers.NewSymptomHandler(symptomService)
+	triageRuleHandler := handlers.NewTriageRuleHandler(triageService)
+	educationEngagementHandler := handlers.NewEducationEngagementHandler(educationEngagementService)
+	educationRecommendationHandler := handlers.NewEducationRecommendationHandler(educationRecommendationService)
+	educationMediaHandler := handlers.NewEducationMediaHandler(educationMediaService, educationService, blobStore)
+	staticFileHandler := handlers.NewStaticFileHandler(blobStore)
 	// (Tambahkan handler lain di sini jika ada)
 
 	// --- Tahap 3: Setup Router dan Server ---
 	router := gin.Default()
-	router.Static("/static/profiles", "./uploads/profiles")
-	router.Static("/static/educations", "./uploads/educations")
 
 	// Mendaftarkan semua routes
+	routes.SetupStaticRoutes(router, staticFileHandler)
 	routes.SetupAuthRoutes(router, authHandler)
 	routes.SetupDrugScheduleRoutes(router, drugScheduleHandler)
 	routes.SetupQuizRoutes(router,quizHandler)
@@ -111,6 +143,19 @@ func main() {
 	routes.SetupProfileRoutes(router, profileHandler)
 	routes.SetupComplaintRoutes(router, complaintHandler)
 	routes.SetupMedicationRefillRoutes(router, medicationReffilHandler)
+	routes.SetupJobRoutes(router, jobHandler)
+	routes.SetupCareTeamRoutes(router, careTeamHandler)
+	routes.SetupIntradialyticReadingRoutes(router, intradialyticReadingHandler)
+	routes.SetupFluidPrescriptionRoutes(router, fluidPrescriptionHandler)
+	routes.SetupFluidContainerPresetRoutes(router, fluidContainerPresetHandler)
+	routes.SetupLabTestRoutes(router, labTestHandler)
+	routes.SetupLabResultRoutes(router, labResultHandler)
+	routes.SetupVascularAccessRoutes(router, vascularAccessHandler)
+	routes.SetupSymptomRoutes(router, symptomHandler)
+	routes.SetupTriageRuleRoutes(router, triageRuleHandler)
+	routes.SetupEducationEngagementRoutes(router, educationEngagementHandler)
+	routes.SetupEducationRecommendationRoutes(router, educationRecommendationHandler)
+	routes.SetupEducationMediaRoutes(router, educationMediaHandler)
 
 	// (Tambahkan pendaftaran route lain di sini)
 
@@ -0,0 +1,42 @@
+// Command migrate-storage memindahkan file upload lama dari disk lokal ke BlobStore yang
+// dikonfigurasi (STORAGE_DRIVER) dan mengubah kolom DB dari path file menjadi key objek.
+// Jalankan sekali setelah deploy abstraksi storage; aman diulang.
+//
+//	go run ./cmd/migrate-storage --dry-run
+//	STORAGE_DRIVER=s3 go run ./cmd/migrate-storage --source=./uploads
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/darmawguna/tirtaapp.git/config"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/storage"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Only report what would be copied/rewritten, without writing anything")
+	source := flag.String("source", "./uploads", "Directory containing the legacy upload files")
+	flag.Parse()
+
+	config.LoadConfig()
+	db := config.ConnectDB()
+
+	store, err := storage.NewBlobStoreFromConfig()
+	if err != nil {
+		log.Fatalf("FATAL: Could not initialize storage: %v", err)
+	}
+	migrationService := services.NewStorageMigrationService(repositories.NewUploadReferenceRepository(db), store)
+
+	if *dryRun {
+		log.Println("Dry run: no changes will be written.")
+	}
+	report, err := migrationService.Migrate(*source, *dryRun)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+	log.Printf("Done. Checked: %d, copied: %d, rewritten: %d, missing: %d, skipped (external URL): %d, failed: %d",
+		report.Checked, report.Copied, report.Rewritten, report.Missing, report.Skipped, report.Failed)
+}
@@ -0,0 +1,117 @@
+// Command migrate mengelola migrasi skema database berversi (lihat package migrations).
+// API dan worker tidak lagi mengubah skema saat start; jalankan `up` sebelum deploy.
+//
+//	go run ./cmd/migrate up          # jalankan semua migrasi yang belum diterapkan
+//	go run ./cmd/migrate up 1        # hanya satu versi berikutnya
+//	go run ./cmd/migrate down        # batalkan versi terakhir (down N untuk N versi)
+//	go run ./cmd/migrate status
+//	go run ./cmd/migrate create add_user_bio
+//	go run ./cmd/migrate -fill-legacy-drift up  # adopsi database lama yang kurang tabel/kolom baseline
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/darmawguna/tirtaapp.git/config"
+	"github.com/darmawguna/tirtaapp.git/migrations"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: migrate [flags] <up [N] | down [N] | status | create NAME>")
+	flag.PrintDefaults()
+}
+
+func main() {
+	dir := flag.String("dir", migrations.SourceDir, "Directory where the create command writes new migration files")
+	fillLegacyDrift := flag.Bool("fill-legacy-drift", false, "When adopting a database created before versioned migrations, create the baseline tables/columns it is missing instead of failing")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	command, args := flag.Arg(0), flag.Args()[1:]
+
+	// create hanya menulis file, tidak perlu koneksi database
+	if command == "create" {
+		if len(args) != 1 {
+			usage()
+			os.Exit(2)
+		}
+		upPath, downPath, err := migrations.Create(*dir, args[0])
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+		log.Printf("Created %s and %s", upPath, downPath)
+		return
+	}
+
+	steps := 0
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			log.Fatalf("FATAL: Invalid step count '%s'", args[0])
+		}
+		steps = n
+	}
+
+	config.LoadConfig()
+	db := config.ConnectDB()
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+	migrator.FillLegacyDrift = *fillLegacyDrift
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx, steps)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+		if len(applied) == 0 {
+			log.Println("Database schema is already up to date.")
+			return
+		}
+		log.Printf("Applied %d migration(s).", len(applied))
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+		log.Printf("Reverted %d migration(s).", len(reverted))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", "-"
+			switch {
+			case status.Dirty:
+				state = "dirty"
+			case status.Missing:
+				state = "applied (unknown to this binary)"
+			case status.Applied:
+				state = "applied"
+			}
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		w.Flush()
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
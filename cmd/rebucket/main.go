@@ -0,0 +1,75 @@
+// Command rebucket memindahkan log cairan dan monitoring HD lama ke tanggal lokal
+// pasien. Jalankan sekali setelah deploy perhitungan hari berbasis timezone user.
+//
+//	go run ./cmd/rebucket --dry-run
+//	go run ./cmd/rebucket --user-id=42
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/darmawguna/tirtaapp.git/config"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/services"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Only report what would be moved, without writing to the database")
+	userID := flag.Uint("user-id", 0, "Rebucket a single user instead of all users")
+	flag.Parse()
+
+	config.LoadConfig()
+	db := config.ConnectDB()
+
+	userRepo := repositories.NewUserRepository(db)
+	rebucketService := services.NewDayRebucketService(
+		repositories.NewFluidBalanceRepository(db),
+		userRepo,
+		repositories.NewFluidPrescriptionRepository(db),
+		repositories.NewFluidEntryRepository(db),
+		repositories.NewFluidContainerPresetRepository(db),
+		repositories.NewHemodialysisMonitoringRepository(db),
+	)
+
+	var users []models.User
+	if *userID != 0 {
+		user, err := userRepo.FindByID(*userID)
+		if err != nil {
+			log.Fatalf("FATAL: User %d not found: %v", *userID, err)
+		}
+		users = append(users, user)
+	} else {
+		var err error
+		users, err = userRepo.FindAll()
+		if err != nil {
+			log.Fatalf("FATAL: Failed to load users: %v", err)
+		}
+	}
+
+	if *dryRun {
+		log.Println("Dry run: no changes will be written.")
+	}
+	totalEntries, totalMonitoring, totalConflicts, failed := 0, 0, 0, 0
+	for _, user := range users {
+		report, err := rebucketService.RebucketUser(user, *dryRun)
+		if err != nil {
+			log.Printf("ERROR: User %d: %v", user.ID, err)
+			failed++
+			continue
+		}
+		for _, conflict := range report.MonitoringConflicts {
+			log.Printf("CONFLICT: User %d: %s", user.ID, conflict)
+		}
+		if report.FluidEntriesMoved > 0 || report.MonitoringMoved > 0 || report.MonitoringKept > 0 || len(report.MonitoringConflicts) > 0 {
+			log.Printf("User %d (%s): fluid entries moved %d, empty logs deleted %d, monitoring moved %d, backdated monitoring kept %d, conflicts %d",
+				user.ID, report.Timezone, report.FluidEntriesMoved, report.FluidLogsDeleted, report.MonitoringMoved, report.MonitoringKept, len(report.MonitoringConflicts))
+		}
+		totalEntries += report.FluidEntriesMoved
+		totalMonitoring += report.MonitoringMoved
+		totalConflicts += len(report.MonitoringConflicts)
+	}
+	log.Printf("Done. Users: %d, fluid entries moved: %d, monitoring moved: %d, conflicts: %d, failed users: %d",
+		len(users), totalEntries, totalMonitoring, totalConflicts, failed)
+}
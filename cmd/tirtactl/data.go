@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/spf13/viper"
+)
+
+func runExportUser(args []string) error {
+	fs := flag.NewFlagSet("export-user", flag.ExitOnError)
+	email := fs.String("email", "", "Email of the user to export (required)")
+	file := fs.String("file", "-", "Output file ('-' writes to stdout)")
+	fs.Parse(args)
+	if *email == "" {
+		fs.Usage()
+		return errors.New("--email is required")
+	}
+
+	db := connectDB()
+	userDataService := services.NewUserDataService(repositories.NewUserDataRepository(db), repositories.NewUserRepository(db))
+	bundle, err := userDataService.Export(*email)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *file != "-" {
+		f, err := os.OpenFile(*file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600) // Berisi data kesehatan & hash password
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		return fmt.Errorf("could not write export: %w", err)
+	}
+	log.Printf("Exported user %s (ID %d): %d fluid entries, %d monitoring sessions, %d lab results, %d complaints.",
+		bundle.User.Email, bundle.User.ID, len(bundle.FluidEntries), len(bundle.HemodialysisMonitorings), len(bundle.LabResults), len(bundle.ComplaintLogs))
+	return nil
+}
+
+func runImportUser(args []string) error {
+	fs := flag.NewFlagSet("import-user", flag.ExitOnError)
+	file := fs.String("file", "-", "File produced by export-user ('-' reads from stdin)")
+	email := fs.String("email", "", "Import under this email instead of the one in the file")
+	attributeMissingTo := fs.String("attribute-missing-to", "", "Email of an existing user to credit records whose prescriber/recorder does not exist here (default: fail and list them)")
+	fs.Parse(args)
+
+	var in io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	var bundle repositories.UserDataBundle
+	if err := json.NewDecoder(in).Decode(&bundle); err != nil {
+		return fmt.Errorf("could not read export file: %w", err)
+	}
+
+	db := connectDB()
+	userDataService := services.NewUserDataService(repositories.NewUserDataRepository(db), repositories.NewUserRepository(db))
+	user, err := userDataService.Import(bundle, *email, *attributeMissingTo)
+	if err != nil {
+		return err
+	}
+	log.Printf("Imported user %s as ID %d (was ID %d).", user.Email, user.ID, bundle.User.ID)
+	return nil
+}
+
+// purgeScopeWarning menjelaskan cakupan purge-data sebelum konfirmasi.
+const purgeScopeWarning = `This deletes ALL rows in every application table, not only patient data:
+  - every account, including admins and clinicians
+  - catalogs: symptoms, triage rules, lab tests, container presets, education content, tags and quizzes
+  - job history and devices
+Uploaded files (profile pictures, complaint attachments, education media) are NOT deleted from
+storage and become orphaned; empty the uploads directory or bucket separately if needed.
+Run "tirtactl seed" and "tirtactl create-admin" afterwards to make the database usable again.
+`
+
+func runPurgeData(args []string) error {
+	fs := flag.NewFlagSet("purge-data", flag.ExitOnError)
+	confirm := fs.String("confirm", "", "Name of the database to purge; asked interactively if empty")
+	fs.Parse(args)
+
+	db := connectDB()
+	dbName := viper.GetString("DB_NAME")
+	fmt.Print(purgeScopeWarning)
+	if *confirm == "" {
+		fmt.Printf("Database %q (%s:%s) will be emptied. Type the database name to continue: ",
+			dbName, viper.GetString("DB_HOST"), viper.GetString("DB_PORT"))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		*confirm = strings.TrimSpace(answer)
+	}
+	if *confirm != dbName {
+		return errors.New("confirmation does not match the database name, nothing was deleted")
+	}
+
+	if err := utils.ClearAllData(db); err != nil {
+		return fmt.Errorf("could not purge data: %w", err)
+	}
+	log.Printf("All data in %q deleted, including accounts and catalogs. The schema and migration history are unchanged; uploaded files were left in storage.", dbName)
+	return nil
+}
@@ -0,0 +1,81 @@
+// Command tirtactl berisi perintah operasional untuk admin: mengelola akun, data demo,
+// ekspor/impor data pasien, pembersihan data uji, dan pemeriksaan queue pengingat.
+// Semua perintah memakai repository dan service yang sama dengan API.
+//
+//	go run ./cmd/tirtactl create-admin --email=admin@example.com --name="Admin"
+//	go run ./cmd/tirtactl reset-password --email=pasien@example.com
+//	go run ./cmd/tirtactl seed
+//	go run ./cmd/tirtactl seed-demo
+//	go run ./cmd/tirtactl export-user --email=pasien@example.com --file=pasien.json
+//	go run ./cmd/tirtactl import-user --file=pasien.json --email=pasien.staging@example.com
+//	go run ./cmd/tirtactl import-user --file=pasien.json --attribute-missing-to=klinisi@example.com
+//	go run ./cmd/tirtactl purge-data --confirm=<DB_NAME>
+//	go run ./cmd/tirtactl republish-reminders --type=DRUG --dry-run
+//	go run ./cmd/tirtactl queue-status
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/darmawguna/tirtaapp.git/config"
+	"gorm.io/gorm"
+)
+
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{
+	"create-admin":        {"Create an admin account", runCreateAdmin},
+	"reset-password":      {"Set a new password for an existing account", runResetPassword},
+	"seed":                {"Create the default catalogs (symptoms, triage rules, container presets, lab tests)", runSeed},
+	"seed-demo":           {"Create a demo patient and clinician with sample data", runSeedDemo},
+	"export-user":         {"Export all data of one user to a JSON file", runExportUser},
+	"import-user":         {"Import a user exported with export-user as a new account", runImportUser},
+	"purge-data":          {"Delete ALL rows incl. accounts and catalogs (keeps the schema and uploaded files)", runPurgeData},
+	"republish-reminders": {"Re-queue pending reminders that have not been sent yet", runRepublishReminders},
+	"queue-status":        {"Show message and consumer counts of the reminder queues", runQueueStatus},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: tirtactl <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-20s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'tirtactl <command> -h' for the flags of a command.")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+}
+
+// connectDB memuat konfigurasi dan membuka database; perintah menolak jalan jika skema belum dimigrasi.
+func connectDB() *gorm.DB {
+	config.LoadConfig()
+	db := config.ConnectDB()
+	config.RequireMigratedSchema(db)
+	return db
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/darmawguna/tirtaapp.git/config"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/services"
+)
+
+func runRepublishReminders(args []string) error {
+	fs := flag.NewFlagSet("republish-reminders", flag.ExitOnError)
+	scheduleType := fs.String("type", "", "Only this reminder type: "+strings.Join(services.RepublishableReminderTypes, ", "))
+	userID := fs.Uint("user-id", 0, "Only reminders of this user")
+	dryRun := fs.Bool("dry-run", false, "Only count the reminders that would be published")
+	fs.Parse(args)
+
+	db := connectDB()
+	queueService := services.NewQueueService()
+	if !*dryRun {
+		if err := queueService.Connect(); err != nil {
+			return err
+		}
+		defer queueService.Close()
+	}
+
+	republishService := services.NewReminderRepublishService(
+		repositories.NewUserRepository(db),
+		repositories.NewDrugScheduleRepository(db),
+		repositories.NewControlScheduleRepository(db),
+		repositories.NewHemodialysisScheduleRepository(db),
+		repositories.NewMedicationRefillRepository(db),
+		queueService,
+	)
+	report, err := republishService.Republish(strings.ToUpper(*scheduleType), *userID, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		log.Println("Dry run: nothing was published.")
+	}
+	total := 0
+	for _, t := range services.RepublishableReminderTypes {
+		log.Printf("%-12s %d", t, report.Published[t])
+		total += report.Published[t]
+	}
+	log.Printf("Done. Published: %d, failed: %d", total, report.Failed)
+	if report.Failed > 0 {
+		return fmt.Errorf("%d reminder(s) could not be published", report.Failed)
+	}
+	return nil
+}
+
+func runQueueStatus(args []string) error {
+	fs := flag.NewFlagSet("queue-status", flag.ExitOnError)
+	fs.Parse(args)
+
+	// Hanya butuh RabbitMQ, tidak perlu koneksi database
+	config.LoadConfig()
+	queueService := services.NewQueueService()
+	if err := queueService.Connect(); err != nil {
+		return err
+	}
+	defer queueService.Close()
+
+	stats, err := queueService.Inspect()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "QUEUE\tREADY MESSAGES\tCONSUMERS")
+	for _, queue := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", queue.Name, queue.Messages, queue.Consumers)
+	}
+	w.Flush()
+	if len(stats) > 0 && stats[0].Consumers == 0 {
+		log.Println("WARNING: No worker is consuming the main queue.")
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/storage"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"gorm.io/gorm"
+)
+
+const minPasswordLength = 6 // Sama dengan validasi RegisterDTO
+
+// resolvePassword memakai password dari flag, atau membuat password acak yang dicetak sekali.
+func resolvePassword(password string) (string, bool, error) {
+	if password == "" {
+		return utils.RandomToken(8), true, nil
+	}
+	if len(password) < minPasswordLength {
+		return "", false, fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return password, false, nil
+}
+
+func runCreateAdmin(args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	email := fs.String("email", "", "Email of the new admin (required)")
+	name := fs.String("name", "Administrator", "Display name")
+	phone := fs.String("phone", "", "Phone number")
+	password := fs.String("password", "", "Password (a random one is generated and printed if empty)")
+	timezone := fs.String("timezone", "Asia/Makassar", "IANA timezone of the admin")
+	fs.Parse(args)
+	if *email == "" {
+		fs.Usage()
+		return errors.New("--email is required")
+	}
+	plain, generated, err := resolvePassword(*password)
+	if err != nil {
+		return err
+	}
+
+	db := connectDB()
+	userRepo := repositories.NewUserRepository(db)
+	if _, err := userRepo.FindByEmail(*email); err == nil {
+		return fmt.Errorf("a user with email %s already exists (use reset-password instead)", *email)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	authService := services.NewAuthService(userRepo, services.NewDeviceService(repositories.NewDeviceRepository(db)))
+	admin, err := authService.Register(dto.RegisterDTO{
+		Name:        *name,
+		Email:       *email,
+		Role:        models.RoleAdmin,
+		PhoneNumber: *phone,
+		Password:    plain,
+		Timezone:    *timezone,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create admin: %w", err)
+	}
+	log.Printf("Admin %s created with ID %d.", admin.Email, admin.ID)
+	if generated {
+		fmt.Printf("Generated password: %s\n", plain)
+	}
+	return nil
+}
+
+func runResetPassword(args []string) error {
+	fs := flag.NewFlagSet("reset-password", flag.ExitOnError)
+	email := fs.String("email", "", "Email of the account (required)")
+	password := fs.String("password", "", "New password (a random one is generated and printed if empty)")
+	fs.Parse(args)
+	if *email == "" {
+		fs.Usage()
+		return errors.New("--email is required")
+	}
+	plain, generated, err := resolvePassword(*password)
+	if err != nil {
+		return err
+	}
+
+	db := connectDB()
+	userRepo := repositories.NewUserRepository(db)
+	user, err := userRepo.FindByEmail(*email)
+	if err != nil {
+		return fmt.Errorf("user %s not found: %w", *email, err)
+	}
+	store, err := storage.NewBlobStoreFromConfig()
+	if err != nil {
+		return fmt.Errorf("could not initialize storage: %w", err)
+	}
+	profileService := services.NewProfileService(userRepo, store)
+	if _, err := profileService.UpdateProfile(user.ID, dto.UpdateProfileDTO{Password: &plain}, nil); err != nil {
+		return fmt.Errorf("could not reset password: %w", err)
+	}
+	log.Printf("Password for %s (ID %d) has been reset.", user.Email, user.ID)
+	if generated {
+		fmt.Printf("Generated password: %s\n", plain)
+	}
+	return nil
+}
+
+// seedCatalogs mengisi katalog bawaan (gejala, aturan triase, preset wadah, tes lab) yang masih
+// kosong. Katalog yang sudah berisi tidak diubah, jadi aman dijalankan di setiap deploy.
+func seedCatalogs(db *gorm.DB) error {
+	symptomRepo := repositories.NewSymptomRepository(db)
+	seeders := []struct {
+		name   string
+		seeder interface{ EnsureDefaults() error }
+	}{
+		// Gejala lebih dulu karena aturan triase merujuk kode gejala
+		{"symptoms", services.NewSymptomService(symptomRepo)},
+		{"triage rules", services.NewTriageService(repositories.NewTriageRuleRepository(db), symptomRepo)},
+		{"container presets", services.NewFluidContainerPresetService(repositories.NewFluidContainerPresetRepository(db))},
+		{"lab tests", services.NewLabTestService(repositories.NewLabTestRepository(db))},
+	}
+	for _, s := range seeders {
+		if err := s.seeder.EnsureDefaults(); err != nil {
+			return fmt.Errorf("could not seed default %s: %w", s.name, err)
+		}
+	}
+	return nil
+}
+
+func runSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	fs.Parse(args)
+
+	if err := seedCatalogs(connectDB()); err != nil {
+		return err
+	}
+	log.Println("Default catalogs are in place.")
+	return nil
+}
+
+func runSeedDemo(args []string) error {
+	fs := flag.NewFlagSet("seed-demo", flag.ExitOnError)
+	password := fs.String("password", "demo1234", "Password for both demo accounts")
+	fs.Parse(args)
+	if len(*password) < minPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+
+	db := connectDB()
+	queueService := services.NewQueueService()
+	defer queueService.Close()
+
+	userRepo := repositories.NewUserRepository(db)
+	careTeamRepo := repositories.NewCareTeamRepository(db)
+	labTestRepo := repositories.NewLabTestRepository(db)
+	fluidPrescriptionRepo := repositories.NewFluidPrescriptionRepository(db)
+	fluidContainerPresetRepo := repositories.NewFluidContainerPresetRepository(db)
+	hemodialysisScheduleRepo := repositories.NewHemodialysisScheduleRepository(db)
+
+	// Katalog bawaan dibutuhkan oleh hasil lab dan keluhan
+	if err := seedCatalogs(db); err != nil {
+		return err
+	}
+
+	seedService := services.NewDemoSeedService(
+		userRepo,
+		services.NewAuthService(userRepo, services.NewDeviceService(repositories.NewDeviceRepository(db))),
+		services.NewCareTeamService(careTeamRepo, userRepo),
+		services.NewFluidPrescriptionService(fluidPrescriptionRepo, userRepo, careTeamRepo),
+		services.NewFluidBalanceService(repositories.NewFluidBalanceRepository(db), userRepo, fluidPrescriptionRepo, repositories.NewFluidEntryRepository(db), fluidContainerPresetRepo, careTeamRepo, queueService),
+		services.NewDrugScheduleService(repositories.NewDrugScheduleRepository(db), queueService),
+		services.NewControlScheduleService(repositories.NewControlScheduleRepository(db), queueService),
+		services.NewHemodialysisScheduleService(hemodialysisScheduleRepo, queueService),
+		services.NewMedicationRefillService(repositories.NewMedicationRefillRepository(db), queueService),
+		services.NewLabResultService(repositories.NewLabResultRepository(db), labTestRepo, repositories.NewHemodialysisMonitoringRepository(db), userRepo, careTeamRepo),
+	)
+	result, err := seedService.Seed(*password)
+	if err != nil {
+		return err
+	}
+	if !result.Created {
+		log.Printf("Demo data already exists (patient ID %d). Nothing to do.", result.Patient.ID)
+		return nil
+	}
+	log.Printf("Demo data created. Patient: %s (ID %d), clinician: %s (ID %d), password: %s",
+		result.Patient.Email, result.Patient.ID, result.Clinician.Email, result.Clinician.ID, *password)
+	return nil
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/darmawguna/tirtaapp.git/config"   // Adjust path
 	"github.com/darmawguna/tirtaapp.git/services" // Adjust path
 	"github.com/darmawguna/tirtaapp.git/worker"   // <-- Import paket worker
-	"github.com/robfig/cron/v3"
 	// "github.com/spf13/viper" // Tidak diperlukan lagi di sini
 )
 
@@ -59,14 +58,12 @@ func main() {
 		log.Fatalf("FATAL: Failed to set QoS: %v", err)
 	}
 
-	// Setup Cron Job
-	cr := cron.New()
-	_, err = cr.AddFunc("0 6 * * *", workerInstance.SendDailyMonitoringReminders)
-	if err != nil {
-		log.Fatalf("FATAL: Could not add cron job: %v", err)
+	// Setup Scheduler (registry job + cron per timezone user)
+	scheduler := worker.NewScheduler(workerInstance)
+	if err := scheduler.Start(); err != nil {
+		log.Fatalf("FATAL: Could not start job scheduler: %v", err)
 	}
-	cr.Start()
-	log.Println("Cron job scheduled.")
+	log.Println("Job scheduler started.")
 
 	// Mulai Consumer RabbitMQ menggunakan channel yang didapat dari QueueService
 	msgs, err := ch.Consume(services.MainQueue, "", false, false, false, false, nil)
@@ -101,9 +98,11 @@ func main() {
 
 	<-shutdownChan // Tunggu sinyal shutdown
 	log.Println("Shutting down worker gracefully...")
-	ctx := cr.Stop()
+	ctx := scheduler.Stop()
 	<-ctx.Done()
 	log.Println("Cron jobs stopped.")
+	workerInstance.WaitManualJobs()
+	log.Println("Manual jobs finished.")
 	// Koneksi RabbitMQ akan ditutup oleh defer queueService.Close()
 	log.Println("Worker exiting.")
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"net/url"
 
 	"github.com/spf13/viper"
 	"gorm.io/driver/mysql"
@@ -12,13 +13,21 @@ import (
 // ConnectDB hanya bertanggung jawab untuk membuat koneksi ke database.
 func ConnectDB() *gorm.DB {
 
+	// Timezone koneksi DB. Default UTC agar kolom DATE (tanggal lokal pasien) tidak
+	// bergeser hari mengikuti timezone server.
+	dbTimezone := viper.GetString("DB_TIMEZONE")
+	if dbTimezone == "" {
+		dbTimezone = "UTC"
+	}
+
 	// Buat Data Source Name (DSN) dari environment variables
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=%s",
 		viper.GetString("DB_USER"),
 		viper.GetString("DB_PASSWORD"),
 		viper.GetString("DB_HOST"),
 		viper.GetString("DB_PORT"),
 		viper.GetString("DB_NAME"),
+		url.QueryEscape(dbTimezone),
 	)
 
 	// Buka koneksi ke database
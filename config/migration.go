@@ -1,17 +1,23 @@
 package config
 
 import (
+	"context"
 	"log"
 
+	"github.com/darmawguna/tirtaapp.git/migrations"
 	"gorm.io/gorm"
 )
 
-// RunMigration menjalankan auto-migrasi GORM.
-func RunMigration(db *gorm.DB, models ...interface{}) {
-	err := db.AutoMigrate(models...)
+// RequireMigratedSchema memastikan semua migrasi berversi sudah dijalankan (lihat cmd/migrate).
+// Server tidak lagi mengubah skema sendiri; jika skema tertinggal, proses berhenti.
+func RequireMigratedSchema(db *gorm.DB) {
+	migrator, err := migrations.NewMigrator(db)
 	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		log.Fatal("Failed to load database migrations:", err)
+	}
+	if err := migrator.CheckUpToDate(context.Background()); err != nil {
+		log.Fatal("Database schema is not ready: ", err)
 	}
 
-	log.Println("Database migration successful.")
-}
\ No newline at end of file
+	log.Println("Database schema is up to date.")
+}
@@ -0,0 +1,22 @@
+package dto
+
+// AddCaregiverDTO dipakai pasien untuk menautkan akun pendamping (keluarga) lewat email.
+type AddCaregiverDTO struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// AssignClinicianDTO dipakai klinisi/admin untuk menugaskan klinisi ke pasien.
+type AssignClinicianDTO struct {
+	ClinicianID uint `json:"clinician_id" binding:"required"`
+}
+
+type CareTeamMemberResponseDTO struct {
+	ID          uint   `json:"id"`
+	PatientID   uint   `json:"patient_id"`
+	PatientName string `json:"patient_name,omitempty"`
+	MemberID    uint   `json:"member_id"`
+	MemberName  string `json:"member_name"`
+	MemberEmail string `json:"member_email"`
+	Relation    string `json:"relation"` // caregiver | clinician
+	Status      string `json:"status"`   // pending | accepted
+}
@@ -1,8 +1,18 @@
 package dto
 
 // CreateComplaintDTO adalah DTO untuk request pembuatan keluhan.
+// Isi complaints dengan kode gejala dari katalog; nama gejala dan teks bebas tetap diterima.
+// Bisa dikirim sebagai JSON atau multipart/form-data (complaints berulang + file attachments).
 type CreateComplaintDTO struct {
-	Complaints []string `json:"complaints" binding:"required,min=1"`
+	Complaints []string `json:"complaints" form:"complaints" binding:"required,min=1"`
+}
+
+type ComplaintAttachmentResponseDTO struct {
+	ID        uint   `json:"id"`
+	Kind      string `json:"kind"`
+	MimeType  string `json:"mime_type"`
+	SizeBytes int64  `json:"size_bytes"`
+	URL       string `json:"url"`
 }
 
 type ComplaintResponse struct {
@@ -10,3 +20,32 @@ type ComplaintResponse struct {
 	Complaint []string `json:"complaints"`
 	Message   string   `json:"message" binding:"required,url"`
 }
+
+// ComplaintReplyDTO adalah DTO untuk membalas percakapan keluhan.
+type ComplaintReplyDTO struct {
+	Message string `json:"message" binding:"required"`
+}
+
+type ComplaintReplyResponseDTO struct {
+	ID             uint   `json:"id"`
+	ComplaintLogID uint   `json:"complaint_id"`
+	AuthorID       uint   `json:"author_id"`
+	AuthorRole     string `json:"author_role"`
+	Message        string `json:"message"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// ComplaintQueueItemDTO adalah satu baris antrean keluhan untuk petugas.
+type ComplaintQueueItemDTO struct {
+	ID             uint     `json:"id"`
+	UserID         uint     `json:"user_id"`
+	PatientName    string   `json:"patient_name"`
+	Complaints     []string `json:"complaints"`
+	TriageLevel    string   `json:"triage_level"`
+	TriageRuleName string   `json:"triage_rule_name,omitempty"`
+	Status         string   `json:"status"`
+	CreatedAt      string   `json:"created_at"`
+	AcknowledgedAt *string  `json:"acknowledged_at"`
+	SLADueAt       *string  `json:"sla_due_at"`
+	Escalated      bool     `json:"escalated"`
+}
@@ -31,4 +31,7 @@ type DrugScheduleResponseDTO struct {
 	At12         bool   `json:"at_12"`
 	At18         bool   `json:"at_18"`
 	IsActive     bool   `json:"is_active"`
+	At06Missed   bool   `json:"at_06_missed"`
+	At12Missed   bool   `json:"at_12_missed"`
+	At18Missed   bool   `json:"at_18_missed"`
 }
\ No newline at end of file
@@ -1,21 +1,129 @@
 package dto
 
 // CreateEducationDTO adalah DTO untuk membuat data edukasi baru.
+// Tags boleh dikirim berulang atau dipisah koma.
 type CreateEducationDTO struct {
-	Name string `json:"name" form:"name"` // Hapus binding:"required"
-	Url  string `json:"url" form:"url"`   // Hapus binding:"required,url"
+	Name        string   `json:"name" form:"name"` // Hapus binding:"required"
+	Url         string   `json:"url" form:"url"`   // Hapus binding:"required,url"
+	Description string   `json:"description" form:"description"`
+	Category    string   `json:"category" form:"category"`
+	Tags        []string `json:"tags" form:"tags"`
+	// ID sesi upload bertahap yang sudah selesai; alternatif dari field file "media"
+	MediaUploadID string `json:"media_upload_id" form:"media_upload_id"`
 }
 
 // UpdateEducationDTO (Hapus tag binding)
 type UpdateEducationDTO struct {
-	Name string `json:"name" form:"name"` // Hapus binding:"required"
-	Url  string `json:"url" form:"url"`   // Hapus binding:"required,url"
+	Name        string   `json:"name" form:"name"` // Hapus binding:"required"
+	Url         string   `json:"url" form:"url"`   // Hapus binding:"required,url"
+	Description string   `json:"description" form:"description"`
+	Category    string   `json:"category" form:"category"`
+	Tags        []string `json:"tags" form:"tags"`
+	// ID sesi upload bertahap yang sudah selesai; alternatif dari field file "media"
+	MediaUploadID string `json:"media_upload_id" form:"media_upload_id"`
+}
+
+// SetFeaturedEducationDTO berisi ID edukasi unggulan sesuai urutan tampil; array kosong menghapus semua.
+type SetFeaturedEducationDTO struct {
+	EducationIDs []uint `json:"education_ids" binding:"required"`
 }
 
 type EducationResponseDTO struct {
-	ID        uint   `json:"id"`
-	Name      string `json:"name"`
-	Url       string `json:"url"`
-	Thumbnail string `json:"thumbnail"` // Tetap ada untuk response
-	CreatedBy uint   `json:"created_by"`
-}
\ No newline at end of file
+	ID                uint              `json:"id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	Category          string            `json:"category"`
+	Tags              []string          `json:"tags"`
+	FeaturedOrder     *int              `json:"featured_order"`
+	Url               string            `json:"url"`        // Untuk media yang di-host sendiri, mengarah ke endpoint media
+	MediaType         string            `json:"media_type"` // url, pdf atau video
+	MediaMimeType     *string           `json:"media_mime_type"`
+	MediaSize         *int64            `json:"media_size"`
+	Thumbnail         string            `json:"thumbnail"`                    // Tetap ada untuk response
+	ThumbnailVariants map[string]string `json:"thumbnail_variants,omitempty"` // 128, 512, 128_webp
+	CreatedBy         uint              `json:"created_by"`
+	CreatedAt         string            `json:"created_at"`
+}
+
+type EducationPageResponseDTO struct {
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"page_size"`
+	Total    int64                  `json:"total"`
+	Items    []EducationResponseDTO `json:"items"`
+}
+
+type EducationCategoryResponseDTO struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
+}
+
+// RecordEducationViewDTO dilaporkan aplikasi saat pasien menutup konten edukasi.
+type RecordEducationViewDTO struct {
+	DurationSeconds *int `json:"duration_seconds" binding:"omitempty,min=0,max=86400"`
+	PercentWatched  *int `json:"percent_watched" binding:"omitempty,min=0,max=100"`
+}
+
+type EducationProgressItemDTO struct {
+	EducationID       uint    `json:"education_id"`
+	Name              string  `json:"name"`
+	Category          string  `json:"category"`
+	ViewCount         int     `json:"view_count"`
+	MaxPercentWatched int     `json:"max_percent_watched"`
+	LastViewedAt      *string `json:"last_viewed_at"`
+	Completed         bool    `json:"completed"`
+	CompletedAt       *string `json:"completed_at"`
+}
+
+type EducationProgressResponseDTO struct {
+	UserID          uint                       `json:"user_id"`
+	Completed       int                        `json:"completed"`
+	Total           int                        `json:"total"`
+	PercentComplete float64                    `json:"percent_complete"`
+	Summary         string                     `json:"summary"`
+	Items           []EducationProgressItemDTO `json:"items"`
+}
+
+type EducationAnalyticsResponseDTO struct {
+	EducationID       uint    `json:"education_id"`
+	Name              string  `json:"name"`
+	UniqueViewers     int64   `json:"unique_viewers"`
+	TotalViews        int64   `json:"total_views"`
+	Completions       int64   `json:"completions"`
+	CompletionRate    float64 `json:"completion_rate"`
+	AvgPercentWatched float64 `json:"avg_percent_watched"`
+}
+
+// SetEducationTriggersDTO mengganti seluruh pemicu rekomendasi sebuah edukasi.
+type SetEducationTriggersDTO struct {
+	Triggers []EducationTriggerDTO `json:"triggers" binding:"required,dive"`
+}
+
+type EducationTriggerDTO struct {
+	Type        string `json:"type" binding:"required,oneof=fluid_over_limit high_idwg symptom onboarding"`
+	SymptomCode string `json:"symptom_code" binding:"max=50"`
+}
+
+// EducationRecommendationDTO adalah edukasi yang disarankan beserta alasannya.
+type EducationRecommendationDTO struct {
+	EducationResponseDTO
+	Reason      string `json:"reason"`
+	ReasonLabel string `json:"reason_label"`
+	SymptomCode string `json:"symptom_code,omitempty"`
+}
+
+// StartEducationUploadDTO membuka sesi upload bertahap untuk file media edukasi.
+type StartEducationUploadDTO struct {
+	FileName   string `json:"file_name" binding:"required,max=255"`
+	TotalBytes int64  `json:"total_bytes" binding:"required,min=1"`
+}
+
+type EducationUploadResponseDTO struct {
+	UploadID       string  `json:"upload_id"`
+	FileName       string  `json:"file_name"`
+	TotalBytes     int64   `json:"total_bytes"`
+	ReceivedBytes  int64   `json:"received_bytes"` // Offset untuk potongan berikutnya
+	MimeType       *string `json:"mime_type"`      // null sampai potongan pertama diterima
+	Status         string  `json:"status"`
+	ChunkSizeBytes int64   `json:"chunk_size_bytes"` // Ukuran potongan maksimal per request
+	ExpiresAt      string  `json:"expires_at"`
+}
@@ -1,8 +1,9 @@
 package dto
 
 type CreateOrUpdateFluidLogDTO struct {
-	IntakeCC *int `json:"intake_cc" binding:"required,min=0"`
-	OutputCC *int `json:"output_cc" binding:"required,min=0"`
+	IntakeCC *int   `json:"intake_cc" binding:"required,min=0"`
+	OutputCC *int   `json:"output_cc" binding:"required,min=0"`
+	LogDate  string `json:"log_date" binding:"omitempty,datetime=2006-01-02"` // Opsional, tanggal lokal untuk pengisian susulan
 }
 
 type FluidBalanceLogResponseDTO struct {
@@ -13,4 +14,129 @@ type FluidBalanceLogResponseDTO struct {
 	OutputCC       int    `json:"output_cc"`
 	BalanceCC      int    `json:"balance_cc"`
 	WarningMessage string `json:"warning_message,omitempty"` // Hanya muncul jika ada warning
-}
\ No newline at end of file
+}
+
+// FluidAllowanceDTO adalah batas cairan yang berlaku hari ini beserta sisanya.
+type FluidAllowanceDTO struct {
+	DailyLimitCC       int   `json:"daily_limit_cc"`
+	WarningThresholdCC int   `json:"warning_threshold_cc"`
+	RemainingCC        int   `json:"remaining_cc"`
+	PrescriptionID     *uint `json:"prescription_id"` // null jika memakai batas bawaan
+}
+
+type FluidLogWithAllowanceResponseDTO struct {
+	FluidBalanceLogResponseDTO
+	Allowance             FluidAllowanceDTO            `json:"allowance"`
+	RecommendedEducations []EducationRecommendationDTO `json:"recommended_educations,omitempty"` // Saat melewati batas harian
+}
+
+type CreateFluidPrescriptionDTO struct {
+	DailyLimitCC    *int   `json:"daily_limit_cc" binding:"omitempty,min=100,max=5000"`
+	ResidualUrineCC *int   `json:"residual_urine_cc" binding:"omitempty,min=0,max=3000"`
+	WarningPercent  int    `json:"warning_percent" binding:"omitempty,min=50,max=100"`
+	EffectiveDate   string `json:"effective_date" binding:"required,datetime=2006-01-02"`
+	Notes           string `json:"notes"`
+}
+
+type FluidPrescriptionResponseDTO struct {
+	ID              uint   `json:"id"`
+	UserID          uint   `json:"user_id"`
+	EffectiveDate   string `json:"effective_date"` // Format YYYY-MM-DD
+	DailyLimitCC    int    `json:"daily_limit_cc"`
+	ResidualUrineCC *int   `json:"residual_urine_cc"`
+	WarningPercent  int    `json:"warning_percent"`
+	Notes           string `json:"notes,omitempty"`
+	PrescribedBy    uint   `json:"prescribed_by"`
+}
+
+// FluidEntryDTO dipakai untuk membuat maupun mengoreksi satu catatan cairan.
+// Volume diisi langsung (volume_cc) atau dari preset wadah (container_preset_id x quantity).
+type FluidEntryDTO struct {
+	Direction         string  `json:"direction" binding:"required,oneof=intake output"`
+	Category          string  `json:"category" binding:"required,oneof=water tea coffee milk soup fruit urine vomit other"`
+	VolumeCC          *int    `json:"volume_cc" binding:"omitempty,min=1,max=5000"`
+	ContainerPresetID *uint   `json:"container_preset_id"`
+	Quantity          float64 `json:"quantity" binding:"omitempty,gt=0,lte=20"`
+	RecordedAt        string  `json:"recorded_at"`                                      // RFC3339, opsional (default: sekarang)
+	LogDate           string  `json:"log_date" binding:"omitempty,datetime=2006-01-02"` // Opsional, tanggal lokal untuk pengisian susulan
+	Notes             string  `json:"notes"`
+}
+
+type FluidEntryResponseDTO struct {
+	ID                uint   `json:"id"`
+	LogDate           string `json:"log_date"` // Format YYYY-MM-DD
+	RecordedAt        string `json:"recorded_at"`
+	Direction         string `json:"direction"`
+	Category          string `json:"category"`
+	VolumeCC          int    `json:"volume_cc"`
+	ContainerPresetID *uint  `json:"container_preset_id"`
+	Notes             string `json:"notes,omitempty"`
+}
+
+type FluidEntryResultResponseDTO struct {
+	Entry                 FluidEntryResponseDTO        `json:"entry"`
+	DailyLog              FluidBalanceLogResponseDTO   `json:"daily_log"`
+	Allowance             FluidAllowanceDTO            `json:"allowance"`
+	RecommendedEducations []EducationRecommendationDTO `json:"recommended_educations,omitempty"` // Saat melewati batas harian
+}
+
+type FluidTimelineResponseDTO struct {
+	Date      string                     `json:"date"`
+	DailyLog  FluidBalanceLogResponseDTO `json:"daily_log"`
+	Entries   []FluidEntryResponseDTO    `json:"entries"`
+	Allowance FluidAllowanceDTO          `json:"allowance"`
+}
+
+type FluidContainerPresetDTO struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	VolumeCC  int    `json:"volume_cc" binding:"required,min=1,max=3000"`
+	SortOrder int    `json:"sort_order"`
+	IsActive  *bool  `json:"is_active"`
+}
+
+type FluidContainerPresetResponseDTO struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	VolumeCC  int    `json:"volume_cc"`
+	SortOrder int    `json:"sort_order"`
+	IsActive  bool   `json:"is_active"`
+}
+
+// FluidStatsPeriodDTO adalah agregat cairan untuk satu periode (hari/minggu/bulan).
+type FluidStatsPeriodDTO struct {
+	PeriodStart   string  `json:"period_start"` // YYYY-MM-DD, minggu dimulai Senin
+	DaysLogged    int     `json:"days_logged"`
+	TotalIntakeCC int     `json:"total_intake_cc"`
+	TotalOutputCC int     `json:"total_output_cc"`
+	AvgIntakeCC   float64 `json:"avg_intake_cc"`
+	AvgOutputCC   float64 `json:"avg_output_cc"`
+	AvgBalanceCC  float64 `json:"avg_balance_cc"`
+	MaxBalanceCC  int     `json:"max_balance_cc"`
+	DaysOverLimit int     `json:"days_over_limit"`
+}
+
+type FluidStatsResponseDTO struct {
+	From                   string                `json:"from"`
+	To                     string                `json:"to"`
+	Granularity            string                `json:"granularity"`
+	DaysInRange            int                   `json:"days_in_range"`
+	DaysLogged             int                   `json:"days_logged"`
+	PercentDaysLogged      float64               `json:"percent_days_logged"`
+	TotalIntakeCC          int                   `json:"total_intake_cc"`
+	TotalOutputCC          int                   `json:"total_output_cc"`
+	AvgIntakeCC            float64               `json:"avg_intake_cc"`
+	AvgOutputCC            float64               `json:"avg_output_cc"`
+	AvgBalanceCC           float64               `json:"avg_balance_cc"`
+	DaysOverLimit          int                   `json:"days_over_limit"`
+	LongestCompliantStreak int                   `json:"longest_compliant_streak"`
+	Periods                []FluidStatsPeriodDTO `json:"periods"`
+}
+
+type FluidHistoryPageResponseDTO struct {
+	From     string                       `json:"from"`
+	To       string                       `json:"to"`
+	Page     int                          `json:"page"`
+	PageSize int                          `json:"page_size"`
+	Total    int64                        `json:"total"`
+	Items    []FluidBalanceLogResponseDTO `json:"items"`
+}
@@ -1,18 +1,104 @@
 package dto
 
+// BloodPressureDTO adalah tekanan darah terstruktur. Rentang divalidasi ulang di service
+// (sistolik harus lebih besar dari diastolik).
+type BloodPressureDTO struct {
+	Systolic  int  `json:"systolic" binding:"required,min=60,max=260"`
+	Diastolic int  `json:"diastolic" binding:"required,min=30,max=160"`
+	Pulse     *int `json:"pulse" binding:"omitempty,min=30,max=200"`
+}
+
 type CreateHemodialysisMonitoringDTO struct {
-	BPBefore     string  `json:"bp_before" binding:"required"`
-	BPAfter      string  `json:"bp_after" binding:"required"`
-	WeightBefore float64 `json:"weight_before" binding:"required,gt=0"`
-	WeightAfter  float64 `json:"weight_after" binding:"required,gt=0"`
+	// Tekanan darah terstruktur (disarankan). BPBefore/BPAfter teks tetap diterima
+	// untuk aplikasi lama dan akan diparse ("120/80", "120-80", ...).
+	BloodPressureBefore *BloodPressureDTO `json:"blood_pressure_before"`
+	BloodPressureAfter  *BloodPressureDTO `json:"blood_pressure_after"`
+	BPBefore            string            `json:"bp_before"`
+	BPAfter             string            `json:"bp_after"`
+	WeightBefore        float64           `json:"weight_before" binding:"required,gt=0,lte=300"`
+	WeightAfter         float64           `json:"weight_after" binding:"required,gt=0,lte=300"`
+	UFGoalML            *int              `json:"uf_goal_ml" binding:"omitempty,min=0,max=6000"`
+	UFAchievedML        *int              `json:"uf_achieved_ml" binding:"omitempty,min=0,max=6000"`
+	DurationMinutes     *int              `json:"duration_minutes" binding:"omitempty,min=30,max=600"`
+	DryWeight           *float64          `json:"dry_weight" binding:"omitempty,gt=0,lte=300"`
+	Symptoms            []string          `json:"symptoms" binding:"omitempty,dive,oneof=cramp hypotension nausea vomiting headache chest_pain itching dizziness fatigue"`
+	// Opsional (YYYY-MM-DD, waktu lokal). Kosong = hari ini; tanggal lampau dibatasi ENTRY_BACKDATE_WINDOW_DAYS
+	MonitoringDate string `json:"monitoring_date" binding:"omitempty,datetime=2006-01-02"`
+	// Opsional: sesi HD yang dipantau. Jika diisi, tanggal pemantauan mengikuti tanggal jadwal.
+	ScheduleID *uint `json:"schedule_id"`
+}
+
+type BloodPressureResponseDTO struct {
+	Systolic  int  `json:"systolic"`
+	Diastolic int  `json:"diastolic"`
+	Pulse     *int `json:"pulse"`
 }
 
 type HemodialysisMonitoringResponseDTO struct {
-	ID             uint    `json:"id"`
-	UserID         uint    `json:"user_id"`
-	MonitoringDate string  `json:"monitoring_date"` // Format YYYY-MM-DD
-	BPBefore       string  `json:"bp_before"`
-	BPAfter        string  `json:"bp_after"`
-	WeightBefore   float64 `json:"weight_before"`
-	WeightAfter    float64 `json:"weight_after"`
-}
\ No newline at end of file
+	ID                  uint                      `json:"id"`
+	UserID              uint                      `json:"user_id"`
+	MonitoringDate      string                    `json:"monitoring_date"` // Format YYYY-MM-DD
+	ScheduleID          *uint                     `json:"schedule_id"`
+	BPBefore            string                    `json:"bp_before"`
+	BPAfter             string                    `json:"bp_after"`
+	BloodPressureBefore *BloodPressureResponseDTO `json:"blood_pressure_before"` // null untuk data lama yang tidak bisa diparse
+	BloodPressureAfter  *BloodPressureResponseDTO `json:"blood_pressure_after"`
+	BPNeedsReview       bool                      `json:"bp_needs_review"`
+	WeightBefore        float64                   `json:"weight_before"`
+	WeightAfter         float64                   `json:"weight_after"`
+	UFGoalML            *int                      `json:"uf_goal_ml"`
+	UFAchievedML        *int                      `json:"uf_achieved_ml"`
+	DurationMinutes     *int                      `json:"duration_minutes"`
+	DryWeight           *float64                  `json:"dry_weight"`
+	Symptoms            []string                  `json:"symptoms"`
+	IDWGKg              *float64                  `json:"idwg_kg"`      // null untuk sesi pertama
+	IDWGPercent         *float64                  `json:"idwg_percent"` // null jika berat kering belum diketahui
+	IDWGExceeded        bool                      `json:"idwg_exceeded"`
+	// Edukasi yang disarankan saat IDWG melewati ambang (hanya pada response simpan)
+	RecommendedEducations []EducationRecommendationDTO `json:"recommended_educations,omitempty"`
+}
+
+// IDWGAlertResponseDTO adalah sesi dengan IDWG melewati ambang untuk ditinjau klinisi.
+type IDWGAlertResponseDTO struct {
+	HemodialysisMonitoringResponseDTO
+	PatientName string `json:"patient_name"`
+}
+
+// CreateIntradialyticReadingDTO adalah satu pengukuran selama sesi HD (dicatat perawat).
+type CreateIntradialyticReadingDTO struct {
+	RecordedAt       string `json:"recorded_at" binding:"required"` // RFC3339
+	Systolic         int    `json:"systolic" binding:"required,min=40,max=260"`
+	Diastolic        int    `json:"diastolic" binding:"required,min=20,max=160"`
+	Pulse            *int   `json:"pulse" binding:"omitempty,min=30,max=200"`
+	BloodFlowRate    *int   `json:"blood_flow_rate" binding:"omitempty,min=0,max=600"`
+	VenousPressure   *int   `json:"venous_pressure" binding:"omitempty,min=-100,max=500"`
+	ArterialPressure *int   `json:"arterial_pressure" binding:"omitempty,min=-400,max=100"`
+	UFRate           *int   `json:"uf_rate" binding:"omitempty,min=0,max=3000"`
+	Notes            string `json:"notes"`
+}
+
+type IntradialyticReadingResponseDTO struct {
+	ID                uint     `json:"id"`
+	RecordedAt        string   `json:"recorded_at"`
+	Systolic          int      `json:"systolic"`
+	Diastolic         int      `json:"diastolic"`
+	Pulse             *int     `json:"pulse"`
+	BloodFlowRate     *int     `json:"blood_flow_rate"`
+	VenousPressure    *int     `json:"venous_pressure"`
+	ArterialPressure  *int     `json:"arterial_pressure"`
+	UFRate            *int     `json:"uf_rate"`
+	Notes             string   `json:"notes,omitempty"`
+	RecordedBy        uint     `json:"recorded_by"`
+	Hypotension       bool     `json:"hypotension"`
+	HypotensionReason []string `json:"hypotension_reasons"`
+}
+
+// IntradialyticSeriesResponseDTO adalah deret waktu tanda vital satu sesi HD.
+type IntradialyticSeriesResponseDTO struct {
+	MonitoringID        uint                              `json:"monitoring_id"`
+	BaselineSystolic    *int                              `json:"baseline_systolic"` // Sistolik sebelum HD
+	SystolicDropMMHg    int                               `json:"systolic_drop_threshold"`
+	NadirSystolicMMHg   int                               `json:"nadir_systolic_threshold"`
+	HypotensionReadings int                               `json:"hypotension_readings"`
+	Readings            []IntradialyticReadingResponseDTO `json:"readings"`
+}
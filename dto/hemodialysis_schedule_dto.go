@@ -12,8 +12,9 @@ type UpdateHemodialysisScheduleDTO struct {
 }
 
 type HemodialysisScheduleResponseDTO struct {
-	ID           uint   `json:"id"`
-	UserID       uint   `json:"user_id"`
-	ScheduleDate string `json:"schedule_date"`
-	IsActive     bool   `json:"is_active"`
-}
\ No newline at end of file
+	ID               uint   `json:"id"`
+	UserID           uint   `json:"user_id"`
+	ScheduleDate     string `json:"schedule_date"`
+	IsActive         bool   `json:"is_active"`
+	MonitoringStatus string `json:"monitoring_status"` // pending | recorded | missing
+}
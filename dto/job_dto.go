@@ -0,0 +1,26 @@
+package dto
+
+type TriggerJobDTO struct {
+	Timezone string `json:"timezone"` // Opsional, kosong = semua timezone user
+}
+
+type JobRunResponseDTO struct {
+	ID             uint    `json:"id"`
+	JobName        string  `json:"job_name"`
+	Timezone       string  `json:"timezone,omitempty"`
+	Trigger        string  `json:"trigger"`
+	Status         string  `json:"status"`
+	StartedAt      string  `json:"started_at"`
+	FinishedAt     *string `json:"finished_at"`
+	ItemsProcessed int     `json:"items_processed"`
+	ErrorCount     int     `json:"error_count"`
+	ErrorMessage   string  `json:"error_message,omitempty"`
+}
+
+type JobResponseDTO struct {
+	Name            string             `json:"name"`
+	Description     string             `json:"description"`
+	CronSpec        string             `json:"cron_spec"`
+	PerUserTimezone bool               `json:"per_user_timezone"`
+	LastRun         *JobRunResponseDTO `json:"last_run"`
+}
@@ -0,0 +1,93 @@
+package dto
+
+// LabTestDTO dipakai untuk membuat & mengubah katalog tes lab. Ambang yang tidak diisi tidak dievaluasi.
+type LabTestDTO struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	Unit         string   `json:"unit" binding:"required,max=30"`
+	RefLow       *float64 `json:"ref_low" binding:"omitempty,gte=0"`
+	RefHigh      *float64 `json:"ref_high" binding:"omitempty,gte=0"`
+	CriticalLow  *float64 `json:"critical_low" binding:"omitempty,gte=0"`
+	CriticalHigh *float64 `json:"critical_high" binding:"omitempty,gte=0"`
+	SortOrder    int      `json:"sort_order"`
+	IsActive     *bool    `json:"is_active"`
+}
+
+// CreateLabTestDTO menambahkan kode unik tes; kode tidak bisa diubah setelah dibuat.
+type CreateLabTestDTO struct {
+	Code string `json:"code" binding:"required,max=50,lowercase"`
+	LabTestDTO
+}
+
+type LabTestResponseDTO struct {
+	ID           uint     `json:"id"`
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Unit         string   `json:"unit"`
+	RefLow       *float64 `json:"ref_low"`
+	RefHigh      *float64 `json:"ref_high"`
+	CriticalLow  *float64 `json:"critical_low"`
+	CriticalHigh *float64 `json:"critical_high"`
+	SortOrder    int      `json:"sort_order"`
+	IsActive     bool     `json:"is_active"`
+}
+
+type LabResultItemDTO struct {
+	TestCode string   `json:"test_code" binding:"required"`
+	Value    *float64 `json:"value" binding:"required,gte=0"`
+}
+
+// RecordLabResultsDTO adalah satu panel hasil lab pada satu tanggal sampel.
+type RecordLabResultsDTO struct {
+	SampleDate string             `json:"sample_date" binding:"required,datetime=2006-01-02"` // Tanggal lokal pengambilan sampel
+	Results    []LabResultItemDTO `json:"results" binding:"required,min=1,dive"`
+	Notes      string             `json:"notes"`
+}
+
+type LabResultResponseDTO struct {
+	ID         uint     `json:"id"`
+	UserID     uint     `json:"user_id"`
+	TestCode   string   `json:"test_code"`
+	TestName   string   `json:"test_name"`
+	Unit       string   `json:"unit"`
+	Value      float64  `json:"value"`
+	SampleDate string   `json:"sample_date"`
+	Flag       string   `json:"flag"` // normal, low, high, critical_low, critical_high
+	RefLow     *float64 `json:"ref_low"`
+	RefHigh    *float64 `json:"ref_high"`
+	EnteredBy  uint     `json:"entered_by"`
+	Notes      string   `json:"notes,omitempty"`
+}
+
+type LabTrendPointDTO struct {
+	ResultID   uint    `json:"result_id"`
+	SampleDate string  `json:"sample_date"`
+	Value      float64 `json:"value"`
+	Flag       string  `json:"flag"`
+}
+
+type LabTrendResponseDTO struct {
+	Test   LabTestResponseDTO `json:"test"`
+	From   string             `json:"from"`
+	To     string             `json:"to"`
+	Points []LabTrendPointDTO `json:"points"`
+}
+
+// DialysisAdequacyPointDTO adalah URR & Kt/V satu tanggal sampel ureum pra/pasca HD.
+// Kt/V nil jika data sesi HD (durasi, berat sesudah HD) pada tanggal itu belum lengkap.
+type DialysisAdequacyPointDTO struct {
+	SampleDate   string   `json:"sample_date"`
+	UreaPre      float64  `json:"urea_pre"`
+	UreaPost     float64  `json:"urea_post"`
+	URRPercent   float64  `json:"urr_percent"`
+	KtV          *float64 `json:"kt_v"`
+	MonitoringID *uint    `json:"monitoring_id"`
+	BelowTarget  bool     `json:"below_target"`
+}
+
+type DialysisAdequacyResponseDTO struct {
+	From             string                     `json:"from"`
+	To               string                     `json:"to"`
+	URRTargetPercent float64                    `json:"urr_target_percent"`
+	KtVTarget        float64                    `json:"kt_v_target"`
+	Points           []DialysisAdequacyPointDTO `json:"points"`
+}
@@ -1,13 +1,77 @@
 package dto
 
 // CreateQuizDTO adalah DTO untuk membuat kuis baru.
+// Type "url" (default) memerlukan Url; type "native" memerlukan minimal satu soal.
 type CreateQuizDTO struct {
-	Name string `json:"name" binding:"required"`
-	Url  string `json:"url" binding:"required,url"`
+	Name         string            `json:"name" binding:"required"`
+	Type         string            `json:"type" binding:"omitempty,oneof=url native"`
+	Url          string            `json:"url" binding:"omitempty,url"`
+	Description  string            `json:"description"`
+	PassingScore *int              `json:"passing_score" binding:"omitempty,min=0,max=100"`
+	Questions    []QuizQuestionDTO `json:"questions" binding:"omitempty,dive"`
 }
 
-// UpdateQuizDTO adalah DTO untuk memperbarui kuis.
+// UpdateQuizDTO adalah DTO untuk memperbarui kuis. Soal kuis native diganti seluruhnya.
 type UpdateQuizDTO struct {
-	Name string `json:"name" binding:"required"`
-	Url  string `json:"url" binding:"required,url"`
-}
\ No newline at end of file
+	Name         string            `json:"name" binding:"required"`
+	Type         string            `json:"type" binding:"omitempty,oneof=url native"`
+	Url          string            `json:"url" binding:"omitempty,url"`
+	Description  string            `json:"description"`
+	PassingScore *int              `json:"passing_score" binding:"omitempty,min=0,max=100"`
+	Questions    []QuizQuestionDTO `json:"questions" binding:"omitempty,dive"`
+}
+
+// QuizQuestionDTO adalah satu soal. Untuk true_false isi Answer, untuk pilihan ganda isi Options.
+type QuizQuestionDTO struct {
+	Type        string          `json:"type" binding:"required,oneof=single_choice multiple_choice true_false"`
+	Text        string          `json:"text" binding:"required"`
+	Explanation string          `json:"explanation"`
+	Answer      *bool           `json:"answer"`
+	Options     []QuizOptionDTO `json:"options" binding:"omitempty,dive"`
+}
+
+type QuizOptionDTO struct {
+	Text      string `json:"text" binding:"required,max=500"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+// SubmitQuizAttemptDTO berisi jawaban pasien; soal yang tidak dijawab dihitung salah.
+type SubmitQuizAttemptDTO struct {
+	Answers []QuizAnswerDTO `json:"answers" binding:"required,dive"`
+}
+
+type QuizAnswerDTO struct {
+	QuestionID uint   `json:"question_id" binding:"required"`
+	OptionIDs  []uint `json:"option_ids"`
+}
+
+type QuizAttemptResponseDTO struct {
+	ID         uint                           `json:"id"`
+	QuizID     uint                           `json:"quiz_id"`
+	QuizName   string                         `json:"quiz_name,omitempty"`
+	Score      int                            `json:"score"`
+	MaxScore   int                            `json:"max_score"`
+	Percentage float64                        `json:"percentage"`
+	Passed     bool                           `json:"passed"`
+	CreatedAt  string                         `json:"created_at"`
+	Answers    []QuizAttemptAnswerResponseDTO `json:"answers,omitempty"`
+}
+
+// QuizAttemptAnswerResponseDTO menampilkan koreksi per soal setelah jawaban dikirim.
+type QuizAttemptAnswerResponseDTO struct {
+	QuestionID        uint   `json:"question_id"`
+	SelectedOptionIDs []uint `json:"selected_option_ids"`
+	CorrectOptionIDs  []uint `json:"correct_option_ids"`
+	IsCorrect         bool   `json:"is_correct"`
+	Explanation       string `json:"explanation,omitempty"`
+}
+
+// QuizAttemptHistoryDTO adalah riwayat pengerjaan beserta nilai terbaik per kuis.
+type QuizAttemptHistoryDTO struct {
+	QuizID         uint                     `json:"quiz_id"`
+	QuizName       string                   `json:"quiz_name"`
+	AttemptCount   int                      `json:"attempt_count"`
+	BestPercentage float64                  `json:"best_percentage"`
+	Passed         bool                     `json:"passed"`
+	Attempts       []QuizAttemptResponseDTO `json:"attempts"`
+}
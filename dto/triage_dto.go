@@ -0,0 +1,64 @@
+package dto
+
+type SymptomDTO struct {
+	Name      string   `json:"name" binding:"required,max=100"`
+	Aliases   []string `json:"aliases" binding:"omitempty,max=10,dive,required,max=45,excludesall=0x2C"` // Muat di kolom aliases (500)
+	Severity  string   `json:"severity" binding:"required,oneof=mild moderate severe"`
+	IsRedFlag bool     `json:"is_red_flag"`
+	SortOrder int      `json:"sort_order"`
+	IsActive  *bool    `json:"is_active"`
+}
+
+// CreateSymptomDTO menambahkan kode unik gejala; kode tidak bisa diubah setelah dibuat.
+type CreateSymptomDTO struct {
+	Code string `json:"code" binding:"required,max=50,lowercase"`
+	SymptomDTO
+}
+
+type SymptomResponseDTO struct {
+	ID        uint     `json:"id"`
+	Code      string   `json:"code"`
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases"`
+	Severity  string   `json:"severity"`
+	IsRedFlag bool     `json:"is_red_flag"`
+	SortOrder int      `json:"sort_order"`
+	IsActive  bool     `json:"is_active"`
+}
+
+// TriageRuleDTO dipakai untuk membuat & mengubah aturan triase. Minimal satu kondisi harus diisi.
+type TriageRuleDTO struct {
+	Name           string   `json:"name" binding:"required,max=100"`
+	Priority       int      `json:"priority"`
+	TriageLevel    string   `json:"triage_level" binding:"required,oneof=self_care routine urgent emergency"`
+	Message        string   `json:"message" binding:"required"`
+	RequireRedFlag bool     `json:"require_red_flag"`
+	MinSeverity    string   `json:"min_severity" binding:"omitempty,oneof=mild moderate severe"`
+	SymptomCodes   []string `json:"symptom_codes"`
+	MinCodeMatches int      `json:"min_code_matches" binding:"min=0"`
+	MinCount       int      `json:"min_count" binding:"min=0"`
+	IsActive       *bool    `json:"is_active"`
+}
+
+type TriageRuleResponseDTO struct {
+	ID             uint     `json:"id"`
+	Name           string   `json:"name"`
+	Priority       int      `json:"priority"`
+	TriageLevel    string   `json:"triage_level"`
+	Message        string   `json:"message"`
+	RequireRedFlag bool     `json:"require_red_flag"`
+	MinSeverity    string   `json:"min_severity,omitempty"`
+	SymptomCodes   []string `json:"symptom_codes"`
+	MinCodeMatches int      `json:"min_code_matches"`
+	MinCount       int      `json:"min_count"`
+	IsActive       bool     `json:"is_active"`
+}
+
+// TriageResultDTO adalah hasil evaluasi triase atas daftar keluhan.
+type TriageResultDTO struct {
+	TriageLevel  string               `json:"triage_level"`
+	Message      string               `json:"message"`
+	MatchedRule  *string              `json:"matched_rule"`
+	Symptoms     []SymptomResponseDTO `json:"symptoms"`
+	Unrecognized []string             `json:"unrecognized"`
+}
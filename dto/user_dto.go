@@ -8,6 +8,7 @@ type UserResponseDTO struct {
 	Role  string `json:"role"`
 	PhoneNumber string `json:"phone_number"`
 	ProfilePicture string `json:"profile_picture,omitempty"`
+	ProfilePictureVariants map[string]string `json:"profile_picture_variants,omitempty"` // 128, 512, 128_webp
 }
 
 type UpdateProfileDTO struct {
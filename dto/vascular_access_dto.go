@@ -0,0 +1,44 @@
+package dto
+
+type VascularAccessDTO struct {
+	AccessType string `json:"access_type" binding:"required,oneof=AVF AVG CVC"`
+	Site       string `json:"site" binding:"required,max=100"`
+	CreatedOn  string `json:"created_on" binding:"required,datetime=2006-01-02"` // Tanggal akses dibuat/dipasang
+	IsActive   *bool  `json:"is_active"`
+	Notes      string `json:"notes"`
+}
+
+type VascularAccessResponseDTO struct {
+	ID         uint   `json:"id"`
+	UserID     uint   `json:"user_id"`
+	AccessType string `json:"access_type"`
+	Site       string `json:"site"`
+	CreatedOn  string `json:"created_on"`
+	IsActive   bool   `json:"is_active"`
+	Notes      string `json:"notes,omitempty"`
+}
+
+// AccessSelfCheckDTO adalah pemeriksaan mandiri harian. thrill_present wajib untuk AVF/AVG.
+type AccessSelfCheckDTO struct {
+	ThrillPresent *bool  `json:"thrill_present"`
+	Swelling      bool   `json:"swelling"`
+	Redness       bool   `json:"redness"`
+	Pain          bool   `json:"pain"`
+	Notes         string `json:"notes"`
+	CheckDate     string `json:"check_date" binding:"omitempty,datetime=2006-01-02"` // Opsional, default hari ini (waktu lokal)
+}
+
+type AccessSelfCheckResponseDTO struct {
+	ID               uint     `json:"id"`
+	VascularAccessID uint     `json:"vascular_access_id"`
+	CheckDate        string   `json:"check_date"`
+	ThrillPresent    *bool    `json:"thrill_present"`
+	Swelling         bool     `json:"swelling"`
+	Redness          bool     `json:"redness"`
+	Pain             bool     `json:"pain"`
+	Notes            string   `json:"notes,omitempty"`
+	Problems         []string `json:"problems"`
+	Escalated        bool     `json:"escalated"`
+	ComplaintID      *uint    `json:"complaint_id"`
+	Advice           string   `json:"advice"`
+}
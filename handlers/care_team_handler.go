@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CareTeamHandler mengelola pendamping dan klinisi yang terhubung dengan pasien.
+type CareTeamHandler struct {
+	service services.CareTeamService
+}
+
+func NewCareTeamHandler(service services.CareTeamService) *CareTeamHandler {
+	return &CareTeamHandler{service: service}
+}
+
+func toCareTeamMemberResponse(member models.CareTeamMember) dto.CareTeamMemberResponseDTO {
+	status := "accepted"
+	if member.AcceptedAt == nil {
+		status = "pending"
+	}
+	return dto.CareTeamMemberResponseDTO{
+		ID:          member.ID,
+		PatientID:   member.PatientID,
+		PatientName: member.Patient.Name,
+		MemberID:    member.MemberID,
+		MemberName:  member.Member.Name,
+		MemberEmail: member.Member.Email,
+		Relation:    member.Relation,
+		Status:      status,
+	}
+}
+
+func respondCareTeamError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrPatientNotFound), errors.Is(err, services.ErrCareTeamMemberNotFound), errors.Is(err, services.ErrCareTeamInviteNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrPatientAccessDenied):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrCareTeamMemberExists):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(err.Error(), nil))
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(message, err.Error()))
+	}
+}
+
+func (h *CareTeamHandler) respondTeam(c *gin.Context, patientID uint) {
+	members, err := h.service.GetTeam(patientID)
+	if err != nil {
+		respondCareTeamError(c, "Failed to fetch care team", err)
+		return
+	}
+
+	responseDTOs := []dto.CareTeamMemberResponseDTO{}
+	for _, member := range members {
+		responseDTOs = append(responseDTOs, toCareTeamMemberResponse(member))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Care team fetched successfully", responseDTOs))
+}
+
+func (h *CareTeamHandler) removeMember(c *gin.Context, patientID uint) {
+	linkID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+	if err := h.service.RemoveMember(patientID, uint(linkID)); err != nil {
+		respondCareTeamError(c, "Failed to remove care team member", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Care team member removed successfully", nil))
+}
+
+// GetMyTeam menangani GET /api/v1/care-team
+func (h *CareTeamHandler) GetMyTeam(c *gin.Context) {
+	userID := c.MustGet("userID").(float64)
+	h.respondTeam(c, uint(userID))
+}
+
+// AddCaregiver menangani POST /api/v1/care-team/caregivers
+func (h *CareTeamHandler) AddCaregiver(c *gin.Context) {
+	var input dto.AddCaregiverDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID := c.MustGet("userID").(float64)
+	member, err := h.service.AddCaregiver(uint(userID), input.Email)
+	if err != nil {
+		respondCareTeamError(c, "Failed to add caregiver", err)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Caregiver invited successfully, waiting for acceptance", toCareTeamMemberResponse(member)))
+}
+
+// RemoveMyMember menangani DELETE /api/v1/care-team/members/:id
+func (h *CareTeamHandler) RemoveMyMember(c *gin.Context) {
+	userID := c.MustGet("userID").(float64)
+	h.removeMember(c, uint(userID))
+}
+
+// GetInvitations menangani GET /api/v1/care-team/invitations (undangan untuk user yang login)
+func (h *CareTeamHandler) GetInvitations(c *gin.Context) {
+	userID := c.MustGet("userID").(float64)
+	invitations, err := h.service.GetInvitations(uint(userID))
+	if err != nil {
+		respondCareTeamError(c, "Failed to fetch care team invitations", err)
+		return
+	}
+
+	responseDTOs := []dto.CareTeamMemberResponseDTO{}
+	for _, invitation := range invitations {
+		responseDTOs = append(responseDTOs, toCareTeamMemberResponse(invitation))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Care team invitations fetched successfully", responseDTOs))
+}
+
+// AcceptInvitation menangani POST /api/v1/care-team/invitations/:id/accept
+func (h *CareTeamHandler) AcceptInvitation(c *gin.Context) {
+	linkID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+	userID := c.MustGet("userID").(float64)
+	member, err := h.service.AcceptInvitation(uint(userID), uint(linkID))
+	if err != nil {
+		respondCareTeamError(c, "Failed to accept care team invitation", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Care team invitation accepted successfully", toCareTeamMemberResponse(member)))
+}
+
+// DeclineInvitation menangani DELETE /api/v1/care-team/invitations/:id
+func (h *CareTeamHandler) DeclineInvitation(c *gin.Context) {
+	linkID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+	userID := c.MustGet("userID").(float64)
+	if err := h.service.DeclineInvitation(uint(userID), uint(linkID)); err != nil {
+		respondCareTeamError(c, "Failed to decline care team invitation", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Care team invitation declined successfully", nil))
+}
+
+// managedPatientID membaca :user_id dan memastikan requester (admin atau klinisi di tim pasien)
+// boleh mengelola tim perawatannya. Respons error sudah dikirim jika false.
+func (h *CareTeamHandler) managedPatientID(c *gin.Context) (uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid user ID format", err.Error()))
+		return 0, false
+	}
+	requesterID, role := requester(c)
+	if err := h.service.AuthorizeManager(requesterID, role, uint(patientID)); err != nil {
+		respondCareTeamError(c, "Failed to authorize care team access", err)
+		return 0, false
+	}
+	return uint(patientID), true
+}
+
+// GetPatientTeam menangani GET /api/v1/care-team/patients/:user_id (admin, atau klinisi di tim pasien)
+func (h *CareTeamHandler) GetPatientTeam(c *gin.Context) {
+	patientID, ok := h.managedPatientID(c)
+	if !ok {
+		return
+	}
+	h.respondTeam(c, patientID)
+}
+
+// AssignClinician menangani POST /api/v1/care-team/patients/:user_id/clinicians (admin, atau klinisi di tim pasien)
+func (h *CareTeamHandler) AssignClinician(c *gin.Context) {
+	patientID, ok := h.managedPatientID(c)
+	if !ok {
+		return
+	}
+
+	var input dto.AssignClinicianDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	member, err := h.service.AssignClinician(patientID, input.ClinicianID)
+	if err != nil {
+		respondCareTeamError(c, "Failed to assign clinician", err)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Clinician assigned successfully", toCareTeamMemberResponse(member)))
+}
+
+// RemovePatientMember menangani DELETE /api/v1/care-team/patients/:user_id/members/:id (admin, atau klinisi di tim pasien)
+func (h *CareTeamHandler) RemovePatientMember(c *gin.Context) {
+	patientID, ok := h.managedPatientID(c)
+	if !ok {
+		return
+	}
+	h.removeMember(c, patientID)
+}
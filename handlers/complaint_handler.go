@@ -1,32 +1,77 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/storage"
 	"github.com/darmawguna/tirtaapp.git/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 )
 
+const (
+	maxComplaintAttachments    = 3
+	maxComplaintImageSizeBytes = 5 * 1024 * 1024
+	maxComplaintAudioSizeBytes = 10 * 1024 * 1024 // Cukup untuk rekaman suara singkat (~1-2 menit)
+)
+
+// complaintAttachmentTypes memetakan MIME type hasil sniffing yang diizinkan ke jenis & ekstensi file.
+var complaintAttachmentTypes = map[string]struct{ kind, ext string }{
+	"image/jpeg": {models.AttachmentKindImage, ".jpg"},
+	"image/png":  {models.AttachmentKindImage, ".png"},
+	"image/webp": {models.AttachmentKindImage, ".webp"},
+	"audio/mpeg": {models.AttachmentKindAudio, ".mp3"},
+	"audio/mp4":  {models.AttachmentKindAudio, ".m4a"},
+	"audio/aac":  {models.AttachmentKindAudio, ".aac"},
+	"audio/3gpp": {models.AttachmentKindAudio, ".3gp"},
+	"audio/ogg":  {models.AttachmentKindAudio, ".ogg"},
+	"audio/wav":  {models.AttachmentKindAudio, ".wav"},
+}
+
 // **ComplaintHandler** adalah struct yang menampung service untuk keluhan.
 type ComplaintHandler struct {
 	complaintService services.ComplaintService
+	recommendations  services.EducationRecommendationService
+	store            storage.BlobStore
 }
 
 // **NewComplaintHandler** adalah constructor untuk ComplaintHandler.
-func NewComplaintHandler(complaintService services.ComplaintService) *ComplaintHandler {
-	return &ComplaintHandler{complaintService: complaintService}
+func NewComplaintHandler(complaintService services.ComplaintService, recommendations services.EducationRecommendationService, store storage.BlobStore) *ComplaintHandler {
+	return &ComplaintHandler{complaintService: complaintService, recommendations: recommendations, store: store}
 }
 
 // **Create** menangani pembuatan log keluhan baru.
-// Endpoint: POST /api/v1/complaints
+// Endpoint: POST /api/v1/complaints (JSON, atau multipart/form-data dengan file 'attachments')
 func (h *ComplaintHandler) Create(c *gin.Context) {
 	var input dto.CreateComplaintDTO
+	var files []*multipart.FileHeader
 
 	// Binding dan validasi request body.
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		if err := c.ShouldBind(&input); err != nil {
+			response := utils.ErrorResponse("Validation failed. Make sure 'complaints' is filled at least once.", err.Error())
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+		form, err := c.MultipartForm()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid multipart form", err.Error()))
+			return
+		}
+		files = form.File["attachments"]
+	} else if err := c.ShouldBindJSON(&input); err != nil {
 		response := utils.ErrorResponse("Validation failed. Make sure 'complaints' is a non-empty array.", err.Error())
 		c.JSON(http.StatusBadRequest, response)
 		return
@@ -35,17 +80,43 @@ func (h *ComplaintHandler) Create(c *gin.Context) {
 	// Ambil ID user dari context yang di-set oleh middleware.
 	userID := c.MustGet("userID").(float64)
 
-	// Panggil service untuk memproses keluhan dan mendapatkan pesan balasan.
-	generatedMessage, err := h.complaintService.ProcessComplaint(uint(userID), input)
+	// Validasi semua lampiran dulu sebelum ada file yang disimpan.
+	attachments, err := validateComplaintAttachments(files)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error(), nil))
+		return
+	}
+	for i := range attachments {
+		savedKey, saveErr := saveComplaintAttachmentFile(c, h.store, files[i], uint(userID), attachments[i].MimeType)
+		if saveErr != nil {
+			h.removeAttachmentFiles(attachments[:i])
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save attachment", saveErr.Error()))
+			return
+		}
+		attachments[i].FilePath = savedKey
+	}
+
+	// Panggil service untuk triase keluhan dan mendapatkan pesan balasan.
+	complaint, outcome, err := h.complaintService.ProcessComplaint(uint(userID), input, attachments)
 	if err != nil {
+		h.removeAttachmentFiles(attachments)
 		response := utils.ErrorResponse("Failed to process complaint", err.Error())
 		c.JSON(http.StatusInternalServerError, response)
 		return
 	}
 
-	// Kirim response sukses yang berisi pesan yang dihasilkan.
+	attachmentDTOs := []dto.ComplaintAttachmentResponseDTO{}
+	for _, attachment := range complaint.Attachments {
+		attachmentDTOs = append(attachmentDTOs, toComplaintAttachmentResponse(attachment))
+	}
+
+	// Kirim response sukses yang berisi pesan yang dihasilkan beserta hasil triase.
 	response := utils.SuccessResponse("Complaint processed successfully", gin.H{
-		"generated_message": generatedMessage,
+		"complaint_id":           complaint.ID,
+		"generated_message":      outcome.Message,
+		"triage":                 toTriageResultResponse(outcome),
+		"attachments":            attachmentDTOs,
+		"recommended_educations": complaintRecommendations(h.recommendations, uint(userID), outcome),
 	})
 	c.JSON(http.StatusCreated, response)
 }
@@ -78,15 +149,255 @@ func (h *ComplaintHandler) GetDetailComplaint(c *gin.Context) {
 		return
 	}
 
-	complaint, err := h.complaintService.GetComplainById(uint(complaint_id))
+	userID, userRole := requester(c)
+	complaint, err := h.complaintService.GetComplainById(userID, userRole, uint(complaint_id))
 	if err != nil {
-		// Jika record tidak ditemukan, GORM akan memberikan error.
-		response := utils.ErrorResponse("Fetching Quiz failed", err.Error())
-		c.JSON(http.StatusBadRequest, response)
+		respondComplaintError(c, "Fetching complaint failed", err)
 		return
 	}
 
 	// Kirim response sukses dengan data riwayat.
 	response := utils.SuccessResponse("Complaint  fetched successfully", complaint)
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+func toComplaintReplyResponse(reply models.ComplaintReply) dto.ComplaintReplyResponseDTO {
+	return dto.ComplaintReplyResponseDTO{
+		ID:             reply.ID,
+		ComplaintLogID: reply.ComplaintLogID,
+		AuthorID:       reply.AuthorID,
+		AuthorRole:     reply.AuthorRole,
+		Message:        reply.Message,
+		CreatedAt:      reply.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toComplaintQueueItemResponse(complaint models.ComplaintLog) dto.ComplaintQueueItemDTO {
+	item := dto.ComplaintQueueItemDTO{
+		ID:             complaint.ID,
+		UserID:         complaint.UserID,
+		PatientName:    complaint.User.Name,
+		Complaints:     []string{},
+		TriageLevel:    complaint.TriageLevel,
+		TriageRuleName: complaint.TriageRuleName,
+		Status:         complaint.Status,
+		CreatedAt:      complaint.CreatedAt.Format(time.RFC3339),
+		Escalated:      complaint.EscalatedAt != nil,
+	}
+	_ = json.Unmarshal(complaint.Complaints, &item.Complaints)
+	if complaint.AcknowledgedAt != nil {
+		acknowledgedAt := complaint.AcknowledgedAt.Format(time.RFC3339)
+		item.AcknowledgedAt = &acknowledgedAt
+	} else if sla := services.ComplaintSLA(complaint.TriageLevel); sla > 0 {
+		dueAt := complaint.CreatedAt.Add(sla).Format(time.RFC3339)
+		item.SLADueAt = &dueAt
+	}
+	return item
+}
+
+func respondComplaintError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrComplaintNotFound), errors.Is(err, services.ErrAttachmentNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrComplaintClosed):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(err.Error(), nil))
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(message, err.Error()))
+	}
+}
+
+func parseComplaintID(c *gin.Context) (uint, bool) {
+	complaintID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return 0, false
+	}
+	return uint(complaintID), true
+}
+
+// GetQueue menampilkan antrean keluhan untuk petugas, urut tingkat triase lalu waktu masuk.
+// Endpoint: GET /api/v1/complaints/queue?status=open,acknowledged
+func (h *ComplaintHandler) GetQueue(c *gin.Context) {
+	var statuses []string
+	if raw := c.Query("status"); raw != "" {
+		for _, status := range strings.Split(raw, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				statuses = append(statuses, status)
+			}
+		}
+	}
+
+	userID, userRole := requester(c)
+	complaints, err := h.complaintService.GetQueue(userID, userRole, statuses)
+	if err != nil {
+		respondComplaintError(c, "Failed to fetch complaint queue", err)
+		return
+	}
+
+	responseDTOs := []dto.ComplaintQueueItemDTO{}
+	for _, complaint := range complaints {
+		responseDTOs = append(responseDTOs, toComplaintQueueItemResponse(complaint))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Complaint queue fetched successfully", responseDTOs))
+}
+
+// Acknowledge menandai keluhan sudah diterima petugas.
+// Endpoint: POST /api/v1/complaints/:id/acknowledge
+func (h *ComplaintHandler) Acknowledge(c *gin.Context) {
+	complaintID, ok := parseComplaintID(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole := requester(c)
+	complaint, err := h.complaintService.Acknowledge(userID, userRole, complaintID)
+	if err != nil {
+		respondComplaintError(c, "Failed to acknowledge complaint", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Complaint acknowledged successfully", complaint))
+}
+
+// AddReply menambahkan pesan ke percakapan keluhan (pasien maupun petugas).
+// Endpoint: POST /api/v1/complaints/:id/replies
+func (h *ComplaintHandler) AddReply(c *gin.Context) {
+	complaintID, ok := parseComplaintID(c)
+	if !ok {
+		return
+	}
+
+	var input dto.ComplaintReplyDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	reply, err := h.complaintService.AddReply(userID, userRole, complaintID, input.Message)
+	if err != nil {
+		respondComplaintError(c, "Failed to send reply", err)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Reply sent successfully", toComplaintReplyResponse(reply)))
+}
+
+// Close menutup keluhan.
+// Endpoint: POST /api/v1/complaints/:id/close
+func (h *ComplaintHandler) Close(c *gin.Context) {
+	complaintID, ok := parseComplaintID(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole := requester(c)
+	complaint, err := h.complaintService.Close(userID, userRole, complaintID)
+	if err != nil {
+		respondComplaintError(c, "Failed to close complaint", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Complaint closed successfully", complaint))
+}
+
+func toComplaintAttachmentResponse(attachment models.ComplaintAttachment) dto.ComplaintAttachmentResponseDTO {
+	baseUrl := viper.GetString("BASE_URL")
+	if baseUrl == "" {
+		baseUrl = "http://localhost:8080"
+	}
+	return dto.ComplaintAttachmentResponseDTO{
+		ID:        attachment.ID,
+		Kind:      attachment.Kind,
+		MimeType:  attachment.MimeType,
+		SizeBytes: attachment.SizeBytes,
+		URL:       fmt.Sprintf("%s/api/v1/complaints/%d/attachments/%d", baseUrl, attachment.ComplaintLogID, attachment.ID),
+	}
+}
+
+// validateComplaintAttachments memeriksa jumlah, jenis (dari isi file) dan ukuran setiap lampiran.
+func validateComplaintAttachments(files []*multipart.FileHeader) ([]models.ComplaintAttachment, error) {
+	if len(files) > maxComplaintAttachments {
+		return nil, fmt.Errorf("Maximum %d attachments per complaint", maxComplaintAttachments)
+	}
+	attachments := make([]models.ComplaintAttachment, 0, len(files))
+	for _, file := range files {
+		mimeType, err := utils.SniffContentType(file)
+		if err != nil {
+			return nil, err
+		}
+		fileType, ok := complaintAttachmentTypes[mimeType]
+		if !ok {
+			return nil, fmt.Errorf("Attachment '%s' has unsupported type %s (allowed: JPEG/PNG/WebP images, MP3/M4A/AAC/3GP/OGG/WAV audio)", file.Filename, mimeType)
+		}
+		maxSize := int64(maxComplaintImageSizeBytes)
+		if fileType.kind == models.AttachmentKindAudio {
+			maxSize = maxComplaintAudioSizeBytes
+		}
+		if file.Size > maxSize {
+			return nil, fmt.Errorf("Attachment '%s' exceeds %dMB limit", file.Filename, maxSize/(1024*1024))
+		}
+		attachments = append(attachments, models.ComplaintAttachment{
+			Kind:      fileType.kind,
+			MimeType:  mimeType,
+			SizeBytes: file.Size,
+		})
+	}
+	return attachments, nil
+}
+
+// saveComplaintAttachmentFile menyimpan lampiran dengan nama acak berbasis waktu; nama asli dari
+// klien tidak dipakai karena bisa memuat data pribadi. Prefix complaints tidak dilayani sebagai static.
+func saveComplaintAttachmentFile(c *gin.Context, store storage.BlobStore, file *multipart.FileHeader, userID uint, mimeType string) (string, error) {
+	filename := fmt.Sprintf("%d_%d%s", time.Now().UnixNano(), userID, complaintAttachmentTypes[mimeType].ext)
+	key := storage.Key(storage.PrefixComplaints, filename)
+
+	if err := putUploadedFile(c, store, file, key, mimeType); err != nil {
+		return "", err
+	}
+	log.Printf("Lampiran keluhan berhasil disimpan sebagai: %s", key)
+	return key, nil
+}
+
+func (h *ComplaintHandler) removeAttachmentFiles(attachments []models.ComplaintAttachment) {
+	for _, attachment := range attachments {
+		if attachment.FilePath != "" {
+			go h.store.Delete(context.Background(), attachment.FilePath)
+		}
+	}
+}
+
+// GetAttachment mengirim file lampiran ke pemilik keluhan, klinisinya, atau admin.
+// Endpoint: GET /api/v1/complaints/:id/attachments/:attachment_id
+func (h *ComplaintHandler) GetAttachment(c *gin.Context) {
+	complaintID, ok := parseComplaintID(c)
+	if !ok {
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid attachment ID format", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	attachment, err := h.complaintService.GetAttachment(userID, userRole, complaintID, uint(attachmentID))
+	if err != nil {
+		respondComplaintError(c, "Failed to fetch attachment", err)
+		return
+	}
+
+	serveBlob(c, h.store, storage.NormalizeKey(attachment.FilePath), attachment.MimeType, "private, no-store")
+}
+
+// complaintRecommendations menyarankan edukasi yang pemicunya cocok dengan gejala yang dikenali.
+func complaintRecommendations(service services.EducationRecommendationService, userID uint, outcome services.TriageOutcome) []dto.EducationRecommendationDTO {
+	codes := make([]string, 0, len(outcome.Symptoms))
+	for _, symptom := range outcome.Symptoms {
+		codes = append(codes, symptom.Code)
+	}
+	recommendations := []dto.EducationRecommendationDTO{}
+	if len(codes) > 0 {
+		if found := recommendEducations(service, userID, services.EducationContext{TriggerType: models.EducationTriggerSymptom, SymptomCodes: codes}); found != nil {
+			recommendations = found
+		}
+	}
+	return recommendations
+}
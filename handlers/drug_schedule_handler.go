@@ -32,6 +32,9 @@ func toDrugScheduleResponse(schedule models.DrugSchedule) dto.DrugScheduleRespon
 		At12:         schedule.At12,
 		At18:         schedule.At18,
 		IsActive:     schedule.IsActive,
+		At06Missed:   schedule.At06Missed,
+		At12Missed:   schedule.At12Missed,
+		At18Missed:   schedule.At18Missed,
 	}
 }
 
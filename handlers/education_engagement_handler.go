@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// EducationEngagementHandler mencatat tayangan & penyelesaian edukasi dan menampilkan progres serta statistiknya.
+type EducationEngagementHandler struct {
+	service services.EducationEngagementService
+}
+
+func NewEducationEngagementHandler(service services.EducationEngagementService) *EducationEngagementHandler {
+	return &EducationEngagementHandler{service: service}
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
+
+func toEducationProgressResponse(progress models.EducationProgress) dto.EducationProgressItemDTO {
+	return dto.EducationProgressItemDTO{
+		EducationID:       progress.EducationID,
+		ViewCount:         progress.ViewCount,
+		MaxPercentWatched: progress.MaxPercentWatched,
+		LastViewedAt:      formatOptionalTime(progress.LastViewedAt),
+		Completed:         progress.CompletedAt != nil,
+		CompletedAt:       formatOptionalTime(progress.CompletedAt),
+	}
+}
+
+func respondEducationEngagementError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrEducationNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrEducationNotViewed):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrPatientAccessDenied):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(message, err.Error()))
+	}
+}
+
+// RecordView menangani POST /api/v1/educations/:id/views
+func (h *EducationEngagementHandler) RecordView(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+	var input dto.RecordEducationViewDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID, _ := requester(c)
+	progress, err := h.service.RecordView(userID, uint(id), input)
+	if err != nil {
+		respondEducationEngagementError(c, "Failed to record education view", err)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Education view recorded successfully", toEducationProgressResponse(progress)))
+}
+
+// MarkCompleted menangani POST /api/v1/educations/:id/complete
+func (h *EducationEngagementHandler) MarkCompleted(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	userID, _ := requester(c)
+	progress, err := h.service.MarkCompleted(userID, uint(id))
+	if err != nil {
+		respondEducationEngagementError(c, "Failed to mark education as completed", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Education marked as completed", toEducationProgressResponse(progress)))
+}
+
+// GetProgress menangani GET /api/v1/educations/progress dan /api/v1/educations/patients/:user_id/progress
+func (h *EducationEngagementHandler) GetProgress(c *gin.Context) {
+	patientID, ok := patientParam(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole := requester(c)
+	summary, err := h.service.GetProgress(userID, userRole, patientID)
+	if err != nil {
+		respondEducationEngagementError(c, "Failed to fetch education progress", err)
+		return
+	}
+
+	response := dto.EducationProgressResponseDTO{
+		UserID:    summary.UserID,
+		Completed: summary.Completed,
+		Total:     summary.Total,
+		Summary:   fmt.Sprintf("%d dari %d modul selesai", summary.Completed, summary.Total),
+		Items:     []dto.EducationProgressItemDTO{},
+	}
+	if summary.Total > 0 {
+		response.PercentComplete = math.Round(float64(summary.Completed)/float64(summary.Total)*1000) / 10
+	}
+	for _, item := range summary.Items {
+		itemDTO := dto.EducationProgressItemDTO{EducationID: item.Education.ID}
+		if item.Progress != nil {
+			itemDTO = toEducationProgressResponse(*item.Progress)
+		}
+		itemDTO.Name = item.Education.Name
+		itemDTO.Category = item.Education.Category
+		response.Items = append(response.Items, itemDTO)
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Education progress fetched successfully", response))
+}
+
+// GetAnalytics menangani GET /api/v1/educations/analytics (admin)
+func (h *EducationEngagementHandler) GetAnalytics(c *gin.Context) {
+	analytics, err := h.service.GetAnalytics()
+	if err != nil {
+		respondEducationEngagementError(c, "Failed to fetch education analytics", err)
+		return
+	}
+
+	responseDTOs := []dto.EducationAnalyticsResponseDTO{}
+	for _, item := range analytics {
+		responseDTOs = append(responseDTOs, dto.EducationAnalyticsResponseDTO{
+			EducationID:       item.EducationID,
+			Name:              item.Name,
+			UniqueViewers:     item.UniqueViewers,
+			TotalViews:        item.TotalViews,
+			Completions:       item.Completions,
+			CompletionRate:    item.CompletionRate,
+			AvgPercentWatched: item.AvgPercentWatched,
+		})
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Education analytics fetched successfully", responseDTOs))
+}
@@ -4,10 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -15,63 +12,167 @@ import (
 	"github.com/darmawguna/tirtaapp.git/dto"          // Adjust path
 	models "github.com/darmawguna/tirtaapp.git/model" // Adjust path
 	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/storage"
 	"github.com/darmawguna/tirtaapp.git/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper" // Untuk base URL
 	"gorm.io/gorm"
 )
 
-const UPLOAD_PATH_EDUCATION = "./uploads/educations" // Definisikan path upload
-
 type EducationHandler struct {
 	educationService services.EducationService
+	mediaService     services.EducationMediaService
+	imageService     services.ImageService
+	store            storage.BlobStore
 }
 
-func NewEducationHandler(educationService services.EducationService) *EducationHandler {
-	// Pastikan direktori upload ada
-	if err := os.MkdirAll(UPLOAD_PATH_EDUCATION, os.ModePerm); err != nil {
-		log.Fatalf("FATAL: Tidak bisa membuat direktori upload edukasi: %v", err)
-	}
-	return &EducationHandler{educationService: educationService}
+func NewEducationHandler(educationService services.EducationService, mediaService services.EducationMediaService, imageService services.ImageService, store storage.BlobStore) *EducationHandler {
+	return &EducationHandler{educationService: educationService, mediaService: mediaService, imageService: imageService, store: store}
 }
 
 // Helper untuk konversi ke Response DTO
 func toEducationResponseDTO(edu models.Education) dto.EducationResponseDTO {
-	// Buat URL lengkap jika path relatif
+	// Buat URL lengkap jika thumbnail berupa key objek (bukan URL eksternal)
 	thumbnailUrl := edu.Thumbnail
+	var thumbnailVariants map[string]string
 	if !strings.HasPrefix(thumbnailUrl, "http") {
-		// Asumsi Anda akan setup static file serving di /static
-		// Ganti "http://localhost:8080" dengan base URL API Anda dari config
-		baseUrl := viper.GetString("BASE_URL") // Tambahkan APP_BASE_URL=http://localhost:8080 ke .env
+		thumbnailUrl = storage.PublicURL(thumbnailUrl)
+		thumbnailVariants = imageVariantURLs(edu.Thumbnail, storage.PublicURL)
+	}
+
+	// Konten yang di-host sendiri dibuka lewat endpoint media (mendukung range request)
+	url := edu.Url
+	var mediaMimeType *string
+	var mediaSize *int64
+	mediaType := edu.MediaType
+	if mediaType == "" {
+		mediaType = models.EducationMediaURL
+	}
+	if mediaType != models.EducationMediaURL {
+		baseUrl := viper.GetString("BASE_URL")
 		if baseUrl == "" {
 			baseUrl = "http://localhost:8080"
-		} // Fallback
-		thumbnailUrl = fmt.Sprintf("%s/static/educations/%s", baseUrl, filepath.Base(thumbnailUrl))
+		}
+		url = fmt.Sprintf("%s/api/v1/educations/%d/media", baseUrl, edu.ID)
+		mediaMimeType = &edu.MediaMimeType
+		mediaSize = &edu.MediaSize
+	}
+
+	tags := []string{}
+	for _, tag := range edu.Tags {
+		tags = append(tags, tag.Name)
 	}
 
 	return dto.EducationResponseDTO{
-		ID:        edu.ID,
-		Name:      edu.Name,
-		Url:       edu.Url,
-		Thumbnail: thumbnailUrl, // Kirim URL lengkap ke frontend
-		CreatedBy: edu.CreatedBy,
+		ID:                edu.ID,
+		Name:              edu.Name,
+		Description:       edu.Description,
+		Category:          edu.Category,
+		Tags:              tags,
+		FeaturedOrder:     edu.FeaturedOrder,
+		Url:               url,
+		MediaType:         mediaType,
+		MediaMimeType:     mediaMimeType,
+		MediaSize:         mediaSize,
+		Thumbnail:         thumbnailUrl, // Kirim URL lengkap ke frontend
+		ThumbnailVariants: thumbnailVariants,
+		CreatedBy:         edu.CreatedBy,
+		CreatedAt:         edu.CreatedAt.Format(time.RFC3339),
 	}
 }
 
+// GetAll menangani GET /api/v1/educations?q=&category=&tag=&sort=featured|newest|oldest|name|relevance
+// dan mengembalikan array seluruh edukasi yang cocok. Dengan paged=true (ditambah page= & page_size=)
+// respons berupa satu halaman beserta total.
 func (h *EducationHandler) GetAll(c *gin.Context) {
-	educations, err := h.educationService.FindAll()
+	paged, err := strconv.ParseBool(c.DefaultQuery("paged", "false"))
 	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid paged, must be true or false", nil))
+		return
+	}
+	params := services.EducationListParams{
+		Query:    c.Query("q"),
+		Category: c.Query("category"),
+		Tag:      c.Query("tag"),
+		Sort:     c.Query("sort"),
+		All:      !paged,
+	}
+	if paged {
+		params.Page, err = strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || params.Page <= 0 {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid page, must be a positive number", nil))
+			return
+		}
+		params.PageSize, err = strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(services.DefaultEducationPageSize)))
+		if err != nil || params.PageSize <= 0 || params.PageSize > services.MaxEducationPageSize {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid page_size, must be between 1 and 100", nil))
+			return
+		}
+	}
+
+	result, err := h.educationService.FindAll(params)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidEducationCategory) || errors.Is(err, services.ErrInvalidEducationSort) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch educations", err.Error()))
 		return
 	}
 
 	// [PEMBARUAN] Konversi list model ke list DTO response
-	var responseDTOs []dto.EducationResponseDTO
-	for _, edu := range educations {
+	responseDTOs := []dto.EducationResponseDTO{}
+	for _, edu := range result.Items {
 		responseDTOs = append(responseDTOs, toEducationResponseDTO(edu))
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Educations fetched successfully", responseDTOs)) // Kirim DTO list
+	if !paged {
+		c.JSON(http.StatusOK, utils.SuccessResponse("Educations fetched successfully", responseDTOs))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Educations fetched successfully", dto.EducationPageResponseDTO{
+		Page:     result.Page,
+		PageSize: result.PageSize,
+		Total:    result.Total,
+		Items:    responseDTOs,
+	}))
+}
+
+// GetCategories menangani GET /api/v1/educations/categories
+func (h *EducationHandler) GetCategories(c *gin.Context) {
+	responseDTOs := []dto.EducationCategoryResponseDTO{}
+	for _, category := range services.EducationCategories {
+		responseDTOs = append(responseDTOs, dto.EducationCategoryResponseDTO{Code: category.Code, Label: category.Label})
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Education categories fetched successfully", responseDTOs))
+}
+
+// GetTags menangani GET /api/v1/educations/tags
+func (h *EducationHandler) GetTags(c *gin.Context) {
+	tags, err := h.educationService.FindAllTags()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch education tags", err.Error()))
+		return
+	}
+	names := []string{}
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Education tags fetched successfully", names))
+}
+
+// SetFeatured menangani PUT /api/v1/educations/featured (admin)
+func (h *EducationHandler) SetFeatured(c *gin.Context) {
+	var input dto.SetFeaturedEducationDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+	if err := h.educationService.SetFeaturedOrder(input.EducationIDs); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to set featured educations", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Featured educations updated successfully", gin.H{"education_ids": input.EducationIDs}))
 }
 
 // GetByID (Diperbarui)
@@ -98,13 +199,16 @@ func (h *EducationHandler) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, utils.SuccessResponse("Education fetched successfully", responseDTO)) // Kirim DTO
 }
 
-// Create: Menangani upload file dan data form
+// Create: Menangani upload file dan data form.
+// Konten bisa berupa tautan (url), file PDF/video langsung (field "media"), atau hasil
+// upload bertahap (media_upload_id). Thumbnail PDF dibuat otomatis jika tidak dikirim.
 func (h *EducationHandler) Create(c *gin.Context) {
 
 	log.Println(">>> MASUK HANDLER: Create Education <<<")
 	// Bind data form (Name, Url) dari multipart/form-data
 	name := c.PostForm("name")
 	url := c.PostForm("url")
+	category := c.PostForm("category")
 
 	log.Printf(">>> Data Form: Name=%s, Url=%s", name, url)
 	// Validasi manual sederhana
@@ -112,43 +216,47 @@ func (h *EducationHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", "Name is required"))
 		return
 	}
-	if url == "" {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", "Url is required"))
-		return
-	}
 
 	input := dto.CreateEducationDTO{
-		Name: name,
-		Url:  url,
+		Name:          name,
+		Url:           url,
+		Description:   c.PostForm("description"),
+		Category:      category,
+		Tags:          c.PostFormArray("tags"),
+		MediaUploadID: c.PostForm("media_upload_id"),
 	}
-	// Ambil file thumbnail dari form-data
-	file, err := c.FormFile("thumbnail") // Nama field di form-data
-	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Thumbnail file is required", err.Error()))
+	userID := c.MustGet("userID").(float64)
+
+	media, ok := h.resolveMedia(c, uint(userID), input.MediaUploadID)
+	if !ok {
 		return
 	}
-
-	// Validasi sederhana (contoh: ukuran < 5MB)
-	if file.Size > 5*1024*1024 { // 5 MB
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("File size exceeds 5MB limit", nil))
+	if url == "" && media == nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", "Url or media is required"))
 		return
 	}
-	// Anda bisa menambahkan validasi tipe file (misal: hanya gambar)
-
-	// Simpan file dan dapatkan path relatifnya
-	thumbnailPath, err := saveUploadedFile(c, file, UPLOAD_PATH_EDUCATION)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save thumbnail", err.Error()))
+	if url != "" && media != nil {
+		discardMedia(h.mediaService, media)
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", "Provide either url or media, not both"))
 		return
 	}
 
-	userID := c.MustGet("userID").(float64)
+	thumbnailPath, ok := h.resolveThumbnail(c, media)
+	if !ok {
+		discardMedia(h.mediaService, media)
+		return
+	}
 
 	// Panggil service dengan path thumbnail yang sudah disimpan
-	education, err := h.educationService.Create(input, uint(userID), thumbnailPath)
+	education, err := h.educationService.Create(input, uint(userID), thumbnailPath, media)
 	if err != nil {
 		// Jika gagal simpan DB, coba hapus file yang sudah terupload
-		go os.Remove(thumbnailPath)
+		go deleteImage(h.store, thumbnailPath)
+		discardMedia(h.mediaService, media)
+		if errors.Is(err, services.ErrInvalidEducationCategory) || errors.Is(err, services.ErrEducationTagTooLong) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create education", err.Error()))
 		return
 	}
@@ -171,32 +279,47 @@ func (h *EducationHandler) Update(c *gin.Context) {
 		return
 	}
 
+	userID := c.MustGet("userID").(float64)
+	media, ok := h.resolveMedia(c, uint(userID), input.MediaUploadID)
+	if !ok {
+		return
+	}
+
 	var newThumbnailPath *string         // Pointer untuk menandakan ada file baru atau tidak
 	file, err := c.FormFile("thumbnail") // Coba ambil file
 
 	// Jika ada file baru di request
 	if err == nil {
-		// Validasi file baru
-		if file.Size > 5*1024*1024 { /* ... handle error ... */
-		}
-
-		// Simpan file baru
-		savedPath, saveErr := saveUploadedFile(c, file, UPLOAD_PATH_EDUCATION)
-		if saveErr != nil {
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save new thumbnail", saveErr.Error()))
+		// Validasi, proses, dan simpan file baru
+		savedPath, ok := storeUploadedImage(c, h.imageService, storage.PrefixEducations, file, "thumbnail")
+		if !ok {
+			discardMedia(h.mediaService, media)
 			return
 		}
 		newThumbnailPath = &savedPath // Set pointer ke path baru
 	} else if err != http.ErrMissingFile {
 		// Error selain karena file tidak ada (misal: format request salah)
+		discardMedia(h.mediaService, media)
 		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Error processing thumbnail file", err.Error()))
 		return
+	} else if media != nil && media.Type == models.EducationMediaPDF {
+		// PDF baru tanpa thumbnail: coba buat dari halaman pertama, jika gagal thumbnail lama dipertahankan
+		if generated, genErr := h.mediaService.GeneratePDFThumbnail(media.Key); genErr == nil {
+			newThumbnailPath = &generated
+		} else {
+			log.Printf("WARNING: %v", genErr)
+		}
 	}
 	// Jika err == http.ErrMissingFile, berarti tidak ada thumbnail baru, newThumbnailPath tetap nil
 
 	// Panggil service update (service akan menangani penghapusan file lama jika perlu)
-	updatedEdu, err := h.educationService.Update(uint(id), input, newThumbnailPath)
+	updatedEdu, err := h.educationService.Update(uint(id), input, newThumbnailPath, media)
 	if err != nil {
+		discardMedia(h.mediaService, media)
+		if errors.Is(err, services.ErrInvalidEducationCategory) || errors.Is(err, services.ErrEducationTagTooLong) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update education", err.Error()))
 		return
 	}
@@ -224,25 +347,58 @@ func (h *EducationHandler) Delete(c *gin.Context) {
 
 // Implementasikan GetAll, GetByID dengan konversi ke DTO Response...
 
-// --- Helper Function untuk Menyimpan File ---
-// Mengembalikan path absolut file yang disimpan
-func saveUploadedFile(c *gin.Context, file *multipart.FileHeader, destDirectory string) (string, error) {
-	// Buat nama file unik (timestamp_namaasli.ext)
-	ext := filepath.Ext(file.Filename)
-	baseName := strings.TrimSuffix(file.Filename, ext)
-	filename := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), baseName, ext)
-
-	// Pastikan nama file aman (opsional, tapi bagus)
-	// filename = sanitize.BaseName(filename)
+// resolveMedia mengambil media dari field file "media" atau dari sesi upload bertahap.
+// Mengembalikan nil jika request tidak membawa media; ok=false berarti response error sudah dikirim.
+func (h *EducationHandler) resolveMedia(c *gin.Context, userID uint, uploadID string) (*services.EducationMedia, bool) {
+	file, err := c.FormFile("media")
+	if err != nil && err != http.ErrMissingFile {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Error processing media file", err.Error()))
+		return nil, false
+	}
+	if file != nil && uploadID != "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", "Provide either media or media_upload_id, not both"))
+		return nil, false
+	}
 
-	dst := filepath.Join(destDirectory, filename)
+	var media services.EducationMedia
+	switch {
+	case file != nil:
+		media, err = h.mediaService.SaveFile(file)
+	case uploadID != "":
+		media, err = h.mediaService.ClaimUpload(userID, uploadID)
+	default:
+		return nil, true
+	}
+	if err != nil {
+		respondEducationMediaError(c, "Failed to save education media", err)
+		return nil, false
+	}
+	return &media, true
+}
 
-	// Simpan file
-	if err := c.SaveUploadedFile(file, dst); err != nil {
-		return "", fmt.Errorf("gagal menyimpan file ke '%s': %w", dst, err)
+// resolveThumbnail menyimpan thumbnail yang diunggah, atau membuatnya dari halaman pertama PDF.
+func (h *EducationHandler) resolveThumbnail(c *gin.Context, media *services.EducationMedia) (string, bool) {
+	file, err := c.FormFile("thumbnail") // Nama field di form-data
+	if err != nil {
+		if err == http.ErrMissingFile && media != nil && media.Type == models.EducationMediaPDF {
+			generated, genErr := h.mediaService.GeneratePDFThumbnail(media.Key)
+			if genErr == nil {
+				return generated, true
+			}
+			log.Printf("WARNING: %v", genErr)
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Thumbnail file is required (automatic PDF thumbnail failed)", genErr.Error()))
+			return "", false
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Thumbnail file is required", err.Error()))
+		return "", false
 	}
-	log.Printf("File '%s' berhasil disimpan di: %s", file.Filename, dst)
 
-	// Kembalikan path absolut untuk disimpan di DB dan digunakan os.Remove
-	return dst, nil
+	// Validasi, proses (varian & pembersihan metadata), lalu simpan
+	return storeUploadedImage(c, h.imageService, storage.PrefixEducations, file, "thumbnail")
+}
+
+func discardMedia(mediaService services.EducationMediaService, media *services.EducationMedia) {
+	if media != nil {
+		mediaService.Discard(*media)
+	}
 }
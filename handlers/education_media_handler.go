@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/storage"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// EducationMediaHandler melayani file PDF/video edukasi dan upload bertahap untuk admin.
+type EducationMediaHandler struct {
+	mediaService     services.EducationMediaService
+	educationService services.EducationService
+	store            storage.BlobStore
+}
+
+func NewEducationMediaHandler(mediaService services.EducationMediaService, educationService services.EducationService, store storage.BlobStore) *EducationMediaHandler {
+	return &EducationMediaHandler{mediaService: mediaService, educationService: educationService, store: store}
+}
+
+func toEducationUploadResponse(upload models.EducationMediaUpload) dto.EducationUploadResponseDTO {
+	var mimeType *string
+	if upload.MimeType != "" {
+		mimeType = &upload.MimeType
+	}
+	return dto.EducationUploadResponseDTO{
+		UploadID:       upload.ID,
+		FileName:       upload.FileName,
+		TotalBytes:     upload.TotalBytes,
+		ReceivedBytes:  upload.ReceivedBytes,
+		MimeType:       mimeType,
+		Status:         upload.Status,
+		ChunkSizeBytes: services.EducationUploadChunkBytes(),
+		ExpiresAt:      upload.ExpiresAt.Format(time.RFC3339),
+	}
+}
+
+func respondEducationMediaError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrEducationUploadNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrEducationUploadOffset), errors.Is(err, services.ErrEducationUploadIncomplete):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrEducationUploadOverflow):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrEducationMediaUnsupported):
+		c.JSON(http.StatusUnsupportedMediaType, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrEducationMediaTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse(err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(message, err.Error()))
+	}
+}
+
+// StartUpload menangani POST /api/v1/educations/uploads (admin)
+func (h *EducationMediaHandler) StartUpload(c *gin.Context) {
+	var input dto.StartEducationUploadDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID, _ := requester(c)
+	upload, err := h.mediaService.StartUpload(userID, input)
+	if err != nil {
+		respondEducationMediaError(c, "Failed to start upload", err)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Upload started successfully", toEducationUploadResponse(upload)))
+}
+
+// GetUpload menangani GET /api/v1/educations/uploads/:upload_id (admin).
+// Dipakai klien untuk mengetahui offset saat melanjutkan upload yang terputus.
+func (h *EducationMediaHandler) GetUpload(c *gin.Context) {
+	userID, _ := requester(c)
+	upload, err := h.mediaService.GetUpload(userID, c.Param("upload_id"))
+	if err != nil {
+		respondEducationMediaError(c, "Failed to fetch upload", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Upload fetched successfully", toEducationUploadResponse(upload)))
+}
+
+// UploadChunk menangani PUT /api/v1/educations/uploads/:upload_id (admin).
+// Body berisi byte mentah satu potongan dengan header "Content-Range: bytes <awal>-<akhir>/<total>".
+func (h *EducationMediaHandler) UploadChunk(c *gin.Context) {
+	start, total, err := parseChunkContentRange(c.GetHeader("Content-Range"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid Content-Range header", err.Error()))
+		return
+	}
+
+	userID, _ := requester(c)
+	current, err := h.mediaService.GetUpload(userID, c.Param("upload_id"))
+	if err != nil {
+		respondEducationMediaError(c, "Failed to upload chunk", err)
+		return
+	}
+	if total != current.TotalBytes {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Content-Range total does not match upload size", nil))
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, services.EducationUploadChunkBytes())
+	upload, err := h.mediaService.AppendChunk(userID, current.ID, start, body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse("Chunk exceeds maximum chunk size", toEducationUploadResponse(upload)))
+			return
+		}
+		if errors.Is(err, services.ErrEducationUploadOffset) {
+			// Sertakan status terkini agar klien bisa melanjutkan dari received_bytes
+			c.JSON(http.StatusConflict, utils.ErrorResponse(err.Error(), toEducationUploadResponse(upload)))
+			return
+		}
+		respondEducationMediaError(c, "Failed to upload chunk", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Chunk uploaded successfully", toEducationUploadResponse(upload)))
+}
+
+// GetMedia menangani GET/HEAD /api/v1/educations/:id/media.
+// serveBlob (http.ServeContent) menangani header Range sehingga video bisa di-seek dan PDF dibaca per bagian.
+func (h *EducationMediaHandler) GetMedia(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	education, err := h.educationService.FindByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("Education not found", nil))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch education", err.Error()))
+		}
+		return
+	}
+	if education.MediaPath == "" {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Education has no hosted media", nil))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", fmt.Sprintf("education-%d%s", education.ID, path.Ext(education.MediaPath))))
+	serveBlob(c, h.store, storage.NormalizeKey(education.MediaPath), education.MediaMimeType, "private, max-age=3600")
+}
+
+// parseChunkContentRange membaca "bytes <awal>-<akhir>/<total>" dan mengembalikan offset awal serta total.
+func parseChunkContentRange(header string) (int64, int64, error) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(header), "bytes ")
+	if !found {
+		return 0, 0, errors.New("expected format 'bytes <start>-<end>/<total>'")
+	}
+	rangePart, totalPart, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, errors.New("missing total size")
+	}
+	startPart, endPart, found := strings.Cut(rangePart, "-")
+	if !found {
+		return 0, 0, errors.New("missing range end")
+	}
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, errors.New("invalid range start")
+	}
+	end, err := strconv.ParseInt(endPart, 10, 64)
+	if err != nil || end < start {
+		return 0, 0, errors.New("invalid range end")
+	}
+	total, err := strconv.ParseInt(totalPart, 10, 64)
+	if err != nil || end >= total {
+		return 0, 0, errors.New("invalid total size")
+	}
+	return start, total, nil
+}
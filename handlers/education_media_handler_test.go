@@ -0,0 +1,40 @@
+package handlers
+
+import "testing"
+
+func TestParseChunkContentRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		wantStart int64
+		wantTotal int64
+		wantErr   bool
+	}{
+		{"first chunk", "bytes 0-1048575/5242880", 0, 5242880, false},
+		{"middle chunk", "bytes 1048576-2097151/5242880", 1048576, 5242880, false},
+		{"last byte", "bytes 5242879-5242879/5242880", 5242879, 5242880, false},
+		{"surrounding whitespace", "  bytes 0-9/10 ", 0, 10, false},
+		{"missing unit", "0-9/10", 0, 0, true},
+		{"wrong unit", "items 0-9/10", 0, 0, true},
+		{"missing total", "bytes 0-9", 0, 0, true},
+		{"unknown total", "bytes 0-9/*", 0, 0, true},
+		{"missing end", "bytes 0/10", 0, 0, true},
+		{"negative start", "bytes -1-9/10", 0, 0, true},
+		{"end before start", "bytes 10-9/20", 0, 0, true},
+		{"end past total", "bytes 0-10/10", 0, 0, true},
+		{"non-numeric start", "bytes a-9/10", 0, 0, true},
+		{"empty", "", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, total, err := parseChunkContentRange(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseChunkContentRange(%q) error = %v, wantErr %v", tt.header, err, tt.wantErr)
+			}
+			if start != tt.wantStart || total != tt.wantTotal {
+				t.Errorf("parseChunkContentRange(%q) = (%d, %d), want (%d, %d)", tt.header, start, total, tt.wantStart, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// EducationRecommendationHandler mengelola pemicu rekomendasi edukasi dan daftar edukasi yang disarankan.
+type EducationRecommendationHandler struct {
+	service services.EducationRecommendationService
+}
+
+func NewEducationRecommendationHandler(service services.EducationRecommendationService) *EducationRecommendationHandler {
+	return &EducationRecommendationHandler{service: service}
+}
+
+func toEducationRecommendationResponses(recommendations []services.EducationRecommendation) []dto.EducationRecommendationDTO {
+	responseDTOs := []dto.EducationRecommendationDTO{}
+	for _, recommendation := range recommendations {
+		responseDTOs = append(responseDTOs, dto.EducationRecommendationDTO{
+			EducationResponseDTO: toEducationResponseDTO(recommendation.Education),
+			Reason:               recommendation.TriggerType,
+			ReasonLabel:          services.EducationTriggerLabels[recommendation.TriggerType],
+			SymptomCode:          recommendation.SymptomCode,
+		})
+	}
+	return responseDTOs
+}
+
+// recommendEducations mengambil edukasi yang relevan untuk ditempelkan di response fitur lain.
+// Kegagalan hanya dicatat agar tidak menggagalkan request utama.
+func recommendEducations(service services.EducationRecommendationService, userID uint, contexts ...services.EducationContext) []dto.EducationRecommendationDTO {
+	if service == nil || len(contexts) == 0 {
+		return nil
+	}
+	recommendations, err := service.ForContexts(userID, contexts)
+	if err != nil {
+		log.Printf("WARNING: Failed to load education recommendations for user %d: %v", userID, err)
+		return nil
+	}
+	if len(recommendations) == 0 {
+		return nil
+	}
+	return toEducationRecommendationResponses(recommendations)
+}
+
+// GetRecommended menangani GET /api/v1/educations/recommended
+func (h *EducationRecommendationHandler) GetRecommended(c *gin.Context) {
+	userID, _ := requester(c)
+	recommendations, err := h.service.Recommend(userID)
+	if err != nil {
+		if errors.Is(err, services.ErrPatientNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch recommended educations", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Recommended educations fetched successfully", toEducationRecommendationResponses(recommendations)))
+}
+
+// GetTriggers menangani GET /api/v1/educations/:id/triggers (admin)
+func (h *EducationRecommendationHandler) GetTriggers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	triggers, err := h.service.GetTriggers(uint(id))
+	if err != nil {
+		respondEducationEngagementError(c, "Failed to fetch education triggers", err)
+		return
+	}
+	responseDTOs := []dto.EducationTriggerDTO{}
+	for _, trigger := range triggers {
+		responseDTOs = append(responseDTOs, dto.EducationTriggerDTO{Type: trigger.TriggerType, SymptomCode: trigger.SymptomCode})
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Education triggers fetched successfully", responseDTOs))
+}
+
+// SetTriggers menangani PUT /api/v1/educations/:id/triggers (admin)
+func (h *EducationRecommendationHandler) SetTriggers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+	var input dto.SetEducationTriggersDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	triggers, err := h.service.SetTriggers(uint(id), input)
+	if err != nil {
+		if errors.Is(err, services.ErrEducationNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to save education triggers", err.Error()))
+		return
+	}
+	responseDTOs := []dto.EducationTriggerDTO{}
+	for _, trigger := range triggers {
+		responseDTOs = append(responseDTOs, dto.EducationTriggerDTO{Type: trigger.TriggerType, SymptomCode: trigger.SymptomCode})
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Education triggers saved successfully", responseDTOs))
+}
@@ -1,21 +1,37 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/darmawguna/tirtaapp.git/dto" // Sesuaikan path
 	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
 	"github.com/darmawguna/tirtaapp.git/services"
 	"github.com/darmawguna/tirtaapp.git/utils"
 	"github.com/gin-gonic/gin"
 )
 
 type FluidBalanceHandler struct {
-	service services.FluidBalanceService
+	service         services.FluidBalanceService
+	recommendations services.EducationRecommendationService
 }
 
-func NewFluidBalanceHandler(service services.FluidBalanceService) *FluidBalanceHandler {
-	return &FluidBalanceHandler{service: service}
+func NewFluidBalanceHandler(service services.FluidBalanceService, recommendations services.EducationRecommendationService) *FluidBalanceHandler {
+	return &FluidBalanceHandler{service: service, recommendations: recommendations}
+}
+
+// fluidRecommendations menyarankan edukasi pengelolaan cairan bila service menilai log harian
+// sudah melewati batas.
+func (h *FluidBalanceHandler) fluidRecommendations(dailyLog models.FluidBalanceLog, allowance services.FluidAllowance) []dto.EducationRecommendationDTO {
+	if allowance.AlertLevel != models.FluidAlertLevelExceeded {
+		return nil
+	}
+	return recommendEducations(h.recommendations, dailyLog.UserID,
+		services.EducationContext{TriggerType: models.EducationTriggerFluidOverLimit})
 }
 
 func toFluidBalanceResponse(log models.FluidBalanceLog) dto.FluidBalanceLogResponseDTO {
@@ -30,6 +46,15 @@ func toFluidBalanceResponse(log models.FluidBalanceLog) dto.FluidBalanceLogRespo
 	}
 }
 
+func toFluidAllowanceResponse(allowance services.FluidAllowance) dto.FluidAllowanceDTO {
+	return dto.FluidAllowanceDTO{
+		DailyLimitCC:       allowance.DailyLimitCC,
+		WarningThresholdCC: allowance.WarningThresholdCC,
+		RemainingCC:        allowance.RemainingCC,
+		PrescriptionID:     allowance.PrescriptionID,
+	}
+}
+
 func (h *FluidBalanceHandler) CreateOrUpdate(c *gin.Context) {
 	var input dto.CreateOrUpdateFluidLogDTO
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -38,13 +63,21 @@ func (h *FluidBalanceHandler) CreateOrUpdate(c *gin.Context) {
 	}
 
 	userID := c.MustGet("userID").(float64)
-	logEntry, err := h.service.CreateOrUpdateLog(uint(userID), input)
+	logEntry, allowance, err := h.service.CreateOrUpdateLog(uint(userID), input)
 	if err != nil {
+		if errors.Is(err, services.ErrOutsideBackdateWindow) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save fluid log", err.Error()))
 		return
 	}
 
-	response := utils.SuccessResponse("Fluid log saved successfully", toFluidBalanceResponse(logEntry))
+	response := utils.SuccessResponse("Fluid log saved successfully", dto.FluidLogWithAllowanceResponseDTO{
+		FluidBalanceLogResponseDTO: toFluidBalanceResponse(logEntry),
+		Allowance:                  toFluidAllowanceResponse(allowance),
+		RecommendedEducations:      h.fluidRecommendations(logEntry, allowance),
+	})
 	c.JSON(http.StatusOK, response) // Gunakan 200 OK karena ini bisa create atau update
 }
 
@@ -63,4 +96,274 @@ func (h *FluidBalanceHandler) GetHistory(c *gin.Context) {
 
 	response := utils.SuccessResponse("History fetched successfully", responseDTOs)
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// GetAllowance menangani GET /api/v1/fluids/allowance (batas & sisa cairan hari ini)
+func (h *FluidBalanceHandler) GetAllowance(c *gin.Context) {
+	userID := c.MustGet("userID").(float64)
+	allowance, err := h.service.GetTodayAllowance(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch fluid allowance", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Fluid allowance fetched successfully", toFluidAllowanceResponse(allowance)))
+}
+
+func toFluidEntryResponse(entry models.FluidEntry) dto.FluidEntryResponseDTO {
+	return dto.FluidEntryResponseDTO{
+		ID:                entry.ID,
+		LogDate:           entry.LogDate.Format("2006-01-02"),
+		RecordedAt:        entry.RecordedAt.Format(time.RFC3339),
+		Direction:         entry.Direction,
+		Category:          entry.Category,
+		VolumeCC:          entry.VolumeCC,
+		ContainerPresetID: entry.ContainerPresetID,
+		Notes:             entry.Notes,
+	}
+}
+
+func toFluidEntryResultResponse(result services.FluidEntryResult) dto.FluidEntryResultResponseDTO {
+	return dto.FluidEntryResultResponseDTO{
+		Entry:     toFluidEntryResponse(result.Entry),
+		DailyLog:  toFluidBalanceResponse(result.Log),
+		Allowance: toFluidAllowanceResponse(result.Allowance),
+	}
+}
+
+// respondFluidEntryError memetakan error service entry cairan ke status HTTP.
+func respondFluidEntryError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrFluidEntryNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrFluidEntryForbidden):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(message, err.Error()))
+	}
+}
+
+// AddEntry menangani POST /api/v1/fluids/entries
+func (h *FluidBalanceHandler) AddEntry(c *gin.Context) {
+	var input dto.FluidEntryDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID := c.MustGet("userID").(float64)
+	result, err := h.service.AddEntry(uint(userID), input)
+	if err != nil {
+		respondFluidEntryError(c, "Failed to save fluid entry", err)
+		return
+	}
+
+	response := toFluidEntryResultResponse(result)
+	response.RecommendedEducations = h.fluidRecommendations(result.Log, result.Allowance)
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Fluid entry saved successfully", response))
+}
+
+// UpdateEntry menangani PUT /api/v1/fluids/entries/:id
+func (h *FluidBalanceHandler) UpdateEntry(c *gin.Context) {
+	entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	var input dto.FluidEntryDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID := c.MustGet("userID").(float64)
+	result, err := h.service.UpdateEntry(uint(userID), uint(entryID), input)
+	if err != nil {
+		respondFluidEntryError(c, "Failed to update fluid entry", err)
+		return
+	}
+
+	response := toFluidEntryResultResponse(result)
+	response.RecommendedEducations = h.fluidRecommendations(result.Log, result.Allowance)
+	c.JSON(http.StatusOK, utils.SuccessResponse("Fluid entry updated successfully", response))
+}
+
+// DeleteEntry menangani DELETE /api/v1/fluids/entries/:id
+func (h *FluidBalanceHandler) DeleteEntry(c *gin.Context) {
+	entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	userID := c.MustGet("userID").(float64)
+	result, err := h.service.DeleteEntry(uint(userID), uint(entryID))
+	if err != nil {
+		respondFluidEntryError(c, "Failed to delete fluid entry", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Fluid entry deleted successfully", toFluidEntryResultResponse(result)))
+}
+
+// GetTimeline menangani GET /api/v1/fluids/timeline?date=YYYY-MM-DD
+func (h *FluidBalanceHandler) GetTimeline(c *gin.Context) {
+	userID := c.MustGet("userID").(float64)
+	timeline, err := h.service.GetTimeline(uint(userID), c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to fetch fluid timeline", err.Error()))
+		return
+	}
+
+	entries := []dto.FluidEntryResponseDTO{}
+	for _, entry := range timeline.Entries {
+		entries = append(entries, toFluidEntryResponse(entry))
+	}
+	dailyLog := toFluidBalanceResponse(timeline.Log)
+	dailyLog.LogDate = timeline.Date.Format("2006-01-02")
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Fluid timeline fetched successfully", dto.FluidTimelineResponseDTO{
+		Date:      timeline.Date.Format("2006-01-02"),
+		DailyLog:  dailyLog,
+		Entries:   entries,
+		Allowance: toFluidAllowanceResponse(timeline.Allowance),
+	}))
+}
+
+// Batas ukuran halaman riwayat cairan.
+const (
+	defaultFluidHistoryPageSize = 30
+	maxFluidHistoryPageSize     = 100
+)
+
+func roundOneDecimal(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+func toFluidStatsPeriodResponse(period repositories.FluidAggregate) dto.FluidStatsPeriodDTO {
+	return dto.FluidStatsPeriodDTO{
+		PeriodStart:   period.PeriodStart.Format("2006-01-02"),
+		DaysLogged:    period.DaysLogged,
+		TotalIntakeCC: period.TotalIntakeCC,
+		TotalOutputCC: period.TotalOutputCC,
+		AvgIntakeCC:   roundOneDecimal(period.AvgIntakeCC),
+		AvgOutputCC:   roundOneDecimal(period.AvgOutputCC),
+		AvgBalanceCC:  roundOneDecimal(period.AvgBalanceCC),
+		MaxBalanceCC:  period.MaxBalanceCC,
+		DaysOverLimit: period.DaysOverLimit,
+	}
+}
+
+func toFluidStatsResponse(stats services.FluidStats) dto.FluidStatsResponseDTO {
+	periods := []dto.FluidStatsPeriodDTO{}
+	for _, period := range stats.Periods {
+		periods = append(periods, toFluidStatsPeriodResponse(period))
+	}
+	return dto.FluidStatsResponseDTO{
+		From:                   stats.From.Format("2006-01-02"),
+		To:                     stats.To.Format("2006-01-02"),
+		Granularity:            stats.Granularity,
+		DaysInRange:            stats.DaysInRange,
+		DaysLogged:             stats.Summary.DaysLogged,
+		PercentDaysLogged:      stats.PercentDaysLogged,
+		TotalIntakeCC:          stats.Summary.TotalIntakeCC,
+		TotalOutputCC:          stats.Summary.TotalOutputCC,
+		AvgIntakeCC:            roundOneDecimal(stats.Summary.AvgIntakeCC),
+		AvgOutputCC:            roundOneDecimal(stats.Summary.AvgOutputCC),
+		AvgBalanceCC:           roundOneDecimal(stats.Summary.AvgBalanceCC),
+		DaysOverLimit:          stats.Summary.DaysOverLimit,
+		LongestCompliantStreak: stats.LongestCompliantStreak,
+		Periods:                periods,
+	}
+}
+
+// GetStats menangani GET /api/v1/fluids/stats?from=&to=&granularity=day|week|month
+func (h *FluidBalanceHandler) GetStats(c *gin.Context) {
+	userID := c.MustGet("userID").(float64)
+	h.respondStats(c, func(from, to, granularity string) (services.FluidStats, error) {
+		return h.service.GetStats(uint(userID), from, to, granularity)
+	})
+}
+
+// GetHistoryRange menangani GET /api/v1/fluids/history?from=&to=&page=&page_size=
+func (h *FluidBalanceHandler) GetHistoryRange(c *gin.Context) {
+	userID := c.MustGet("userID").(float64)
+	h.respondHistoryRange(c, func(from, to string, page, pageSize int) (services.FluidHistoryPage, error) {
+		return h.service.GetHistoryRange(uint(userID), from, to, page, pageSize)
+	})
+}
+
+// GetPatientStats menangani GET /api/v1/fluids/patients/:user_id/stats (klinisi & admin)
+func (h *FluidBalanceHandler) GetPatientStats(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid user ID format", err.Error()))
+		return
+	}
+	requesterID, role := requester(c)
+	h.respondStats(c, func(from, to, granularity string) (services.FluidStats, error) {
+		return h.service.GetPatientStats(requesterID, role, uint(patientID), from, to, granularity)
+	})
+}
+
+// GetPatientHistory menangani GET /api/v1/fluids/patients/:user_id/history (klinisi & admin)
+func (h *FluidBalanceHandler) GetPatientHistory(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid user ID format", err.Error()))
+		return
+	}
+	requesterID, role := requester(c)
+	h.respondHistoryRange(c, func(from, to string, page, pageSize int) (services.FluidHistoryPage, error) {
+		return h.service.GetPatientHistoryRange(requesterID, role, uint(patientID), from, to, page, pageSize)
+	})
+}
+
+func (h *FluidBalanceHandler) respondStats(c *gin.Context, fetch func(from, to, granularity string) (services.FluidStats, error)) {
+	stats, err := fetch(c.Query("from"), c.Query("to"), c.Query("granularity"))
+	if err != nil {
+		if respondPatientAccessError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to fetch fluid stats", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Fluid stats fetched successfully", toFluidStatsResponse(stats)))
+}
+
+func (h *FluidBalanceHandler) respondHistoryRange(c *gin.Context, fetch func(from, to string, page, pageSize int) (services.FluidHistoryPage, error)) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid page, must be a positive number", nil))
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultFluidHistoryPageSize)))
+	if err != nil || pageSize <= 0 || pageSize > maxFluidHistoryPageSize {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid page_size, must be between 1 and 100", nil))
+		return
+	}
+
+	result, err := fetch(c.Query("from"), c.Query("to"), page, pageSize)
+	if err != nil {
+		if respondPatientAccessError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to fetch fluid history", err.Error()))
+		return
+	}
+
+	items := []dto.FluidBalanceLogResponseDTO{}
+	for _, fluidLog := range result.Logs {
+		items = append(items, toFluidBalanceResponse(fluidLog))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("History fetched successfully", dto.FluidHistoryPageResponseDTO{
+		From:     result.From.Format("2006-01-02"),
+		To:       result.To.Format("2006-01-02"),
+		Page:     result.Page,
+		PageSize: result.PageSize,
+		Total:    result.Total,
+		Items:    items,
+	}))
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// FluidContainerPresetHandler mengelola preset wadah minum (gelas, botol, mangkuk).
+type FluidContainerPresetHandler struct {
+	service services.FluidContainerPresetService
+}
+
+func NewFluidContainerPresetHandler(service services.FluidContainerPresetService) *FluidContainerPresetHandler {
+	return &FluidContainerPresetHandler{service: service}
+}
+
+func toFluidContainerPresetResponse(preset models.FluidContainerPreset) dto.FluidContainerPresetResponseDTO {
+	return dto.FluidContainerPresetResponseDTO{
+		ID:        preset.ID,
+		Name:      preset.Name,
+		VolumeCC:  preset.VolumeCC,
+		SortOrder: preset.SortOrder,
+		IsActive:  preset.IsActive,
+	}
+}
+
+// GetAll menangani GET /api/v1/fluids/containers (admin bisa menambah ?include_inactive=true)
+func (h *FluidContainerPresetHandler) GetAll(c *gin.Context) {
+	includeInactive := c.Query("include_inactive") == "true" && c.GetString("userRole") == models.RoleAdmin
+	presets, err := h.service.FindAll(includeInactive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch container presets", err.Error()))
+		return
+	}
+
+	var responseDTOs []dto.FluidContainerPresetResponseDTO
+	for _, preset := range presets {
+		responseDTOs = append(responseDTOs, toFluidContainerPresetResponse(preset))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Container presets fetched successfully", responseDTOs))
+}
+
+func (h *FluidContainerPresetHandler) Create(c *gin.Context) {
+	var input dto.FluidContainerPresetDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	preset, err := h.service.Create(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create container preset", err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Container preset created successfully", toFluidContainerPresetResponse(preset)))
+}
+
+func (h *FluidContainerPresetHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	var input dto.FluidContainerPresetDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	preset, err := h.service.Update(uint(id), input)
+	if err != nil {
+		if errors.Is(err, services.ErrFluidContainerPresetNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update container preset", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Container preset updated successfully", toFluidContainerPresetResponse(preset)))
+}
+
+func (h *FluidContainerPresetHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		if errors.Is(err, services.ErrFluidContainerPresetNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete container preset", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Container preset deleted successfully", nil))
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// FluidPrescriptionHandler mengelola resep batas cairan pasien oleh klinisi/admin.
+type FluidPrescriptionHandler struct {
+	service services.FluidPrescriptionService
+}
+
+func NewFluidPrescriptionHandler(service services.FluidPrescriptionService) *FluidPrescriptionHandler {
+	return &FluidPrescriptionHandler{service: service}
+}
+
+func toFluidPrescriptionResponse(p models.FluidPrescription) dto.FluidPrescriptionResponseDTO {
+	return dto.FluidPrescriptionResponseDTO{
+		ID:              p.ID,
+		UserID:          p.UserID,
+		EffectiveDate:   p.EffectiveDate.Format("2006-01-02"),
+		DailyLimitCC:    p.DailyLimitCC,
+		ResidualUrineCC: p.ResidualUrineCC,
+		WarningPercent:  p.WarningPercent,
+		Notes:           p.Notes,
+		PrescribedBy:    p.PrescribedBy,
+	}
+}
+
+// respondPatientAccessError menulis 404/403 untuk pasien yang tidak ada atau bukan tim perawatan requester.
+func respondPatientAccessError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrPatientNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrPatientAccessDenied):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+	default:
+		return false
+	}
+	return true
+}
+
+// Create menangani POST /api/v1/fluids/prescriptions/:user_id
+func (h *FluidPrescriptionHandler) Create(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid user ID format", err.Error()))
+		return
+	}
+
+	var input dto.CreateFluidPrescriptionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	prescriberID, prescriberRole := requester(c)
+	prescription, err := h.service.Prescribe(prescriberID, prescriberRole, uint(patientID), input)
+	if err != nil {
+		if respondPatientAccessError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to save fluid prescription", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Fluid prescription saved successfully", toFluidPrescriptionResponse(prescription)))
+}
+
+// GetHistory menangani GET /api/v1/fluids/prescriptions/:user_id
+func (h *FluidPrescriptionHandler) GetHistory(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid user ID format", err.Error()))
+		return
+	}
+
+	requesterID, requesterRole := requester(c)
+	prescriptions, err := h.service.GetHistory(requesterID, requesterRole, uint(patientID))
+	if err != nil {
+		if respondPatientAccessError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch fluid prescriptions", err.Error()))
+		return
+	}
+
+	var responseDTOs []dto.FluidPrescriptionResponseDTO
+	for _, p := range prescriptions {
+		responseDTOs = append(responseDTOs, toFluidPrescriptionResponse(p))
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Fluid prescriptions fetched successfully", responseDTOs))
+}
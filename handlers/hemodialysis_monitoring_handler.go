@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings" // Import strings untuk cek error
@@ -14,25 +15,49 @@ import (
 
 // HemodialysisMonitoringHandler mengelola request HTTP untuk pemantauan HD.
 type HemodialysisMonitoringHandler struct {
-	service services.HemodialysisMonitoringService
+	service         services.HemodialysisMonitoringService
+	recommendations services.EducationRecommendationService
 }
 
 // NewHemodialysisMonitoringHandler adalah constructor.
-func NewHemodialysisMonitoringHandler(service services.HemodialysisMonitoringService) *HemodialysisMonitoringHandler {
-	return &HemodialysisMonitoringHandler{service: service}
+func NewHemodialysisMonitoringHandler(service services.HemodialysisMonitoringService, recommendations services.EducationRecommendationService) *HemodialysisMonitoringHandler {
+	return &HemodialysisMonitoringHandler{service: service, recommendations: recommendations}
 }
 
 // toHemodialysisMonitoringResponse mengonversi model ke DTO response.
 func toHemodialysisMonitoringResponse(m models.HemodialysisMonitoring) dto.HemodialysisMonitoringResponseDTO {
+	symptoms := []string{}
+	if m.Symptoms != "" {
+		symptoms = strings.Split(m.Symptoms, ",")
+	}
 	return dto.HemodialysisMonitoringResponseDTO{
-		ID:             m.ID,
-		UserID:         m.UserID,
-		MonitoringDate: m.MonitoringDate.Format("2006-01-02"), // Format tanggal YYYY-MM-DD
-		BPBefore:       m.BPBefore,
-		BPAfter:        m.BPAfter,
-		WeightBefore:   m.WeightBefore,
-		WeightAfter:    m.WeightAfter,
+		ID:                  m.ID,
+		UserID:              m.UserID,
+		MonitoringDate:      m.MonitoringDate.Format("2006-01-02"), // Format tanggal YYYY-MM-DD
+		ScheduleID:          m.HemodialysisScheduleID,
+		BPBefore:            m.BPBefore,
+		BPAfter:             m.BPAfter,
+		BloodPressureBefore: toBloodPressureResponse(m.SystolicBefore, m.DiastolicBefore, m.PulseBefore),
+		BloodPressureAfter:  toBloodPressureResponse(m.SystolicAfter, m.DiastolicAfter, m.PulseAfter),
+		BPNeedsReview:       m.BPNeedsReview,
+		WeightBefore:        m.WeightBefore,
+		WeightAfter:         m.WeightAfter,
+		UFGoalML:            m.UFGoalML,
+		UFAchievedML:        m.UFAchievedML,
+		DurationMinutes:     m.DurationMinutes,
+		DryWeight:           m.DryWeight,
+		Symptoms:            symptoms,
+		IDWGKg:              m.IDWGKg,
+		IDWGPercent:         m.IDWGPercent,
+		IDWGExceeded:        m.IDWGExceeded,
+	}
+}
+
+func toBloodPressureResponse(systolic, diastolic, pulse *int) *dto.BloodPressureResponseDTO {
+	if systolic == nil || diastolic == nil {
+		return nil
 	}
+	return &dto.BloodPressureResponseDTO{Systolic: *systolic, Diastolic: *diastolic, Pulse: pulse}
 }
 
 // Create menangani request POST /api/v1/hemodialysis-monitoring
@@ -50,17 +75,38 @@ func (h *HemodialysisMonitoringHandler) Create(c *gin.Context) {
 	// Panggil service untuk membuat atau memperbarui data hari ini
 	monitoring, err := h.service.CreateOrUpdateMonitoringForToday(uint(userID), input)
 	if err != nil {
+		if errors.Is(err, services.ErrOutsideBackdateWindow) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		if errors.Is(err, services.ErrHemodialysisScheduleNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		if errors.Is(err, services.ErrHemodialysisScheduleForbidden) {
+			c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
 		// Tangani error spesifik dari service jika perlu (misal: duplikasi ditangani di repo/service)
 		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") { // Contoh error Postgres
 			c.JSON(http.StatusConflict, utils.ErrorResponse("Data pemantauan untuk hari ini sudah ada", err.Error()))
 			return
 		}
+		if errors.Is(err, utils.ErrInvalidBloodPressure) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal menyimpan data pemantauan", err.Error()))
 		return
 	}
 
 	// Kirim response sukses dengan data yang disimpan/diperbarui
-	c.JSON(http.StatusOK, utils.SuccessResponse("Data pemantauan berhasil disimpan", toHemodialysisMonitoringResponse(monitoring)))
+	response := toHemodialysisMonitoringResponse(monitoring)
+	if monitoring.IDWGExceeded {
+		response.RecommendedEducations = recommendEducations(h.recommendations, uint(userID),
+			services.EducationContext{TriggerType: models.EducationTriggerHighIDWG})
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Data pemantauan berhasil disimpan", response))
 }
 
 // GetHistory menangani request GET /api/v1/hemodialysis-monitoring/history
@@ -112,4 +158,54 @@ func (h *HemodialysisMonitoringHandler) GetByID(c *gin.Context) {
 
 	// Kirim response sukses
 	c.JSON(http.StatusOK, utils.SuccessResponse("Data pemantauan berhasil diambil", toHemodialysisMonitoringResponse(monitoring)))
-}
\ No newline at end of file
+}
+// GetIDWGAlerts menangani GET /api/v1/hemodialysis-monitoring/idwg-alerts?days=14 (klinisi & admin)
+func (h *HemodialysisMonitoringHandler) GetIDWGAlerts(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "14"))
+	if err != nil || days <= 0 || days > 90 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid days, must be between 1 and 90", nil))
+		return
+	}
+
+	userID := c.MustGet("userID").(float64)
+	userRole, _ := c.MustGet("userRole").(string)
+	monitorings, err := h.service.GetIDWGAlerts(uint(userID), userRole, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal mengambil peringatan IDWG", err.Error()))
+		return
+	}
+
+	responseDTOs := []dto.IDWGAlertResponseDTO{}
+	for _, m := range monitorings {
+		responseDTOs = append(responseDTOs, dto.IDWGAlertResponseDTO{
+			HemodialysisMonitoringResponseDTO: toHemodialysisMonitoringResponse(m),
+			PatientName:                       m.User.Name,
+		})
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Peringatan IDWG berhasil diambil", responseDTOs))
+}
+
+// GetBySchedule menangani GET /api/v1/hemodialysis-monitoring/by-schedule/:schedule_id
+func (h *HemodialysisMonitoringHandler) GetBySchedule(c *gin.Context) {
+	scheduleID, err := strconv.ParseUint(c.Param("schedule_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid schedule ID format", err.Error()))
+		return
+	}
+
+	userID := c.MustGet("userID").(float64)
+	monitoring, err := h.service.GetMonitoringBySchedule(uint(userID), uint(scheduleID))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrHemodialysisScheduleNotFound), errors.Is(err, services.ErrMonitoringNotFound):
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+		case errors.Is(err, services.ErrHemodialysisScheduleForbidden):
+			c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Gagal mengambil data pemantauan", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Data pemantauan berhasil diambil", toHemodialysisMonitoringResponse(monitoring)))
+}
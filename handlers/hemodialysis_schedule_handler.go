@@ -22,10 +22,11 @@ func NewHemodialysisScheduleHandler(service services.HemodialysisScheduleService
 
 func toHemodialysisScheduleResponse(schedule models.HemodialysisSchedule) dto.HemodialysisScheduleResponseDTO {
 	return dto.HemodialysisScheduleResponseDTO{
-		ID:           schedule.ID,
-		UserID:       schedule.UserID,
-		ScheduleDate: schedule.ScheduleDate.Format("2006-01-02"),
-		IsActive:     schedule.IsActive,
+		ID:               schedule.ID,
+		UserID:           schedule.UserID,
+		ScheduleDate:     schedule.ScheduleDate.Format("2006-01-02"),
+		IsActive:         schedule.IsActive,
+		MonitoringStatus: schedule.MonitoringStatus,
 	}
 }
 
@@ -56,7 +57,7 @@ func (h *HemodialysisScheduleHandler) GetByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, utils.ErrorResponse("Hemodialysis schedule not found", err.Error()))
 		return
 	}
-	
+
 	// Verifikasi otorisasi
 	userID := c.MustGet("userID").(float64)
 	if schedule.UserID != uint(userID) {
@@ -131,4 +132,4 @@ func (h *HemodialysisScheduleHandler) Delete(c *gin.Context) {
 }
 
 // Implementasikan GetAll, GetByID, Update, dan Delete dengan pola yang sama seperti ControlScheduleHandler
-// ...
\ No newline at end of file
+// ...
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// IntradialyticReadingHandler mengelola tanda vital selama sesi HD.
+type IntradialyticReadingHandler struct {
+	service services.IntradialyticReadingService
+}
+
+func NewIntradialyticReadingHandler(service services.IntradialyticReadingService) *IntradialyticReadingHandler {
+	return &IntradialyticReadingHandler{service: service}
+}
+
+func toIntradialyticReadingResponse(flagged services.FlaggedReading) dto.IntradialyticReadingResponseDTO {
+	reading := flagged.Reading
+	return dto.IntradialyticReadingResponseDTO{
+		ID:                reading.ID,
+		RecordedAt:        reading.RecordedAt.Format(time.RFC3339),
+		Systolic:          reading.Systolic,
+		Diastolic:         reading.Diastolic,
+		Pulse:             reading.Pulse,
+		BloodFlowRate:     reading.BloodFlowRate,
+		VenousPressure:    reading.VenousPressure,
+		ArterialPressure:  reading.ArterialPressure,
+		UFRate:            reading.UFRate,
+		Notes:             reading.Notes,
+		RecordedBy:        reading.RecordedBy,
+		Hypotension:       len(flagged.HypotensionReasons) > 0,
+		HypotensionReason: flagged.HypotensionReasons,
+	}
+}
+
+func respondReadingError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrMonitoringNotFound), errors.Is(err, services.ErrReadingNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrMonitoringAccessDenied):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(message, err.Error()))
+	}
+}
+
+// requester mengambil ID & role user dari context (AuthMiddleware).
+func requester(c *gin.Context) (uint, string) {
+	userID := c.MustGet("userID").(float64)
+	userRole, _ := c.MustGet("userRole").(string)
+	return uint(userID), userRole
+}
+
+// Create menangani POST /api/v1/hemodialysis-monitoring/:id/readings (klinisi & admin)
+func (h *IntradialyticReadingHandler) Create(c *gin.Context) {
+	monitoringID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid monitoring ID format", err.Error()))
+		return
+	}
+
+	var input dto.CreateIntradialyticReadingDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	reading, err := h.service.AddReading(userID, userRole, uint(monitoringID), input)
+	if err != nil {
+		respondReadingError(c, "Gagal menyimpan data pengukuran", err)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Data pengukuran berhasil disimpan", toIntradialyticReadingResponse(reading)))
+}
+
+// GetSeries menangani GET /api/v1/hemodialysis-monitoring/:id/readings
+func (h *IntradialyticReadingHandler) GetSeries(c *gin.Context) {
+	monitoringID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid monitoring ID format", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	series, err := h.service.GetSeries(userID, userRole, uint(monitoringID))
+	if err != nil {
+		respondReadingError(c, "Gagal mengambil data pengukuran", err)
+		return
+	}
+
+	readings := []dto.IntradialyticReadingResponseDTO{}
+	for _, reading := range series.Readings {
+		readings = append(readings, toIntradialyticReadingResponse(reading))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Data pengukuran berhasil diambil", dto.IntradialyticSeriesResponseDTO{
+		MonitoringID:        series.Monitoring.ID,
+		BaselineSystolic:    series.Monitoring.SystolicBefore,
+		SystolicDropMMHg:    series.Criteria.SystolicDrop,
+		NadirSystolicMMHg:   series.Criteria.NadirSystolic,
+		HypotensionReadings: series.HypotensionReadings,
+		Readings:            readings,
+	}))
+}
+
+// Delete menangani DELETE /api/v1/hemodialysis-monitoring/:id/readings/:reading_id (klinisi & admin)
+func (h *IntradialyticReadingHandler) Delete(c *gin.Context) {
+	monitoringID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid monitoring ID format", err.Error()))
+		return
+	}
+	readingID, err := strconv.ParseUint(c.Param("reading_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid reading ID format", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	if err := h.service.DeleteReading(userID, userRole, uint(monitoringID), uint(readingID)); err != nil {
+		respondReadingError(c, "Gagal menghapus data pengukuran", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Data pengukuran berhasil dihapus", nil))
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler mengelola endpoint admin untuk job terjadwal worker.
+type JobHandler struct {
+	service services.JobService
+}
+
+func NewJobHandler(service services.JobService) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+func toJobRunResponse(run models.JobRun) dto.JobRunResponseDTO {
+	response := dto.JobRunResponseDTO{
+		ID:             run.ID,
+		JobName:        run.JobName,
+		Timezone:       run.Timezone,
+		Trigger:        run.Trigger,
+		Status:         run.Status,
+		StartedAt:      run.StartedAt.Format(time.RFC3339),
+		ItemsProcessed: run.ItemsProcessed,
+		ErrorCount:     run.ErrorCount,
+		ErrorMessage:   run.ErrorMessage,
+	}
+	if run.FinishedAt != nil {
+		finishedAt := run.FinishedAt.Format(time.RFC3339)
+		response.FinishedAt = &finishedAt
+	}
+	return response
+}
+
+// GetAll menangani GET /api/v1/admin/jobs
+func (h *JobHandler) GetAll(c *gin.Context) {
+	statuses, err := h.service.ListJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch jobs", err.Error()))
+		return
+	}
+
+	var responseDTOs []dto.JobResponseDTO
+	for _, status := range statuses {
+		item := dto.JobResponseDTO{
+			Name:            status.Definition.Name,
+			Description:     status.Definition.Description,
+			CronSpec:        status.Definition.CronSpec,
+			PerUserTimezone: status.Definition.PerUserTimezone,
+		}
+		if status.LastRun != nil {
+			lastRun := toJobRunResponse(*status.LastRun)
+			item.LastRun = &lastRun
+		}
+		responseDTOs = append(responseDTOs, item)
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Jobs fetched successfully", responseDTOs))
+}
+
+// GetRuns menangani GET /api/v1/admin/jobs/:name/runs?limit=20
+func (h *JobHandler) GetRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid limit, must be between 1 and 100", nil))
+		return
+	}
+
+	runs, err := h.service.GetRuns(c.Param("name"), limit)
+	if err != nil {
+		if errors.Is(err, services.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch job runs", err.Error()))
+		return
+	}
+
+	var responseDTOs []dto.JobRunResponseDTO
+	for _, run := range runs {
+		responseDTOs = append(responseDTOs, toJobRunResponse(run))
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Job runs fetched successfully", responseDTOs))
+}
+
+// Trigger menangani POST /api/v1/admin/jobs/:name/trigger
+// Job dijalankan secara asinkron oleh worker; hasilnya terlihat di riwayat runs.
+func (h *JobHandler) Trigger(c *gin.Context) {
+	var input dto.TriggerJobDTO
+	// Body opsional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+			return
+		}
+	}
+
+	if err := h.service.TriggerJob(c.Param("name"), input.Timezone); err != nil {
+		if errors.Is(err, services.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to trigger job", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.SuccessResponse("Job triggered successfully", gin.H{
+		"job_name": c.Param("name"),
+		"timezone": input.Timezone,
+	}))
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// LabResultHandler mengelola hasil lab pasien, tren, dan adekuasi dialisis.
+// Endpoint /labs/... untuk pasien sendiri, /labs/patients/:user_id/... untuk klinisi & admin.
+type LabResultHandler struct {
+	service services.LabResultService
+}
+
+func NewLabResultHandler(service services.LabResultService) *LabResultHandler {
+	return &LabResultHandler{service: service}
+}
+
+func toLabResultResponse(view services.LabResultView) dto.LabResultResponseDTO {
+	result := view.Result
+	return dto.LabResultResponseDTO{
+		ID:         result.ID,
+		UserID:     result.UserID,
+		TestCode:   result.LabTest.Code,
+		TestName:   result.LabTest.Name,
+		Unit:       result.LabTest.Unit,
+		Value:      result.Value,
+		SampleDate: result.SampleDate.Format("2006-01-02"),
+		Flag:       view.Flag,
+		RefLow:     result.LabTest.RefLow,
+		RefHigh:    result.LabTest.RefHigh,
+		EnteredBy:  result.EnteredBy,
+		Notes:      result.Notes,
+	}
+}
+
+func respondLabError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrPatientNotFound), errors.Is(err, services.ErrLabTestNotFound), errors.Is(err, services.ErrLabResultNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrPatientAccessDenied):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrLabResultConflict):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(err.Error(), nil))
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(message, err.Error()))
+	}
+}
+
+// patientParam mengambil pasien target: :user_id untuk endpoint klinisi, selain itu requester sendiri.
+func patientParam(c *gin.Context) (uint, bool) {
+	if c.Param("user_id") == "" {
+		userID, _ := requester(c)
+		return userID, true
+	}
+	patientID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid user ID format", err.Error()))
+		return 0, false
+	}
+	return uint(patientID), true
+}
+
+// Create menangani POST /api/v1/labs/results dan /api/v1/labs/patients/:user_id/results
+func (h *LabResultHandler) Create(c *gin.Context) {
+	patientID, ok := patientParam(c)
+	if !ok {
+		return
+	}
+	var input dto.RecordLabResultsDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	views, err := h.service.RecordResults(userID, userRole, patientID, input)
+	if err != nil {
+		respondLabError(c, "Gagal menyimpan hasil lab", err)
+		return
+	}
+
+	responseDTOs := []dto.LabResultResponseDTO{}
+	for _, view := range views {
+		responseDTOs = append(responseDTOs, toLabResultResponse(view))
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Hasil lab berhasil disimpan", responseDTOs))
+}
+
+// GetResults menangani GET /api/v1/labs/results?from=&to=&flagged=true
+func (h *LabResultHandler) GetResults(c *gin.Context) {
+	patientID, ok := patientParam(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole := requester(c)
+	views, err := h.service.GetResults(userID, userRole, patientID, c.Query("from"), c.Query("to"), c.Query("flagged") == "true")
+	if err != nil {
+		respondLabError(c, "Gagal mengambil hasil lab", err)
+		return
+	}
+
+	responseDTOs := []dto.LabResultResponseDTO{}
+	for _, view := range views {
+		responseDTOs = append(responseDTOs, toLabResultResponse(view))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Hasil lab berhasil diambil", responseDTOs))
+}
+
+// GetTrend menangani GET /api/v1/labs/trends/:test_code?from=&to=
+func (h *LabResultHandler) GetTrend(c *gin.Context) {
+	patientID, ok := patientParam(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole := requester(c)
+	trend, err := h.service.GetTrend(userID, userRole, patientID, c.Param("test_code"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondLabError(c, "Gagal mengambil tren hasil lab", err)
+		return
+	}
+
+	points := []dto.LabTrendPointDTO{}
+	for _, point := range trend.Points {
+		points = append(points, dto.LabTrendPointDTO{
+			ResultID:   point.Result.ID,
+			SampleDate: point.Result.SampleDate.Format("2006-01-02"),
+			Value:      point.Result.Value,
+			Flag:       point.Flag,
+		})
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Tren hasil lab berhasil diambil", dto.LabTrendResponseDTO{
+		Test:   toLabTestResponse(trend.Test),
+		From:   trend.From.Format("2006-01-02"),
+		To:     trend.To.Format("2006-01-02"),
+		Points: points,
+	}))
+}
+
+// GetAdequacy menangani GET /api/v1/labs/adequacy?from=&to= (URR & Kt/V)
+func (h *LabResultHandler) GetAdequacy(c *gin.Context) {
+	patientID, ok := patientParam(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole := requester(c)
+	report, err := h.service.GetAdequacy(userID, userRole, patientID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondLabError(c, "Gagal menghitung adekuasi dialisis", err)
+		return
+	}
+
+	points := []dto.DialysisAdequacyPointDTO{}
+	for _, point := range report.Points {
+		points = append(points, dto.DialysisAdequacyPointDTO{
+			SampleDate:   point.SampleDate.Format("2006-01-02"),
+			UreaPre:      point.UreaPre,
+			UreaPost:     point.UreaPost,
+			URRPercent:   point.URRPercent,
+			KtV:          point.KtV,
+			MonitoringID: point.MonitoringID,
+			BelowTarget:  point.BelowTarget,
+		})
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Adekuasi dialisis berhasil dihitung", dto.DialysisAdequacyResponseDTO{
+		From:             report.From.Format("2006-01-02"),
+		To:               report.To.Format("2006-01-02"),
+		URRTargetPercent: report.Targets.URRPercent,
+		KtVTarget:        report.Targets.KtV,
+		Points:           points,
+	}))
+}
+
+// Delete menangani DELETE /api/v1/labs/results/:id
+func (h *LabResultHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	if err := h.service.DeleteResult(userID, userRole, uint(id)); err != nil {
+		respondLabError(c, "Gagal menghapus hasil lab", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Hasil lab berhasil dihapus", nil))
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// LabTestHandler mengelola katalog tes lab (satuan, rentang rujukan, ambang kritis).
+type LabTestHandler struct {
+	service services.LabTestService
+}
+
+func NewLabTestHandler(service services.LabTestService) *LabTestHandler {
+	return &LabTestHandler{service: service}
+}
+
+func toLabTestResponse(test models.LabTest) dto.LabTestResponseDTO {
+	return dto.LabTestResponseDTO{
+		ID:           test.ID,
+		Code:         test.Code,
+		Name:         test.Name,
+		Unit:         test.Unit,
+		RefLow:       test.RefLow,
+		RefHigh:      test.RefHigh,
+		CriticalLow:  test.CriticalLow,
+		CriticalHigh: test.CriticalHigh,
+		SortOrder:    test.SortOrder,
+		IsActive:     test.IsActive,
+	}
+}
+
+// GetAll menangani GET /api/v1/labs/tests (admin bisa menambah ?include_inactive=true)
+func (h *LabTestHandler) GetAll(c *gin.Context) {
+	includeInactive := c.Query("include_inactive") == "true" && c.GetString("userRole") == models.RoleAdmin
+	tests, err := h.service.FindAll(includeInactive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch lab tests", err.Error()))
+		return
+	}
+
+	responseDTOs := []dto.LabTestResponseDTO{}
+	for _, test := range tests {
+		responseDTOs = append(responseDTOs, toLabTestResponse(test))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Lab tests fetched successfully", responseDTOs))
+}
+
+func (h *LabTestHandler) Create(c *gin.Context) {
+	var input dto.CreateLabTestDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	test, err := h.service.Create(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to create lab test", err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Lab test created successfully", toLabTestResponse(test)))
+}
+
+func (h *LabTestHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	var input dto.LabTestDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	test, err := h.service.Update(uint(id), input)
+	if err != nil {
+		if errors.Is(err, services.ErrLabTestNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to update lab test", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Lab test updated successfully", toLabTestResponse(test)))
+}
+
+// Delete menonaktifkan tes; hasil lab lama tetap tersimpan.
+func (h *LabTestHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		if errors.Is(err, services.ErrLabTestNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete lab test", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Lab test deactivated successfully", nil))
+}
@@ -1,48 +1,37 @@
 package handlers
 
 import (
-	"fmt"            // Import fmt
-	"log"            // Import log
-	"mime/multipart" // Import multipart
 	"net/http"
-	"os"            // Import os
-	"path/filepath" // Import filepath
 	"strings"
-	"time" // Import time
 
 	"github.com/darmawguna/tirtaapp.git/dto"          // Adjust path
 	models "github.com/darmawguna/tirtaapp.git/model" // Adjust path
 	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/storage"
 	"github.com/darmawguna/tirtaapp.git/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/spf13/viper" // Import viper
 )
 
-const UPLOAD_PATH_PROFILE = "./uploads/profiles" // Definisikan path upload profile
-
 // ProfileHandler mengelola request terkait profil user.
 type ProfileHandler struct {
 	profileService services.ProfileService
+	imageService   services.ImageService
+	store          storage.BlobStore
 }
 
 // NewProfileHandler adalah constructor untuk ProfileHandler.
-func NewProfileHandler(profileService services.ProfileService) *ProfileHandler {
-	// Pastikan direktori upload ada
-	if err := os.MkdirAll(UPLOAD_PATH_PROFILE, os.ModePerm); err != nil {
-		log.Fatalf("FATAL: Tidak bisa membuat direktori upload profile: %v", err)
-	}
-	return &ProfileHandler{profileService: profileService}
+func NewProfileHandler(profileService services.ProfileService, imageService services.ImageService, store storage.BlobStore) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService, imageService: imageService, store: store}
 }
 
 // toUserResponseDTO (Diperbarui)
 func toUserResponseDTO(user models.User) dto.UserResponseDTO {
 	profilePictureUrl := ""
+	var profilePictureVariants map[string]string
 	if user.ProfilePicture != "" {
-		// Asumsi path relatif disimpan di DB, buat URL lengkap
-		baseUrl := viper.GetString("BASE_URL")
-		if baseUrl == "" { baseUrl = "http://localhost:8080" } // Fallback
-		// Sesuaikan "/static/profiles/" jika path serving berbeda
-		profilePictureUrl = fmt.Sprintf("%s/static/profiles/%s", baseUrl, filepath.Base(user.ProfilePicture))
+		// Foto profil privat: kirim URL bertanda tangan yang berlaku singkat
+		profilePictureUrl = storage.SignedURL(user.ProfilePicture)
+		profilePictureVariants = imageVariantURLs(user.ProfilePicture, storage.SignedURL)
 	}
 	return dto.UserResponseDTO{
 		ID:             user.ID,
@@ -50,6 +39,7 @@ func toUserResponseDTO(user models.User) dto.UserResponseDTO {
 		Email:          user.Email,
 		PhoneNumber: user.PhoneNumber,
 		ProfilePicture: profilePictureUrl, // <-- Kirim URL lengkap
+		ProfilePictureVariants: profilePictureVariants,
 		Role:           user.Role,
 	}
 }
@@ -77,22 +67,15 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	var newProfilePicturePath *string // Pointer untuk path file baru
+	var newProfilePicturePath *string // Pointer untuk key objek baru
 	file, err := c.FormFile("profile_picture") // Nama field file di form-data
 
 	// Jika ada file baru di request
 	if err == nil {
-		// Validasi file (contoh: ukuran < 2MB)
-		if file.Size > 5*1024*1024 { // 2 MB
-			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Profile picture size exceeds 2MB limit", nil))
-			return
-		}
-		// Anda bisa menambahkan validasi tipe file (misal: image/jpeg, image/png)
-
-		// Simpan file baru
-		savedPath, saveErr := saveUploadedProfileFile(c, file, UPLOAD_PATH_PROFILE) // Gunakan helper baru/spesifik
-		if saveErr != nil {
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save profile picture", saveErr.Error()))
+		// Validasi isi file, proses (orientasi, buang metadata/GPS, varian ukuran), lalu simpan.
+		// Nama objek acak sehingga URL foto profil tidak bisa ditebak.
+		savedPath, ok := storeUploadedImage(c, h.imageService, storage.PrefixProfiles, file, "profile picture")
+		if !ok {
 			return
 		}
 		newProfilePicturePath = &savedPath // Set pointer ke path baru
@@ -113,7 +96,7 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 		}
 		// Jika gagal update DB, dan ada file baru yang tersimpan, coba hapus lagi
 		if newProfilePicturePath != nil {
-			go os.Remove(*newProfilePicturePath)
+			go deleteImage(h.store, *newProfilePicturePath)
 		}
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update profile", err.Error()))
 		return
@@ -125,25 +108,6 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 }
 
 
-// --- Helper Function untuk Menyimpan File Foto Profil ---
-// Mirip dengan saveUploadedFile di education handler, tapi path berbeda
-// Mengembalikan path absolut file yang disimpan
-func saveUploadedProfileFile(c *gin.Context, file *multipart.FileHeader, destDirectory string) (string, error) {
-	ext := filepath.Ext(file.Filename)
-	baseName := strings.TrimSuffix(file.Filename, ext)
-	safeBaseName := strings.ReplaceAll(baseName, " ", "_")
-	filename := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), safeBaseName, ext)
-
-	dst := filepath.Join(destDirectory, filename)
-
-	if err := c.SaveUploadedFile(file, dst); err != nil {
-		return "", fmt.Errorf("gagal menyimpan file ke '%s': %w", dst, err)
-	}
-	log.Printf("File '%s' berhasil disimpan sebagai '%s' di: %s", file.Filename, filename, dst)
-
-	return dst, nil
-}
-
 func (h *ProfileHandler) GetUserCount(c *gin.Context) {
 	// Panggil service untuk menghitung
 	count, err := h.profileService.CountRegularUsers()
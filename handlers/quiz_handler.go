@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/darmawguna/tirtaapp.git/services"
 	"github.com/darmawguna/tirtaapp.git/utils"
 	"github.com/gin-gonic/gin"
@@ -71,7 +76,9 @@ func (h *QuizHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	quiz, err := h.quizService.FindByID(uint(id))
+	// Kunci jawaban & pembahasan hanya dikirim ke admin
+	_, userRole := requester(c)
+	quiz, err := h.quizService.FindByID(uint(id), userRole == models.RoleAdmin)
 	if err != nil {
 		// Jika record tidak ditemukan, GORM akan memberikan error.
 		response := utils.ErrorResponse("Fetching Quiz failed", err.Error())
@@ -124,11 +131,134 @@ func (h *QuizHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.quizService.Delete(uint(id)); err != nil {
-		response := utils.ErrorResponse("Failed to delete quiz", err.Error())
-		c.JSON(http.StatusBadRequest, response)
+		respondQuizError(c, "Failed to delete quiz", err)
 		return
 	}
 
 	response := utils.SuccessResponse("Quiz successfully deleted", struct{}{})
 	c.JSON(http.StatusCreated, response)
 }
+
+
+func toQuizAttemptResponse(attempt models.QuizAttempt) dto.QuizAttemptResponseDTO {
+	return dto.QuizAttemptResponseDTO{
+		ID:         attempt.ID,
+		QuizID:     attempt.QuizID,
+		QuizName:   attempt.Quiz.Name,
+		Score:      attempt.Score,
+		MaxScore:   attempt.MaxScore,
+		Percentage: attempt.Percentage,
+		Passed:     attempt.Passed,
+		CreatedAt:  attempt.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// toQuizAttemptReviewResponse menambahkan koreksi per soal (kunci jawaban & pembahasan).
+// Error jika jawaban tersimpan tidak bisa dibaca, agar koreksi tidak tampil dengan pilihan kosong.
+func toQuizAttemptReviewResponse(attempt models.QuizAttempt, quiz models.Quiz) (dto.QuizAttemptResponseDTO, error) {
+	response := toQuizAttemptResponse(attempt)
+	response.QuizName = quiz.Name
+
+	questions := make(map[uint]models.QuizQuestion, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		questions[question.ID] = question
+	}
+	for _, answer := range attempt.Answers {
+		selected := []uint{}
+		if err := json.Unmarshal(answer.SelectedOptionIDs, &selected); err != nil {
+			return dto.QuizAttemptResponseDTO{}, fmt.Errorf("jawaban soal %d tidak valid: %w", answer.QuestionID, err)
+		}
+		question := questions[answer.QuestionID]
+		response.Answers = append(response.Answers, dto.QuizAttemptAnswerResponseDTO{
+			QuestionID:        answer.QuestionID,
+			SelectedOptionIDs: selected,
+			CorrectOptionIDs:  services.QuizCorrectOptionIDs(question),
+			IsCorrect:         answer.IsCorrect,
+			Explanation:       question.Explanation,
+		})
+	}
+	return response, nil
+}
+
+func respondQuizError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrQuizNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrPatientAccessDenied):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrQuizHasAttempts):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(err.Error(), nil))
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(message, err.Error()))
+	}
+}
+
+// SubmitAttempt menilai jawaban kuis native dan mengembalikan koreksi per soal.
+// Endpoint: POST /api/v1/quizzes/:id/attempts
+func (h *QuizHandler) SubmitAttempt(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	var input dto.SubmitQuizAttemptDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID, _ := requester(c)
+	attempt, quiz, err := h.quizService.SubmitAttempt(userID, uint(id), input)
+	if err != nil {
+		respondQuizError(c, "Failed to submit quiz attempt", err)
+		return
+	}
+	response, err := toQuizAttemptReviewResponse(attempt, quiz)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to build quiz attempt review", err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Quiz attempt submitted successfully", response))
+}
+
+// GetHistory menampilkan riwayat pengerjaan & nilai terbaik per kuis.
+// Endpoint: GET /api/v1/quizzes/attempts dan /api/v1/quizzes/patients/:user_id/attempts (?quiz_id=)
+func (h *QuizHandler) GetHistory(c *gin.Context) {
+	patientID, ok := patientParam(c)
+	if !ok {
+		return
+	}
+	var quizID uint64
+	if raw := c.Query("quiz_id"); raw != "" {
+		var err error
+		if quizID, err = strconv.ParseUint(raw, 10, 32); err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid quiz_id format", err.Error()))
+			return
+		}
+	}
+
+	userID, userRole := requester(c)
+	histories, err := h.quizService.GetHistory(userID, userRole, patientID, uint(quizID))
+	if err != nil {
+		respondQuizError(c, "Failed to fetch quiz history", err)
+		return
+	}
+
+	responseDTOs := []dto.QuizAttemptHistoryDTO{}
+	for _, history := range histories {
+		historyDTO := dto.QuizAttemptHistoryDTO{
+			QuizID:         history.Quiz.ID,
+			QuizName:       history.Quiz.Name,
+			AttemptCount:   len(history.Attempts),
+			BestPercentage: history.BestPercentage,
+			Passed:         history.Passed,
+			Attempts:       []dto.QuizAttemptResponseDTO{},
+		}
+		for _, attempt := range history.Attempts {
+			historyDTO.Attempts = append(historyDTO.Attempts, toQuizAttemptResponse(attempt))
+		}
+		responseDTOs = append(responseDTOs, historyDTO)
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Quiz history fetched successfully", responseDTOs))
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/storage"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// StaticFileHandler menyajikan file dari BlobStore sehingga klien tidak perlu tahu backend
+// penyimpanannya: thumbnail edukasi publik di /static/..., dan media pengguna (foto profil)
+// hanya lewat URL bertanda tangan di /media/... (lihat storage.SignedURL).
+type StaticFileHandler struct {
+	store storage.BlobStore
+}
+
+func NewStaticFileHandler(store storage.BlobStore) *StaticFileHandler {
+	return &StaticFileHandler{store: store}
+}
+
+// Serve mengembalikan handler publik untuk satu prefix; parameter route *filepath adalah nama file
+// di prefix itu. Key selalu unik per upload sehingga aman di-cache sebagai immutable.
+func (h *StaticFileHandler) Serve(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := prefixedKey(c, prefix)
+		if !ok {
+			return
+		}
+		serveBlob(c, h.store, key, "", "public, max-age=31536000, immutable")
+	}
+}
+
+// ServeSigned seperti Serve, tetapi hanya melayani request dengan expires & signature yang valid.
+// Cache dibatasi private dan tidak melebihi sisa masa berlaku URL.
+func (h *StaticFileHandler) ServeSigned(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := prefixedKey(c, prefix)
+		if !ok {
+			return
+		}
+		remaining, err := storage.VerifySignature(key, c.Query("expires"), c.Query("signature"))
+		if err != nil {
+			c.JSON(http.StatusForbidden, utils.ErrorResponse("Invalid or expired media URL", err.Error()))
+			return
+		}
+		serveBlob(c, h.store, key, "", fmt.Sprintf("private, max-age=%d", int(remaining.Seconds())))
+	}
+}
+
+func prefixedKey(c *gin.Context, prefix string) (string, bool) {
+	key := path.Join(prefix, strings.TrimPrefix(c.Param("filepath"), "/"))
+	if !strings.HasPrefix(key, prefix+"/") {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("File not found", nil))
+		return "", false
+	}
+	return key, true
+}
+
+// putUploadedFile menyimpan file multipart ke BlobStore. contentType kosong berarti
+// ditentukan dari ekstensi key.
+func putUploadedFile(c *gin.Context, store storage.BlobStore, file *multipart.FileHeader, key string, contentType string) error {
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("gagal membuka file '%s': %w", file.Filename, err)
+	}
+	defer src.Close()
+
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	if err := store.Put(c.Request.Context(), key, src, file.Size, contentType); err != nil {
+		return fmt.Errorf("gagal menyimpan file '%s': %w", file.Filename, err)
+	}
+	return nil
+}
+
+// storeUploadedImage memproses gambar unggahan lewat ImageService (decode, orientasi, buang metadata,
+// varian ukuran) dan mengembalikan key gambar utama. Response error sudah dikirim jika ok bernilai false.
+func storeUploadedImage(c *gin.Context, images services.ImageService, prefix string, file *multipart.FileHeader, field string) (string, bool) {
+	if file.Size > services.MaxImageUploadBytes {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(fmt.Sprintf("The %s exceeds the %dMB size limit", field, services.MaxImageUploadBytes/(1024*1024)), nil))
+		return "", false
+	}
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(fmt.Sprintf("Error processing %s file", field), err.Error()))
+		return "", false
+	}
+	defer src.Close()
+
+	key, err := images.Store(c.Request.Context(), prefix, src)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImageUnsupported):
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(fmt.Sprintf("The %s must be a JPEG, PNG, GIF or WebP image", field), err.Error()))
+		case errors.Is(err, services.ErrImageTooLarge):
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(fmt.Sprintf("The %s is too large", field), err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse(fmt.Sprintf("Failed to save %s", field), err.Error()))
+		}
+		return "", false
+	}
+	log.Printf("Gambar '%s' berhasil diproses dan disimpan sebagai '%s'", file.Filename, key)
+	return key, true
+}
+
+// imageVariantURLs membangun URL setiap varian gambar dengan urlFor (storage.PublicURL atau
+// storage.SignedURL). Gambar lama tanpa varian menghasilkan nil.
+func imageVariantURLs(key string, urlFor func(string) string) map[string]string {
+	variantKeys := services.ImageVariantKeys(key)
+	if variantKeys == nil {
+		return nil
+	}
+	urls := make(map[string]string, len(variantKeys))
+	for name, variantKey := range variantKeys {
+		urls[name] = urlFor(variantKey)
+	}
+	return urls
+}
+
+// deleteImage menghapus gambar utama beserta variannya (dipakai untuk membersihkan upload yang gagal).
+func deleteImage(store storage.BlobStore, key string) {
+	for _, objectKey := range services.ImageObjectKeys(key) {
+		if err := store.Delete(context.Background(), objectKey); err != nil {
+			log.Printf("WARNING: Gagal menghapus gambar '%s': %v", objectKey, err)
+		}
+	}
+}
+
+// serveBlob mengirim objek BlobStore dengan dukungan Range/If-Modified-Since lewat http.ServeContent.
+// contentType kosong berarti memakai content type yang tersimpan di backend.
+func serveBlob(c *gin.Context, store storage.BlobStore, key string, contentType string, cacheControl string) {
+	object, info, err := store.Open(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse("File not found", nil))
+		} else {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to read file", err.Error()))
+		}
+		return
+	}
+	defer object.Close()
+
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+	if contentType != "" {
+		c.Header("Content-Type", contentType)
+	}
+	c.Header("Cache-Control", cacheControl)
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime, object)
+}
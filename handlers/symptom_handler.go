@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SymptomHandler mengelola katalog gejala untuk keluhan.
+type SymptomHandler struct {
+	service services.SymptomService
+}
+
+func NewSymptomHandler(service services.SymptomService) *SymptomHandler {
+	return &SymptomHandler{service: service}
+}
+
+func toSymptomResponse(symptom models.Symptom) dto.SymptomResponseDTO {
+	return dto.SymptomResponseDTO{
+		ID:        symptom.ID,
+		Code:      symptom.Code,
+		Name:      symptom.Name,
+		Aliases:   services.SplitTriageCodes(symptom.Aliases),
+		Severity:  symptom.Severity,
+		IsRedFlag: symptom.IsRedFlag,
+		SortOrder: symptom.SortOrder,
+		IsActive:  symptom.IsActive,
+	}
+}
+
+// GetAll menangani GET /api/v1/symptoms (admin bisa menambah ?include_inactive=true)
+func (h *SymptomHandler) GetAll(c *gin.Context) {
+	includeInactive := c.Query("include_inactive") == "true" && c.GetString("userRole") == models.RoleAdmin
+	symptoms, err := h.service.FindAll(includeInactive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch symptoms", err.Error()))
+		return
+	}
+
+	responseDTOs := []dto.SymptomResponseDTO{}
+	for _, symptom := range symptoms {
+		responseDTOs = append(responseDTOs, toSymptomResponse(symptom))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Symptoms fetched successfully", responseDTOs))
+}
+
+func (h *SymptomHandler) Create(c *gin.Context) {
+	var input dto.CreateSymptomDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	symptom, err := h.service.Create(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to create symptom", err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Symptom created successfully", toSymptomResponse(symptom)))
+}
+
+func (h *SymptomHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	var input dto.SymptomDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	symptom, err := h.service.Update(uint(id), input)
+	if err != nil {
+		if errors.Is(err, services.ErrSymptomNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update symptom", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Symptom updated successfully", toSymptomResponse(symptom)))
+}
+
+// Delete menonaktifkan gejala; riwayat keluhan tetap menyimpan kodenya.
+func (h *SymptomHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		if errors.Is(err, services.ErrSymptomNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete symptom", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Symptom deactivated successfully", nil))
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// TriageRuleHandler mengelola aturan triase keluhan (khusus admin).
+type TriageRuleHandler struct {
+	service services.TriageService
+}
+
+func NewTriageRuleHandler(service services.TriageService) *TriageRuleHandler {
+	return &TriageRuleHandler{service: service}
+}
+
+func toTriageRuleResponse(rule models.TriageRule) dto.TriageRuleResponseDTO {
+	return dto.TriageRuleResponseDTO{
+		ID:             rule.ID,
+		Name:           rule.Name,
+		Priority:       rule.Priority,
+		TriageLevel:    rule.TriageLevel,
+		Message:        rule.Message,
+		RequireRedFlag: rule.RequireRedFlag,
+		MinSeverity:    rule.MinSeverity,
+		SymptomCodes:   services.SplitTriageCodes(rule.SymptomCodes),
+		MinCodeMatches: rule.MinCodeMatches,
+		MinCount:       rule.MinCount,
+		IsActive:       rule.IsActive,
+	}
+}
+
+func toTriageResultResponse(outcome services.TriageOutcome) dto.TriageResultDTO {
+	result := dto.TriageResultDTO{
+		TriageLevel:  outcome.Level,
+		Message:      outcome.Message,
+		Symptoms:     []dto.SymptomResponseDTO{},
+		Unrecognized: outcome.Unrecognized,
+	}
+	if outcome.Rule != nil {
+		result.MatchedRule = &outcome.Rule.Name
+	}
+	for _, symptom := range outcome.Symptoms {
+		result.Symptoms = append(result.Symptoms, toSymptomResponse(symptom))
+	}
+	return result
+}
+
+// GetAll menangani GET /api/v1/triage-rules (urut sesuai evaluasi)
+func (h *TriageRuleHandler) GetAll(c *gin.Context) {
+	rules, err := h.service.FindAllRules(c.Query("include_inactive") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch triage rules", err.Error()))
+		return
+	}
+
+	responseDTOs := []dto.TriageRuleResponseDTO{}
+	for _, rule := range rules {
+		responseDTOs = append(responseDTOs, toTriageRuleResponse(rule))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Triage rules fetched successfully", responseDTOs))
+}
+
+func (h *TriageRuleHandler) Create(c *gin.Context) {
+	var input dto.TriageRuleDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	rule, err := h.service.CreateRule(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to create triage rule", err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Triage rule created successfully", toTriageRuleResponse(rule)))
+}
+
+func (h *TriageRuleHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	var input dto.TriageRuleDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	rule, err := h.service.UpdateRule(uint(id), input)
+	if err != nil {
+		if errors.Is(err, services.ErrTriageRuleNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to update triage rule", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Triage rule updated successfully", toTriageRuleResponse(rule)))
+}
+
+func (h *TriageRuleHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	if err := h.service.DeleteRule(uint(id)); err != nil {
+		if errors.Is(err, services.ErrTriageRuleNotFound) {
+			c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete triage rule", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Triage rule deleted successfully", nil))
+}
+
+// Preview menangani POST /api/v1/triage-rules/preview: menguji aturan tanpa menyimpan keluhan.
+func (h *TriageRuleHandler) Preview(c *gin.Context) {
+	var input dto.CreateComplaintDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	outcome, err := h.service.Triage(input.Complaints)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to evaluate triage", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Triage evaluated successfully", toTriageResultResponse(outcome)))
+}
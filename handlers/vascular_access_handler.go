@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// VascularAccessHandler mengelola akses vaskular (AVF/AVG/CVC) dan pemeriksaan mandiri hariannya.
+type VascularAccessHandler struct {
+	service services.VascularAccessService
+}
+
+func NewVascularAccessHandler(service services.VascularAccessService) *VascularAccessHandler {
+	return &VascularAccessHandler{service: service}
+}
+
+func toVascularAccessResponse(access models.VascularAccess) dto.VascularAccessResponseDTO {
+	return dto.VascularAccessResponseDTO{
+		ID:         access.ID,
+		UserID:     access.UserID,
+		AccessType: access.AccessType,
+		Site:       access.Site,
+		CreatedOn:  access.CreatedOn.Format("2006-01-02"),
+		IsActive:   access.IsActive,
+		Notes:      access.Notes,
+	}
+}
+
+func toAccessSelfCheckResponse(result services.AccessCheckResult) dto.AccessSelfCheckResponseDTO {
+	check := result.Check
+	return dto.AccessSelfCheckResponseDTO{
+		ID:               check.ID,
+		VascularAccessID: check.VascularAccessID,
+		CheckDate:        check.CheckDate.Format("2006-01-02"),
+		ThrillPresent:    check.ThrillPresent,
+		Swelling:         check.Swelling,
+		Redness:          check.Redness,
+		Pain:             check.Pain,
+		Notes:            check.Notes,
+		Problems:         result.Problems,
+		Escalated:        check.ComplaintLogID != nil,
+		ComplaintID:      check.ComplaintLogID,
+		Advice:           result.Advice,
+	}
+}
+
+func respondVascularAccessError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrVascularAccessNotFound), errors.Is(err, services.ErrPatientNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(err.Error(), nil))
+	case errors.Is(err, services.ErrPatientAccessDenied):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(err.Error(), nil))
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(message, err.Error()))
+	}
+}
+
+// GetAll menangani GET /api/v1/vascular-access dan /api/v1/vascular-access/patients/:user_id
+func (h *VascularAccessHandler) GetAll(c *gin.Context) {
+	patientID, ok := patientParam(c)
+	if !ok {
+		return
+	}
+
+	userID, userRole := requester(c)
+	accesses, err := h.service.GetAccesses(userID, userRole, patientID)
+	if err != nil {
+		respondVascularAccessError(c, "Gagal mengambil akses vaskular", err)
+		return
+	}
+
+	responseDTOs := []dto.VascularAccessResponseDTO{}
+	for _, access := range accesses {
+		responseDTOs = append(responseDTOs, toVascularAccessResponse(access))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Akses vaskular berhasil diambil", responseDTOs))
+}
+
+// Create menangani POST /api/v1/vascular-access dan /api/v1/vascular-access/patients/:user_id
+func (h *VascularAccessHandler) Create(c *gin.Context) {
+	patientID, ok := patientParam(c)
+	if !ok {
+		return
+	}
+	var input dto.VascularAccessDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	access, err := h.service.CreateAccess(userID, userRole, patientID, input)
+	if err != nil {
+		respondVascularAccessError(c, "Gagal menyimpan akses vaskular", err)
+		return
+	}
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Akses vaskular berhasil disimpan", toVascularAccessResponse(access)))
+}
+
+// Update menangani PUT /api/v1/vascular-access/:id
+func (h *VascularAccessHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+	var input dto.VascularAccessDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	access, err := h.service.UpdateAccess(userID, userRole, uint(id), input)
+	if err != nil {
+		respondVascularAccessError(c, "Gagal memperbarui akses vaskular", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Akses vaskular berhasil diperbarui", toVascularAccessResponse(access)))
+}
+
+// SubmitCheck menangani POST /api/v1/vascular-access/:id/checks (pasien)
+func (h *VascularAccessHandler) SubmitCheck(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+	var input dto.AccessSelfCheckDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Validation failed", err.Error()))
+		return
+	}
+
+	userID, _ := requester(c)
+	result, err := h.service.SubmitSelfCheck(userID, uint(id), input)
+	if err != nil {
+		if errors.Is(err, services.ErrOutsideBackdateWindow) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error(), nil))
+			return
+		}
+		respondVascularAccessError(c, "Gagal menyimpan pemeriksaan akses", err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Pemeriksaan akses berhasil disimpan", toAccessSelfCheckResponse(result)))
+}
+
+// GetChecks menangani GET /api/v1/vascular-access/:id/checks?from=&to=
+func (h *VascularAccessHandler) GetChecks(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid ID format", err.Error()))
+		return
+	}
+
+	userID, userRole := requester(c)
+	results, err := h.service.GetSelfChecks(userID, userRole, uint(id), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondVascularAccessError(c, "Gagal mengambil pemeriksaan akses", err)
+		return
+	}
+
+	responseDTOs := []dto.AccessSelfCheckResponseDTO{}
+	for _, result := range results {
+		responseDTOs = append(responseDTOs, toAccessSelfCheckResponse(result))
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse("Pemeriksaan akses berhasil diambil", responseDTOs))
+}
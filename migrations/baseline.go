@@ -0,0 +1,129 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// baselineTable adalah satu CREATE TABLE di file baseline beserta definisi kolomnya.
+type baselineTable struct {
+	Name    string
+	Create  string
+	Columns []baselineColumn
+}
+
+type baselineColumn struct {
+	Name       string
+	Definition string // Baris definisi apa adanya, misal "`bp_before` varchar(20)"
+}
+
+// parseBaselineTables membaca tabel dan kolom dari statement CREATE TABLE di file baseline.
+// Baris kolom diawali nama dalam backtick; baris PRIMARY KEY, INDEX dan CONSTRAINT dilewati.
+func parseBaselineTables(script string) []baselineTable {
+	var tables []baselineTable
+	for _, statement := range splitStatements(script) {
+		if !strings.HasPrefix(statement, "CREATE TABLE `") {
+			continue
+		}
+		table := baselineTable{Name: backtickName(strings.TrimPrefix(statement, "CREATE TABLE ")), Create: statement}
+		for _, line := range strings.Split(statement, "\n")[1:] {
+			trimmed := strings.TrimSuffix(strings.TrimSpace(line), ",")
+			if !strings.HasPrefix(trimmed, "`") {
+				continue
+			}
+			table.Columns = append(table.Columns, baselineColumn{Name: backtickName(trimmed), Definition: trimmed})
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// backtickName mengambil nama pertama yang diapit backtick, misal "`users` (" menjadi "users".
+func backtickName(text string) string {
+	parts := strings.SplitN(text, "`", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+// schemaDrift adalah perbedaan antara database lama dan baseline.
+type schemaDrift struct {
+	missingTables  []baselineTable
+	missingColumns map[string][]baselineColumn // Per tabel yang sudah ada
+	extra          []string                    // Tabel/kolom yang tidak ada di baseline
+}
+
+// diffBaseline membandingkan tabel baseline dengan kolom yang ada di database.
+func diffBaseline(tables []baselineTable, existing map[string]map[string]bool) schemaDrift {
+	drift := schemaDrift{missingColumns: map[string][]baselineColumn{}}
+	known := map[string]map[string]bool{}
+	for _, table := range tables {
+		known[table.Name] = map[string]bool{}
+		columns, ok := existing[table.Name]
+		if !ok {
+			drift.missingTables = append(drift.missingTables, table)
+		}
+		for _, column := range table.Columns {
+			known[table.Name][column.Name] = true
+			if ok && !columns[column.Name] {
+				drift.missingColumns[table.Name] = append(drift.missingColumns[table.Name], column)
+			}
+		}
+	}
+	for table, columns := range existing {
+		if table == schemaMigrationsTable {
+			continue
+		}
+		if known[table] == nil {
+			drift.extra = append(drift.extra, fmt.Sprintf("tabel %s", table))
+			continue
+		}
+		for column := range columns {
+			if !known[table][column] {
+				drift.extra = append(drift.extra, fmt.Sprintf("kolom %s.%s", table, column))
+			}
+		}
+	}
+	sort.Strings(drift.extra)
+	return drift
+}
+
+// missingItems mengembalikan tabel/kolom baseline yang belum ada, untuk log dan pesan error.
+func (d schemaDrift) missingItems() []string {
+	var items []string
+	for _, table := range d.missingTables {
+		items = append(items, fmt.Sprintf("tabel %s", table.Name))
+	}
+	for _, table := range d.sortedColumnTables() {
+		for _, column := range d.missingColumns[table] {
+			items = append(items, fmt.Sprintf("kolom %s.%s", table, column.Name))
+		}
+	}
+	return items
+}
+
+// fillStatements menghasilkan DDL baseline untuk tabel dan kolom yang belum ada. Tabel dibuat
+// sesuai urutan di file baseline sehingga foreign key merujuk tabel yang sudah ada.
+func (d schemaDrift) fillStatements() []string {
+	var statements []string
+	for _, table := range d.missingTables {
+		statements = append(statements, table.Create)
+	}
+	for _, table := range d.sortedColumnTables() {
+		for _, column := range d.missingColumns[table] {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN %s", table, column.Definition))
+		}
+	}
+	return statements
+}
+
+func (d schemaDrift) sortedColumnTables() []string {
+	tables := make([]string, 0, len(d.missingColumns))
+	for table := range d.missingColumns {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
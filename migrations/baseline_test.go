@@ -0,0 +1,123 @@
+package migrations
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testBaseline = "-- baseline\n" +
+	"CREATE TABLE `users` (\n" +
+	"  `id` bigint unsigned AUTO_INCREMENT,\n" +
+	"  `email` varchar(255) NOT NULL,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  CONSTRAINT `uni_users_email` UNIQUE (`email`)\n" +
+	");\n\n" +
+	"CREATE TABLE `notes` (\n" +
+	"  `id` bigint unsigned AUTO_INCREMENT,\n" +
+	"  `user_id` bigint unsigned NOT NULL,\n" +
+	"  `body` text,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  INDEX `idx_notes_user_id` (`user_id`)\n" +
+	");\n"
+
+func TestParseBaselineTables(t *testing.T) {
+	tables := parseBaselineTables(testBaseline)
+	if len(tables) != 2 {
+		t.Fatalf("parseBaselineTables() returned %d tables, want 2", len(tables))
+	}
+
+	tests := []struct {
+		table   string
+		columns []baselineColumn
+	}{
+		{"users", []baselineColumn{
+			{"id", "`id` bigint unsigned AUTO_INCREMENT"},
+			{"email", "`email` varchar(255) NOT NULL"},
+		}},
+		{"notes", []baselineColumn{
+			{"id", "`id` bigint unsigned AUTO_INCREMENT"},
+			{"user_id", "`user_id` bigint unsigned NOT NULL"},
+			{"body", "`body` text"},
+		}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			if tables[i].Name != tt.table {
+				t.Fatalf("table %d name = %q, want %q", i, tables[i].Name, tt.table)
+			}
+			if !reflect.DeepEqual(tables[i].Columns, tt.columns) {
+				t.Errorf("columns = %q, want %q", tables[i].Columns, tt.columns)
+			}
+		})
+	}
+}
+
+func TestDiffBaseline(t *testing.T) {
+	tables := parseBaselineTables(testBaseline)
+	cols := func(names ...string) map[string]bool {
+		set := map[string]bool{}
+		for _, name := range names {
+			set[name] = true
+		}
+		return set
+	}
+
+	tests := []struct {
+		name      string
+		existing  map[string]map[string]bool
+		missing   []string
+		extra     []string
+		fillCount int
+	}{
+		{
+			name:     "identical schema",
+			existing: map[string]map[string]bool{"users": cols("id", "email"), "notes": cols("id", "user_id", "body")},
+		},
+		{
+			name:      "missing table",
+			existing:  map[string]map[string]bool{"users": cols("id", "email")},
+			missing:   []string{"tabel notes"},
+			fillCount: 1,
+		},
+		{
+			name:      "missing column",
+			existing:  map[string]map[string]bool{"users": cols("id"), "notes": cols("id", "user_id", "body")},
+			missing:   []string{"kolom users.email"},
+			fillCount: 1,
+		},
+		{
+			name: "extra table and column are reported but not missing",
+			existing: map[string]map[string]bool{
+				"users": cols("id", "email", "nickname"), "notes": cols("id", "user_id", "body"),
+				"old_logs": cols("id"), schemaMigrationsTable: cols("version"),
+			},
+			extra: []string{"kolom users.nickname", "tabel old_logs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := diffBaseline(tables, tt.existing)
+			if got := drift.missingItems(); !reflect.DeepEqual(got, tt.missing) {
+				t.Errorf("missingItems() = %q, want %q", got, tt.missing)
+			}
+			if !reflect.DeepEqual(drift.extra, tt.extra) {
+				t.Errorf("extra = %q, want %q", drift.extra, tt.extra)
+			}
+			if got := len(drift.fillStatements()); got != tt.fillCount {
+				t.Errorf("fillStatements() returned %d statements, want %d", got, tt.fillCount)
+			}
+		})
+	}
+}
+
+func TestDiffBaselineFillStatements(t *testing.T) {
+	drift := diffBaseline(parseBaselineTables(testBaseline), map[string]map[string]bool{"users": {"id": true}})
+	want := []string{
+		"CREATE TABLE `notes` (\n  `id` bigint unsigned AUTO_INCREMENT,\n  `user_id` bigint unsigned NOT NULL,\n  `body` text,\n  PRIMARY KEY (`id`),\n  INDEX `idx_notes_user_id` (`user_id`)\n)",
+		"ALTER TABLE `users` ADD COLUMN `email` varchar(255) NOT NULL",
+	}
+	if got := drift.fillStatements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("fillStatements() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var nonNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create membuat pasangan file migrasi kosong dengan versi berikutnya di dir (biasanya SourceDir)
+// dan mengembalikan path file up dan down.
+func Create(dir string, name string) (string, string, error) {
+	name = strings.Trim(nonNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("nama migrasi tidak boleh kosong")
+	}
+
+	existing, err := load(os.DirFS(dir), ".")
+	if err != nil {
+		return "", "", err
+	}
+	version := int64(1)
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%06d_%s", version, name)
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+	files := map[string]string{
+		upPath:   fmt.Sprintf("-- %s: perubahan skema.\n-- Setiap statement diakhiri \";\" di akhir baris.\n", base),
+		downPath: fmt.Sprintf("-- %s: membatalkan perubahan di file .up.sql.\n", base),
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return "", "", fmt.Errorf("gagal membuat file migrasi '%s': %w", path, err)
+		}
+	}
+	return upPath, downPath, nil
+}
@@ -0,0 +1,107 @@
+// Package migrations berisi migrasi skema database berversi (file SQL up/down di sql/) dan
+// runner-nya. Skema hanya diubah lewat `go run ./cmd/migrate up`; API dan worker menolak start
+// jika masih ada migrasi yang belum dijalankan.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SourceDir adalah lokasi file migrasi relatif terhadap root repo (dipakai `migrate create`).
+const SourceDir = "migrations/sql"
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// Nama file: <versi>_<nama>.up.sql / <versi>_<nama>.down.sql, misal 000002_add_user_bio.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration adalah satu versi skema beserta SQL untuk menerapkan dan membatalkannya.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// All mengembalikan semua migrasi yang dibundel di binary, urut berdasarkan versi.
+func All() ([]Migration, error) {
+	return load(sqlFiles, "sql")
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca direktori migrasi: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("nama file migrasi '%s' tidak valid (format: <versi>_<nama>.up.sql)", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca file migrasi '%s': %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("versi migrasi %d dipakai oleh dua nama: '%s' dan '%s'", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			return nil, fmt.Errorf("migrasi %06d_%s harus punya file .up.sql dan .down.sql yang berisi", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// splitStatements memecah isi file migrasi menjadi statement terpisah karena driver MySQL tidak
+// mengaktifkan multiStatements. Baris komentar "--" diabaikan, dan setiap statement harus diakhiri
+// ";" di akhir baris.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
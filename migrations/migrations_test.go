@@ -0,0 +1,159 @@
+package migrations
+
+import (
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "empty script",
+			script: "",
+			want:   nil,
+		},
+		{
+			name:   "comments and blank lines only",
+			script: "-- komentar\n\n   -- komentar lain\n",
+			want:   nil,
+		},
+		{
+			name:   "single statement",
+			script: "CREATE TABLE `a` (`id` bigint);\n",
+			want:   []string{"CREATE TABLE `a` (`id` bigint)"},
+		},
+		{
+			name:   "multi-line statements",
+			script: "-- header\nCREATE TABLE `a` (\n  `id` bigint\n);\n\nUPDATE `a`\nSET `id` = 1;\n",
+			want:   []string{"CREATE TABLE `a` (\n  `id` bigint\n)", "UPDATE `a`\nSET `id` = 1"},
+		},
+		{
+			name:   "semicolon inside a line is not a terminator",
+			script: "UPDATE `a` SET `note` = 'x;y' WHERE `id` = 1;\n",
+			want:   []string{"UPDATE `a` SET `note` = 'x;y' WHERE `id` = 1"},
+		},
+		{
+			name:   "comment lines inside a statement are dropped",
+			script: "UPDATE `a`\n-- hanya baris lama\nSET `id` = 1;\n",
+			want:   []string{"UPDATE `a`\nSET `id` = 1"},
+		},
+		{
+			name:   "trailing statement without semicolon",
+			script: "DROP TABLE `a`;\nDROP TABLE `b`",
+			want:   []string{"DROP TABLE `a`", "DROP TABLE `b`"},
+		},
+		{
+			name:   "windows line endings",
+			script: "DROP TABLE `a`;\r\nDROP TABLE `b`;\r\n",
+			want:   []string{"DROP TABLE `a`", "DROP TABLE `b`"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := func(content string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(content)} }
+
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int64
+		wantErr  string
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"sql/000010_later.up.sql":     file("SELECT 10;"),
+				"sql/000010_later.down.sql":   file("SELECT 10;"),
+				"sql/000002_earlier.up.sql":   file("SELECT 2;"),
+				"sql/000002_earlier.down.sql": file("SELECT 2;"),
+			},
+			versions: []int64{2, 10},
+		},
+		{
+			name:     "empty directory",
+			files:    fstest.MapFS{"sql": &fstest.MapFile{Mode: fs.ModeDir | 0o755}},
+			versions: []int64{},
+		},
+		{
+			name:    "invalid file name",
+			files:   fstest.MapFS{"sql/2_AddTable.up.sql": file("SELECT 1;")},
+			wantErr: "tidak valid",
+		},
+		{
+			name: "missing down file",
+			files: fstest.MapFS{
+				"sql/000001_baseline.up.sql": file("SELECT 1;"),
+			},
+			wantErr: "harus punya file .up.sql dan .down.sql",
+		},
+		{
+			name: "blank down file",
+			files: fstest.MapFS{
+				"sql/000001_baseline.up.sql":   file("SELECT 1;"),
+				"sql/000001_baseline.down.sql": file("  \n"),
+			},
+			wantErr: "harus punya file .up.sql dan .down.sql",
+		},
+		{
+			name: "same version with two names",
+			files: fstest.MapFS{
+				"sql/000001_first.up.sql":    file("SELECT 1;"),
+				"sql/000001_second.down.sql": file("SELECT 1;"),
+			},
+			wantErr: "dipakai oleh dua nama",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := load(tt.files, "sql")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("load() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load() unexpected error: %v", err)
+			}
+			versions := []int64{}
+			for _, migration := range got {
+				versions = append(versions, migration.Version)
+			}
+			if !reflect.DeepEqual(versions, tt.versions) {
+				t.Errorf("load() versions = %v, want %v", versions, tt.versions)
+			}
+		})
+	}
+}
+
+// Migrasi yang dibundel harus selalu bisa dimuat dan diawali baseline.
+func TestAllBundledMigrations(t *testing.T) {
+	all, err := All()
+	if err != nil {
+		t.Fatalf("All() error: %v", err)
+	}
+	if len(all) == 0 || all[0].Version != baselineVersion {
+		t.Fatalf("first bundled migration must be the baseline (version %d)", baselineVersion)
+	}
+	for i, migration := range all {
+		if want := int64(i + 1); migration.Version != want {
+			t.Errorf("migration %06d_%s: versions must be consecutive, want %d", migration.Version, migration.Name, want)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	schemaMigrationsTable = "schema_migrations"
+	// Nama lock MySQL (GET_LOCK) agar dua proses tidak menjalankan migrasi bersamaan.
+	lockName           = "tirtaapp_schema_migrations"
+	lockTimeoutSeconds = 60
+	// Versi migrasi skema awal (000001_baseline).
+	baselineVersion = 1
+)
+
+var (
+	ErrSchemaBehind      = errors.New("skema database belum terbaru, jalankan `go run ./cmd/migrate up`")
+	ErrSchemaDirty       = errors.New("migrasi sebelumnya gagal di tengah jalan (dirty); perbaiki skema secara manual lalu hapus baris versi tersebut di schema_migrations")
+	ErrLockTimeout       = errors.New("gagal mendapatkan lock migrasi, kemungkinan migrasi lain sedang berjalan")
+	ErrNothingToRollback = errors.New("tidak ada migrasi yang bisa dibatalkan")
+	ErrLegacySchemaDrift = errors.New("skema database lama berbeda dari baseline")
+)
+
+// MigrationStatus adalah status satu versi migrasi di database.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt *time.Time
+	// Missing berarti versi tercatat di database tetapi tidak ada di binary ini (binary lebih lama).
+	Missing bool
+}
+
+type appliedRecord struct {
+	Version   int64
+	Name      string
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Migrator menjalankan migrasi berversi dan mencatatnya di tabel schema_migrations.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	// FillLegacyDrift mengizinkan adopsi database lama yang belum punya sebagian tabel/kolom
+	// baseline: yang kurang dibuat dengan DDL dari file baseline. Jika false, adopsi gagal.
+	FillLegacyDrift bool
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up menjalankan migrasi yang belum diterapkan secara berurutan. steps <= 0 berarti semuanya.
+func (m *Migrator) Up(ctx context.Context, steps int) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		records, err := m.prepare(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			if records, err = m.adoptLegacySchema(ctx, conn); err != nil {
+				return err
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, done := records[migration.Version]; done {
+				continue
+			}
+			if steps > 0 && len(applied) >= steps {
+				break
+			}
+			log.Printf("Menjalankan migrasi %06d_%s (up)...", migration.Version, migration.Name)
+			if err := m.applyUp(ctx, conn, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down membatalkan migrasi terakhir yang sudah diterapkan. steps <= 0 dianggap 1.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		records, err := m.prepare(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, done := records[migration.Version]; !done {
+				continue
+			}
+			log.Printf("Membatalkan migrasi %06d_%s (down)...", migration.Version, migration.Name)
+			if err := m.applyDown(ctx, conn, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		if len(reverted) == 0 {
+			return ErrNothingToRollback
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status mengembalikan status setiap versi: yang dibundel di binary dan yang tercatat di database.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	records, err := m.appliedRecords(ctx, sqlDB)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	known := map[int64]bool{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := records[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied, status.Dirty, status.AppliedAt = true, record.Dirty, &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range records {
+		if !known[record.Version] {
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, MigrationStatus{Version: record.Version, Name: record.Name, Applied: true, Dirty: record.Dirty, AppliedAt: &appliedAt, Missing: true})
+		}
+	}
+	return statuses, nil
+}
+
+// CheckUpToDate dipanggil API dan worker saat start: mengembalikan ErrSchemaBehind jika masih ada
+// migrasi yang belum diterapkan, atau ErrSchemaDirty jika ada migrasi yang gagal di tengah jalan.
+// Database yang lebih baru dari binary (misal saat rollback deploy) tetap diizinkan.
+func (m *Migrator) CheckUpToDate(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, status := range statuses {
+		if status.Dirty {
+			return fmt.Errorf("%w (versi %d)", ErrSchemaDirty, status.Version)
+		}
+		if !status.Applied {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: %d migrasi belum dijalankan", ErrSchemaBehind, pending)
+	}
+	return nil
+}
+
+// withLock menjalankan fn dengan satu koneksi yang memegang lock GET_LOCK MySQL. Lock terikat
+// ke koneksi, jadi seluruh statement migrasi dijalankan lewat koneksi yang sama.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("gagal membuka koneksi migrasi: %w", err)
+	}
+	defer conn.Close()
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, lockTimeoutSeconds).Scan(&acquired); err != nil {
+		return fmt.Errorf("gagal mengambil lock migrasi: %w", err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return ErrLockTimeout
+	}
+	defer func() {
+		var released sql.NullInt64
+		if err := conn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName).Scan(&released); err != nil {
+			log.Printf("WARNING: Gagal melepas lock migrasi: %v", err)
+		}
+	}()
+
+	return fn(conn)
+}
+
+// prepare memastikan tabel schema_migrations ada dan tidak ada migrasi yang dirty.
+func (m *Migrator) prepare(ctx context.Context, conn *sql.Conn) (map[int64]appliedRecord, error) {
+	_, err := conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `"+schemaMigrationsTable+"` ("+
+		"`version` bigint NOT NULL,"+
+		"`name` varchar(255) NOT NULL,"+
+		"`dirty` boolean NOT NULL DEFAULT false,"+
+		"`applied_at` datetime(3) NOT NULL,"+
+		"PRIMARY KEY (`version`))")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat tabel %s: %w", schemaMigrationsTable, err)
+	}
+	records, err := m.appliedRecords(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.Dirty {
+			return nil, fmt.Errorf("%w (versi %d)", ErrSchemaDirty, record.Version)
+		}
+	}
+	return records, nil
+}
+
+func (m *Migrator) appliedRecords(ctx context.Context, q queryer) (map[int64]appliedRecord, error) {
+	exists, err := tableExists(ctx, q, schemaMigrationsTable)
+	if err != nil || !exists {
+		return map[int64]appliedRecord{}, err
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT `version`, `name`, `dirty`, `applied_at` FROM `"+schemaMigrationsTable+"`")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca %s: %w", schemaMigrationsTable, err)
+	}
+	defer rows.Close()
+
+	records := map[int64]appliedRecord{}
+	for rows.Next() {
+		var record appliedRecord
+		if err := rows.Scan(&record.Version, &record.Name, &record.Dirty, &record.AppliedAt); err != nil {
+			return nil, fmt.Errorf("gagal membaca %s: %w", schemaMigrationsTable, err)
+		}
+		records[record.Version] = record
+	}
+	return records, rows.Err()
+}
+
+// adoptLegacySchema menangani database yang dibuat sebelum migrasi berversi: tabel aplikasi sudah
+// ada tetapi schema_migrations masih kosong. Tabel dan kolom dibandingkan dengan file baseline;
+// perbedaannya selalu dicatat di log. Tabel/kolom baseline yang belum ada hanya dibuat jika
+// FillLegacyDrift aktif, selain itu adopsi gagal. Setelah itu baseline dicatat sebagai sudah
+// diterapkan tanpa menjalankan CREATE TABLE.
+func (m *Migrator) adoptLegacySchema(ctx context.Context, conn *sql.Conn) (map[int64]appliedRecord, error) {
+	records := map[int64]appliedRecord{}
+	legacy, err := tableExists(ctx, conn, "users")
+	if err != nil || !legacy {
+		return records, err
+	}
+	if len(m.migrations) == 0 || m.migrations[0].Version != baselineVersion {
+		return nil, fmt.Errorf("database lama ditemukan tetapi migrasi baseline (versi %d) tidak ada", baselineVersion)
+	}
+
+	baseline := m.migrations[0]
+	log.Printf("Database lama tanpa %s ditemukan, mengadopsi skema sebagai %06d_%s...", schemaMigrationsTable, baseline.Version, baseline.Name)
+	existing, err := existingColumns(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	drift := diffBaseline(parseBaselineTables(baseline.Up), existing)
+	for _, extra := range drift.extra {
+		log.Printf("WARNING: Drift skema: %s tidak ada di baseline dan dibiarkan", extra)
+	}
+	if missing := drift.missingItems(); len(missing) > 0 {
+		for _, item := range missing {
+			log.Printf("WARNING: Drift skema: %s dari baseline belum ada", item)
+		}
+		if !m.FillLegacyDrift {
+			return nil, fmt.Errorf("%w: %s belum ada; lengkapi skema secara manual atau jalankan ulang dengan --fill-legacy-drift", ErrLegacySchemaDrift, strings.Join(missing, ", "))
+		}
+		for _, statement := range drift.fillStatements() {
+			if _, err := conn.ExecContext(ctx, statement); err != nil {
+				return nil, fmt.Errorf("gagal melengkapi skema lama: %w", err)
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	if _, err := conn.ExecContext(ctx, "INSERT INTO `"+schemaMigrationsTable+"` (`version`, `name`, `dirty`, `applied_at`) VALUES (?, ?, false, ?)", baseline.Version, baseline.Name, now); err != nil {
+		return nil, fmt.Errorf("gagal mencatat baseline: %w", err)
+	}
+	records[baseline.Version] = appliedRecord{Version: baseline.Version, Name: baseline.Name, AppliedAt: now}
+	return records, nil
+}
+
+// existingColumns mengembalikan kolom setiap tabel di database saat ini.
+func existingColumns(ctx context.Context, q queryer) (map[string]map[string]bool, error) {
+	rows, err := q.QueryContext(ctx, "SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = DATABASE()")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca kolom database: %w", err)
+	}
+	defer rows.Close()
+
+	tables := map[string]map[string]bool{}
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, fmt.Errorf("gagal membaca kolom database: %w", err)
+		}
+		if tables[table] == nil {
+			tables[table] = map[string]bool{}
+		}
+		tables[table][column] = true
+	}
+	return tables, rows.Err()
+}
+
+// applyUp menandai versi sebagai dirty sebelum dijalankan. DDL MySQL tidak transaksional, jadi jika
+// statement gagal di tengah jalan, tanda dirty mencegah migrasi berikutnya berjalan di atas skema setengah jadi.
+func (m *Migrator) applyUp(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	if _, err := conn.ExecContext(ctx, "INSERT INTO `"+schemaMigrationsTable+"` (`version`, `name`, `dirty`, `applied_at`) VALUES (?, ?, true, ?)", migration.Version, migration.Name, time.Now().UTC()); err != nil {
+		return fmt.Errorf("gagal mencatat migrasi %d: %w", migration.Version, err)
+	}
+	if err := execScript(ctx, conn, migration.Up); err != nil {
+		return fmt.Errorf("migrasi %06d_%s gagal: %w", migration.Version, migration.Name, err)
+	}
+	if _, err := conn.ExecContext(ctx, "UPDATE `"+schemaMigrationsTable+"` SET `dirty` = false, `applied_at` = ? WHERE `version` = ?", time.Now().UTC(), migration.Version); err != nil {
+		return fmt.Errorf("gagal mencatat migrasi %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+func (m *Migrator) applyDown(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	if _, err := conn.ExecContext(ctx, "UPDATE `"+schemaMigrationsTable+"` SET `dirty` = true WHERE `version` = ?", migration.Version); err != nil {
+		return fmt.Errorf("gagal mencatat migrasi %d: %w", migration.Version, err)
+	}
+	if err := execScript(ctx, conn, migration.Down); err != nil {
+		return fmt.Errorf("pembatalan migrasi %06d_%s gagal: %w", migration.Version, migration.Name, err)
+	}
+	if _, err := conn.ExecContext(ctx, "DELETE FROM `"+schemaMigrationsTable+"` WHERE `version` = ?", migration.Version); err != nil {
+		return fmt.Errorf("gagal menghapus catatan migrasi %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+func execScript(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, statement := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tableExists(ctx context.Context, q queryer, table string) (bool, error) {
+	rows, err := q.QueryContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", table)
+	if err != nil {
+		return false, fmt.Errorf("gagal memeriksa tabel %s: %w", table, err)
+	}
+	defer rows.Close()
+	var count int
+	if rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return false, err
+		}
+	}
+	return count > 0, rows.Err()
+}
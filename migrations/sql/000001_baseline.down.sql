@@ -0,0 +1,38 @@
+-- Menghapus seluruh tabel skema awal (urutan terbalik agar foreign key tidak menghalangi).
+
+DROP TABLE IF EXISTS `education_media_uploads`;
+DROP TABLE IF EXISTS `education_triggers`;
+DROP TABLE IF EXISTS `education_progresses`;
+DROP TABLE IF EXISTS `education_views`;
+DROP TABLE IF EXISTS `quiz_attempt_answers`;
+DROP TABLE IF EXISTS `quiz_attempts`;
+DROP TABLE IF EXISTS `quiz_options`;
+DROP TABLE IF EXISTS `quiz_questions`;
+DROP TABLE IF EXISTS `complaint_attachments`;
+DROP TABLE IF EXISTS `complaint_replies`;
+DROP TABLE IF EXISTS `symptoms`;
+DROP TABLE IF EXISTS `access_self_checks`;
+DROP TABLE IF EXISTS `vascular_accesses`;
+DROP TABLE IF EXISTS `lab_results`;
+DROP TABLE IF EXISTS `lab_tests`;
+DROP TABLE IF EXISTS `intradialytic_readings`;
+DROP TABLE IF EXISTS `fluid_alerts`;
+DROP TABLE IF EXISTS `care_team_members`;
+DROP TABLE IF EXISTS `fluid_container_presets`;
+DROP TABLE IF EXISTS `fluid_entries`;
+DROP TABLE IF EXISTS `fluid_prescriptions`;
+DROP TABLE IF EXISTS `job_runs`;
+DROP TABLE IF EXISTS `medication_refill_schedules`;
+DROP TABLE IF EXISTS `hemodialysis_monitorings`;
+DROP TABLE IF EXISTS `fluid_balance_logs`;
+DROP TABLE IF EXISTS `devices`;
+DROP TABLE IF EXISTS `hemodialysis_schedules`;
+DROP TABLE IF EXISTS `control_schedules`;
+DROP TABLE IF EXISTS `drug_schedules`;
+DROP TABLE IF EXISTS `complaint_logs`;
+DROP TABLE IF EXISTS `triage_rules`;
+DROP TABLE IF EXISTS `education_tag_links`;
+DROP TABLE IF EXISTS `education_tags`;
+DROP TABLE IF EXISTS `educations`;
+DROP TABLE IF EXISTS `quizzes`;
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,565 @@
+-- Skema awal, sama dengan hasil GORM AutoMigrate sebelum migrasi berversi diperkenalkan.
+
+CREATE TABLE `users` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `email` varchar(255) NOT NULL,
+  `password` varchar(255) NOT NULL,
+  `profile_picture` varchar(255) DEFAULT null,
+  `phone_number` varchar(30) NOT NULL,
+  `role` varchar(50) NOT NULL DEFAULT 'user',
+  `timezone` varchar(100) NOT NULL DEFAULT 'Asia/Makassar',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `uni_users_email` UNIQUE (`email`)
+);
+
+CREATE TABLE `quizzes` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `type` varchar(20) NOT NULL DEFAULT 'url',
+  `url` longtext NOT NULL,
+  `description` text,
+  `passing_score` bigint NOT NULL DEFAULT 70,
+  `created_by` bigint unsigned NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_quizzes_user` FOREIGN KEY (`created_by`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `educations` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `description` text,
+  `category` varchar(30),
+  `featured_order` bigint,
+  `url` longtext NOT NULL,
+  `thumbnail` longtext NOT NULL,
+  `media_type` varchar(10) NOT NULL DEFAULT 'url',
+  `media_path` varchar(255),
+  `media_mime_type` varchar(100),
+  `media_size` bigint NOT NULL DEFAULT 0,
+  `created_by` bigint unsigned NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  FULLTEXT INDEX `idx_educations_fulltext` (`name`,`description`),
+  INDEX `idx_educations_category` (`category`),
+  INDEX `idx_educations_featured_order` (`featured_order`),
+  CONSTRAINT `fk_educations_user` FOREIGN KEY (`created_by`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `education_tags` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `name` varchar(50) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_education_tags_name` (`name`)
+);
+
+CREATE TABLE `education_tag_links` (
+  `education_id` bigint unsigned,
+  `education_tag_id` bigint unsigned,
+  PRIMARY KEY (`education_id`,`education_tag_id`),
+  CONSTRAINT `fk_education_tag_links_education` FOREIGN KEY (`education_id`) REFERENCES `educations`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_education_tag_links_education_tag` FOREIGN KEY (`education_tag_id`) REFERENCES `education_tags`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `triage_rules` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `priority` bigint NOT NULL DEFAULT 0,
+  `triage_level` varchar(20) NOT NULL,
+  `message` text NOT NULL,
+  `require_red_flag` boolean NOT NULL DEFAULT false,
+  `min_severity` varchar(20),
+  `symptom_codes` varchar(500),
+  `min_code_matches` bigint NOT NULL DEFAULT 0,
+  `min_count` bigint NOT NULL DEFAULT 0,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `complaint_logs` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `complaints` JSON NOT NULL,
+  `message` text NOT NULL,
+  `triage_level` varchar(20) NOT NULL DEFAULT 'routine',
+  `triage_rule_id` bigint unsigned,
+  `triage_rule_name` varchar(100),
+  `status` varchar(20) NOT NULL DEFAULT 'open',
+  `acknowledged_at` datetime(3) NULL,
+  `acknowledged_by` bigint unsigned,
+  `responded_at` datetime(3) NULL,
+  `closed_at` datetime(3) NULL,
+  `escalated_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_complaint_logs_triage_level` (`triage_level`),
+  INDEX `idx_complaint_logs_triage_rule_id` (`triage_rule_id`),
+  INDEX `idx_complaint_logs_status` (`status`),
+  CONSTRAINT `fk_complaint_logs_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_complaint_logs_triage_rule` FOREIGN KEY (`triage_rule_id`) REFERENCES `triage_rules`(`id`) ON DELETE SET NULL
+);
+
+CREATE TABLE `drug_schedules` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `drug_name` varchar(255) NOT NULL,
+  `dose` varchar(100) NOT NULL,
+  `schedule_date` date NOT NULL,
+  `at06` boolean NOT NULL DEFAULT false,
+  `at12` boolean NOT NULL DEFAULT false,
+  `at18` boolean NOT NULL DEFAULT false,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `at06_sent` boolean NOT NULL DEFAULT false,
+  `at12_sent` boolean NOT NULL DEFAULT false,
+  `at18_sent` boolean NOT NULL DEFAULT false,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_drug_schedules_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `control_schedules` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `control_date` date NOT NULL,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `notification_sent` boolean NOT NULL DEFAULT false,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_control_schedules_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `hemodialysis_schedules` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `schedule_date` date NOT NULL,
+  `monitoring_notification_sent` boolean NOT NULL DEFAULT false,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `notification_sent` boolean NOT NULL DEFAULT false,
+  `monitoring_status` varchar(20) NOT NULL DEFAULT 'pending',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_hemodialysis_schedules_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `devices` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `fcm_token` varchar(255) NOT NULL,
+  `device_type` varchar(50),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_devices_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `uni_devices_fcm_token` UNIQUE (`fcm_token`)
+);
+
+CREATE TABLE `fluid_balance_logs` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `log_date` date NOT NULL,
+  `intake_cc` bigint NOT NULL,
+  `output_cc` bigint NOT NULL,
+  `balance_cc` bigint NOT NULL,
+  `daily_limit_cc` bigint NOT NULL DEFAULT 0,
+  `warning_message` text,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_user_date` (`user_id`,`log_date`),
+  CONSTRAINT `fk_fluid_balance_logs_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `hemodialysis_monitorings` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `monitoring_date` date NOT NULL,
+  `hemodialysis_schedule_id` bigint unsigned,
+  `bp_before` varchar(20),
+  `bp_after` varchar(20),
+  `weight_before` decimal(5,2),
+  `weight_after` decimal(5,2),
+  `systolic_before` bigint,
+  `diastolic_before` bigint,
+  `pulse_before` bigint,
+  `systolic_after` bigint,
+  `diastolic_after` bigint,
+  `pulse_after` bigint,
+  `bp_needs_review` boolean NOT NULL DEFAULT false,
+  `uf_goal_ml` bigint,
+  `uf_achieved_ml` bigint,
+  `duration_minutes` bigint,
+  `dry_weight` decimal(5,2),
+  `symptoms` varchar(255),
+  `id_wg_kg` decimal(5,2),
+  `id_wg_percent` decimal(5,2),
+  `id_wg_exceeded` boolean NOT NULL DEFAULT false,
+  `id_wg_alert_sent_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_user_monitoring_date` (`user_id`,`monitoring_date`),
+  UNIQUE INDEX `idx_hemodialysis_monitorings_hemodialysis_schedule_id` (`hemodialysis_schedule_id`),
+  INDEX `idx_hemodialysis_monitorings_id_wg_exceeded` (`id_wg_exceeded`),
+  CONSTRAINT `fk_hemodialysis_monitorings_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_hemodialysis_monitorings_hemodialysis_schedule` FOREIGN KEY (`hemodialysis_schedule_id`) REFERENCES `hemodialysis_schedules`(`id`) ON DELETE SET NULL
+);
+
+CREATE TABLE `medication_refill_schedules` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `refill_date` date NOT NULL,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `notification_sent` boolean NOT NULL DEFAULT false,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_medication_refill_schedules_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `job_runs` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `job_name` varchar(100) NOT NULL,
+  `timezone` varchar(100),
+  `trigger` varchar(20) NOT NULL DEFAULT 'cron',
+  `status` varchar(20) NOT NULL DEFAULT 'running',
+  `started_at` datetime(3) NOT NULL,
+  `finished_at` datetime(3) NULL,
+  `items_processed` bigint NOT NULL DEFAULT 0,
+  `error_count` bigint NOT NULL DEFAULT 0,
+  `error_message` text,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_job_run_name_started` (`job_name`,`started_at`)
+);
+
+CREATE TABLE `fluid_prescriptions` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `effective_date` date NOT NULL,
+  `daily_limit_cc` bigint NOT NULL,
+  `residual_urine_cc` bigint,
+  `warning_percent` bigint NOT NULL DEFAULT 85,
+  `notes` text,
+  `prescribed_by` bigint unsigned NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_user_effective_date` (`user_id`,`effective_date`),
+  CONSTRAINT `fk_fluid_prescriptions_prescriber` FOREIGN KEY (`prescribed_by`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_fluid_prescriptions_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `fluid_entries` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `fluid_balance_log_id` bigint unsigned NOT NULL,
+  `log_date` date NOT NULL,
+  `recorded_at` datetime(3) NOT NULL,
+  `direction` varchar(10) NOT NULL,
+  `category` varchar(30) NOT NULL,
+  `volume_cc` bigint NOT NULL,
+  `container_preset_id` bigint unsigned,
+  `notes` text,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_fluid_entry_user_date` (`user_id`,`log_date`),
+  INDEX `idx_fluid_entries_fluid_balance_log_id` (`fluid_balance_log_id`),
+  CONSTRAINT `fk_fluid_entries_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_fluid_entries_fluid_balance_log` FOREIGN KEY (`fluid_balance_log_id`) REFERENCES `fluid_balance_logs`(`id`)
+);
+
+CREATE TABLE `fluid_container_presets` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `volume_cc` bigint NOT NULL,
+  `sort_order` bigint NOT NULL DEFAULT 0,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `uni_fluid_container_presets_name` UNIQUE (`name`)
+);
+
+CREATE TABLE `care_team_members` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `patient_id` bigint unsigned NOT NULL,
+  `member_id` bigint unsigned NOT NULL,
+  `relation` varchar(20) NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_care_team_pair` (`patient_id`,`member_id`),
+  INDEX `idx_care_team_members_member_id` (`member_id`),
+  CONSTRAINT `fk_care_team_members_patient` FOREIGN KEY (`patient_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_care_team_members_member` FOREIGN KEY (`member_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `fluid_alerts` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `log_date` date NOT NULL,
+  `level` varchar(20) NOT NULL,
+  `balance_cc` bigint NOT NULL,
+  `daily_limit_cc` bigint NOT NULL,
+  `escalated_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_fluid_alert_user_date_level` (`user_id`,`log_date`,`level`),
+  CONSTRAINT `fk_fluid_alerts_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `intradialytic_readings` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `hemodialysis_monitoring_id` bigint unsigned NOT NULL,
+  `recorded_at` datetime(3) NOT NULL,
+  `systolic` bigint NOT NULL,
+  `diastolic` bigint NOT NULL,
+  `pulse` bigint,
+  `blood_flow_rate` bigint,
+  `venous_pressure` bigint,
+  `arterial_pressure` bigint,
+  `uf_rate` bigint,
+  `notes` text,
+  `recorded_by` bigint unsigned NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_intradialytic_readings_hemodialysis_monitoring_id` (`hemodialysis_monitoring_id`),
+  CONSTRAINT `fk_intradialytic_readings_hemodialysis_monitoring` FOREIGN KEY (`hemodialysis_monitoring_id`) REFERENCES `hemodialysis_monitorings`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `lab_tests` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `code` varchar(50) NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `unit` varchar(30) NOT NULL,
+  `ref_low` decimal(10,2),
+  `ref_high` decimal(10,2),
+  `critical_low` decimal(10,2),
+  `critical_high` decimal(10,2),
+  `sort_order` bigint NOT NULL DEFAULT 0,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `uni_lab_tests_code` UNIQUE (`code`)
+);
+
+CREATE TABLE `lab_results` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `lab_test_id` bigint unsigned NOT NULL,
+  `sample_date` date NOT NULL,
+  `value` decimal(10,2) NOT NULL,
+  `entered_by` bigint unsigned NOT NULL,
+  `notes` text,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_lab_result_user_test_date` (`user_id`,`lab_test_id`,`sample_date`),
+  CONSTRAINT `fk_lab_results_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_lab_results_lab_test` FOREIGN KEY (`lab_test_id`) REFERENCES `lab_tests`(`id`) ON DELETE RESTRICT
+);
+
+CREATE TABLE `vascular_accesses` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `access_type` varchar(10) NOT NULL,
+  `site` varchar(100) NOT NULL,
+  `created_on` date NOT NULL,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `notes` text,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_vascular_accesses_user_id` (`user_id`),
+  CONSTRAINT `fk_vascular_accesses_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `access_self_checks` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `vascular_access_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `check_date` date NOT NULL,
+  `thrill_present` boolean,
+  `swelling` boolean NOT NULL DEFAULT false,
+  `redness` boolean NOT NULL DEFAULT false,
+  `pain` boolean NOT NULL DEFAULT false,
+  `notes` text,
+  `complaint_log_id` bigint unsigned,
+  `escalated_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_access_check_date` (`vascular_access_id`,`check_date`),
+  INDEX `idx_access_self_checks_user_id` (`user_id`),
+  CONSTRAINT `fk_access_self_checks_vascular_access` FOREIGN KEY (`vascular_access_id`) REFERENCES `vascular_accesses`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `symptoms` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `code` varchar(50) NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `severity` varchar(20) NOT NULL DEFAULT 'mild',
+  `is_red_flag` boolean NOT NULL DEFAULT false,
+  `sort_order` bigint NOT NULL DEFAULT 0,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `uni_symptoms_code` UNIQUE (`code`)
+);
+
+CREATE TABLE `complaint_replies` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `complaint_log_id` bigint unsigned NOT NULL,
+  `author_id` bigint unsigned NOT NULL,
+  `author_role` varchar(50) NOT NULL,
+  `message` text NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_complaint_replies_complaint_log_id` (`complaint_log_id`),
+  CONSTRAINT `fk_complaint_replies_author` FOREIGN KEY (`author_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_complaint_logs_replies` FOREIGN KEY (`complaint_log_id`) REFERENCES `complaint_logs`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `complaint_attachments` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `complaint_log_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `kind` varchar(10) NOT NULL,
+  `mime_type` varchar(100) NOT NULL,
+  `size_bytes` bigint NOT NULL,
+  `file_path` varchar(255) NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_complaint_attachments_complaint_log_id` (`complaint_log_id`),
+  INDEX `idx_complaint_attachments_user_id` (`user_id`),
+  CONSTRAINT `fk_complaint_logs_attachments` FOREIGN KEY (`complaint_log_id`) REFERENCES `complaint_logs`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `quiz_questions` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `quiz_id` bigint unsigned NOT NULL,
+  `position` bigint NOT NULL,
+  `type` varchar(20) NOT NULL,
+  `text` text NOT NULL,
+  `explanation` text,
+  PRIMARY KEY (`id`),
+  INDEX `idx_quiz_questions_quiz_id` (`quiz_id`),
+  CONSTRAINT `fk_quizzes_questions` FOREIGN KEY (`quiz_id`) REFERENCES `quizzes`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `quiz_options` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `question_id` bigint unsigned NOT NULL,
+  `position` bigint NOT NULL,
+  `text` varchar(500) NOT NULL,
+  `is_correct` boolean NOT NULL DEFAULT false,
+  PRIMARY KEY (`id`),
+  INDEX `idx_quiz_options_question_id` (`question_id`),
+  CONSTRAINT `fk_quiz_questions_options` FOREIGN KEY (`question_id`) REFERENCES `quiz_questions`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `quiz_attempts` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `quiz_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `score` bigint NOT NULL,
+  `max_score` bigint NOT NULL,
+  `percentage` decimal(5,2) NOT NULL,
+  `passed` boolean NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_quiz_attempts_quiz_id` (`quiz_id`),
+  INDEX `idx_quiz_attempts_user_id` (`user_id`),
+  CONSTRAINT `fk_quiz_attempts_quiz` FOREIGN KEY (`quiz_id`) REFERENCES `quizzes`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_quiz_attempts_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `quiz_attempt_answers` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `attempt_id` bigint unsigned NOT NULL,
+  `question_id` bigint unsigned NOT NULL,
+  `selected_option_ids` JSON NOT NULL,
+  `is_correct` boolean NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_quiz_attempt_answers_attempt_id` (`attempt_id`),
+  CONSTRAINT `fk_quiz_attempts_answers` FOREIGN KEY (`attempt_id`) REFERENCES `quiz_attempts`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `education_views` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `education_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `viewed_at` datetime(3) NOT NULL,
+  `duration_seconds` bigint,
+  `percent_watched` bigint,
+  PRIMARY KEY (`id`),
+  INDEX `idx_education_views_education_id` (`education_id`),
+  INDEX `idx_education_views_user_id` (`user_id`),
+  CONSTRAINT `fk_education_views_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_education_views_education` FOREIGN KEY (`education_id`) REFERENCES `educations`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `education_progresses` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL,
+  `education_id` bigint unsigned NOT NULL,
+  `view_count` bigint NOT NULL DEFAULT 0,
+  `max_percent_watched` bigint NOT NULL DEFAULT 0,
+  `total_seconds` bigint NOT NULL DEFAULT 0,
+  `first_viewed_at` datetime(3) NULL,
+  `last_viewed_at` datetime(3) NULL,
+  `completed_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_education_progress_user_item` (`user_id`,`education_id`),
+  INDEX `idx_education_progresses_education_id` (`education_id`),
+  CONSTRAINT `fk_education_progresses_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_education_progresses_education` FOREIGN KEY (`education_id`) REFERENCES `educations`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `education_triggers` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `education_id` bigint unsigned NOT NULL,
+  `trigger_type` varchar(30) NOT NULL,
+  `symptom_code` varchar(50) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_education_trigger` (`education_id`,`trigger_type`,`symptom_code`),
+  INDEX `idx_education_triggers_trigger_type` (`trigger_type`),
+  CONSTRAINT `fk_education_triggers_education` FOREIGN KEY (`education_id`) REFERENCES `educations`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `education_media_uploads` (
+  `id` varchar(32),
+  `created_by` bigint unsigned NOT NULL,
+  `file_name` varchar(255) NOT NULL,
+  `total_bytes` bigint NOT NULL,
+  `received_bytes` bigint NOT NULL DEFAULT 0,
+  `mime_type` varchar(100),
+  `status` varchar(20) NOT NULL DEFAULT 'uploading',
+  `temp_path` varchar(255) NOT NULL,
+  `expires_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_education_media_uploads_created_by` (`created_by`),
+  INDEX `idx_education_media_uploads_status` (`status`),
+  INDEX `idx_education_media_uploads_expires_at` (`expires_at`)
+);
@@ -0,0 +1,2 @@
+-- 000002_care_team_invitations: membatalkan perubahan di file .up.sql.
+ALTER TABLE `care_team_members` DROP COLUMN `accepted_at`;
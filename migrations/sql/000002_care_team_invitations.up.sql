@@ -0,0 +1,4 @@
+-- 000002_care_team_invitations: pendamping harus menerima undangan sebelum mendapat akses.
+-- Klinisi yang sudah ditugaskan dianggap diterima; pendamping lama harus menerima ulang undangannya.
+ALTER TABLE `care_team_members` ADD COLUMN `accepted_at` datetime(3) NULL AFTER `relation`;
+UPDATE `care_team_members` SET `accepted_at` = COALESCE(`created_at`, NOW(3)) WHERE `relation` = 'clinician';
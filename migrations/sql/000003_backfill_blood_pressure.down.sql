@@ -0,0 +1,2 @@
+-- 000003_backfill_blood_pressure: migrasi data, tidak dibatalkan. Kolom terstruktur dan tanda
+-- bp_needs_review dibiarkan karena teks BP asli tetap tersimpan di bp_before/bp_after.
@@ -0,0 +1,30 @@
+-- 000003_backfill_blood_pressure: mengisi kolom tekanan darah terstruktur dari teks BP lama.
+-- Hanya format baku yang diurai: "120/80", "120-80", "120/80 mmHg" dan "120/80/72" (angka ketiga = nadi).
+-- Nilai di luar rentang fisiologis (lihat utils.ValidateBloodPressure) dan teks lain ditandai
+-- bp_needs_review agar diperiksa petugas.
+UPDATE `hemodialysis_monitorings`
+SET `systolic_before` = CAST(REGEXP_SUBSTR(`bp_before`, '[0-9]+', 1, 1) AS UNSIGNED),
+    `diastolic_before` = CAST(REGEXP_SUBSTR(`bp_before`, '[0-9]+', 1, 2) AS UNSIGNED),
+    `pulse_before` = CAST(REGEXP_SUBSTR(`bp_before`, '[0-9]+', 1, 3) AS UNSIGNED)
+WHERE `bp_needs_review` = false AND `systolic_before` IS NULL
+  AND REGEXP_LIKE(TRIM(`bp_before`), '^[0-9]{2,3} *[/-] *[0-9]{2,3}( *mmHg| */ *[0-9]{2,3})?$', 'i');
+UPDATE `hemodialysis_monitorings`
+SET `systolic_after` = CAST(REGEXP_SUBSTR(`bp_after`, '[0-9]+', 1, 1) AS UNSIGNED),
+    `diastolic_after` = CAST(REGEXP_SUBSTR(`bp_after`, '[0-9]+', 1, 2) AS UNSIGNED),
+    `pulse_after` = CAST(REGEXP_SUBSTR(`bp_after`, '[0-9]+', 1, 3) AS UNSIGNED)
+WHERE `bp_needs_review` = false AND `systolic_after` IS NULL
+  AND REGEXP_LIKE(TRIM(`bp_after`), '^[0-9]{2,3} *[/-] *[0-9]{2,3}( *mmHg| */ *[0-9]{2,3})?$', 'i');
+UPDATE `hemodialysis_monitorings`
+SET `systolic_before` = NULL, `diastolic_before` = NULL, `pulse_before` = NULL, `bp_needs_review` = true
+WHERE `bp_before` <> '' AND `systolic_before` IS NOT NULL
+  AND (`systolic_before` NOT BETWEEN 60 AND 260 OR `diastolic_before` NOT BETWEEN 30 AND 160
+    OR `diastolic_before` >= `systolic_before` OR `pulse_before` NOT BETWEEN 30 AND 200);
+UPDATE `hemodialysis_monitorings`
+SET `systolic_after` = NULL, `diastolic_after` = NULL, `pulse_after` = NULL, `bp_needs_review` = true
+WHERE `bp_after` <> '' AND `systolic_after` IS NOT NULL
+  AND (`systolic_after` NOT BETWEEN 60 AND 260 OR `diastolic_after` NOT BETWEEN 30 AND 160
+    OR `diastolic_after` >= `systolic_after` OR `pulse_after` NOT BETWEEN 30 AND 200);
+UPDATE `hemodialysis_monitorings`
+SET `bp_needs_review` = true
+WHERE `bp_needs_review` = false
+  AND ((`bp_before` <> '' AND `systolic_before` IS NULL) OR (`bp_after` <> '' AND `systolic_after` IS NULL));
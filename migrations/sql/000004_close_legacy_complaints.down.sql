@@ -0,0 +1 @@
+-- 000004_close_legacy_complaints: migrasi data, tidak dibatalkan (status keluhan lama tidak tercatat).
@@ -0,0 +1,5 @@
+-- 000004_close_legacy_complaints: keluhan dari sebelum ada alur tindak lanjut (updated_at kosong)
+-- ditutup pada tanggal pembuatannya agar tidak masuk antrean petugas.
+UPDATE `complaint_logs`
+SET `status` = 'closed', `closed_at` = `created_at`, `updated_at` = `created_at`
+WHERE `updated_at` IS NULL;
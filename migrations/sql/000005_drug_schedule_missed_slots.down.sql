@@ -0,0 +1,4 @@
+-- 000005_drug_schedule_missed_slots: membatalkan perubahan di file .up.sql.
+ALTER TABLE `drug_schedules` DROP COLUMN `at18_missed`;
+ALTER TABLE `drug_schedules` DROP COLUMN `at12_missed`;
+ALTER TABLE `drug_schedules` DROP COLUMN `at06_missed`;
@@ -0,0 +1,4 @@
+-- 000005_drug_schedule_missed_slots: menyimpan slot obat yang terlewat saat jadwal ditutup.
+ALTER TABLE `drug_schedules` ADD COLUMN `at06_missed` boolean NOT NULL DEFAULT false;
+ALTER TABLE `drug_schedules` ADD COLUMN `at12_missed` boolean NOT NULL DEFAULT false;
+ALTER TABLE `drug_schedules` ADD COLUMN `at18_missed` boolean NOT NULL DEFAULT false;
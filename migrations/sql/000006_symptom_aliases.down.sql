@@ -0,0 +1,2 @@
+-- 000006_symptom_aliases: membatalkan perubahan di file .up.sql.
+ALTER TABLE `symptoms` DROP COLUMN `aliases`;
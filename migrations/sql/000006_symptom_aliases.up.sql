@@ -0,0 +1,16 @@
+-- 000006_symptom_aliases: sebutan lain gejala untuk mencocokkan teks keluhan bebas.
+ALTER TABLE `symptoms` ADD COLUMN `aliases` varchar(500);
+UPDATE `symptoms` SET `aliases` = 'sakit dada,dada sakit,dada nyeri' WHERE `code` = 'chest_pain' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'sesak,sesak nafas,susah napas,sulit bernapas' WHERE `code` = 'shortness_of_breath' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'pingsan,tidak sadar,hilang kesadaran' WHERE `code` = 'fainting' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'perdarahan akses,akses berdarah,cimino berdarah' WHERE `code` = 'access_bleeding' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'demam,menggigil,meriang,panas badan' WHERE `code` = 'fever' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'bengkak,sembab' WHERE `code` = 'edema' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'berdebar,deg degan' WHERE `code` = 'palpitations' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'pusing,pening,kliyengan' WHERE `code` = 'dizziness' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'eneg,enek' WHERE `code` = 'nausea' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'kram,keram' WHERE `code` = 'muscle_cramp' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'gatal' WHERE `code` = 'itching' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'lemas,lelah,capek' WHERE `code` = 'fatigue' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'nyeri kepala,kepala sakit' WHERE `code` = 'headache' AND `aliases` IS NULL;
+UPDATE `symptoms` SET `aliases` = 'susah tidur,tidak bisa tidur' WHERE `code` = 'insomnia' AND `aliases` IS NULL;
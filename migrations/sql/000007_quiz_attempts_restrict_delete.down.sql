@@ -0,0 +1,3 @@
+-- 000007_quiz_attempts_restrict_delete: membatalkan perubahan di file .up.sql.
+ALTER TABLE `quiz_attempts` DROP FOREIGN KEY `fk_quiz_attempts_quiz`;
+ALTER TABLE `quiz_attempts` ADD CONSTRAINT `fk_quiz_attempts_quiz` FOREIGN KEY (`quiz_id`) REFERENCES `quizzes`(`id`) ON DELETE CASCADE;
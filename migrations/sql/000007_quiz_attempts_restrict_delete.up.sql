@@ -0,0 +1,3 @@
+-- 000007_quiz_attempts_restrict_delete: kuis yang sudah dikerjakan tidak boleh menghapus riwayat nilai pasien.
+ALTER TABLE `quiz_attempts` DROP FOREIGN KEY `fk_quiz_attempts_quiz`;
+ALTER TABLE `quiz_attempts` ADD CONSTRAINT `fk_quiz_attempts_quiz` FOREIGN KEY (`quiz_id`) REFERENCES `quizzes`(`id`) ON DELETE RESTRICT;
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Relasi anggota tim perawatan terhadap pasien.
+const (
+	CareRelationCaregiver = "caregiver" // Keluarga/pendamping yang ikut menerima notifikasi
+	CareRelationClinician = "clinician" // Klinisi penanggung jawab pasien
+)
+
+// CareTeamMember menghubungkan pasien dengan pendamping atau klinisi yang ditugaskan.
+type CareTeamMember struct {
+	ID        uint   `gorm:"primaryKey"`
+	PatientID uint   `gorm:"not null;uniqueIndex:idx_care_team_pair"`
+	Patient   User   `gorm:"foreignKey:PatientID"`
+	MemberID  uint   `gorm:"not null;uniqueIndex:idx_care_team_pair;index"`
+	Member    User   `gorm:"foreignKey:MemberID"`
+	Relation  string `gorm:"size:20;not null"` // caregiver | clinician
+	// AcceptedAt kosong berarti undangan pendamping belum diterima: belum menerima notifikasi
+	// maupun akses data pasien. Klinisi yang ditugaskan langsung terisi.
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
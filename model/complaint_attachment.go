@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Jenis lampiran keluhan.
+const (
+	AttachmentKindImage = "image"
+	AttachmentKindAudio = "audio"
+)
+
+// ComplaintAttachment adalah foto atau rekaman suara yang dilampirkan pasien pada keluhan.
+// File disimpan di luar direktori statis dan hanya dilayani lewat endpoint yang memeriksa akses.
+type ComplaintAttachment struct {
+	ID             uint   `gorm:"primaryKey"`
+	ComplaintLogID uint   `gorm:"not null;index"`
+	UserID         uint   `gorm:"not null;index"`
+	Kind           string `gorm:"size:10;not null"`
+	MimeType       string `gorm:"size:100;not null"` // Hasil sniffing isi file, bukan header dari klien
+	SizeBytes      int64  `gorm:"not null"`
+	FilePath       string `gorm:"size:255;not null" json:"-"` // Key objek di BlobStore (prefix complaints/)
+	CreatedAt      time.Time
+}
@@ -6,11 +6,45 @@ import (
 	"gorm.io/datatypes"
 )
 
+// Status tindak lanjut keluhan.
+const (
+	ComplaintStatusOpen         = "open"         // Belum dilihat petugas
+	ComplaintStatusAcknowledged = "acknowledged" // Sudah diterima petugas, menunggu balasan
+	ComplaintStatusResponded    = "responded"    // Petugas sudah membalas
+	ComplaintStatusClosed       = "closed"
+)
+
 type ComplaintLog struct {
 	ID         uint           `gorm:"primaryKey"`
 	UserID     uint           `gorm:"not null"`
 	User       User           `gorm:"foreignKey:UserID" json:"-"`
 	Complaints datatypes.JSON `gorm:"not null"` // Menyimpan array keluhan
 	Message    string         `gorm:"type:text;not null"`
-	CreatedAt  time.Time
-}
\ No newline at end of file
+	// Hasil triase: tingkat dan aturan yang cocok (nama disalin agar tetap terbaca jika aturan diubah/dihapus)
+	TriageLevel    string      `gorm:"size:20;not null;default:'routine';index"`
+	TriageRuleID   *uint       `gorm:"index"`
+	TriageRule     *TriageRule `gorm:"foreignKey:TriageRuleID;constraint:OnDelete:SET NULL;" json:"-"`
+	TriageRuleName string      `gorm:"size:100"`
+	// Tindak lanjut oleh petugas
+	Status         string `gorm:"size:20;not null;default:'open';index"`
+	AcknowledgedAt *time.Time
+	AcknowledgedBy *uint
+	RespondedAt    *time.Time // Balasan petugas pertama
+	ClosedAt       *time.Time
+	EscalatedAt    *time.Time            // Eskalasi SLA karena belum diterima petugas
+	Replies        []ComplaintReply      `gorm:"foreignKey:ComplaintLogID;constraint:OnDelete:CASCADE;" json:",omitempty"`
+	Attachments    []ComplaintAttachment `gorm:"foreignKey:ComplaintLogID;constraint:OnDelete:CASCADE;" json:",omitempty"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ComplaintReply adalah satu pesan dalam percakapan keluhan antara pasien dan petugas.
+type ComplaintReply struct {
+	ID             uint   `gorm:"primaryKey"`
+	ComplaintLogID uint   `gorm:"not null;index"`
+	AuthorID       uint   `gorm:"not null"`
+	Author         User   `gorm:"foreignKey:AuthorID" json:"-"`
+	AuthorRole     string `gorm:"size:50;not null"`
+	Message        string `gorm:"type:text;not null"`
+	CreatedAt      time.Time
+}
@@ -16,6 +16,9 @@ type DrugSchedule struct {
 	At06Sent     bool      `gorm:"not null;default:false"`
 	At12Sent     bool      `gorm:"not null;default:false"`
 	At18Sent     bool      `gorm:"not null;default:false"`
+	At06Missed   bool      `gorm:"not null;default:false"`
+	At12Missed   bool      `gorm:"not null;default:false"`
+	At18Missed   bool      `gorm:"not null;default:false"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
\ No newline at end of file
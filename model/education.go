@@ -2,13 +2,44 @@ package models
 
 import "time"
 
+// Kategori konten edukasi.
+const (
+	EducationCategoryDiet       = "diet"
+	EducationCategoryFluid      = "fluid"
+	EducationCategoryMedication = "medication"
+	EducationCategoryAccessCare = "access_care"
+	EducationCategoryExercise   = "exercise"
+)
+
+// Sumber konten edukasi: tautan eksternal atau file yang di-host sendiri.
+const (
+	EducationMediaURL   = "url"
+	EducationMediaPDF   = "pdf"
+	EducationMediaVideo = "video"
+)
+
 type Education struct {
-	ID        uint      `gorm:"primaryKey"`
-	Name      string    `gorm:"size:255;not null"`
-	Url       string    `gorm:"not null"`
-	Thumbnail string    `gorm:"not null"`
-	CreatedBy uint      `gorm:"not null"`
-	User      User      `gorm:"foreignKey:CreatedBy" json:"-"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-}
\ No newline at end of file
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:255;not null;index:idx_educations_fulltext,class:FULLTEXT"`
+	Description string `gorm:"type:text;index:idx_educations_fulltext,class:FULLTEXT"`
+	Category    string `gorm:"size:30;index"` // Kosong = belum dikategorikan
+	// Urutan tampil di bagian unggulan (1 = paling atas); nil berarti tidak diunggulkan
+	FeaturedOrder *int           `gorm:"index"`
+	Tags          []EducationTag `gorm:"many2many:education_tag_links;constraint:OnDelete:CASCADE;"`
+	Url           string         `gorm:"not null"` // Kosong jika konten di-host sendiri (MediaType pdf/video)
+	Thumbnail     string         `gorm:"not null"` // Key objek di BlobStore (prefix educations/) atau URL eksternal
+	MediaType     string         `gorm:"size:10;not null;default:'url'"`
+	MediaPath     string         `gorm:"size:255" json:"-"` // Key objek PDF/video di BlobStore (prefix education_media/)
+	MediaMimeType string         `gorm:"size:100"`
+	MediaSize     int64          `gorm:"not null;default:0"` // Byte
+	CreatedBy     uint           `gorm:"not null"`
+	User          User           `gorm:"foreignKey:CreatedBy" json:"-"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// EducationTag adalah label bebas untuk konten edukasi (misal "kalium", "pemula").
+type EducationTag struct {
+	ID   uint   `gorm:"primaryKey"`
+	Name string `gorm:"size:50;not null;uniqueIndex"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// EducationView adalah satu kali pasien membuka konten edukasi, dengan durasi atau
+// persentase tontonan yang dilaporkan aplikasi.
+type EducationView struct {
+	ID              uint      `gorm:"primaryKey"`
+	EducationID     uint      `gorm:"not null;index"`
+	Education       Education `gorm:"foreignKey:EducationID;constraint:OnDelete:CASCADE;" json:"-"`
+	UserID          uint      `gorm:"not null;index"`
+	User            User      `gorm:"foreignKey:UserID" json:"-"`
+	ViewedAt        time.Time `gorm:"not null"`
+	DurationSeconds *int
+	PercentWatched  *int
+}
+
+// EducationProgress adalah ringkasan keterlibatan satu user pada satu konten edukasi.
+type EducationProgress struct {
+	ID                uint      `gorm:"primaryKey"`
+	UserID            uint      `gorm:"not null;uniqueIndex:idx_education_progress_user_item"`
+	User              User      `gorm:"foreignKey:UserID" json:"-"`
+	EducationID       uint      `gorm:"not null;uniqueIndex:idx_education_progress_user_item;index"`
+	Education         Education `gorm:"foreignKey:EducationID;constraint:OnDelete:CASCADE;" json:"-"`
+	ViewCount         int       `gorm:"not null;default:0"`
+	MaxPercentWatched int       `gorm:"not null;default:0"`
+	TotalSeconds      int       `gorm:"not null;default:0"`
+	FirstViewedAt     *time.Time
+	LastViewedAt      *time.Time
+	CompletedAt       *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
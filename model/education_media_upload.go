@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Status sesi upload media edukasi bertahap.
+const (
+	EducationUploadInProgress = "uploading"
+	EducationUploadCompleted  = "completed" // Semua byte diterima, siap dipasang ke edukasi
+)
+
+// EducationMediaUpload adalah sesi upload bertahap (chunked) untuk file PDF/video berukuran besar.
+// Klien mengirim potongan file berurutan dan dapat melanjutkan dari ReceivedBytes bila koneksi terputus.
+// Sesi dihapus setelah file dipasang ke edukasi atau saat kedaluwarsa.
+type EducationMediaUpload struct {
+	ID            string    `gorm:"primaryKey;size:32"` // Token acak, dipakai di URL upload
+	CreatedBy     uint      `gorm:"not null;index"`
+	FileName      string    `gorm:"size:255;not null"`
+	TotalBytes    int64     `gorm:"not null"`
+	ReceivedBytes int64     `gorm:"not null;default:0"`
+	MimeType      string    `gorm:"size:100"` // Hasil sniffing potongan pertama
+	Status        string    `gorm:"size:20;not null;default:'uploading';index"`
+	TempPath      string    `gorm:"size:255;not null" json:"-"`
+	ExpiresAt     time.Time `gorm:"index"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
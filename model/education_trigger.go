@@ -0,0 +1,19 @@
+package models
+
+// Pemicu rekomendasi edukasi berdasarkan data terbaru pasien.
+const (
+	EducationTriggerFluidOverLimit = "fluid_over_limit" // Keseimbangan cairan melewati batas harian
+	EducationTriggerHighIDWG       = "high_idwg"        // Kenaikan berat antar-sesi HD melewati ambang
+	EducationTriggerSymptom        = "symptom"          // Keluhan dengan kode gejala tertentu
+	EducationTriggerOnboarding     = "onboarding"       // Pasien baru
+)
+
+// EducationTrigger menandai konten edukasi yang relevan untuk kondisi tertentu.
+// SymptomCode hanya diisi untuk pemicu symptom.
+type EducationTrigger struct {
+	ID          uint      `gorm:"primaryKey"`
+	EducationID uint      `gorm:"not null;uniqueIndex:idx_education_trigger"`
+	Education   Education `gorm:"foreignKey:EducationID;constraint:OnDelete:CASCADE;" json:"-"`
+	TriggerType string    `gorm:"size:30;not null;uniqueIndex:idx_education_trigger;index"`
+	SymptomCode string    `gorm:"size:50;not null;default:'';uniqueIndex:idx_education_trigger"`
+}
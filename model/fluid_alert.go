@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Level peringatan batas cairan harian.
+const (
+	FluidAlertLevelWarning  = "warning"  // Mendekati batas
+	FluidAlertLevelExceeded = "exceeded" // Melebihi batas
+)
+
+// FluidAlert mencatat peringatan cairan yang sudah dikirim, maksimal satu per level per hari.
+type FluidAlert struct {
+	ID           uint       `gorm:"primaryKey"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_fluid_alert_user_date_level"`
+	User         User       `gorm:"foreignKey:UserID"`
+	LogDate      time.Time  `gorm:"type:date;not null;uniqueIndex:idx_fluid_alert_user_date_level"`
+	Level        string     `gorm:"size:20;not null;uniqueIndex:idx_fluid_alert_user_date_level"`
+	BalanceCC    int        `gorm:"not null"`
+	DailyLimitCC int        `gorm:"not null"`
+	EscalatedAt  *time.Time // Terisi jika klinisi ikut diberi tahu
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
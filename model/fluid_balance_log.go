@@ -10,6 +10,7 @@ type FluidBalanceLog struct {
 	IntakeCC       int       `gorm:"not null"`
 	OutputCC       int       `gorm:"not null"`
 	BalanceCC      int       `gorm:"not null"`
+	DailyLimitCC   int       `gorm:"not null;default:0"` // Batas harian yang berlaku saat log dihitung (0 = log lama)
 	WarningMessage string    `gorm:"type:text"` // Bisa null jika tidak ada warning
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// FluidContainerPreset adalah wadah standar (gelas, botol, mangkuk) beserta volumenya,
+// agar pasien tidak perlu menghitung cc sendiri.
+type FluidContainerPreset struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"size:100;not null;unique"`
+	VolumeCC  int    `gorm:"not null"`
+	SortOrder int    `gorm:"not null;default:0"`
+	IsActive  bool   `gorm:"not null;default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Arah cairan pada FluidEntry
+const (
+	FluidDirectionIntake = "intake"
+	FluidDirectionOutput = "output"
+)
+
+// Kategori cairan yang umum dicatat pasien hemodialisa
+const (
+	FluidCategoryWater  = "water"
+	FluidCategoryTea    = "tea"
+	FluidCategoryCoffee = "coffee"
+	FluidCategoryMilk   = "milk"
+	FluidCategorySoup   = "soup"
+	FluidCategoryFruit  = "fruit"
+	FluidCategoryUrine  = "urine"
+	FluidCategoryVomit  = "vomit"
+	FluidCategoryOther  = "other"
+)
+
+// FluidEntry adalah satu catatan cairan masuk/keluar. Total harian di FluidBalanceLog
+// selalu dihitung ulang dari entry-entry ini.
+type FluidEntry struct {
+	ID                uint            `gorm:"primaryKey"`
+	UserID            uint            `gorm:"not null;index:idx_fluid_entry_user_date"`
+	User              User            `gorm:"foreignKey:UserID" json:"-"`
+	FluidBalanceLogID uint            `gorm:"not null;index"`
+	FluidBalanceLog   FluidBalanceLog `gorm:"foreignKey:FluidBalanceLogID" json:"-"`
+	LogDate           time.Time       `gorm:"type:date;not null;index:idx_fluid_entry_user_date"`
+	RecordedAt        time.Time       `gorm:"not null"`
+	Direction         string          `gorm:"size:10;not null"` // intake | output
+	Category          string          `gorm:"size:30;not null"` // water, tea, soup, fruit, urine, ...
+	VolumeCC          int             `gorm:"not null"`
+	ContainerPresetID *uint
+	Notes             string `gorm:"type:text"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// FluidPrescription adalah batas cairan harian yang diresepkan klinisi untuk satu pasien.
+// Setiap perubahan disimpan sebagai baris baru; yang berlaku adalah EffectiveDate terbaru <= hari ini.
+type FluidPrescription struct {
+	ID              uint      `gorm:"primaryKey"`
+	UserID          uint      `gorm:"not null;uniqueIndex:idx_user_effective_date"`
+	User            User      `gorm:"foreignKey:UserID" json:"-"`
+	EffectiveDate   time.Time `gorm:"type:date;not null;uniqueIndex:idx_user_effective_date"`
+	DailyLimitCC    int       `gorm:"not null"`
+	ResidualUrineCC *int      // Opsional, dasar perhitungan 500 cc + urine residu
+	WarningPercent  int       `gorm:"not null;default:85"`
+	Notes           string    `gorm:"type:text"`
+	PrescribedBy    uint      `gorm:"not null"`
+	Prescriber      User      `gorm:"foreignKey:PrescribedBy" json:"-"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
@@ -2,15 +2,53 @@ package models
 
 import "time"
 
+// Gejala intradialitik yang dikenali aplikasi.
+const (
+	SymptomCramp       = "cramp"
+	SymptomHypotension = "hypotension"
+	SymptomNausea      = "nausea"
+	SymptomVomiting    = "vomiting"
+	SymptomHeadache    = "headache"
+	SymptomChestPain   = "chest_pain"
+	SymptomItching     = "itching"
+	SymptomDizziness   = "dizziness"
+	SymptomFatigue     = "fatigue"
+)
+
 type HemodialysisMonitoring struct {
 	ID             uint      `gorm:"primaryKey"`
 	UserID         uint      `gorm:"not null;uniqueIndex:idx_user_monitoring_date"` // Bagian dari unique index
 	User           User      `gorm:"foreignKey:UserID"`
 	MonitoringDate time.Time `gorm:"type:date;not null;uniqueIndex:idx_user_monitoring_date"` // Bagian dari unique index
-	BPBefore       string    `gorm:"type:varchar(20)"`
-	BPAfter        string    `gorm:"type:varchar(20)"`
-	WeightBefore   float64   `gorm:"type:decimal(5,2)"`
-	WeightAfter    float64   `gorm:"type:decimal(5,2)"`
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-}
\ No newline at end of file
+	// Sesi HD yang dipantau (null untuk data lama atau hari tanpa jadwal)
+	HemodialysisScheduleID *uint                 `gorm:"uniqueIndex"`
+	HemodialysisSchedule   *HemodialysisSchedule `gorm:"foreignKey:HemodialysisScheduleID;constraint:OnDelete:SET NULL;"`
+	BPBefore               string                `gorm:"type:varchar(20)"` // Format tampilan "120/80", diisi dari nilai terstruktur
+	BPAfter                string                `gorm:"type:varchar(20)"`
+	WeightBefore           float64               `gorm:"type:decimal(5,2)"`
+	WeightAfter            float64               `gorm:"type:decimal(5,2)"`
+
+	// Tekanan darah & nadi terstruktur (null untuk data lama yang belum/tidak bisa diparse)
+	SystolicBefore  *int
+	DiastolicBefore *int
+	PulseBefore     *int
+	SystolicAfter   *int
+	DiastolicAfter  *int
+	PulseAfter      *int
+	BPNeedsReview   bool `gorm:"not null;default:false"` // true jika BP lama tidak bisa diparse saat migrasi
+
+	UFGoalML        *int     // Target ultrafiltrasi
+	UFAchievedML    *int     // Ultrafiltrasi tercapai
+	DurationMinutes *int     // Lama sesi HD
+	DryWeight       *float64 `gorm:"type:decimal(5,2)"`
+	Symptoms        string   `gorm:"type:varchar(255)"` // Kode gejala dipisah koma, lihat konstanta Symptom*
+
+	// Interdialytic weight gain: berat sebelum HD dikurangi berat sesudah HD sesi sebelumnya
+	IDWGKg          *float64   `gorm:"type:decimal(5,2)"`
+	IDWGPercent     *float64   `gorm:"type:decimal(5,2)"`            // Persen terhadap berat kering (null jika berat kering belum diketahui)
+	IDWGExceeded    bool       `gorm:"not null;default:false;index"` // Penanda untuk ditinjau klinisi
+	IDWGAlertSentAt *time.Time // Push IDWG sudah dikirim
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -2,14 +2,22 @@ package models
 
 import "time"
 
+// Status pengisian pemantauan untuk satu jadwal HD.
+const (
+	MonitoringStatusPending  = "pending"  // Belum diisi, hari HD belum lewat
+	MonitoringStatusRecorded = "recorded" // Data pemantauan sudah ada
+	MonitoringStatusMissing  = "missing"  // Hari HD lewat tanpa data pemantauan
+)
+
 type HemodialysisSchedule struct {
-	ID           uint      `gorm:"primaryKey"`
-	UserID       uint      `gorm:"not null"`
-	User         User      `gorm:"foreignKey:UserID"`
-	ScheduleDate time.Time `gorm:"type:date;not null"`
-	MonitoringNotificationSent bool `gorm:"not null;default:false"`
-	IsActive     bool      `gorm:"not null;default:true"`
-	NotificationSent bool      `gorm:"not null;default:false"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-}
\ No newline at end of file
+	ID                         uint      `gorm:"primaryKey"`
+	UserID                     uint      `gorm:"not null"`
+	User                       User      `gorm:"foreignKey:UserID"`
+	ScheduleDate               time.Time `gorm:"type:date;not null"`
+	MonitoringNotificationSent bool      `gorm:"not null;default:false"`
+	IsActive                   bool      `gorm:"not null;default:true"`
+	NotificationSent           bool      `gorm:"not null;default:false"`
+	MonitoringStatus           string    `gorm:"size:20;not null;default:'pending'"` // pending | recorded | missing
+	CreatedAt                  time.Time
+	UpdatedAt                  time.Time
+}
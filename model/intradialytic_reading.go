@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// IntradialyticReading adalah satu pengukuran tanda vital selama sesi HD berlangsung.
+type IntradialyticReading struct {
+	ID                       uint                   `gorm:"primaryKey"`
+	HemodialysisMonitoringID uint                   `gorm:"not null;index"`
+	HemodialysisMonitoring   HemodialysisMonitoring `gorm:"foreignKey:HemodialysisMonitoringID;constraint:OnDelete:CASCADE;"`
+	RecordedAt               time.Time              `gorm:"not null"`
+	Systolic                 int                    `gorm:"not null"`
+	Diastolic                int                    `gorm:"not null"`
+	Pulse                    *int
+	BloodFlowRate            *int   // Qb, mL/menit
+	VenousPressure           *int   // mmHg
+	ArterialPressure         *int   // mmHg (umumnya negatif)
+	UFRate                   *int   // mL/jam
+	Notes                    string `gorm:"type:text"`
+	RecordedBy               uint   `gorm:"not null"` // User (perawat/klinisi) yang mencatat
+	CreatedAt                time.Time
+	UpdatedAt                time.Time
+}
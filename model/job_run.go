@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// JobRun menyimpan riwayat eksekusi satu job terjadwal di worker.
+type JobRun struct {
+	ID             uint      `gorm:"primaryKey"`
+	JobName        string    `gorm:"size:100;not null;index:idx_job_run_name_started"`
+	Timezone       string    `gorm:"size:100"`                           // Timezone user, atau SERVER_TIMEZONE untuk job global
+	Trigger        string    `gorm:"size:20;not null;default:'cron'"`    // cron | manual
+	Status         string    `gorm:"size:20;not null;default:'running'"` // running | success | failed
+	StartedAt      time.Time `gorm:"not null;index:idx_job_run_name_started"`
+	FinishedAt     *time.Time
+	ItemsProcessed int    `gorm:"not null;default:0"`
+	ErrorCount     int    `gorm:"not null;default:0"`
+	ErrorMessage   string `gorm:"type:text"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
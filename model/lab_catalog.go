@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Kode tes lab yang dipakai untuk perhitungan adekuasi dialisis (URR & Kt/V).
+const (
+	LabTestUreaPre  = "urea_pre"
+	LabTestUreaPost = "urea_post"
+)
+
+// LabTest adalah katalog pemeriksaan laboratorium beserta satuan, rentang rujukan,
+// dan ambang kritis. Ambang yang nil berarti tidak dievaluasi.
+type LabTest struct {
+	ID           uint     `gorm:"primaryKey"`
+	Code         string   `gorm:"size:50;not null;unique"`
+	Name         string   `gorm:"size:100;not null"`
+	Unit         string   `gorm:"size:30;not null"`
+	RefLow       *float64 `gorm:"type:decimal(10,2)"`
+	RefHigh      *float64 `gorm:"type:decimal(10,2)"`
+	CriticalLow  *float64 `gorm:"type:decimal(10,2)"`
+	CriticalHigh *float64 `gorm:"type:decimal(10,2)"`
+	SortOrder    int      `gorm:"not null;default:0"`
+	IsActive     bool     `gorm:"not null;default:true"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
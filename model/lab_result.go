@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Status hasil lab terhadap rentang rujukan & ambang kritis tes.
+const (
+	LabFlagNormal       = "normal"
+	LabFlagLow          = "low"
+	LabFlagHigh         = "high"
+	LabFlagCriticalLow  = "critical_low"
+	LabFlagCriticalHigh = "critical_high"
+)
+
+// LabResult adalah satu nilai pemeriksaan lab pasien pada tanggal sampel tertentu.
+// Satu tes hanya punya satu nilai per tanggal; input ulang menimpa nilai sebelumnya.
+type LabResult struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_lab_result_user_test_date"`
+	User       User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	LabTestID  uint      `gorm:"not null;uniqueIndex:idx_lab_result_user_test_date"`
+	LabTest    LabTest   `gorm:"foreignKey:LabTestID;constraint:OnDelete:RESTRICT;"`
+	SampleDate time.Time `gorm:"type:date;not null;uniqueIndex:idx_lab_result_user_test_date"` // Tanggal lokal pengambilan sampel
+	Value      float64   `gorm:"type:decimal(10,2);not null"`
+	EnteredBy  uint      `gorm:"not null"` // Pasien sendiri atau klinisi/admin
+	Notes      string    `gorm:"type:text"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -1,13 +1,78 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Jenis kuis: url = tautan kuis eksternal (format lama), native = soal dikelola di aplikasi.
+const (
+	QuizTypeURL    = "url"
+	QuizTypeNative = "native"
+)
+
+// Jenis soal kuis native.
+const (
+	QuestionTypeSingleChoice   = "single_choice"
+	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeTrueFalse      = "true_false"
+)
 
 type Quiz struct {
-	ID        uint      `gorm:"primaryKey"`
-	Name      string    `gorm:"size:255;not null"`
-	Url       string    `gorm:"not null"`
-	CreatedBy uint      `gorm:"not null"`
-	User      User      `gorm:"foreignKey:CreatedBy" json:"-"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-}
\ No newline at end of file
+	ID           uint           `gorm:"primaryKey"`
+	Name         string         `gorm:"size:255;not null"`
+	Type         string         `gorm:"size:20;not null;default:'url'"`
+	Url          string         `gorm:"not null"` // Kosong untuk kuis native
+	Description  string         `gorm:"type:text"`
+	PassingScore int            `gorm:"not null;default:70"` // Persentase minimal untuk lulus
+	CreatedBy    uint           `gorm:"not null"`
+	User         User           `gorm:"foreignKey:CreatedBy" json:"-"`
+	Questions    []QuizQuestion `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE;" json:",omitempty"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// QuizQuestion adalah satu soal kuis native, diurutkan dengan Position.
+type QuizQuestion struct {
+	ID          uint         `gorm:"primaryKey"`
+	QuizID      uint         `gorm:"not null;index"`
+	Position    int          `gorm:"not null"`
+	Type        string       `gorm:"size:20;not null"`
+	Text        string       `gorm:"type:text;not null"`
+	Explanation string       `gorm:"type:text" json:",omitempty"` // Ditampilkan setelah jawaban dikirim
+	Options     []QuizOption `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE;"`
+}
+
+type QuizOption struct {
+	ID         uint   `gorm:"primaryKey"`
+	QuestionID uint   `gorm:"not null;index"`
+	Position   int    `gorm:"not null"`
+	Text       string `gorm:"size:500;not null"`
+	IsCorrect  bool   `gorm:"not null;default:false" json:",omitempty"` // Kunci jawaban, hanya terlihat oleh admin
+}
+
+// QuizAttempt adalah satu kali pengerjaan kuis native beserta nilai yang dihitung server.
+type QuizAttempt struct {
+	ID         uint                `gorm:"primaryKey"`
+	QuizID     uint                `gorm:"not null;index"`
+	Quiz       Quiz                `gorm:"foreignKey:QuizID;constraint:OnDelete:RESTRICT;" json:"-"` // Riwayat nilai tidak boleh ikut terhapus
+	UserID     uint                `gorm:"not null;index"`
+	User       User                `gorm:"foreignKey:UserID" json:"-"`
+	Score      int                 `gorm:"not null"` // Jumlah soal yang dijawab benar
+	MaxScore   int                 `gorm:"not null"`
+	Percentage float64             `gorm:"type:decimal(5,2);not null"`
+	Passed     bool                `gorm:"not null"`
+	Answers    []QuizAttemptAnswer `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE;"`
+	CreatedAt  time.Time
+}
+
+// QuizAttemptAnswer menyimpan jawaban per soal. QuestionID sengaja tanpa foreign key agar
+// riwayat tetap utuh ketika admin mengganti soal kuis.
+type QuizAttemptAnswer struct {
+	ID                uint           `gorm:"primaryKey"`
+	AttemptID         uint           `gorm:"not null;index"`
+	QuestionID        uint           `gorm:"not null"`
+	SelectedOptionIDs datatypes.JSON `gorm:"not null"`
+	IsCorrect         bool           `gorm:"not null"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Tingkat keparahan gejala pada katalog.
+const (
+	SymptomSeverityMild     = "mild"
+	SymptomSeverityModerate = "moderate"
+	SymptomSeveritySevere   = "severe"
+)
+
+// Symptom adalah gejala pada katalog keluhan. IsRedFlag menandai gejala tanda bahaya
+// yang biasanya langsung memicu saran gawat darurat. Aliases berisi sebutan lain (dipisah koma)
+// yang ikut dicocokkan dengan teks keluhan pasien.
+type Symptom struct {
+	ID        uint   `gorm:"primaryKey"`
+	Code      string `gorm:"size:50;not null;unique"`
+	Name      string `gorm:"size:100;not null"`
+	Aliases   string `gorm:"size:500"`
+	Severity  string `gorm:"size:20;not null;default:'mild'"`
+	IsRedFlag bool   `gorm:"not null;default:false"`
+	SortOrder int    `gorm:"not null;default:0"`
+	IsActive  bool   `gorm:"not null;default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
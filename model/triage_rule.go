@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Tingkat triase keluhan, dari yang paling ringan. NeedsReview dipakai untuk keluhan teks bebas
+// yang tidak dikenali katalog sehingga harus dibaca petugas; tidak bisa dipilih oleh aturan.
+const (
+	TriageLevelSelfCare    = "self_care"
+	TriageLevelRoutine     = "routine"
+	TriageLevelNeedsReview = "needs_review"
+	TriageLevelUrgent      = "urgent"
+	TriageLevelEmergency   = "emergency"
+)
+
+// TriageRule adalah aturan triase keluhan. Aturan dievaluasi berurutan menurut Priority
+// (kecil lebih dulu) dan aturan pertama yang cocok menentukan tingkat triase & pesan.
+// Sebuah aturan cocok jika SEMUA kondisi yang diisi terpenuhi:
+//   - RequireRedFlag: ada minimal satu gejala tanda bahaya
+//   - MinSeverity: ada gejala dengan keparahan minimal ini
+//   - SymptomCodes: kode gejala (dipisah koma) yang harus muncul; MinCodeMatches 0 berarti semuanya
+//   - MinCount: jumlah keluhan minimal
+type TriageRule struct {
+	ID             uint   `gorm:"primaryKey"`
+	Name           string `gorm:"size:100;not null"`
+	Priority       int    `gorm:"not null;default:0"`
+	TriageLevel    string `gorm:"size:20;not null"`
+	Message        string `gorm:"type:text;not null"`
+	RequireRedFlag bool   `gorm:"not null;default:false"`
+	MinSeverity    string `gorm:"size:20"`
+	SymptomCodes   string `gorm:"size:500"`
+	MinCodeMatches int    `gorm:"not null;default:0"`
+	MinCount       int    `gorm:"not null;default:0"`
+	IsActive       bool   `gorm:"not null;default:true"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Jenis akses vaskular hemodialisa.
+const (
+	AccessTypeAVF = "AVF" // Fistula arteri-vena
+	AccessTypeAVG = "AVG" // Graft arteri-vena
+	AccessTypeCVC = "CVC" // Kateter vena sentral
+)
+
+// VascularAccess adalah akses vaskular yang dipakai pasien untuk HD.
+type VascularAccess struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;index"`
+	User       User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	AccessType string    `gorm:"size:10;not null"`
+	Site       string    `gorm:"size:100;not null"`  // Contoh: "lengan bawah kiri", "vena jugularis kanan"
+	CreatedOn  time.Time `gorm:"type:date;not null"` // Tanggal akses dibuat/dipasang
+	IsActive   bool      `gorm:"not null;default:true"`
+	Notes      string    `gorm:"type:text"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// AccessSelfCheck adalah pemeriksaan mandiri harian akses vaskular oleh pasien.
+type AccessSelfCheck struct {
+	ID               uint           `gorm:"primaryKey"`
+	VascularAccessID uint           `gorm:"not null;uniqueIndex:idx_access_check_date"`
+	VascularAccess   VascularAccess `gorm:"foreignKey:VascularAccessID;constraint:OnDelete:CASCADE;"`
+	UserID           uint           `gorm:"not null;index"`
+	CheckDate        time.Time      `gorm:"type:date;not null;uniqueIndex:idx_access_check_date"` // Tanggal lokal pasien
+	ThrillPresent    *bool          // Nil untuk CVC
+	Swelling         bool           `gorm:"not null;default:false"`
+	Redness          bool           `gorm:"not null;default:false"`
+	Pain             bool           `gorm:"not null;default:false"`
+	Notes            string         `gorm:"type:text"`
+	ComplaintLogID   *uint          // Keluhan yang dibuat otomatis saat eskalasi
+	EscalatedAt      *time.Time     // Waktu push ke klinisi terkirim
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type CareTeamRepository interface {
+	Create(member models.CareTeamMember) (models.CareTeamMember, error)
+	Delete(id uint) error
+	FindByID(id uint) (models.CareTeamMember, error)
+	FindByPatientID(patientID uint) ([]models.CareTeamMember, error)
+	FindByPatientAndRelation(patientID uint, relation string) ([]models.CareTeamMember, error)
+	FindByPatientAndMember(patientID, memberID uint) (models.CareTeamMember, error)
+	FindPatientIDsByMember(memberID uint, relation string) ([]uint, error)
+	FindPendingByMember(memberID uint) ([]models.CareTeamMember, error)
+	Accept(id uint, at time.Time) (bool, error)
+}
+
+type careTeamRepository struct {
+	db *gorm.DB
+}
+
+func NewCareTeamRepository(db *gorm.DB) CareTeamRepository {
+	return &careTeamRepository{db: db}
+}
+
+func (r *careTeamRepository) Create(member models.CareTeamMember) (models.CareTeamMember, error) {
+	err := r.db.Create(&member).Error
+	return member, err
+}
+
+func (r *careTeamRepository) Delete(id uint) error {
+	return r.db.Delete(&models.CareTeamMember{}, id).Error
+}
+
+func (r *careTeamRepository) FindByID(id uint) (models.CareTeamMember, error) {
+	var member models.CareTeamMember
+	err := r.db.Preload("Member").Preload("Patient").First(&member, id).Error
+	return member, err
+}
+
+// FindByPatientID mengambil seluruh tim perawatan pasien beserta data anggotanya.
+func (r *careTeamRepository) FindByPatientID(patientID uint) ([]models.CareTeamMember, error) {
+	var members []models.CareTeamMember
+	err := r.db.Preload("Member").Where("patient_id = ?", patientID).Order("relation asc, id asc").Find(&members).Error
+	return members, err
+}
+
+// FindByPatientAndRelation hanya mengambil anggota yang sudah menerima undangan.
+func (r *careTeamRepository) FindByPatientAndRelation(patientID uint, relation string) ([]models.CareTeamMember, error) {
+	var members []models.CareTeamMember
+	err := r.db.Where("patient_id = ? AND relation = ? AND accepted_at IS NOT NULL", patientID, relation).Find(&members).Error
+	return members, err
+}
+
+func (r *careTeamRepository) FindByPatientAndMember(patientID, memberID uint) (models.CareTeamMember, error) {
+	var member models.CareTeamMember
+	err := r.db.Where("patient_id = ? AND member_id = ?", patientID, memberID).First(&member).Error
+	return member, err
+}
+
+// FindPatientIDsByMember mengambil ID pasien yang ditangani seorang anggota tim (misal klinisi).
+func (r *careTeamRepository) FindPatientIDsByMember(memberID uint, relation string) ([]uint, error) {
+	patientIDs := []uint{}
+	err := r.db.Model(&models.CareTeamMember{}).
+		Where("member_id = ? AND relation = ? AND accepted_at IS NOT NULL", memberID, relation).
+		Pluck("patient_id", &patientIDs).Error
+	return patientIDs, err
+}
+
+// FindPendingByMember mengambil undangan yang belum diterima seorang user beserta data pasiennya.
+func (r *careTeamRepository) FindPendingByMember(memberID uint) ([]models.CareTeamMember, error) {
+	var members []models.CareTeamMember
+	err := r.db.Preload("Patient").Where("member_id = ? AND accepted_at IS NULL", memberID).Order("id asc").Find(&members).Error
+	return members, err
+}
+
+// Accept menandai undangan diterima. Mengembalikan false jika undangan sudah diterima sebelumnya.
+func (r *careTeamRepository) Accept(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&models.CareTeamMember{}).Where("id = ? AND accepted_at IS NULL", id).Update("accepted_at", at)
+	return result.RowsAffected == 1, result.Error
+}
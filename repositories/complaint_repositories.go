@@ -1,14 +1,25 @@
 package repositories
 
 import (
+	"time"
+
 	models "github.com/darmawguna/tirtaapp.git/model"
 	"gorm.io/gorm"
 )
 
 type ComplaintRepository interface {
 	Create(log models.ComplaintLog) (models.ComplaintLog, error)
+	Update(log models.ComplaintLog) (models.ComplaintLog, error)
 	FindByUserID(userID uint) ([]models.ComplaintLog, error)
 	FindByID(complaint_id uint) (models.ComplaintLog, error)
+	FindQueue(statuses []string, patientIDs []uint) ([]models.ComplaintLog, error)
+	FindUnacknowledgedBefore(triageLevel string, before time.Time) ([]models.ComplaintLog, error)
+	MarkEscalated(id uint, at time.Time) (bool, error)
+
+	FindAttachmentByID(complaintID uint, attachmentID uint) (models.ComplaintAttachment, error)
+
+	CreateReply(reply models.ComplaintReply) (models.ComplaintReply, error)
+	FindReplyByID(id uint) (models.ComplaintReply, error)
 }
 
 type complaintRepository struct {
@@ -24,14 +35,71 @@ func (r *complaintRepository) Create(log models.ComplaintLog) (models.ComplaintL
 	return log, err
 }
 
+// MarkEscalated menandai keluhan sudah dieskalasi hanya jika belum dieskalasi dan belum diterima petugas.
+// Mengembalikan false jika keluhan sudah diproses lebih dulu (misalnya baru saja di-acknowledge).
+func (r *complaintRepository) MarkEscalated(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&models.ComplaintLog{}).
+		Where("id = ? AND escalated_at IS NULL AND acknowledged_at IS NULL", id).
+		Update("escalated_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *complaintRepository) Update(log models.ComplaintLog) (models.ComplaintLog, error) {
+	err := r.db.Omit("Replies", "Attachments", "User", "TriageRule").Save(&log).Error
+	return log, err
+}
+
 func (r *complaintRepository) FindByUserID(userID uint) ([]models.ComplaintLog, error) {
 	var logs []models.ComplaintLog
-	err := r.db.Where("user_id = ?", userID).Order("created_at desc").Find(&logs).Error
+	err := r.db.Preload("Attachments").Where("user_id = ?", userID).Order("created_at desc").Find(&logs).Error
 	return logs, err
 }
 
+// FindByID mengambil keluhan beserta lampiran dan percakapannya (urut waktu).
 func (r *complaintRepository) FindByID(complaint_id uint) (models.ComplaintLog, error) {
 	var logs models.ComplaintLog
-	err := r.db.First(&logs, complaint_id).Error
+	err := r.db.Preload("Attachments").Preload("Replies", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at asc, id asc")
+	}).First(&logs, complaint_id).Error
+	return logs, err
+}
+
+// FindQueue mengambil antrean keluhan: tingkat triase tertinggi lebih dulu, lalu yang paling lama menunggu.
+// patientIDs nil berarti semua pasien (admin).
+func (r *complaintRepository) FindQueue(statuses []string, patientIDs []uint) ([]models.ComplaintLog, error) {
+	var logs []models.ComplaintLog
+	query := r.db.Preload("User").Where("status IN ?", statuses)
+	if patientIDs != nil {
+		query = query.Where("user_id IN ?", patientIDs)
+	}
+	err := query.Order("CASE triage_level WHEN 'emergency' THEN 0 WHEN 'urgent' THEN 1 WHEN 'needs_review' THEN 2 WHEN 'routine' THEN 3 ELSE 4 END").
+		Order("created_at asc").Find(&logs).Error
 	return logs, err
-}
\ No newline at end of file
+}
+
+// FindUnacknowledgedBefore mengambil keluhan berstatus open pada tingkat triase tertentu yang dibuat
+// sebelum batas waktu dan belum pernah dieskalasi.
+func (r *complaintRepository) FindUnacknowledgedBefore(triageLevel string, before time.Time) ([]models.ComplaintLog, error) {
+	var logs []models.ComplaintLog
+	err := r.db.Preload("User").
+		Where("status = ? AND triage_level = ? AND created_at < ? AND escalated_at IS NULL", models.ComplaintStatusOpen, triageLevel, before).
+		Find(&logs).Error
+	return logs, err
+}
+
+func (r *complaintRepository) CreateReply(reply models.ComplaintReply) (models.ComplaintReply, error) {
+	err := r.db.Create(&reply).Error
+	return reply, err
+}
+
+func (r *complaintRepository) FindReplyByID(id uint) (models.ComplaintReply, error) {
+	var reply models.ComplaintReply
+	err := r.db.First(&reply, id).Error
+	return reply, err
+}
+
+func (r *complaintRepository) FindAttachmentByID(complaintID uint, attachmentID uint) (models.ComplaintAttachment, error) {
+	var attachment models.ComplaintAttachment
+	err := r.db.Where("complaint_log_id = ?", complaintID).First(&attachment, attachmentID).Error
+	return attachment, err
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	models "github.com/darmawguna/tirtaapp.git/model"
 	"gorm.io/gorm"
 )
@@ -9,6 +11,7 @@ type DeviceRepository interface {
 	FindByToken(token string) (models.Device, error)
 	CreateOrUpdate(device models.Device) (models.Device, error)
 	FindAllByUserID(userID uint) ([]models.Device, error)
+	DeleteNotUpdatedSince(before time.Time) (int64, error)
 }
 
 type deviceRepository struct {
@@ -38,4 +41,11 @@ func (r *deviceRepository) FindAllByUserID(userID uint) ([]models.Device, error)
 	var devices []models.Device
 	err := r.db.Where("user_id = ?", userID).Find(&devices).Error
 	return devices, err
-}
\ No newline at end of file
+}
+
+// DeleteNotUpdatedSince menghapus token perangkat yang tidak diperbarui sejak waktu tertentu.
+// Mengembalikan jumlah baris yang dihapus.
+func (r *deviceRepository) DeleteNotUpdatedSince(before time.Time) (int64, error) {
+	result := r.db.Where("updated_at < ?", before).Delete(&models.Device{})
+	return result.RowsAffected, result.Error
+}
@@ -11,6 +11,7 @@ type DrugScheduleRepository interface {
 	FindByID(id uint) (models.DrugSchedule, error)
 	Update(schedule models.DrugSchedule) (models.DrugSchedule, error)
 	Delete(id uint) error
+	FindActiveBeforeDateByTimezone(date string, timezone string) ([]models.DrugSchedule, error)
 }
 
 type drugScheduleRepository struct {
@@ -49,4 +50,14 @@ func (r *drugScheduleRepository) Delete(id uint) error {
 	// Pastikan user hanya bisa menghapus jadwal miliknya (opsional, bisa juga di service)
 	// Untuk saat ini, kita sederhanakan.
 	return r.db.Delete(&schedule, id).Error
-}
\ No newline at end of file
+}
+
+// FindActiveBeforeDateByTimezone mengambil jadwal obat yang masih aktif tetapi tanggalnya
+// sudah lewat (sebelum date, format YYYY-MM-DD) untuk user dengan timezone tertentu.
+func (r *drugScheduleRepository) FindActiveBeforeDateByTimezone(date string, timezone string) ([]models.DrugSchedule, error) {
+	var schedules []models.DrugSchedule
+	err := r.db.Joins("JOIN users ON users.id = drug_schedules.user_id").
+		Where("drug_schedules.schedule_date < ? AND drug_schedules.is_active = ? AND users.timezone = ?", date, true, timezone).
+		Find(&schedules).Error
+	return schedules, err
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+// EducationAnalytics adalah agregat keterlibatan pasien (role user) per konten edukasi.
+type EducationAnalytics struct {
+	EducationID       uint
+	Name              string
+	UniqueViewers     int64
+	TotalViews        int64
+	Completions       int64
+	AvgPercentWatched float64
+}
+
+type EducationEngagementRepository interface {
+	CreateView(view models.EducationView) (models.EducationView, error)
+	FindProgress(userID uint, educationID uint) (models.EducationProgress, error)
+	RecordView(view models.EducationView, completionPercent int) (models.EducationProgress, error)
+	MarkCompleted(progressID uint, at time.Time) error
+	FindProgressByUser(userID uint) ([]models.EducationProgress, error)
+	FindAnalytics() ([]EducationAnalytics, error)
+}
+
+type educationEngagementRepository struct {
+	db *gorm.DB
+}
+
+func NewEducationEngagementRepository(db *gorm.DB) EducationEngagementRepository {
+	return &educationEngagementRepository{db: db}
+}
+
+func (r *educationEngagementRepository) CreateView(view models.EducationView) (models.EducationView, error) {
+	err := r.db.Create(&view).Error
+	return view, err
+}
+
+func (r *educationEngagementRepository) FindProgress(userID uint, educationID uint) (models.EducationProgress, error) {
+	var progress models.EducationProgress
+	err := r.db.Where("user_id = ? AND education_id = ?", userID, educationID).First(&progress).Error
+	return progress, err
+}
+
+// RecordView menyimpan tayangan dan menambahkannya ke ringkasan progres dalam satu transaksi.
+// Penambahan dilakukan di database (ON DUPLICATE KEY UPDATE) agar tayangan paralel tidak
+// saling menimpa. completed_at dihitung sebelum max_percent_watched diperbarui.
+func (r *educationEngagementRepository) RecordView(view models.EducationView, completionPercent int) (models.EducationProgress, error) {
+	var progress models.EducationProgress
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&view).Error; err != nil {
+			return err
+		}
+
+		seconds, percent := 0, 0
+		if view.DurationSeconds != nil {
+			seconds = *view.DurationSeconds
+		}
+		if view.PercentWatched != nil {
+			percent = *view.PercentWatched
+		}
+		var completedAt *time.Time
+		if percent >= completionPercent {
+			completedAt = &view.ViewedAt
+		}
+
+		err := tx.Exec("INSERT INTO education_progresses "+
+			"(user_id, education_id, view_count, max_percent_watched, total_seconds, first_viewed_at, last_viewed_at, completed_at, created_at, updated_at) "+
+			"VALUES (?, ?, 1, ?, ?, ?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE "+
+			"completed_at = COALESCE(completed_at, IF(GREATEST(max_percent_watched, VALUES(max_percent_watched)) >= ?, VALUES(last_viewed_at), NULL)), "+
+			"view_count = view_count + 1, "+
+			"total_seconds = total_seconds + VALUES(total_seconds), "+
+			"max_percent_watched = GREATEST(max_percent_watched, VALUES(max_percent_watched)), "+
+			"first_viewed_at = COALESCE(first_viewed_at, VALUES(first_viewed_at)), "+
+			"last_viewed_at = VALUES(last_viewed_at), "+
+			"updated_at = VALUES(updated_at)",
+			view.UserID, view.EducationID, percent, seconds, view.ViewedAt, view.ViewedAt, completedAt, view.ViewedAt, view.ViewedAt,
+			completionPercent).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND education_id = ?", view.UserID, view.EducationID).First(&progress).Error
+	})
+	return progress, err
+}
+
+// MarkCompleted mengisi completed_at hanya jika belum terisi.
+func (r *educationEngagementRepository) MarkCompleted(progressID uint, at time.Time) error {
+	return r.db.Model(&models.EducationProgress{}).
+		Where("id = ? AND completed_at IS NULL", progressID).
+		Updates(map[string]interface{}{"completed_at": at, "updated_at": at}).Error
+}
+
+func (r *educationEngagementRepository) FindProgressByUser(userID uint) ([]models.EducationProgress, error) {
+	var progress []models.EducationProgress
+	err := r.db.Where("user_id = ?", userID).Find(&progress).Error
+	return progress, err
+}
+
+// FindAnalytics menghitung penonton unik, total tayangan, penyelesaian dan rata-rata persentase
+// tontonan per edukasi. Hanya pasien yang dihitung agar pratinjau admin tidak mengacaukan angka.
+func (r *educationEngagementRepository) FindAnalytics() ([]EducationAnalytics, error) {
+	var rows []EducationAnalytics
+	patientProgress := r.db.Table("education_progresses").
+		Select("education_progresses.*").
+		Joins("JOIN users ON users.id = education_progresses.user_id").
+		Where("users.role = ?", models.RoleUser)
+	err := r.db.Table("educations").
+		Select("educations.id AS education_id, educations.name, "+
+			"COUNT(p.user_id) AS unique_viewers, COALESCE(SUM(p.view_count), 0) AS total_views, "+
+			"COUNT(p.completed_at) AS completions, COALESCE(AVG(p.max_percent_watched), 0) AS avg_percent_watched").
+		Joins("LEFT JOIN (?) AS p ON p.education_id = educations.id", patientProgress).
+		Group("educations.id, educations.name").
+		Order("unique_viewers desc, educations.id asc").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type EducationMediaUploadRepository interface {
+	Create(upload models.EducationMediaUpload) (models.EducationMediaUpload, error)
+	FindByID(id string) (models.EducationMediaUpload, error)
+	AdvanceReceived(upload models.EducationMediaUpload, fromBytes int64) (bool, error)
+	Delete(id string) error
+	FindExpired(before time.Time) ([]models.EducationMediaUpload, error)
+}
+
+type educationMediaUploadRepository struct {
+	db *gorm.DB
+}
+
+func NewEducationMediaUploadRepository(db *gorm.DB) EducationMediaUploadRepository {
+	return &educationMediaUploadRepository{db: db}
+}
+
+func (r *educationMediaUploadRepository) Create(upload models.EducationMediaUpload) (models.EducationMediaUpload, error) {
+	err := r.db.Create(&upload).Error
+	return upload, err
+}
+
+func (r *educationMediaUploadRepository) FindByID(id string) (models.EducationMediaUpload, error) {
+	var upload models.EducationMediaUpload
+	err := r.db.Where("id = ?", id).First(&upload).Error
+	return upload, err
+}
+
+// AdvanceReceived menyimpan progres (received_bytes, mime_type, status) hanya jika received_bytes
+// di database masih fromBytes. Mengembalikan false jika request lain sudah memajukan sesi lebih dulu.
+func (r *educationMediaUploadRepository) AdvanceReceived(upload models.EducationMediaUpload, fromBytes int64) (bool, error) {
+	result := r.db.Model(&models.EducationMediaUpload{}).
+		Where("id = ? AND received_bytes = ?", upload.ID, fromBytes).
+		Updates(map[string]interface{}{
+			"received_bytes": upload.ReceivedBytes,
+			"mime_type":      upload.MimeType,
+			"status":         upload.Status,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *educationMediaUploadRepository) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&models.EducationMediaUpload{}).Error
+}
+
+// FindExpired mengambil sesi yang sudah melewati masa berlaku tanpa dipasang ke edukasi.
+func (r *educationMediaUploadRepository) FindExpired(before time.Time) ([]models.EducationMediaUpload, error) {
+	var uploads []models.EducationMediaUpload
+	err := r.db.Where("expires_at < ?", before).Find(&uploads).Error
+	return uploads, err
+}
@@ -1,16 +1,46 @@
 package repositories
 
 import (
+	"fmt"
+	"strings"
+	"unicode"
+
 	models "github.com/darmawguna/tirtaapp.git/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Urutan daftar edukasi yang didukung FindAll.
+const (
+	EducationSortFeatured  = "featured"
+	EducationSortNewest    = "newest"
+	EducationSortOldest    = "oldest"
+	EducationSortName      = "name"
+	EducationSortRelevance = "relevance"
 )
 
+// Panjang kata minimum yang diindeks FULLTEXT InnoDB (innodb_ft_min_token_size).
+const fulltextMinTokenSize = 3
+
+// EducationFilter adalah parameter pencarian & paginasi daftar edukasi.
+type EducationFilter struct {
+	Query    string
+	Category string
+	Tag      string
+	Sort     string
+	Offset   int
+	Limit    int
+}
+
 type EducationRepository interface {
 	Create(education models.Education) (models.Education, error)
-	FindAll() ([]models.Education, error)
+	FindAll(filter EducationFilter) ([]models.Education, int64, error)
 	FindByID(id uint) (models.Education, error)
 	Update(education models.Education) (models.Education, error)
 	Delete(id uint) error
+	SetFeaturedOrder(educationIDs []uint) error
+	FindAllTags() ([]models.EducationTag, error)
+	FindOrCreateTags(names []string) ([]models.EducationTag, error)
 }
 
 type educationRepository struct {
@@ -26,23 +56,157 @@ func (r *educationRepository) Create(education models.Education) (models.Educati
 	return education, err
 }
 
-func (r *educationRepository) FindAll() ([]models.Education, error) {
+// FindAll mengambil satu halaman edukasi sesuai filter beserta total baris yang cocok.
+// Pencarian memakai indeks FULLTEXT (name, description) dengan pencocokan awalan kata.
+func (r *educationRepository) FindAll(filter EducationFilter) ([]models.Education, int64, error) {
+	searchTerms := fulltextTerms(filter.Query)
+	useFulltext := len(searchTerms) > 0
+
+	var total int64
+	if err := r.filteredEducations(filter, searchTerms).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := r.filteredEducations(filter, searchTerms)
+
+	switch filter.Sort {
+	case EducationSortNewest:
+		query = query.Order("created_at desc")
+	case EducationSortOldest:
+		query = query.Order("created_at asc")
+	case EducationSortName:
+		query = query.Order("name asc")
+	case EducationSortRelevance:
+		if useFulltext {
+			query = query.Order(clause.Expr{SQL: "MATCH(name, description) AGAINST (? IN BOOLEAN MODE) DESC", Vars: []interface{}{strings.Join(searchTerms, " ")}})
+		}
+		query = query.Order("created_at desc")
+	default:
+		query = query.Order("featured_order IS NULL").Order("featured_order asc").Order("created_at desc")
+	}
+
 	var educations []models.Education
-	err := r.db.Find(&educations).Error
-	return educations, err
+	err := query.Preload("Tags").Order("id desc").Offset(filter.Offset).Limit(filter.Limit).Find(&educations).Error
+	return educations, total, err
+}
+
+func (r *educationRepository) filteredEducations(filter EducationFilter, searchTerms []string) *gorm.DB {
+	query := r.db.Model(&models.Education{})
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Tag != "" {
+		query = query.Where("id IN (?)", r.db.Table("education_tag_links").
+			Select("education_tag_links.education_id").
+			Joins("JOIN education_tags ON education_tags.id = education_tag_links.education_tag_id").
+			Where("education_tags.name = ?", strings.ToLower(filter.Tag)))
+	}
+	if len(searchTerms) > 0 {
+		query = query.Where("MATCH(name, description) AGAINST (? IN BOOLEAN MODE)", strings.Join(searchTerms, " "))
+	} else if q := strings.TrimSpace(filter.Query); q != "" {
+		// Kata terlalu pendek untuk indeks FULLTEXT
+		like := "%" + escapeLike(q) + "%"
+		query = query.Where("(name LIKE ? OR description LIKE ?)", like, like)
+	}
+	return query
 }
 
 func (r *educationRepository) FindByID(id uint) (models.Education, error) {
 	var education models.Education
-	err := r.db.First(&education, id).Error
+	err := r.db.Preload("Tags").First(&education, id).Error
 	return education, err
 }
 
+// Update menyimpan edukasi dan mengganti daftar tag-nya.
 func (r *educationRepository) Update(education models.Education) (models.Education, error) {
-	err := r.db.Save(&education).Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tags", "User").Save(&education).Error; err != nil {
+			return err
+		}
+		return tx.Model(&education).Association("Tags").Replace(education.Tags)
+	})
 	return education, err
 }
 
 func (r *educationRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Education{}, id).Error
-}
\ No newline at end of file
+	return r.db.Select("Tags").Delete(&models.Education{ID: id}).Error
+}
+
+// SetFeaturedOrder menjadikan educationIDs sebagai daftar unggulan sesuai urutannya;
+// edukasi lain dikeluarkan dari daftar unggulan.
+func (r *educationRepository) SetFeaturedOrder(educationIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Education{}).Where("featured_order IS NOT NULL").Update("featured_order", nil).Error; err != nil {
+			return err
+		}
+		for i, id := range educationIDs {
+			result := tx.Model(&models.Education{}).Where("id = ?", id).Update("featured_order", i+1)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
+func (r *educationRepository) FindAllTags() ([]models.EducationTag, error) {
+	var tags []models.EducationTag
+	err := r.db.Order("name asc").Find(&tags).Error
+	return tags, err
+}
+
+// FindOrCreateTags mengembalikan tag dengan nama tersebut sesuai urutan masukan, membuat yang belum ada.
+// Nama yang sudah ada (termasuk yang dibuat request lain secara bersamaan) diabaikan saat insert lalu dibaca ulang.
+func (r *educationRepository) FindOrCreateTags(names []string) ([]models.EducationTag, error) {
+	if len(names) == 0 {
+		return []models.EducationTag{}, nil
+	}
+	newTags := make([]models.EducationTag, 0, len(names))
+	for _, name := range names {
+		newTags = append(newTags, models.EducationTag{Name: name})
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+		return nil, err
+	}
+
+	var existing []models.EducationTag
+	if err := r.db.Where("name IN ?", names).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.EducationTag, len(existing))
+	for _, tag := range existing {
+		byName[strings.ToLower(tag.Name)] = tag
+	}
+	tags := make([]models.EducationTag, 0, len(names))
+	for _, name := range names {
+		tag, ok := byName[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("tag %q tidak ditemukan setelah disimpan", name)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// escapeLike meng-escape karakter wildcard LIKE (%, _) dan backslash agar kata kunci dicocokkan apa adanya.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// fulltextTerms mengubah kata kunci menjadi term boolean mode ("kata*"), membuang operator
+// dan kata yang lebih pendek dari ukuran token minimum FULLTEXT.
+func fulltextTerms(query string) []string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) >= fulltextMinTokenSize {
+			terms = append(terms, word+"*")
+		}
+	}
+	return terms
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "ginjal", "ginjal"},
+		{"percent", "100%", `100\%`},
+		{"underscore", "cairan_harian", `cairan\_harian`},
+		{"backslash", `a\b`, `a\\b`},
+		{"mixed", `%_\`, `\%\_\\`},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeLike(tt.value); got != tt.want {
+				t.Errorf("escapeLike(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
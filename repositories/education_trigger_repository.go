@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type EducationTriggerRepository interface {
+	FindByEducationID(educationID uint) ([]models.EducationTrigger, error)
+	ReplaceForEducation(educationID uint, triggers []models.EducationTrigger) ([]models.EducationTrigger, error)
+	FindMatching(triggerType string, symptomCodes []string) ([]models.EducationTrigger, error)
+}
+
+type educationTriggerRepository struct {
+	db *gorm.DB
+}
+
+func NewEducationTriggerRepository(db *gorm.DB) EducationTriggerRepository {
+	return &educationTriggerRepository{db: db}
+}
+
+func (r *educationTriggerRepository) FindByEducationID(educationID uint) ([]models.EducationTrigger, error) {
+	var triggers []models.EducationTrigger
+	err := r.db.Where("education_id = ?", educationID).Order("trigger_type asc, symptom_code asc").Find(&triggers).Error
+	return triggers, err
+}
+
+// ReplaceForEducation mengganti seluruh pemicu milik satu edukasi dalam satu transaksi.
+func (r *educationTriggerRepository) ReplaceForEducation(educationID uint, triggers []models.EducationTrigger) ([]models.EducationTrigger, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("education_id = ?", educationID).Delete(&models.EducationTrigger{}).Error; err != nil {
+			return err
+		}
+		for i := range triggers {
+			triggers[i].ID = 0
+			triggers[i].EducationID = educationID
+		}
+		if len(triggers) == 0 {
+			return nil
+		}
+		return tx.Omit("Education").Create(&triggers).Error
+	})
+	return triggers, err
+}
+
+// FindMatching mengambil pemicu dengan tipe tertentu, beserta edukasinya. Untuk tipe symptom,
+// hanya pemicu dengan salah satu kode gejala yang diberikan.
+func (r *educationTriggerRepository) FindMatching(triggerType string, symptomCodes []string) ([]models.EducationTrigger, error) {
+	var triggers []models.EducationTrigger
+	query := r.db.Preload("Education").Preload("Education.Tags").Where("trigger_type = ?", triggerType)
+	if triggerType == models.EducationTriggerSymptom {
+		if len(symptomCodes) == 0 {
+			return triggers, nil
+		}
+		query = query.Where("symptom_code IN ?", symptomCodes)
+	}
+	err := query.Find(&triggers).Error
+	return triggers, err
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FluidAlertRepository interface {
+	CreateIfNotExists(alert models.FluidAlert) (models.FluidAlert, bool, error)
+	Update(alert models.FluidAlert) (models.FluidAlert, error)
+	ExistsForDate(userID uint, date time.Time, level string) (bool, error)
+}
+
+type fluidAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewFluidAlertRepository(db *gorm.DB) FluidAlertRepository {
+	return &fluidAlertRepository{db: db}
+}
+
+// CreateIfNotExists menyimpan alert baru. created bernilai false jika alert untuk
+// user+tanggal+level yang sama sudah ada (unique index), sehingga aman dari pesan ganda.
+func (r *fluidAlertRepository) CreateIfNotExists(alert models.FluidAlert) (models.FluidAlert, bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&alert)
+	if result.Error != nil {
+		return models.FluidAlert{}, false, result.Error
+	}
+	return alert, result.RowsAffected > 0, nil
+}
+
+func (r *fluidAlertRepository) Update(alert models.FluidAlert) (models.FluidAlert, error) {
+	err := r.db.Save(&alert).Error
+	return alert, err
+}
+
+func (r *fluidAlertRepository) ExistsForDate(userID uint, date time.Time, level string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.FluidAlert{}).
+		Where("user_id = ? AND log_date = ? AND level = ?", userID, date.Format("2006-01-02"), level).
+		Count(&count).Error
+	return count > 0, err
+}
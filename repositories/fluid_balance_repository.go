@@ -2,7 +2,7 @@ package repositories
 
 import (
 	// "errors" // Tidak perlu lagi di sini
-	// "fmt" // Tidak perlu lagi di sini
+	"fmt"
 	"time"
 
 	models "github.com/darmawguna/tirtaapp.git/model"
@@ -16,6 +16,34 @@ type FluidBalanceRepository interface {
 	Create(log models.FluidBalanceLog) (models.FluidBalanceLog, error) // <-- Tambah Create
 	Update(log models.FluidBalanceLog) (models.FluidBalanceLog, error) // <-- Tambah Update
 	FindHistoryByUserID(userID uint, limit int) ([]models.FluidBalanceLog, error)
+	FindByDateAndTimezone(date string, timezone string) ([]models.FluidBalanceLog, error)
+	FindAllByUserID(userID uint) ([]models.FluidBalanceLog, error)
+	Delete(id uint) error
+	FindByID(id uint) (models.FluidBalanceLog, error)
+	FindByUserAndDateRange(userID uint, from, to time.Time, offset, limit int) ([]models.FluidBalanceLog, int64, error)
+	AggregateByPeriod(userID uint, from, to time.Time, granularity string, defaultLimitCC int) ([]FluidAggregate, error)
+	AggregateSummary(userID uint, from, to time.Time, defaultLimitCC int) (FluidAggregate, error)
+	LongestCompliantStreak(userID uint, from, to time.Time, defaultLimitCC int) (int, error)
+}
+
+// Granularity agregasi statistik cairan.
+const (
+	FluidGranularityDay   = "day"
+	FluidGranularityWeek  = "week"
+	FluidGranularityMonth = "month"
+)
+
+// FluidAggregate adalah hasil agregasi log cairan untuk satu periode (atau seluruh rentang).
+type FluidAggregate struct {
+	PeriodStart   time.Time
+	DaysLogged    int
+	TotalIntakeCC int
+	TotalOutputCC int
+	AvgIntakeCC   float64
+	AvgOutputCC   float64
+	AvgBalanceCC  float64
+	MaxBalanceCC  int
+	DaysOverLimit int
 }
 
 type fluidBalanceRepository struct {
@@ -51,4 +79,109 @@ func (r *fluidBalanceRepository) FindHistoryByUserID(userID uint, limit int) ([]
 	var logs []models.FluidBalanceLog
 	err := r.db.Where("user_id = ?", userID).Order("log_date desc").Limit(limit).Find(&logs).Error
 	return logs, err
-}
\ No newline at end of file
+}
+
+// FindByDateAndTimezone mengambil semua log pada tanggal tertentu (YYYY-MM-DD)
+// milik user dengan timezone tertentu.
+func (r *fluidBalanceRepository) FindByDateAndTimezone(date string, timezone string) ([]models.FluidBalanceLog, error) {
+	var logs []models.FluidBalanceLog
+	err := r.db.Joins("JOIN users ON users.id = fluid_balance_logs.user_id").
+		Where("fluid_balance_logs.log_date = ? AND users.timezone = ?", date, timezone).
+		Find(&logs).Error
+	return logs, err
+}
+
+func (r *fluidBalanceRepository) FindAllByUserID(userID uint) ([]models.FluidBalanceLog, error) {
+	var logs []models.FluidBalanceLog
+	err := r.db.Where("user_id = ?", userID).Order("log_date asc").Find(&logs).Error
+	return logs, err
+}
+
+func (r *fluidBalanceRepository) Delete(id uint) error {
+	return r.db.Delete(&models.FluidBalanceLog{}, id).Error
+}
+
+// fluidPeriodExpressions memetakan granularity ke ekspresi awal periode (minggu dimulai Senin).
+var fluidPeriodExpressions = map[string]string{
+	FluidGranularityDay:   "log_date",
+	FluidGranularityWeek:  "DATE_SUB(log_date, INTERVAL WEEKDAY(log_date) DAY)",
+	FluidGranularityMonth: "DATE_SUB(log_date, INTERVAL DAYOFMONTH(log_date) - 1 DAY)",
+}
+
+// fluidAggregateSelect menghitung total, rata-rata dan jumlah hari melewati batas. Log lama
+// yang belum menyimpan batas harian memakai defaultLimitCC.
+const fluidAggregateSelect = "COUNT(*) AS days_logged, " +
+	"COALESCE(SUM(intake_cc), 0) AS total_intake_cc, " +
+	"COALESCE(SUM(output_cc), 0) AS total_output_cc, " +
+	"COALESCE(AVG(intake_cc), 0) AS avg_intake_cc, " +
+	"COALESCE(AVG(output_cc), 0) AS avg_output_cc, " +
+	"COALESCE(AVG(balance_cc), 0) AS avg_balance_cc, " +
+	"COALESCE(MAX(balance_cc), 0) AS max_balance_cc, " +
+	"COALESCE(SUM(CASE WHEN balance_cc > COALESCE(NULLIF(daily_limit_cc, 0), ?) THEN 1 ELSE 0 END), 0) AS days_over_limit"
+
+// loggedDaysInRange membatasi ke log user dalam rentang tanggal yang benar-benar berisi catatan.
+func (r *fluidBalanceRepository) loggedDaysInRange(userID uint, from, to time.Time) *gorm.DB {
+	return r.db.Model(&models.FluidBalanceLog{}).
+		Where("user_id = ? AND log_date BETWEEN ? AND ?", userID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Where("(intake_cc > 0 OR output_cc > 0)")
+}
+
+// FindByUserAndDateRange mengambil riwayat log dalam rentang tanggal (terbaru dulu) beserta total barisnya.
+func (r *fluidBalanceRepository) FindByUserAndDateRange(userID uint, from, to time.Time, offset, limit int) ([]models.FluidBalanceLog, int64, error) {
+	var total int64
+	if err := r.loggedDaysInRange(userID, from, to).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var logs []models.FluidBalanceLog
+	err := r.loggedDaysInRange(userID, from, to).
+		Order("log_date desc").Offset(offset).Limit(limit).
+		Find(&logs).Error
+	return logs, total, err
+}
+
+// AggregateByPeriod mengelompokkan log per hari, minggu atau bulan langsung di SQL.
+func (r *fluidBalanceRepository) AggregateByPeriod(userID uint, from, to time.Time, granularity string, defaultLimitCC int) ([]FluidAggregate, error) {
+	period, ok := fluidPeriodExpressions[granularity]
+	if !ok {
+		return nil, fmt.Errorf("granularity tidak dikenal: %s", granularity)
+	}
+	var rows []FluidAggregate
+	err := r.loggedDaysInRange(userID, from, to).
+		Select(period+" AS period_start, "+fluidAggregateSelect, defaultLimitCC).
+		Group("period_start").
+		Order("period_start asc").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// AggregateSummary menghitung agregat untuk seluruh rentang tanggal.
+func (r *fluidBalanceRepository) AggregateSummary(userID uint, from, to time.Time, defaultLimitCC int) (FluidAggregate, error) {
+	var summary FluidAggregate
+	err := r.loggedDaysInRange(userID, from, to).
+		Select(fluidAggregateSelect, defaultLimitCC).
+		Scan(&summary).Error
+	return summary, err
+}
+
+// LongestCompliantStreak mencari deret hari berurutan terpanjang yang tercatat dan tidak
+// melewati batas (gaps-and-islands: tanggal dikurangi nomor urut bernilai sama dalam satu deret).
+func (r *fluidBalanceRepository) LongestCompliantStreak(userID uint, from, to time.Time, defaultLimitCC int) (int, error) {
+	compliant := r.loggedDaysInRange(userID, from, to).
+		Select("DATE_SUB(log_date, INTERVAL ROW_NUMBER() OVER (ORDER BY log_date) DAY) AS streak_key").
+		Where("balance_cc <= COALESCE(NULLIF(daily_limit_cc, 0), ?)", defaultLimitCC)
+	streaks := r.db.Table("(?) AS compliant_days", compliant).
+		Select("COUNT(*) AS streak_length").
+		Group("streak_key")
+
+	var longest int
+	err := r.db.Table("(?) AS streaks", streaks).
+		Select("COALESCE(MAX(streak_length), 0)").
+		Scan(&longest).Error
+	return longest, err
+}
+
+func (r *fluidBalanceRepository) FindByID(id uint) (models.FluidBalanceLog, error) {
+	var log models.FluidBalanceLog
+	err := r.db.First(&log, id).Error
+	return log, err
+}
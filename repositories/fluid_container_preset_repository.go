@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type FluidContainerPresetRepository interface {
+	Create(preset models.FluidContainerPreset) (models.FluidContainerPreset, error)
+	Update(preset models.FluidContainerPreset) (models.FluidContainerPreset, error)
+	Delete(id uint) error
+	FindByID(id uint) (models.FluidContainerPreset, error)
+	FindAll(activeOnly bool) ([]models.FluidContainerPreset, error)
+	Count() (int64, error)
+}
+
+type fluidContainerPresetRepository struct {
+	db *gorm.DB
+}
+
+func NewFluidContainerPresetRepository(db *gorm.DB) FluidContainerPresetRepository {
+	return &fluidContainerPresetRepository{db: db}
+}
+
+func (r *fluidContainerPresetRepository) Create(preset models.FluidContainerPreset) (models.FluidContainerPreset, error) {
+	err := r.db.Create(&preset).Error
+	return preset, err
+}
+
+func (r *fluidContainerPresetRepository) Update(preset models.FluidContainerPreset) (models.FluidContainerPreset, error) {
+	err := r.db.Save(&preset).Error
+	return preset, err
+}
+
+func (r *fluidContainerPresetRepository) Delete(id uint) error {
+	return r.db.Delete(&models.FluidContainerPreset{}, id).Error
+}
+
+func (r *fluidContainerPresetRepository) FindByID(id uint) (models.FluidContainerPreset, error) {
+	var preset models.FluidContainerPreset
+	err := r.db.First(&preset, id).Error
+	return preset, err
+}
+
+func (r *fluidContainerPresetRepository) FindAll(activeOnly bool) ([]models.FluidContainerPreset, error) {
+	var presets []models.FluidContainerPreset
+	query := r.db.Order("sort_order asc, id asc")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&presets).Error
+	return presets, err
+}
+
+func (r *fluidContainerPresetRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.FluidContainerPreset{}).Count(&count).Error
+	return count, err
+}
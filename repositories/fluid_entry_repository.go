@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FluidEntryChanges adalah perubahan entry yang disimpan bersama total log hariannya.
+type FluidEntryChanges struct {
+	Create    []models.FluidEntry
+	Update    []models.FluidEntry
+	DeleteIDs []uint
+}
+
+type FluidEntryRepository interface {
+	Create(entry models.FluidEntry) (models.FluidEntry, error)
+	Update(entry models.FluidEntry) (models.FluidEntry, error)
+	FindByID(id uint) (models.FluidEntry, error)
+	FindByUserAndDate(userID uint, date time.Time) ([]models.FluidEntry, error)
+	CountByLogID(logID uint) (int64, error)
+	FindAllByUserID(userID uint) ([]models.FluidEntry, error)
+	SumByUserAndDate(userID uint, date time.Time) (intakeCC int, outputCC int, err error)
+	ApplyChanges(logID uint, changes FluidEntryChanges, applyTotals func(dailyLog *models.FluidBalanceLog, intakeCC, outputCC int)) (FluidEntryChanges, models.FluidBalanceLog, error)
+}
+
+type fluidEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewFluidEntryRepository(db *gorm.DB) FluidEntryRepository {
+	return &fluidEntryRepository{db: db}
+}
+
+func (r *fluidEntryRepository) Create(entry models.FluidEntry) (models.FluidEntry, error) {
+	err := r.db.Create(&entry).Error
+	return entry, err
+}
+
+func (r *fluidEntryRepository) Update(entry models.FluidEntry) (models.FluidEntry, error) {
+	err := r.db.Save(&entry).Error
+	return entry, err
+}
+
+func (r *fluidEntryRepository) FindByID(id uint) (models.FluidEntry, error) {
+	var entry models.FluidEntry
+	err := r.db.First(&entry, id).Error
+	return entry, err
+}
+
+// FindByUserAndDate mengambil timeline entry satu hari, urut berdasarkan waktu pencatatan.
+func (r *fluidEntryRepository) FindByUserAndDate(userID uint, date time.Time) ([]models.FluidEntry, error) {
+	var entries []models.FluidEntry
+	err := r.db.Where("user_id = ? AND log_date = ?", userID, date.Format("2006-01-02")).
+		Order("recorded_at asc").Find(&entries).Error
+	return entries, err
+}
+
+func (r *fluidEntryRepository) CountByLogID(logID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.FluidEntry{}).Where("fluid_balance_log_id = ?", logID).Count(&count).Error
+	return count, err
+}
+
+// SumByUserAndDate menjumlahkan cairan masuk & keluar satu hari langsung di SQL.
+func (r *fluidEntryRepository) SumByUserAndDate(userID uint, date time.Time) (int, int, error) {
+	return sumFluidEntries(r.db, userID, date)
+}
+
+// ApplyChanges menjalankan perubahan entry dan perhitungan ulang log hariannya dalam satu transaksi.
+// Baris log dikunci lebih dulu sehingga applyTotals menerima total terbaru dan nilai log sebelum
+// diubah; pencatatan paralel pada hari yang sama menunggu transaksi ini selesai.
+func (r *fluidEntryRepository) ApplyChanges(logID uint, changes FluidEntryChanges, applyTotals func(dailyLog *models.FluidBalanceLog, intakeCC, outputCC int)) (FluidEntryChanges, models.FluidBalanceLog, error) {
+	var dailyLog models.FluidBalanceLog
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&dailyLog, logID).Error; err != nil {
+			return err
+		}
+		for i := range changes.Create {
+			if err := tx.Omit(clause.Associations).Create(&changes.Create[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i := range changes.Update {
+			if err := tx.Omit(clause.Associations).Save(&changes.Update[i]).Error; err != nil {
+				return err
+			}
+		}
+		if len(changes.DeleteIDs) > 0 {
+			if err := tx.Delete(&models.FluidEntry{}, changes.DeleteIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		intake, output, err := sumFluidEntries(tx, dailyLog.UserID, dailyLog.LogDate)
+		if err != nil {
+			return err
+		}
+		applyTotals(&dailyLog, intake, output)
+		return tx.Omit(clause.Associations).Save(&dailyLog).Error
+	})
+	return changes, dailyLog, err
+}
+
+func sumFluidEntries(db *gorm.DB, userID uint, date time.Time) (int, int, error) {
+	var result struct {
+		IntakeCC int
+		OutputCC int
+	}
+	err := db.Model(&models.FluidEntry{}).
+		Select("COALESCE(SUM(CASE WHEN direction = ? THEN volume_cc ELSE 0 END), 0) AS intake_cc, "+
+			"COALESCE(SUM(CASE WHEN direction = ? THEN volume_cc ELSE 0 END), 0) AS output_cc",
+			models.FluidDirectionIntake, models.FluidDirectionOutput).
+		Where("user_id = ? AND log_date = ?", userID, date.Format("2006-01-02")).
+		Scan(&result).Error
+	return result.IntakeCC, result.OutputCC, err
+}
+
+func (r *fluidEntryRepository) FindAllByUserID(userID uint) ([]models.FluidEntry, error) {
+	var entries []models.FluidEntry
+	err := r.db.Where("user_id = ?", userID).Order("recorded_at asc").Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type FluidPrescriptionRepository interface {
+	Create(prescription models.FluidPrescription) (models.FluidPrescription, error)
+	Update(prescription models.FluidPrescription) (models.FluidPrescription, error)
+	FindByUserAndEffectiveDate(userID uint, date time.Time) (models.FluidPrescription, error)
+	FindEffectiveForDate(userID uint, date time.Time) (models.FluidPrescription, error)
+	FindHistoryByUserID(userID uint) ([]models.FluidPrescription, error)
+}
+
+type fluidPrescriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewFluidPrescriptionRepository(db *gorm.DB) FluidPrescriptionRepository {
+	return &fluidPrescriptionRepository{db: db}
+}
+
+func (r *fluidPrescriptionRepository) Create(prescription models.FluidPrescription) (models.FluidPrescription, error) {
+	err := r.db.Create(&prescription).Error
+	return prescription, err
+}
+
+func (r *fluidPrescriptionRepository) Update(prescription models.FluidPrescription) (models.FluidPrescription, error) {
+	err := r.db.Save(&prescription).Error
+	return prescription, err
+}
+
+func (r *fluidPrescriptionRepository) FindByUserAndEffectiveDate(userID uint, date time.Time) (models.FluidPrescription, error) {
+	var prescription models.FluidPrescription
+	err := r.db.Where("user_id = ? AND effective_date = ?", userID, date.Format("2006-01-02")).First(&prescription).Error
+	return prescription, err
+}
+
+// FindEffectiveForDate mengambil resep yang berlaku pada tanggal tertentu
+// (EffectiveDate terbaru yang tidak melewati tanggal tersebut).
+func (r *fluidPrescriptionRepository) FindEffectiveForDate(userID uint, date time.Time) (models.FluidPrescription, error) {
+	var prescription models.FluidPrescription
+	err := r.db.Where("user_id = ? AND effective_date <= ?", userID, date.Format("2006-01-02")).
+		Order("effective_date desc").First(&prescription).Error
+	return prescription, err
+}
+
+func (r *fluidPrescriptionRepository) FindHistoryByUserID(userID uint) ([]models.FluidPrescription, error) {
+	var prescriptions []models.FluidPrescription
+	err := r.db.Where("user_id = ?", userID).Order("effective_date desc").Find(&prescriptions).Error
+	return prescriptions, err
+}
@@ -15,6 +15,13 @@ type HemodialysisMonitoringRepository interface {
 	Update(monitoring models.HemodialysisMonitoring) (models.HemodialysisMonitoring, error)
 	FindHistoryByUserID(userID uint, limit int) ([]models.HemodialysisMonitoring, error)
 	FindByID(id uint) (models.HemodialysisMonitoring, error)
+	FindAllByUserID(userID uint) ([]models.HemodialysisMonitoring, error)
+	FindPreviousSession(userID uint, before time.Time) (models.HemodialysisMonitoring, error)
+	FindNextSession(userID uint, after time.Time) (models.HemodialysisMonitoring, error)
+	FindLatestDryWeight(userID uint, before time.Time) (float64, error)
+	FindIDWGExceededSince(since time.Time, patientIDs []uint) ([]models.HemodialysisMonitoring, error)
+	FindByScheduleID(scheduleID uint) (models.HemodialysisMonitoring, error)
+	MarkIDWGAlertSent(id uint, at time.Time) (bool, error)
 }
 
 // Implementasi repository
@@ -65,4 +72,66 @@ func (r *hemodialysisMonitoringRepository) FindByID(id uint) (models.Hemodialysi
 	var monitoring models.HemodialysisMonitoring
 	err := r.db.First(&monitoring, id).Error // Cari berdasarkan Primary Key 'id'
 	return monitoring, err
-}
\ No newline at end of file
+}
+
+// FindAllByUserID mengambil seluruh data monitoring user, terbaru lebih dulu.
+func (r *hemodialysisMonitoringRepository) FindAllByUserID(userID uint) ([]models.HemodialysisMonitoring, error) {
+	var monitorings []models.HemodialysisMonitoring
+	err := r.db.Where("user_id = ?", userID).Order("monitoring_date desc").Find(&monitorings).Error
+	return monitorings, err
+}
+
+// FindPreviousSession mengambil sesi terakhir sebelum tanggal tertentu yang memiliki berat sesudah HD.
+func (r *hemodialysisMonitoringRepository) FindPreviousSession(userID uint, before time.Time) (models.HemodialysisMonitoring, error) {
+	var monitoring models.HemodialysisMonitoring
+	err := r.db.Where("user_id = ? AND monitoring_date < ? AND weight_after > 0", userID, before.Format("2006-01-02")).
+		Order("monitoring_date desc").First(&monitoring).Error
+	return monitoring, err
+}
+
+// FindNextSession mengambil sesi pertama setelah tanggal tertentu.
+func (r *hemodialysisMonitoringRepository) FindNextSession(userID uint, after time.Time) (models.HemodialysisMonitoring, error) {
+	var monitoring models.HemodialysisMonitoring
+	err := r.db.Where("user_id = ? AND monitoring_date > ?", userID, after.Format("2006-01-02")).
+		Order("monitoring_date asc").First(&monitoring).Error
+	return monitoring, err
+}
+
+// FindLatestDryWeight mengambil berat kering terakhir yang tercatat sampai tanggal tertentu (0 jika belum ada).
+func (r *hemodialysisMonitoringRepository) FindLatestDryWeight(userID uint, before time.Time) (float64, error) {
+	var dryWeights []float64
+	err := r.db.Model(&models.HemodialysisMonitoring{}).
+		Where("user_id = ? AND monitoring_date <= ? AND dry_weight IS NOT NULL", userID, before.Format("2006-01-02")).
+		Order("monitoring_date desc").Limit(1).
+		Pluck("dry_weight", &dryWeights).Error
+	if err != nil || len(dryWeights) == 0 {
+		return 0, err
+	}
+	return dryWeights[0], nil
+}
+
+// FindIDWGExceededSince mengambil sesi dengan IDWG melewati ambang sejak tanggal tertentu.
+// patientIDs nil berarti semua pasien (admin).
+func (r *hemodialysisMonitoringRepository) FindIDWGExceededSince(since time.Time, patientIDs []uint) ([]models.HemodialysisMonitoring, error) {
+	var monitorings []models.HemodialysisMonitoring
+	query := r.db.Preload("User").
+		Where("id_wg_exceeded = ? AND monitoring_date >= ?", true, since.Format("2006-01-02"))
+	if patientIDs != nil {
+		query = query.Where("user_id IN ?", patientIDs)
+	}
+	err := query.Order("monitoring_date desc").Find(&monitorings).Error
+	return monitorings, err
+}
+
+func (r *hemodialysisMonitoringRepository) FindByScheduleID(scheduleID uint) (models.HemodialysisMonitoring, error) {
+	var monitoring models.HemodialysisMonitoring
+	err := r.db.Where("hemodialysis_schedule_id = ?", scheduleID).First(&monitoring).Error
+	return monitoring, err
+}
+
+// MarkIDWGAlertSent hanya mengisi id_wg_alert_sent_at yang masih kosong, tanpa menimpa
+// kolom lain yang mungkin sudah dikoreksi pasien sejak pesan diterima.
+func (r *hemodialysisMonitoringRepository) MarkIDWGAlertSent(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&models.HemodialysisMonitoring{}).Where("id = ? AND id_wg_alert_sent_at IS NULL", id).Update("id_wg_alert_sent_at", at)
+	return result.RowsAffected == 1, result.Error
+}
@@ -13,7 +13,10 @@ type HemodialysisScheduleRepository interface {
 	FindByID(id uint) (models.HemodialysisSchedule, error)
 	Update(schedule models.HemodialysisSchedule) (models.HemodialysisSchedule, error)
 	FindSchedulesForDateAndNotNotified(date time.Time) ([]models.HemodialysisSchedule, error)
+	FindSchedulesForDateAndNotNotifiedByTimezone(date string, timezone string) ([]models.HemodialysisSchedule, error)
 	Delete(id uint) error
+	FindActiveByUserAndDate(userID uint, date time.Time) (models.HemodialysisSchedule, error)
+	FindPendingMonitoringBeforeDateByTimezone(date string, timezone string) ([]models.HemodialysisSchedule, error)
 }
 
 type hemodialysisScheduleRepository struct {
@@ -47,6 +50,16 @@ func (r *hemodialysisScheduleRepository) FindSchedulesForDateAndNotNotified(date
 	return schedules, err
 }
 
+// FindSchedulesForDateAndNotNotifiedByTimezone sama seperti FindSchedulesForDateAndNotNotified,
+// tetapi hanya untuk user dengan timezone tertentu. date berformat YYYY-MM-DD (tanggal lokal user).
+func (r *hemodialysisScheduleRepository) FindSchedulesForDateAndNotNotifiedByTimezone(date string, timezone string) ([]models.HemodialysisSchedule, error) {
+	var schedules []models.HemodialysisSchedule
+	err := r.db.Joins("JOIN users ON users.id = hemodialysis_schedules.user_id").
+		Where("hemodialysis_schedules.schedule_date = ? AND hemodialysis_schedules.is_active = ? AND hemodialysis_schedules.monitoring_notification_sent = ? AND users.timezone = ?", date, true, false, timezone).
+		Find(&schedules).Error
+	return schedules, err
+}
+
 func (r *hemodialysisScheduleRepository) Update(schedule models.HemodialysisSchedule) (models.HemodialysisSchedule, error) {
 	err := r.db.Save(&schedule).Error
 	return schedule, err
@@ -54,4 +67,22 @@ func (r *hemodialysisScheduleRepository) Update(schedule models.HemodialysisSche
 
 func (r *hemodialysisScheduleRepository) Delete(id uint) error {
 	return r.db.Delete(&models.HemodialysisSchedule{}, id).Error
-}
\ No newline at end of file
+}
+
+// FindActiveByUserAndDate mencari jadwal HD aktif milik user pada tanggal tertentu.
+func (r *hemodialysisScheduleRepository) FindActiveByUserAndDate(userID uint, date time.Time) (models.HemodialysisSchedule, error) {
+	var schedule models.HemodialysisSchedule
+	err := r.db.Where("user_id = ? AND schedule_date = ? AND is_active = ?", userID, date.Format("2006-01-02"), true).
+		First(&schedule).Error
+	return schedule, err
+}
+
+// FindPendingMonitoringBeforeDateByTimezone mengambil jadwal aktif yang tanggalnya sudah lewat
+// (sebelum date, YYYY-MM-DD lokal) dan pemantauannya masih pending.
+func (r *hemodialysisScheduleRepository) FindPendingMonitoringBeforeDateByTimezone(date string, timezone string) ([]models.HemodialysisSchedule, error) {
+	var schedules []models.HemodialysisSchedule
+	err := r.db.Joins("JOIN users ON users.id = hemodialysis_schedules.user_id").
+		Where("hemodialysis_schedules.schedule_date < ? AND hemodialysis_schedules.is_active = ? AND hemodialysis_schedules.monitoring_status = ? AND users.timezone = ?", date, true, models.MonitoringStatusPending, timezone).
+		Find(&schedules).Error
+	return schedules, err
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type IntradialyticReadingRepository interface {
+	Create(reading models.IntradialyticReading) (models.IntradialyticReading, error)
+	Delete(id uint) error
+	FindByID(id uint) (models.IntradialyticReading, error)
+	FindByMonitoringID(monitoringID uint) ([]models.IntradialyticReading, error)
+}
+
+type intradialyticReadingRepository struct {
+	db *gorm.DB
+}
+
+func NewIntradialyticReadingRepository(db *gorm.DB) IntradialyticReadingRepository {
+	return &intradialyticReadingRepository{db: db}
+}
+
+func (r *intradialyticReadingRepository) Create(reading models.IntradialyticReading) (models.IntradialyticReading, error) {
+	err := r.db.Create(&reading).Error
+	return reading, err
+}
+
+func (r *intradialyticReadingRepository) Delete(id uint) error {
+	return r.db.Delete(&models.IntradialyticReading{}, id).Error
+}
+
+func (r *intradialyticReadingRepository) FindByID(id uint) (models.IntradialyticReading, error) {
+	var reading models.IntradialyticReading
+	err := r.db.First(&reading, id).Error
+	return reading, err
+}
+
+// FindByMonitoringID mengambil seluruh pengukuran satu sesi, urut waktu.
+func (r *intradialyticReadingRepository) FindByMonitoringID(monitoringID uint) ([]models.IntradialyticReading, error) {
+	var readings []models.IntradialyticReading
+	err := r.db.Where("hemodialysis_monitoring_id = ?", monitoringID).Order("recorded_at asc").Find(&readings).Error
+	return readings, err
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type JobRunRepository interface {
+	Create(run models.JobRun) (models.JobRun, error)
+	Update(run models.JobRun) (models.JobRun, error)
+	FindLatestByJobName(jobName string) (models.JobRun, error)
+	FindByJobName(jobName string, limit int) ([]models.JobRun, error)
+}
+
+type jobRunRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRunRepository(db *gorm.DB) JobRunRepository {
+	return &jobRunRepository{db: db}
+}
+
+func (r *jobRunRepository) Create(run models.JobRun) (models.JobRun, error) {
+	err := r.db.Create(&run).Error
+	return run, err
+}
+
+func (r *jobRunRepository) Update(run models.JobRun) (models.JobRun, error) {
+	err := r.db.Save(&run).Error
+	return run, err
+}
+
+// FindLatestByJobName mengambil eksekusi terakhir sebuah job (ErrRecordNotFound jika belum pernah jalan).
+func (r *jobRunRepository) FindLatestByJobName(jobName string) (models.JobRun, error) {
+	var run models.JobRun
+	err := r.db.Where("job_name = ?", jobName).Order("started_at desc").First(&run).Error
+	return run, err
+}
+
+func (r *jobRunRepository) FindByJobName(jobName string, limit int) ([]models.JobRun, error) {
+	var runs []models.JobRun
+	err := r.db.Where("job_name = ?", jobName).Order("started_at desc").Limit(limit).Find(&runs).Error
+	return runs, err
+}
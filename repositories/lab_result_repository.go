@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LabResultRepository interface {
+	SavePanel(results []models.LabResult, checkOverwrite func(existing models.LabResult) error) ([]models.LabResult, error)
+	Delete(id uint) error
+	FindByID(id uint) (models.LabResult, error)
+	FindByUserAndDateRange(userID uint, from, to time.Time, labTestID uint) ([]models.LabResult, error)
+	FindPatientsDueByTimezone(timezone string, since string) ([]models.User, error)
+}
+
+type labResultRepository struct {
+	db *gorm.DB
+}
+
+func NewLabResultRepository(db *gorm.DB) LabResultRepository {
+	return &labResultRepository{db: db}
+}
+
+// SavePanel menyimpan satu panel hasil lab dalam satu transaksi. Jika tes yang sama sudah ada pada
+// tanggal sampel itu, barisnya dikunci dan checkOverwrite menentukan boleh tidaknya ditimpa; error dari
+// checkOverwrite membatalkan seluruh panel.
+func (r *labResultRepository) SavePanel(results []models.LabResult, checkOverwrite func(existing models.LabResult) error) ([]models.LabResult, error) {
+	saved := make([]models.LabResult, 0, len(results))
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, result := range results {
+			var existing models.LabResult
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("user_id = ? AND lab_test_id = ? AND sample_date = ?", result.UserID, result.LabTestID, result.SampleDate.Format("2006-01-02")).
+				First(&existing).Error
+			switch {
+			case err == nil:
+				if err := checkOverwrite(existing); err != nil {
+					return err
+				}
+				existing.Value, existing.EnteredBy, existing.Notes = result.Value, result.EnteredBy, result.Notes
+				if err := tx.Omit(clause.Associations).Save(&existing).Error; err != nil {
+					return err
+				}
+				result = existing
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				if err := tx.Create(&result).Error; err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+			if err := tx.Preload("LabTest").First(&result, result.ID).Error; err != nil {
+				return err
+			}
+			saved = append(saved, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (r *labResultRepository) Delete(id uint) error {
+	return r.db.Delete(&models.LabResult{}, id).Error
+}
+
+func (r *labResultRepository) FindByID(id uint) (models.LabResult, error) {
+	var result models.LabResult
+	err := r.db.Preload("LabTest").First(&result, id).Error
+	return result, err
+}
+
+// FindByUserAndDateRange mengambil hasil lab dalam rentang tanggal (inklusif), urut tanggal lalu urutan katalog.
+// labTestID 0 berarti semua tes.
+func (r *labResultRepository) FindByUserAndDateRange(userID uint, from, to time.Time, labTestID uint) ([]models.LabResult, error) {
+	var results []models.LabResult
+	query := r.db.Preload("LabTest").
+		Joins("JOIN lab_tests ON lab_tests.id = lab_results.lab_test_id").
+		Where("lab_results.user_id = ? AND lab_results.sample_date BETWEEN ? AND ?", userID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if labTestID != 0 {
+		query = query.Where("lab_results.lab_test_id = ?", labTestID)
+	}
+	err := query.Order("lab_results.sample_date asc, lab_tests.sort_order asc, lab_tests.id asc").Find(&results).Error
+	return results, err
+}
+
+// FindPatientsDueByTimezone mengambil pasien di timezone tertentu yang belum punya hasil lab
+// sejak tanggal since (YYYY-MM-DD lokal).
+func (r *labResultRepository) FindPatientsDueByTimezone(timezone string, since string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("timezone = ? AND role = ?", timezone, models.RoleUser).
+		Where("NOT EXISTS (SELECT 1 FROM lab_results WHERE lab_results.user_id = users.id AND lab_results.sample_date >= ?)", since).
+		Find(&users).Error
+	return users, err
+}
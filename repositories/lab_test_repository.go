@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type LabTestRepository interface {
+	Create(test models.LabTest) (models.LabTest, error)
+	Update(test models.LabTest) (models.LabTest, error)
+	FindByID(id uint) (models.LabTest, error)
+	FindByCode(code string) (models.LabTest, error)
+	FindAll(activeOnly bool) ([]models.LabTest, error)
+	Count() (int64, error)
+}
+
+type labTestRepository struct {
+	db *gorm.DB
+}
+
+func NewLabTestRepository(db *gorm.DB) LabTestRepository {
+	return &labTestRepository{db: db}
+}
+
+func (r *labTestRepository) Create(test models.LabTest) (models.LabTest, error) {
+	err := r.db.Create(&test).Error
+	return test, err
+}
+
+func (r *labTestRepository) Update(test models.LabTest) (models.LabTest, error) {
+	err := r.db.Save(&test).Error
+	return test, err
+}
+
+func (r *labTestRepository) FindByID(id uint) (models.LabTest, error) {
+	var test models.LabTest
+	err := r.db.First(&test, id).Error
+	return test, err
+}
+
+func (r *labTestRepository) FindByCode(code string) (models.LabTest, error) {
+	var test models.LabTest
+	err := r.db.Where("code = ?", code).First(&test).Error
+	return test, err
+}
+
+func (r *labTestRepository) FindAll(activeOnly bool) ([]models.LabTest, error) {
+	var tests []models.LabTest
+	query := r.db.Order("sort_order asc, id asc")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&tests).Error
+	return tests, err
+}
+
+func (r *labTestRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.LabTest{}).Count(&count).Error
+	return count, err
+}
@@ -11,6 +11,10 @@ type QuizRepository interface {
 	FindByID(id uint) (models.Quiz, error)
 	Update(quiz models.Quiz) (models.Quiz, error)
 	Delete(id uint) error
+
+	CreateAttempt(attempt models.QuizAttempt) (models.QuizAttempt, error)
+	FindAttemptsByUser(userID uint, quizID uint) ([]models.QuizAttempt, error)
+	CountAttempts(quizID uint) (int64, error)
 }
 
 type quizRepository struct {
@@ -32,17 +36,64 @@ func (r *quizRepository) FindAll() ([]models.Quiz, error) {
 	return quizzes, err
 }
 
+// FindByID mengambil kuis beserta soal dan pilihan jawabannya sesuai urutan.
 func (r *quizRepository) FindByID(id uint) (models.Quiz, error) {
 	var quiz models.Quiz
-	err := r.db.First(&quiz, id).Error
+	err := r.db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc, id asc")
+	}).Preload("Questions.Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc, id asc")
+	}).First(&quiz, id).Error
 	return quiz, err
 }
 
+// Update menyimpan kuis dan mengganti seluruh soalnya dengan quiz.Questions dalam satu transaksi.
 func (r *quizRepository) Update(quiz models.Quiz) (models.Quiz, error) {
-	err := r.db.Save(&quiz).Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Questions", "User").Save(&quiz).Error; err != nil {
+			return err
+		}
+		questionIDs := tx.Model(&models.QuizQuestion{}).Select("id").Where("quiz_id = ?", quiz.ID)
+		if err := tx.Where("question_id IN (?)", questionIDs).Delete(&models.QuizOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("quiz_id = ?", quiz.ID).Delete(&models.QuizQuestion{}).Error; err != nil {
+			return err
+		}
+		for i := range quiz.Questions {
+			quiz.Questions[i].ID = 0
+			quiz.Questions[i].QuizID = quiz.ID
+		}
+		if len(quiz.Questions) > 0 {
+			return tx.Create(&quiz.Questions).Error
+		}
+		return nil
+	})
 	return quiz, err
 }
 
 func (r *quizRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Quiz{}, id).Error
-}
\ No newline at end of file
+}
+
+func (r *quizRepository) CreateAttempt(attempt models.QuizAttempt) (models.QuizAttempt, error) {
+	err := r.db.Omit("Quiz", "User").Create(&attempt).Error
+	return attempt, err
+}
+
+// FindAttemptsByUser mengambil riwayat pengerjaan user, terbaru lebih dulu. quizID 0 berarti semua kuis.
+func (r *quizRepository) FindAttemptsByUser(userID uint, quizID uint) ([]models.QuizAttempt, error) {
+	var attempts []models.QuizAttempt
+	query := r.db.Preload("Quiz").Where("user_id = ?", userID)
+	if quizID != 0 {
+		query = query.Where("quiz_id = ?", quizID)
+	}
+	err := query.Order("created_at desc, id desc").Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *quizRepository) CountAttempts(quizID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.QuizAttempt{}).Where("quiz_id = ?", quizID).Count(&count).Error
+	return count, err
+}
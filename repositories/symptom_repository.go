@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type SymptomRepository interface {
+	Create(symptom models.Symptom) (models.Symptom, error)
+	Update(symptom models.Symptom) (models.Symptom, error)
+	FindByID(id uint) (models.Symptom, error)
+	FindByCode(code string) (models.Symptom, error)
+	FindAll(activeOnly bool) ([]models.Symptom, error)
+	Count() (int64, error)
+}
+
+type symptomRepository struct {
+	db *gorm.DB
+}
+
+func NewSymptomRepository(db *gorm.DB) SymptomRepository {
+	return &symptomRepository{db: db}
+}
+
+func (r *symptomRepository) Create(symptom models.Symptom) (models.Symptom, error) {
+	err := r.db.Create(&symptom).Error
+	return symptom, err
+}
+
+func (r *symptomRepository) Update(symptom models.Symptom) (models.Symptom, error) {
+	err := r.db.Save(&symptom).Error
+	return symptom, err
+}
+
+func (r *symptomRepository) FindByID(id uint) (models.Symptom, error) {
+	var symptom models.Symptom
+	err := r.db.First(&symptom, id).Error
+	return symptom, err
+}
+
+func (r *symptomRepository) FindByCode(code string) (models.Symptom, error) {
+	var symptom models.Symptom
+	err := r.db.Where("code = ?", code).First(&symptom).Error
+	return symptom, err
+}
+
+func (r *symptomRepository) FindAll(activeOnly bool) ([]models.Symptom, error) {
+	var symptoms []models.Symptom
+	query := r.db.Order("sort_order asc, id asc")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&symptoms).Error
+	return symptoms, err
+}
+
+func (r *symptomRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Symptom{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+)
+
+type TriageRuleRepository interface {
+	Create(rule models.TriageRule) (models.TriageRule, error)
+	Update(rule models.TriageRule) (models.TriageRule, error)
+	Delete(id uint) error
+	FindByID(id uint) (models.TriageRule, error)
+	FindAll(activeOnly bool) ([]models.TriageRule, error)
+	Count() (int64, error)
+}
+
+type triageRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewTriageRuleRepository(db *gorm.DB) TriageRuleRepository {
+	return &triageRuleRepository{db: db}
+}
+
+func (r *triageRuleRepository) Create(rule models.TriageRule) (models.TriageRule, error) {
+	err := r.db.Create(&rule).Error
+	return rule, err
+}
+
+func (r *triageRuleRepository) Update(rule models.TriageRule) (models.TriageRule, error) {
+	err := r.db.Save(&rule).Error
+	return rule, err
+}
+
+func (r *triageRuleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TriageRule{}, id).Error
+}
+
+func (r *triageRuleRepository) FindByID(id uint) (models.TriageRule, error) {
+	var rule models.TriageRule
+	err := r.db.First(&rule, id).Error
+	return rule, err
+}
+
+// FindAll mengambil aturan dalam urutan evaluasi.
+func (r *triageRuleRepository) FindAll(activeOnly bool) ([]models.TriageRule, error) {
+	var rules []models.TriageRule
+	query := r.db.Order("priority asc, id asc")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&rules).Error
+	return rules, err
+}
+
+func (r *triageRuleRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TriageRule{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// UploadReference adalah satu nilai kolom DB yang menunjuk ke file upload.
+type UploadReference struct {
+	Table  string
+	Column string
+	ID     uint
+	Value  string
+}
+
+// uploadReferenceColumns adalah semua kolom yang menyimpan path/key file upload.
+var uploadReferenceColumns = []struct{ table, column string }{
+	{"users", "profile_picture"},
+	{"educations", "thumbnail"},
+	{"educations", "media_path"},
+	{"complaint_attachments", "file_path"},
+}
+
+// UploadReferenceRepository dipakai migrasi storage untuk membaca dan menulis ulang
+// referensi file lintas tabel.
+type UploadReferenceRepository interface {
+	FindAll() ([]UploadReference, error)
+	UpdateValue(ref UploadReference, value string) error
+}
+
+type uploadReferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewUploadReferenceRepository(db *gorm.DB) UploadReferenceRepository {
+	return &uploadReferenceRepository{db: db}
+}
+
+func (r *uploadReferenceRepository) FindAll() ([]UploadReference, error) {
+	var refs []UploadReference
+	for _, col := range uploadReferenceColumns {
+		var rows []struct {
+			ID    uint
+			Value string
+		}
+		err := r.db.Table(col.table).
+			Select(fmt.Sprintf("id, %s AS value", col.column)).
+			Where(fmt.Sprintf("%s IS NOT NULL AND %s <> ''", col.column, col.column)).
+			Order("id").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", col.table, col.column, err)
+		}
+		for _, row := range rows {
+			refs = append(refs, UploadReference{Table: col.table, Column: col.column, ID: row.ID, Value: row.Value})
+		}
+	}
+	return refs, nil
+}
+
+// UpdateValue hanya mengubah kolom referensi tanpa menyentuh updated_at.
+func (r *uploadReferenceRepository) UpdateValue(ref UploadReference, value string) error {
+	return r.db.Table(ref.Table).Where("id = ?", ref.ID).UpdateColumn(ref.Column, value).Error
+}
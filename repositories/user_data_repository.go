@@ -0,0 +1,341 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserDataFormatVersion adalah versi format file ekspor data user.
+const UserDataFormatVersion = 1
+
+// UserDataBundle adalah seluruh data klinis milik satu user, dipakai untuk ekspor/impor antar database.
+// ID di dalamnya adalah ID di database asal; relasi ke user lain (peresep, pencatat) disimpan sebagai
+// email di ReferencedUsers dan tes lab sebagai kode di LabTestCodes karena ID bisa berbeda di tujuan.
+type UserDataBundle struct {
+	FormatVersion             int                               `json:"format_version"`
+	ExportedAt                time.Time                         `json:"exported_at"`
+	User                      models.User                       `json:"user"`
+	ReferencedUsers           map[uint]string                   `json:"referenced_users"`
+	LabTestCodes              map[uint]string                   `json:"lab_test_codes"`
+	DrugSchedules             []models.DrugSchedule             `json:"drug_schedules"`
+	ControlSchedules          []models.ControlSchedule          `json:"control_schedules"`
+	HemodialysisSchedules     []models.HemodialysisSchedule     `json:"hemodialysis_schedules"`
+	MedicationRefillSchedules []models.MedicationRefillSchedule `json:"medication_refill_schedules"`
+	FluidPrescriptions        []models.FluidPrescription        `json:"fluid_prescriptions"`
+	FluidBalanceLogs          []models.FluidBalanceLog          `json:"fluid_balance_logs"`
+	FluidEntries              []models.FluidEntry               `json:"fluid_entries"`
+	HemodialysisMonitorings   []models.HemodialysisMonitoring   `json:"hemodialysis_monitorings"`
+	IntradialyticReadings     []models.IntradialyticReading     `json:"intradialytic_readings"`
+	LabResults                []models.LabResult                `json:"lab_results"`
+	VascularAccesses          []models.VascularAccess           `json:"vascular_accesses"`
+	AccessSelfChecks          []models.AccessSelfCheck          `json:"access_self_checks"`
+	ComplaintLogs             []models.ComplaintLog             `json:"complaint_logs"`
+}
+
+// ReferencedUserIDs mengembalikan ID (database asal) user lain yang tercatat sebagai peresep,
+// pencatat atau penanggap pada data di bundle.
+func (b UserDataBundle) ReferencedUserIDs() []uint {
+	var ids []uint
+	for _, prescription := range b.FluidPrescriptions {
+		ids = append(ids, prescription.PrescribedBy)
+	}
+	for _, reading := range b.IntradialyticReadings {
+		ids = append(ids, reading.RecordedBy)
+	}
+	for _, result := range b.LabResults {
+		ids = append(ids, result.EnteredBy)
+	}
+	for _, complaint := range b.ComplaintLogs {
+		if complaint.AcknowledgedBy != nil {
+			ids = append(ids, *complaint.AcknowledgedBy)
+		}
+	}
+	return ids
+}
+
+// UserDataRepository mengekspor dan mengimpor seluruh data satu user dalam satu transaksi.
+type UserDataRepository interface {
+	Export(userID uint) (UserDataBundle, error)
+	// Import membuat user dari bundle. userIDs memetakan ID user lain di database asal
+	// (lihat ReferencedUserIDs) ke ID di database ini.
+	Import(bundle UserDataBundle, userIDs map[uint]uint) (models.User, error)
+}
+
+type userDataRepository struct {
+	db *gorm.DB
+}
+
+func NewUserDataRepository(db *gorm.DB) UserDataRepository {
+	return &userDataRepository{db: db}
+}
+
+func (r *userDataRepository) Export(userID uint) (UserDataBundle, error) {
+	bundle := UserDataBundle{
+		FormatVersion:   UserDataFormatVersion,
+		ExportedAt:      time.Now().UTC(),
+		ReferencedUsers: map[uint]string{},
+		LabTestCodes:    map[uint]string{},
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&bundle.User, userID).Error; err != nil {
+			return err
+		}
+
+		byUser := func(dest interface{}) error {
+			return tx.Where("user_id = ?", userID).Order("id asc").Find(dest).Error
+		}
+		for _, dest := range []interface{}{
+			&bundle.DrugSchedules, &bundle.ControlSchedules, &bundle.HemodialysisSchedules,
+			&bundle.MedicationRefillSchedules, &bundle.FluidPrescriptions, &bundle.FluidBalanceLogs,
+			&bundle.FluidEntries, &bundle.HemodialysisMonitorings, &bundle.LabResults,
+			&bundle.VascularAccesses, &bundle.AccessSelfChecks, &bundle.ComplaintLogs,
+		} {
+			if err := byUser(dest); err != nil {
+				return err
+			}
+		}
+		monitoringIDs := tx.Model(&models.HemodialysisMonitoring{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("hemodialysis_monitoring_id IN (?)", monitoringIDs).Order("id asc").Find(&bundle.IntradialyticReadings).Error; err != nil {
+			return err
+		}
+
+		// Kumpulkan user lain yang direferensikan agar bisa dicocokkan lewat email saat impor
+		referencedIDs := bundle.ReferencedUserIDs()
+		if len(referencedIDs) > 0 {
+			var users []models.User
+			if err := tx.Where("id IN ?", referencedIDs).Find(&users).Error; err != nil {
+				return err
+			}
+			for _, user := range users {
+				bundle.ReferencedUsers[user.ID] = user.Email
+			}
+		}
+
+		var labTestIDs []uint
+		for _, result := range bundle.LabResults {
+			labTestIDs = append(labTestIDs, result.LabTestID)
+		}
+		if len(labTestIDs) > 0 {
+			var tests []models.LabTest
+			if err := tx.Where("id IN ?", labTestIDs).Find(&tests).Error; err != nil {
+				return err
+			}
+			for _, test := range tests {
+				bundle.LabTestCodes[test.ID] = test.Code
+			}
+		}
+		return nil
+	})
+	return bundle, err
+}
+
+// Import membuat user baru beserta seluruh datanya. ID lama dipetakan ulang ke ID baru; referensi
+// yang tidak bisa dipetakan (aturan triase, preset wadah, balasan & lampiran keluhan) tidak dibawa.
+func (r *userDataRepository) Import(bundle UserDataBundle, userIDs map[uint]uint) (models.User, error) {
+	if bundle.FormatVersion != UserDataFormatVersion {
+		return models.User{}, fmt.Errorf("versi format %d tidak didukung (harus %d)", bundle.FormatVersion, UserDataFormatVersion)
+	}
+
+	user := bundle.User
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// GORM mengganti nilai false/0 dengan default tag saat Create (misal is_active=false menjadi
+		// true), jadi salinan asli ditulis ulang setelah insert. UpdateColumns tidak mengubah updated_at.
+		create := func(value interface{}) error {
+			exact := reflect.New(reflect.TypeOf(value).Elem())
+			exact.Elem().Set(reflect.ValueOf(value).Elem())
+			if err := tx.Omit(clause.Associations).Create(value).Error; err != nil {
+				return err
+			}
+			return tx.Model(value).Select("*").Omit("id", clause.Associations).UpdateColumns(exact.Interface()).Error
+		}
+
+		oldUserID := user.ID
+		user.ID = 0
+		user.ProfilePicture = "" // File foto ada di storage asal
+		if err := create(&user); err != nil {
+			return fmt.Errorf("gagal membuat user: %w", err)
+		}
+
+		// resolveUser memetakan ID user di database asal ke ID di database ini
+		resolveUser := func(oldID uint) (uint, error) {
+			if oldID == oldUserID {
+				return user.ID, nil
+			}
+			if id, ok := userIDs[oldID]; ok {
+				return id, nil
+			}
+			return 0, fmt.Errorf("user %d yang direferensikan di file tidak dipetakan", oldID)
+		}
+		var err error
+
+		for _, schedule := range bundle.DrugSchedules {
+			schedule.ID, schedule.UserID = 0, user.ID
+			if err := create(&schedule); err != nil {
+				return fmt.Errorf("gagal mengimpor jadwal obat: %w", err)
+			}
+		}
+		for _, schedule := range bundle.ControlSchedules {
+			schedule.ID, schedule.UserID = 0, user.ID
+			if err := create(&schedule); err != nil {
+				return fmt.Errorf("gagal mengimpor jadwal kontrol: %w", err)
+			}
+		}
+		hemodialysisScheduleIDs := map[uint]uint{}
+		for _, schedule := range bundle.HemodialysisSchedules {
+			oldID := schedule.ID
+			schedule.ID, schedule.UserID = 0, user.ID
+			if err := create(&schedule); err != nil {
+				return fmt.Errorf("gagal mengimpor jadwal hemodialisa: %w", err)
+			}
+			hemodialysisScheduleIDs[oldID] = schedule.ID
+		}
+		for _, schedule := range bundle.MedicationRefillSchedules {
+			schedule.ID, schedule.UserID = 0, user.ID
+			if err := create(&schedule); err != nil {
+				return fmt.Errorf("gagal mengimpor jadwal ambil obat: %w", err)
+			}
+		}
+
+		for _, prescription := range bundle.FluidPrescriptions {
+			prescription.ID, prescription.UserID = 0, user.ID
+			if prescription.PrescribedBy, err = resolveUser(prescription.PrescribedBy); err != nil {
+				return err
+			}
+			if err := create(&prescription); err != nil {
+				return fmt.Errorf("gagal mengimpor resep cairan: %w", err)
+			}
+		}
+		fluidLogIDs := map[uint]uint{}
+		for _, fluidLog := range bundle.FluidBalanceLogs {
+			oldID := fluidLog.ID
+			fluidLog.ID, fluidLog.UserID = 0, user.ID
+			if err := create(&fluidLog); err != nil {
+				return fmt.Errorf("gagal mengimpor log cairan: %w", err)
+			}
+			fluidLogIDs[oldID] = fluidLog.ID
+		}
+		for _, entry := range bundle.FluidEntries {
+			logID, ok := fluidLogIDs[entry.FluidBalanceLogID]
+			if !ok {
+				return fmt.Errorf("entri cairan %d merujuk log cairan %d yang tidak ada di file", entry.ID, entry.FluidBalanceLogID)
+			}
+			entry.ID, entry.UserID, entry.FluidBalanceLogID = 0, user.ID, logID
+			entry.ContainerPresetID = nil // Katalog preset bisa berbeda, volume sudah tersimpan di entri
+			if err := create(&entry); err != nil {
+				return fmt.Errorf("gagal mengimpor entri cairan: %w", err)
+			}
+		}
+
+		monitoringIDs := map[uint]uint{}
+		for _, monitoring := range bundle.HemodialysisMonitorings {
+			oldID := monitoring.ID
+			monitoring.ID, monitoring.UserID = 0, user.ID
+			if monitoring.HemodialysisScheduleID != nil {
+				if scheduleID, ok := hemodialysisScheduleIDs[*monitoring.HemodialysisScheduleID]; ok {
+					monitoring.HemodialysisScheduleID = &scheduleID
+				} else {
+					monitoring.HemodialysisScheduleID = nil
+				}
+			}
+			if err := create(&monitoring); err != nil {
+				return fmt.Errorf("gagal mengimpor monitoring hemodialisa: %w", err)
+			}
+			monitoringIDs[oldID] = monitoring.ID
+		}
+		for _, reading := range bundle.IntradialyticReadings {
+			monitoringID, ok := monitoringIDs[reading.HemodialysisMonitoringID]
+			if !ok {
+				return fmt.Errorf("pembacaan intradialitik %d merujuk monitoring %d yang tidak ada di file", reading.ID, reading.HemodialysisMonitoringID)
+			}
+			reading.ID, reading.HemodialysisMonitoringID = 0, monitoringID
+			if reading.RecordedBy, err = resolveUser(reading.RecordedBy); err != nil {
+				return err
+			}
+			if err := create(&reading); err != nil {
+				return fmt.Errorf("gagal mengimpor pembacaan intradialitik: %w", err)
+			}
+		}
+
+		labTestIDs := map[uint]uint{}
+		for oldID, code := range bundle.LabTestCodes {
+			var test models.LabTest
+			if err := tx.Where("code = ?", code).First(&test).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("tes lab '%s' tidak ada di katalog database tujuan", code)
+				}
+				return err
+			}
+			labTestIDs[oldID] = test.ID
+		}
+		for _, result := range bundle.LabResults {
+			testID, ok := labTestIDs[result.LabTestID]
+			if !ok {
+				return fmt.Errorf("hasil lab %d merujuk tes lab %d yang tidak ada di file", result.ID, result.LabTestID)
+			}
+			result.ID, result.UserID, result.LabTestID = 0, user.ID, testID
+			if result.EnteredBy, err = resolveUser(result.EnteredBy); err != nil {
+				return err
+			}
+			if err := create(&result); err != nil {
+				return fmt.Errorf("gagal mengimpor hasil lab: %w", err)
+			}
+		}
+
+		complaintIDs := map[uint]uint{}
+		for _, complaint := range bundle.ComplaintLogs {
+			oldID := complaint.ID
+			complaint.ID, complaint.UserID = 0, user.ID
+			complaint.TriageRuleID = nil // Nama aturan tetap tersimpan di TriageRuleName
+			complaint.Replies, complaint.Attachments = nil, nil
+			if complaint.AcknowledgedBy != nil {
+				id, err := resolveUser(*complaint.AcknowledgedBy)
+				if err != nil {
+					return err
+				}
+				complaint.AcknowledgedBy = &id
+			}
+			if err := create(&complaint); err != nil {
+				return fmt.Errorf("gagal mengimpor keluhan: %w", err)
+			}
+			complaintIDs[oldID] = complaint.ID
+		}
+
+		accessIDs := map[uint]uint{}
+		for _, access := range bundle.VascularAccesses {
+			oldID := access.ID
+			access.ID, access.UserID = 0, user.ID
+			if err := create(&access); err != nil {
+				return fmt.Errorf("gagal mengimpor akses vaskular: %w", err)
+			}
+			accessIDs[oldID] = access.ID
+		}
+		for _, check := range bundle.AccessSelfChecks {
+			accessID, ok := accessIDs[check.VascularAccessID]
+			if !ok {
+				return fmt.Errorf("pemeriksaan akses %d merujuk akses vaskular %d yang tidak ada di file", check.ID, check.VascularAccessID)
+			}
+			check.ID, check.UserID, check.VascularAccessID = 0, user.ID, accessID
+			if check.ComplaintLogID != nil {
+				if complaintID, ok := complaintIDs[*check.ComplaintLogID]; ok {
+					check.ComplaintLogID = &complaintID
+				} else {
+					check.ComplaintLogID = nil
+				}
+			}
+			if err := create(&check); err != nil {
+				return fmt.Errorf("gagal mengimpor pemeriksaan akses: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
@@ -13,6 +13,9 @@ type UserRepository interface {
 	Update(user models.User) (models.User, error)
 	UpdateTimeZone (user models.User) (models.User, error)
 	CountByRole(role string) (int64, error)
+	FindByRole(role string) ([]models.User, error)
+	FindDistinctTimezones() ([]string, error)
+	FindAll() ([]models.User, error)
 }
 
 type userRepository struct {
@@ -80,4 +83,23 @@ func (r *userRepository) CountByRole(role string) (int64, error) {
 	// Menghitung record di tabel 'users' yang cocok dengan 'role'
 	err := r.db.Model(&models.User{}).Where("role = ?", role).Count(&count).Error
 	return count, err
-}
\ No newline at end of file
+}
+
+func (r *userRepository) FindByRole(role string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("role = ?", role).Find(&users).Error
+	return users, err
+}
+
+// FindDistinctTimezones mengambil semua timezone unik yang dipakai user.
+func (r *userRepository) FindDistinctTimezones() ([]string, error) {
+	var timezones []string
+	err := r.db.Model(&models.User{}).Distinct("timezone").Pluck("timezone", &timezones).Error
+	return timezones, err
+}
+
+func (r *userRepository) FindAll() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Order("id asc").Find(&users).Error
+	return users, err
+}
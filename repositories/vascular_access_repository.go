@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VascularAccessRepository interface {
+	Create(access models.VascularAccess) (models.VascularAccess, error)
+	Update(access models.VascularAccess) (models.VascularAccess, error)
+	FindByID(id uint) (models.VascularAccess, error)
+	FindByUserID(userID uint) ([]models.VascularAccess, error)
+	FindDueSelfCheckByTimezone(date string, timezone string) ([]models.VascularAccess, error)
+
+	SaveCheck(check models.AccessSelfCheck, complaint *models.ComplaintLog) (models.AccessSelfCheck, error)
+	MarkCheckEscalated(id uint, at time.Time) (bool, error)
+	FindCheckByID(id uint) (models.AccessSelfCheck, error)
+	FindCheckByAccessAndDate(accessID uint, date time.Time) (models.AccessSelfCheck, error)
+	FindChecksByAccessAndDateRange(accessID uint, from, to time.Time) ([]models.AccessSelfCheck, error)
+}
+
+type vascularAccessRepository struct {
+	db *gorm.DB
+}
+
+func NewVascularAccessRepository(db *gorm.DB) VascularAccessRepository {
+	return &vascularAccessRepository{db: db}
+}
+
+func (r *vascularAccessRepository) Create(access models.VascularAccess) (models.VascularAccess, error) {
+	err := r.db.Create(&access).Error
+	return access, err
+}
+
+func (r *vascularAccessRepository) Update(access models.VascularAccess) (models.VascularAccess, error) {
+	err := r.db.Save(&access).Error
+	return access, err
+}
+
+func (r *vascularAccessRepository) FindByID(id uint) (models.VascularAccess, error) {
+	var access models.VascularAccess
+	err := r.db.First(&access, id).Error
+	return access, err
+}
+
+// FindByUserID mengambil semua akses pasien, yang aktif dan terbaru lebih dulu.
+func (r *vascularAccessRepository) FindByUserID(userID uint) ([]models.VascularAccess, error) {
+	var accesses []models.VascularAccess
+	err := r.db.Where("user_id = ?", userID).Order("is_active desc, created_on desc").Find(&accesses).Error
+	return accesses, err
+}
+
+// FindDueSelfCheckByTimezone mengambil fistula/graft aktif milik user di timezone tertentu
+// yang belum diperiksa pada tanggal (YYYY-MM-DD lokal) tersebut.
+func (r *vascularAccessRepository) FindDueSelfCheckByTimezone(date string, timezone string) ([]models.VascularAccess, error) {
+	var accesses []models.VascularAccess
+	err := r.db.Joins("JOIN users ON users.id = vascular_accesses.user_id").
+		Where("vascular_accesses.is_active = ? AND vascular_accesses.access_type IN ? AND users.timezone = ?", true, []string{models.AccessTypeAVF, models.AccessTypeAVG}, timezone).
+		Where("NOT EXISTS (SELECT 1 FROM access_self_checks WHERE access_self_checks.vascular_access_id = vascular_accesses.id AND access_self_checks.check_date = ?)", date).
+		Find(&accesses).Error
+	return accesses, err
+}
+
+// SaveCheck menyimpan pemeriksaan mandiri. Bila complaint tidak nil, keluhan eskalasi dibuat
+// dalam transaksi yang sama agar tidak ada keluhan yatim saat penyimpanan pemeriksaan gagal.
+func (r *vascularAccessRepository) SaveCheck(check models.AccessSelfCheck, complaint *models.ComplaintLog) (models.AccessSelfCheck, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if complaint != nil {
+			if err := tx.Create(complaint).Error; err != nil {
+				return err
+			}
+			check.ComplaintLogID = &complaint.ID
+		}
+		return tx.Omit(clause.Associations).Save(&check).Error
+	})
+	return check, err
+}
+
+// MarkCheckEscalated hanya mengisi escalated_at yang masih kosong.
+func (r *vascularAccessRepository) MarkCheckEscalated(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&models.AccessSelfCheck{}).Where("id = ? AND escalated_at IS NULL", id).Update("escalated_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *vascularAccessRepository) FindCheckByID(id uint) (models.AccessSelfCheck, error) {
+	var check models.AccessSelfCheck
+	err := r.db.Preload("VascularAccess").First(&check, id).Error
+	return check, err
+}
+
+func (r *vascularAccessRepository) FindCheckByAccessAndDate(accessID uint, date time.Time) (models.AccessSelfCheck, error) {
+	var check models.AccessSelfCheck
+	err := r.db.Where("vascular_access_id = ? AND check_date = ?", accessID, date.Format("2006-01-02")).First(&check).Error
+	return check, err
+}
+
+// FindChecksByAccessAndDateRange mengambil pemeriksaan dalam rentang tanggal (inklusif), terbaru lebih dulu.
+func (r *vascularAccessRepository) FindChecksByAccessAndDateRange(accessID uint, from, to time.Time) ([]models.AccessSelfCheck, error) {
+	var checks []models.AccessSelfCheck
+	err := r.db.Where("vascular_access_id = ? AND check_date BETWEEN ? AND ?", accessID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("check_date desc").Find(&checks).Error
+	return checks, err
+}
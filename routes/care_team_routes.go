@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/darmawguna/tirtaapp.git/handlers"
+	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCareTeamRoutes mendaftarkan endpoint tim perawatan (pendamping & klinisi) pasien.
+func SetupCareTeamRoutes(router *gin.Engine, handler *handlers.CareTeamHandler) {
+	routes := router.Group("/api/v1/care-team")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		routes.GET("/", handler.GetMyTeam)
+		routes.POST("/caregivers", handler.AddCaregiver)
+		routes.DELETE("/members/:id", handler.RemoveMyMember)
+		routes.GET("/invitations", handler.GetInvitations)
+		routes.POST("/invitations/:id/accept", handler.AcceptInvitation)
+		routes.DELETE("/invitations/:id", handler.DeclineInvitation)
+
+		// Selain role, handler memeriksa bahwa klinisi sudah ada di tim pasien (admin selalu boleh)
+
+		patients := routes.Group("/patients/:user_id")
+		patients.Use(middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician))
+		{
+			patients.GET("/", handler.GetPatientTeam)
+			patients.POST("/clinicians", handler.AssignClinician)
+			patients.DELETE("/members/:id", handler.RemovePatientMember)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/darmawguna/tirtaapp.git/handlers"
 	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,5 +17,16 @@ func SetupComplaintRoutes(router *gin.Engine, complaintHandler *handlers.Complai
 		// Endpoint untuk melihat riwayat keluhan sendiri
 		complaintRoutes.GET("/", complaintHandler.GetMyComplaints) // <-- TAMBAHKAN INI
 		complaintRoutes.GET("/:id", complaintHandler.GetDetailComplaint)
+		complaintRoutes.GET("/:id/attachments/:attachment_id", complaintHandler.GetAttachment)
+		complaintRoutes.POST("/:id/replies", complaintHandler.AddReply)
+		complaintRoutes.POST("/:id/close", complaintHandler.Close)
+
+		// Antrean & penerimaan keluhan oleh petugas
+		staff := complaintRoutes.Group("")
+		staff.Use(middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician))
+		{
+			staff.GET("/queue", complaintHandler.GetQueue)
+			staff.POST("/:id/acknowledge", complaintHandler.Acknowledge)
+		}
 	}
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/darmawguna/tirtaapp.git/handlers"
 	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,6 +14,8 @@ func SetupEducationRoutes(router *gin.Engine, educationHandler *handlers.Educati
 	{
 		// Semua user yang login bisa melihat daftar edukasi.
 		educationRoutes.GET("/", educationHandler.GetAll)
+		educationRoutes.GET("/categories", educationHandler.GetCategories)
+		educationRoutes.GET("/tags", educationHandler.GetTags)
 		educationRoutes.GET("/:id", educationHandler.GetByID)
 
 		// Hanya Admin yang bisa CUD.
@@ -20,8 +23,66 @@ func SetupEducationRoutes(router *gin.Engine, educationHandler *handlers.Educati
 		adminRoutes.Use(middlewares.AdminMiddleware())
 		{
 			adminRoutes.POST("/", educationHandler.Create)
+			adminRoutes.PUT("/featured", educationHandler.SetFeatured)
 			adminRoutes.PUT("/:id", educationHandler.Update)
 			adminRoutes.DELETE("/:id", educationHandler.Delete)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// SetupEducationEngagementRoutes mendaftarkan pelacakan tayangan, progres pasien dan statistik edukasi.
+func SetupEducationEngagementRoutes(router *gin.Engine, handler *handlers.EducationEngagementHandler) {
+	routes := router.Group("/api/v1/educations")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		routes.POST("/:id/views", handler.RecordView)
+		routes.POST("/:id/complete", handler.MarkCompleted)
+		routes.GET("/progress", handler.GetProgress)
+
+		patients := routes.Group("/patients/:user_id")
+		patients.Use(middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician))
+		{
+			patients.GET("/progress", handler.GetProgress)
+		}
+
+		admin := routes.Group("/")
+		admin.Use(middlewares.AdminMiddleware())
+		{
+			admin.GET("/analytics", handler.GetAnalytics)
+		}
+	}
+}
+
+// SetupEducationRecommendationRoutes mendaftarkan rekomendasi edukasi dan pengaturan pemicunya.
+func SetupEducationRecommendationRoutes(router *gin.Engine, handler *handlers.EducationRecommendationHandler) {
+	routes := router.Group("/api/v1/educations")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		routes.GET("/recommended", handler.GetRecommended)
+
+		admin := routes.Group("/")
+		admin.Use(middlewares.AdminMiddleware())
+		{
+			admin.GET("/:id/triggers", handler.GetTriggers)
+			admin.PUT("/:id/triggers", handler.SetTriggers)
+		}
+	}
+}
+
+// SetupEducationMediaRoutes mendaftarkan penyajian media edukasi dan upload bertahap untuk admin.
+func SetupEducationMediaRoutes(router *gin.Engine, handler *handlers.EducationMediaHandler) {
+	routes := router.Group("/api/v1/educations")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		routes.GET("/:id/media", handler.GetMedia)
+		routes.HEAD("/:id/media", handler.GetMedia)
+
+		admin := routes.Group("/uploads")
+		admin.Use(middlewares.AdminMiddleware())
+		{
+			admin.POST("", handler.StartUpload)
+			admin.GET("/:upload_id", handler.GetUpload)
+			admin.PUT("/:upload_id", handler.UploadChunk)
+		}
+	}
+}
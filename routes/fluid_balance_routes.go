@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/darmawguna/tirtaapp.git/handlers" // Sesuaikan path
 	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,5 +14,20 @@ func SetupFluidBalanceRoutes(router *gin.Engine, handler *handlers.FluidBalanceH
 	{
 		routes.POST("/", handler.CreateOrUpdate) // Endpoint untuk input harian
 		routes.GET("/", handler.GetHistory)     // Endpoint untuk riwayat
+		routes.GET("/allowance", handler.GetAllowance) // Batas & sisa cairan hari ini
+		routes.GET("/timeline", handler.GetTimeline)   // Catatan per item dalam satu hari
+		routes.POST("/entries", handler.AddEntry)
+		routes.PUT("/entries/:id", handler.UpdateEntry)
+		routes.DELETE("/entries/:id", handler.DeleteEntry)
+		routes.GET("/stats", handler.GetStats)        // Agregat & tren (?from=&to=&granularity=)
+		routes.GET("/history", handler.GetHistoryRange) // Riwayat rentang tanggal dengan paginasi
+
+		// Tinjauan kepatuhan pasien oleh klinisi
+		patients := routes.Group("/patients/:user_id")
+		patients.Use(middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician))
+		{
+			patients.GET("/stats", handler.GetPatientStats)
+			patients.GET("/history", handler.GetPatientHistory)
+		}
 	}
 }
\ No newline at end of file
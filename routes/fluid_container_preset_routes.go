@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/darmawguna/tirtaapp.git/handlers"
+	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupFluidContainerPresetRoutes(router *gin.Engine, handler *handlers.FluidContainerPresetHandler) {
+	routes := router.Group("/api/v1/fluids/containers")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		// Semua user yang login bisa melihat daftar wadah
+		routes.GET("/", handler.GetAll)
+
+		// Hanya Admin yang bisa CUD
+		adminRoutes := routes.Group("/")
+		adminRoutes.Use(middlewares.AdminMiddleware())
+		{
+			adminRoutes.POST("/", handler.Create)
+			adminRoutes.PUT("/:id", handler.Update)
+			adminRoutes.DELETE("/:id", handler.Delete)
+		}
+	}
+}
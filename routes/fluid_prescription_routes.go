@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/darmawguna/tirtaapp.git/handlers"
+	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupFluidPrescriptionRoutes mendaftarkan endpoint resep batas cairan (khusus klinisi & admin).
+func SetupFluidPrescriptionRoutes(router *gin.Engine, handler *handlers.FluidPrescriptionHandler) {
+	routes := router.Group("/api/v1/fluids/prescriptions")
+	routes.Use(middlewares.AuthMiddleware())
+	routes.Use(middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician))
+	{
+		routes.GET("/:user_id", handler.GetHistory)
+		routes.POST("/:user_id", handler.Create)
+	}
+}
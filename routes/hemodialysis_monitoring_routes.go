@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/darmawguna/tirtaapp.git/handlers" // Adjust path
 	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,8 +15,10 @@ func SetupHemodialysisMonitoringRoutes(router *gin.Engine, handler *handlers.Hem
 		routes.POST("/", handler.Create)
 		// Get monitoring history for the logged-in user
 		routes.GET("/history", handler.GetHistory)
+		// Sesi dengan IDWG melewati ambang, untuk ditinjau klinisi
+		routes.GET("/idwg-alerts", middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician), handler.GetIDWGAlerts)
 		routes.GET("/:id", handler.GetByID)
-		// Get specific monitoring data by schedule ID (Add handler if needed)
-		// routes.GET("/:schedule_id", handler.GetByScheduleID)
+		// Data pemantauan untuk satu sesi HD
+		routes.GET("/by-schedule/:schedule_id", handler.GetBySchedule)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/darmawguna/tirtaapp.git/handlers"
+	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupIntradialyticReadingRoutes mendaftarkan endpoint tanda vital selama sesi HD.
+// Pencatatan hanya oleh klinisi/admin; pasien boleh melihat deret waktunya sendiri.
+func SetupIntradialyticReadingRoutes(router *gin.Engine, handler *handlers.IntradialyticReadingHandler) {
+	routes := router.Group("/api/v1/hemodialysis-monitoring/:id/readings")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		routes.GET("/", handler.GetSeries)
+		routes.POST("/", middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician), handler.Create)
+		routes.DELETE("/:reading_id", middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician), handler.Delete)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/darmawguna/tirtaapp.git/handlers"
+	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupJobRoutes mendaftarkan endpoint admin untuk memantau dan memicu job worker.
+func SetupJobRoutes(router *gin.Engine, handler *handlers.JobHandler) {
+	jobRoutes := router.Group("/api/v1/admin/jobs")
+	jobRoutes.Use(middlewares.AuthMiddleware())
+	jobRoutes.Use(middlewares.AdminMiddleware())
+	{
+		jobRoutes.GET("/", handler.GetAll)
+		jobRoutes.GET("/:name/runs", handler.GetRuns)
+		jobRoutes.POST("/:name/trigger", handler.Trigger)
+	}
+}
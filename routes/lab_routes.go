@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"github.com/darmawguna/tirtaapp.git/handlers"
+	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupLabTestRoutes mendaftarkan katalog tes lab. Semua user bisa melihat, hanya admin yang mengubah.
+func SetupLabTestRoutes(router *gin.Engine, handler *handlers.LabTestHandler) {
+	routes := router.Group("/api/v1/labs/tests")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		routes.GET("/", handler.GetAll)
+
+		adminRoutes := routes.Group("/")
+		adminRoutes.Use(middlewares.AdminMiddleware())
+		{
+			adminRoutes.POST("/", handler.Create)
+			adminRoutes.PUT("/:id", handler.Update)
+			adminRoutes.DELETE("/:id", handler.Delete)
+		}
+	}
+}
+
+// SetupLabResultRoutes mendaftarkan hasil lab. Pasien mencatat & melihat datanya sendiri;
+// klinisi (pasien yang ditugaskan) dan admin lewat /patients/:user_id.
+func SetupLabResultRoutes(router *gin.Engine, handler *handlers.LabResultHandler) {
+	routes := router.Group("/api/v1/labs")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		routes.POST("/results", handler.Create)
+		routes.GET("/results", handler.GetResults)
+		routes.DELETE("/results/:id", handler.Delete)
+		routes.GET("/trends/:test_code", handler.GetTrend)
+		routes.GET("/adequacy", handler.GetAdequacy)
+
+		patients := routes.Group("/patients/:user_id")
+		patients.Use(middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician))
+		{
+			patients.POST("/results", handler.Create)
+			patients.GET("/results", handler.GetResults)
+			patients.GET("/trends/:test_code", handler.GetTrend)
+			patients.GET("/adequacy", handler.GetAdequacy)
+		}
+	}
+}
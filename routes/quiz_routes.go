@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/darmawguna/tirtaapp.git/handlers"
 	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,6 +16,15 @@ func SetupQuizRoutes(router *gin.Engine, quizHandler *handlers.QuizHandler) {
 		// Hanya user biasa & admin bisa melihat semua kuis
 		quizRoutes.GET("/", quizHandler.GetAll)
 		quizRoutes.GET("/:id", quizHandler.GetByID)
+		// Pengerjaan kuis native & riwayat nilai milik sendiri
+		quizRoutes.POST("/:id/attempts", quizHandler.SubmitAttempt)
+		quizRoutes.GET("/attempts", quizHandler.GetHistory)
+		// Klinisi & admin melihat riwayat kuis pasien
+		patients := quizRoutes.Group("/patients/:user_id")
+		patients.Use(middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician))
+		{
+			patients.GET("/attempts", quizHandler.GetHistory)
+		}
 		// Hanya ADMIN yang bisa membuat, update, dan delete
 		adminRoutes := quizRoutes.Group("/")
 		adminRoutes.Use(middlewares.AdminMiddleware())
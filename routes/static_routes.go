@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/darmawguna/tirtaapp.git/handlers"
+	"github.com/darmawguna/tirtaapp.git/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupStaticRoutes menyajikan file dari BlobStore: thumbnail edukasi publik di /static,
+// foto profil hanya lewat URL bertanda tangan berumur pendek di /media.
+func SetupStaticRoutes(router *gin.Engine, handler *handlers.StaticFileHandler) {
+	router.GET("/static/"+storage.PrefixEducations+"/*filepath", handler.Serve(storage.PrefixEducations))
+	router.HEAD("/static/"+storage.PrefixEducations+"/*filepath", handler.Serve(storage.PrefixEducations))
+
+	router.GET("/media/"+storage.PrefixProfiles+"/*filepath", handler.ServeSigned(storage.PrefixProfiles))
+	router.HEAD("/media/"+storage.PrefixProfiles+"/*filepath", handler.ServeSigned(storage.PrefixProfiles))
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/darmawguna/tirtaapp.git/handlers"
+	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSymptomRoutes mendaftarkan katalog gejala. Semua user bisa melihat, hanya admin yang mengubah.
+func SetupSymptomRoutes(router *gin.Engine, handler *handlers.SymptomHandler) {
+	routes := router.Group("/api/v1/symptoms")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		routes.GET("/", handler.GetAll)
+
+		adminRoutes := routes.Group("/")
+		adminRoutes.Use(middlewares.AdminMiddleware())
+		{
+			adminRoutes.POST("/", handler.Create)
+			adminRoutes.PUT("/:id", handler.Update)
+			adminRoutes.DELETE("/:id", handler.Delete)
+		}
+	}
+}
+
+// SetupTriageRuleRoutes mendaftarkan aturan triase keluhan (khusus admin).
+func SetupTriageRuleRoutes(router *gin.Engine, handler *handlers.TriageRuleHandler) {
+	routes := router.Group("/api/v1/triage-rules")
+	routes.Use(middlewares.AuthMiddleware(), middlewares.AdminMiddleware())
+	{
+		routes.GET("/", handler.GetAll)
+		routes.POST("/", handler.Create)
+		routes.POST("/preview", handler.Preview)
+		routes.PUT("/:id", handler.Update)
+		routes.DELETE("/:id", handler.Delete)
+	}
+}
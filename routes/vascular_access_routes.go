@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/darmawguna/tirtaapp.git/handlers"
+	middlewares "github.com/darmawguna/tirtaapp.git/middleware"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupVascularAccessRoutes mendaftarkan akses vaskular & pemeriksaan mandiri harian.
+// Pemeriksaan diisi pasien; klinisi & admin bisa mencatat akses dan melihat riwayat pemeriksaan.
+func SetupVascularAccessRoutes(router *gin.Engine, handler *handlers.VascularAccessHandler) {
+	routes := router.Group("/api/v1/vascular-access")
+	routes.Use(middlewares.AuthMiddleware())
+	{
+		routes.GET("/", handler.GetAll)
+		routes.POST("/", handler.Create)
+		routes.PUT("/:id", handler.Update)
+		routes.POST("/:id/checks", handler.SubmitCheck)
+		routes.GET("/:id/checks", handler.GetChecks)
+
+		patients := routes.Group("/patients/:user_id")
+		patients.Use(middlewares.RoleMiddleware(models.RoleAdmin, models.RoleClinician))
+		{
+			patients.GET("/", handler.GetAll)
+			patients.POST("/", handler.Create)
+		}
+	}
+}
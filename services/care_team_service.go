@@ -0,0 +1,214 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCareTeamMemberNotFound = errors.New("anggota tim perawatan tidak ditemukan")
+	ErrCareTeamMemberExists   = errors.New("user sudah terdaftar di tim perawatan pasien ini")
+	ErrCareTeamInvalidMember  = errors.New("user tidak dapat ditambahkan ke tim perawatan")
+	ErrPatientAccessDenied    = errors.New("tidak berwenang mengakses data pasien ini")
+	ErrCareTeamInviteNotFound = errors.New("undangan tim perawatan tidak ditemukan")
+)
+
+// authorizePatientAccess memeriksa hak akses ke data klinis pasien: admin selalu boleh,
+// klinisi hanya untuk pasien yang ditugaskan kepadanya, selain itu hanya pasien itu sendiri.
+func authorizePatientAccess(careTeamRepo repositories.CareTeamRepository, requesterID uint, requesterRole string, patientID uint) error {
+	switch requesterRole {
+	case models.RoleAdmin:
+		return nil
+	case models.RoleClinician:
+		member, err := careTeamRepo.FindByPatientAndMember(patientID, requesterID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPatientAccessDenied
+			}
+			return fmt.Errorf("gagal memeriksa tim perawatan: %w", err)
+		}
+		if member.AcceptedAt == nil {
+			return ErrPatientAccessDenied
+		}
+		return nil
+	}
+	if requesterID != patientID {
+		return ErrPatientAccessDenied
+	}
+	return nil
+}
+
+type CareTeamService interface {
+	GetTeam(patientID uint) ([]models.CareTeamMember, error)
+	AddCaregiver(patientID uint, email string) (models.CareTeamMember, error)
+	AssignClinician(patientID, clinicianID uint) (models.CareTeamMember, error)
+	RemoveMember(patientID, memberLinkID uint) error
+	// AuthorizeManager memastikan requester boleh mengelola tim perawatan pasien lain:
+	// admin, atau klinisi yang sudah ada di tim pasien tersebut.
+	AuthorizeManager(requesterID uint, requesterRole string, patientID uint) error
+	GetInvitations(memberID uint) ([]models.CareTeamMember, error)
+	AcceptInvitation(memberID, memberLinkID uint) (models.CareTeamMember, error)
+	DeclineInvitation(memberID, memberLinkID uint) error
+}
+
+type careTeamService struct {
+	repo     repositories.CareTeamRepository
+	userRepo repositories.UserRepository
+}
+
+func NewCareTeamService(repo repositories.CareTeamRepository, userRepo repositories.UserRepository) CareTeamService {
+	return &careTeamService{repo: repo, userRepo: userRepo}
+}
+
+func (s *careTeamService) GetTeam(patientID uint) ([]models.CareTeamMember, error) {
+	if _, err := s.findPatient(patientID); err != nil {
+		return nil, err
+	}
+	members, err := s.repo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil tim perawatan: %w", err)
+	}
+	return members, nil
+}
+
+// AddCaregiver mengundang akun pendamping (harus sudah terdaftar) ke tim pasien. Pendamping baru
+// menerima notifikasi setelah undangan diterima lewat AcceptInvitation.
+func (s *careTeamService) AddCaregiver(patientID uint, email string) (models.CareTeamMember, error) {
+	caregiver, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.CareTeamMember{}, errors.New("akun pendamping dengan email tersebut tidak ditemukan")
+		}
+		return models.CareTeamMember{}, fmt.Errorf("gagal mencari akun pendamping: %w", err)
+	}
+	if caregiver.ID == patientID {
+		return models.CareTeamMember{}, ErrCareTeamInvalidMember
+	}
+	return s.addMember(patientID, caregiver, models.CareRelationCaregiver, nil)
+}
+
+// AssignClinician menugaskan user ber-role clinician sebagai penanggung jawab pasien.
+func (s *careTeamService) AssignClinician(patientID, clinicianID uint) (models.CareTeamMember, error) {
+	clinician, err := s.userRepo.FindByID(clinicianID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.CareTeamMember{}, errors.New("klinisi tidak ditemukan")
+		}
+		return models.CareTeamMember{}, fmt.Errorf("gagal mencari klinisi: %w", err)
+	}
+	if clinician.Role != models.RoleClinician {
+		return models.CareTeamMember{}, ErrCareTeamInvalidMember
+	}
+	now := time.Now()
+	return s.addMember(patientID, clinician, models.CareRelationClinician, &now)
+}
+
+func (s *careTeamService) RemoveMember(patientID, memberLinkID uint) error {
+	member, err := s.repo.FindByID(memberLinkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCareTeamMemberNotFound
+		}
+		return fmt.Errorf("gagal mencari anggota tim perawatan: %w", err)
+	}
+	if member.PatientID != patientID {
+		return ErrCareTeamMemberNotFound
+	}
+	if err := s.repo.Delete(member.ID); err != nil {
+		return fmt.Errorf("gagal menghapus anggota tim perawatan: %w", err)
+	}
+	return nil
+}
+
+func (s *careTeamService) AuthorizeManager(requesterID uint, requesterRole string, patientID uint) error {
+	if requesterRole != models.RoleAdmin && requesterRole != models.RoleClinician {
+		return ErrPatientAccessDenied
+	}
+	return authorizePatientAccess(s.repo, requesterID, requesterRole, patientID)
+}
+
+func (s *careTeamService) GetInvitations(memberID uint) ([]models.CareTeamMember, error) {
+	invitations, err := s.repo.FindPendingByMember(memberID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil undangan tim perawatan: %w", err)
+	}
+	return invitations, nil
+}
+
+func (s *careTeamService) AcceptInvitation(memberID, memberLinkID uint) (models.CareTeamMember, error) {
+	invitation, err := s.findInvitation(memberID, memberLinkID)
+	if err != nil {
+		return models.CareTeamMember{}, err
+	}
+	now := time.Now()
+	accepted, err := s.repo.Accept(invitation.ID, now)
+	if err != nil {
+		return models.CareTeamMember{}, fmt.Errorf("gagal menerima undangan tim perawatan: %w", err)
+	}
+	if !accepted {
+		return models.CareTeamMember{}, ErrCareTeamInviteNotFound
+	}
+	invitation.AcceptedAt = &now
+	return invitation, nil
+}
+
+// DeclineInvitation menolak undangan sehingga pasien bisa mengundang ulang di kemudian hari.
+func (s *careTeamService) DeclineInvitation(memberID, memberLinkID uint) error {
+	invitation, err := s.findInvitation(memberID, memberLinkID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(invitation.ID); err != nil {
+		return fmt.Errorf("gagal menolak undangan tim perawatan: %w", err)
+	}
+	return nil
+}
+
+// findInvitation mengambil undangan milik memberID yang belum diterima.
+func (s *careTeamService) findInvitation(memberID, memberLinkID uint) (models.CareTeamMember, error) {
+	invitation, err := s.repo.FindByID(memberLinkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.CareTeamMember{}, ErrCareTeamInviteNotFound
+		}
+		return models.CareTeamMember{}, fmt.Errorf("gagal mencari undangan tim perawatan: %w", err)
+	}
+	if invitation.MemberID != memberID || invitation.AcceptedAt != nil {
+		return models.CareTeamMember{}, ErrCareTeamInviteNotFound
+	}
+	return invitation, nil
+}
+
+func (s *careTeamService) addMember(patientID uint, member models.User, relation string, acceptedAt *time.Time) (models.CareTeamMember, error) {
+	if _, err := s.findPatient(patientID); err != nil {
+		return models.CareTeamMember{}, err
+	}
+	if _, err := s.repo.FindByPatientAndMember(patientID, member.ID); err == nil {
+		return models.CareTeamMember{}, ErrCareTeamMemberExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.CareTeamMember{}, fmt.Errorf("gagal memeriksa tim perawatan: %w", err)
+	}
+
+	link, err := s.repo.Create(models.CareTeamMember{PatientID: patientID, MemberID: member.ID, Relation: relation, AcceptedAt: acceptedAt})
+	if err != nil {
+		return models.CareTeamMember{}, fmt.Errorf("gagal menambahkan anggota tim perawatan: %w", err)
+	}
+	link.Member = member
+	return link, nil
+}
+
+func (s *careTeamService) findPatient(patientID uint) (models.User, error) {
+	patient, err := s.userRepo.FindByID(patientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.User{}, ErrPatientNotFound
+		}
+		return models.User{}, fmt.Errorf("gagal mencari pasien: %w", err)
+	}
+	return patient, nil
+}
@@ -2,64 +2,314 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/darmawguna/tirtaapp.git/dto"
 	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/spf13/viper"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
+// ScheduleType untuk balasan keluhan yang dikirim ke worker sebagai push.
+const ScheduleTypeComplaintReply = "COMPLAINT_REPLY"
+
+// Default SLA (menit) sebelum keluhan yang belum diterima petugas dieskalasi.
+const (
+	defaultComplaintSLAEmergencyMinutes = 15
+	defaultComplaintSLAUrgentMinutes    = 60
+)
+
+var (
+	ErrComplaintNotFound      = errors.New("keluhan tidak ditemukan")
+	ErrComplaintClosed        = errors.New("keluhan sudah ditutup")
+	ErrComplaintInvalidStatus = errors.New("status keluhan tidak valid")
+	ErrAttachmentNotFound     = errors.New("lampiran tidak ditemukan")
+)
+
+// ComplaintSLA mengembalikan batas waktu penerimaan keluhan per tingkat triase
+// (COMPLAINT_SLA_EMERGENCY_MINUTES, COMPLAINT_SLA_URGENT_MINUTES). 0 berarti tanpa SLA.
+func ComplaintSLA(triageLevel string) time.Duration {
+	var minutes int
+	switch triageLevel {
+	case models.TriageLevelEmergency:
+		minutes = defaultComplaintSLAEmergencyMinutes
+		if viper.IsSet("COMPLAINT_SLA_EMERGENCY_MINUTES") {
+			minutes = viper.GetInt("COMPLAINT_SLA_EMERGENCY_MINUTES")
+		}
+	case models.TriageLevelUrgent:
+		minutes = defaultComplaintSLAUrgentMinutes
+		if viper.IsSet("COMPLAINT_SLA_URGENT_MINUTES") {
+			minutes = viper.GetInt("COMPLAINT_SLA_URGENT_MINUTES")
+		}
+	}
+	if minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
 type ComplaintService interface {
-	ProcessComplaint(userID uint, input dto.CreateComplaintDTO) (string, error)
+	ProcessComplaint(userID uint, input dto.CreateComplaintDTO, attachments []models.ComplaintAttachment) (models.ComplaintLog, TriageOutcome, error)
 	GetMyComplaints(userID uint) ([]models.ComplaintLog, error)
-	GetComplainById (complaint_id uint) (models.ComplaintLog, error)
+	GetComplainById(requesterID uint, requesterRole string, complaint_id uint) (models.ComplaintLog, error)
+	GetAttachment(requesterID uint, requesterRole string, complaintID uint, attachmentID uint) (models.ComplaintAttachment, error)
+	GetQueue(requesterID uint, requesterRole string, statuses []string) ([]models.ComplaintLog, error)
+	Acknowledge(requesterID uint, requesterRole string, complaintID uint) (models.ComplaintLog, error)
+	AddReply(requesterID uint, requesterRole string, complaintID uint, message string) (models.ComplaintReply, error)
+	Close(requesterID uint, requesterRole string, complaintID uint) (models.ComplaintLog, error)
 }
 
 type complaintService struct {
 	complaintRepo repositories.ComplaintRepository
+	triageService TriageService
+	careTeamRepo  repositories.CareTeamRepository
+	queueService  QueueService
 }
 
-func NewComplaintService(complaintRepo repositories.ComplaintRepository) ComplaintService {
-	return &complaintService{complaintRepo: complaintRepo}
+func NewComplaintService(complaintRepo repositories.ComplaintRepository, triageService TriageService, careTeamRepo repositories.CareTeamRepository, queueService QueueService) ComplaintService {
+	return &complaintService{complaintRepo: complaintRepo, triageService: triageService, careTeamRepo: careTeamRepo, queueService: queueService}
 }
 
-func (s *complaintService) ProcessComplaint(userID uint, input dto.CreateComplaintDTO) (string, error) {
-	var generatedMessage string
-	complaintCount := len(input.Complaints)
+// ProcessComplaint menjalankan triase berbasis aturan atas keluhan, lalu menyimpan keluhan
+// beserta tingkat triase, aturan yang cocok, dan lampiran yang sudah disimpan handler.
+// Keluhan masuk antrean petugas dengan status open.
+func (s *complaintService) ProcessComplaint(userID uint, input dto.CreateComplaintDTO, attachments []models.ComplaintAttachment) (models.ComplaintLog, TriageOutcome, error) {
+	outcome, err := s.triageService.Triage(input.Complaints)
+	if err != nil {
+		return models.ComplaintLog{}, TriageOutcome{}, err
+	}
 
-	// Logika bisnis utama
-	if complaintCount == 1 {
-		generatedMessage = "Konsultasikan keluhan bapak/ibu kepada dokter/perawat yang bertugas atau hubungi petugas pada link TANYA PETUGAS"
-	} else if complaintCount > 1 {
-		generatedMessage = "Segera konsultasikan keluhan bapak/ibu ke poliklinik atau faskes terdekat"
+	// Simpan kode gejala yang dikenali dan teks bebas apa adanya
+	complaints := make([]string, 0, len(outcome.Symptoms)+len(outcome.Unrecognized))
+	for _, symptom := range outcome.Symptoms {
+		complaints = append(complaints, symptom.Code)
 	}
+	complaints = append(complaints, outcome.Unrecognized...)
 
 	// Ubah array string menjadi format JSON untuk disimpan
-	complaintsJSON, err := json.Marshal(input.Complaints)
+	complaintsJSON, err := json.Marshal(complaints)
 	if err != nil {
-		return "", err
+		return models.ComplaintLog{}, TriageOutcome{}, err
 	}
 
 	// Buat log untuk disimpan
 	log := models.ComplaintLog{
-		UserID:     userID,
-		Complaints: datatypes.JSON(complaintsJSON),
-		Message:    generatedMessage,
+		UserID:      userID,
+		Complaints:  datatypes.JSON(complaintsJSON),
+		Message:     outcome.Message,
+		TriageLevel: outcome.Level,
+		Status:      models.ComplaintStatusOpen,
+		Attachments: attachments,
+	}
+	for i := range log.Attachments {
+		log.Attachments[i].UserID = userID
+	}
+	if outcome.Rule != nil {
+		log.TriageRuleID = &outcome.Rule.ID
+		log.TriageRuleName = outcome.Rule.Name
 	}
 
 	// Simpan log ke database
-	_, err = s.complaintRepo.Create(log)
+	saved, err := s.complaintRepo.Create(log)
 	if err != nil {
-		return "", err
+		return models.ComplaintLog{}, TriageOutcome{}, err
 	}
 
-	return generatedMessage, nil
+	return saved, outcome, nil
 }
 
 func (s *complaintService) GetMyComplaints(userID uint) ([]models.ComplaintLog, error) {
 	return s.complaintRepo.FindByUserID(userID)
 }
 
-func (s *complaintService) GetComplainById(complaint_id uint) (models.ComplaintLog, error) {
-	return s.complaintRepo.FindByID(complaint_id)
-}
\ No newline at end of file
+// GetComplainById mengambil keluhan beserta percakapannya untuk pemilik, klinisinya, atau admin.
+func (s *complaintService) GetComplainById(requesterID uint, requesterRole string, complaint_id uint) (models.ComplaintLog, error) {
+	return s.findAccessibleComplaint(requesterID, requesterRole, complaint_id)
+}
+
+// GetAttachment mengambil metadata lampiran; hanya pemilik keluhan, klinisinya, atau admin.
+func (s *complaintService) GetAttachment(requesterID uint, requesterRole string, complaintID uint, attachmentID uint) (models.ComplaintAttachment, error) {
+	if _, err := s.findAccessibleComplaint(requesterID, requesterRole, complaintID); err != nil {
+		return models.ComplaintAttachment{}, err
+	}
+	attachment, err := s.complaintRepo.FindAttachmentByID(complaintID, attachmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ComplaintAttachment{}, ErrAttachmentNotFound
+		}
+		return models.ComplaintAttachment{}, fmt.Errorf("gagal mencari lampiran: %w", err)
+	}
+	return attachment, nil
+}
+
+// GetQueue mengambil antrean keluhan untuk petugas. Klinisi hanya melihat pasien yang
+// ditugaskan kepadanya. Tanpa filter status, yang ditampilkan adalah keluhan yang belum dibalas.
+func (s *complaintService) GetQueue(requesterID uint, requesterRole string, statuses []string) ([]models.ComplaintLog, error) {
+	if len(statuses) == 0 {
+		statuses = []string{models.ComplaintStatusOpen, models.ComplaintStatusAcknowledged}
+	}
+	for _, status := range statuses {
+		if !isValidComplaintStatus(status) {
+			return nil, ErrComplaintInvalidStatus
+		}
+	}
+
+	var patientIDs []uint
+	if requesterRole != models.RoleAdmin {
+		var err error
+		patientIDs, err = s.careTeamRepo.FindPatientIDsByMember(requesterID, models.CareRelationClinician)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil daftar pasien: %w", err)
+		}
+		if len(patientIDs) == 0 {
+			return []models.ComplaintLog{}, nil
+		}
+	}
+
+	complaints, err := s.complaintRepo.FindQueue(statuses, patientIDs)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil antrean keluhan: %w", err)
+	}
+	return complaints, nil
+}
+
+// Acknowledge menandai keluhan sudah diterima petugas (menghentikan hitungan SLA).
+func (s *complaintService) Acknowledge(requesterID uint, requesterRole string, complaintID uint) (models.ComplaintLog, error) {
+	complaint, err := s.findAccessibleComplaint(requesterID, requesterRole, complaintID)
+	if err != nil {
+		return models.ComplaintLog{}, err
+	}
+	if complaint.Status == models.ComplaintStatusClosed {
+		return models.ComplaintLog{}, ErrComplaintClosed
+	}
+	if complaint.AcknowledgedAt != nil {
+		return complaint, nil
+	}
+
+	markComplaintAcknowledged(&complaint, requesterID)
+	updated, err := s.complaintRepo.Update(complaint)
+	if err != nil {
+		return models.ComplaintLog{}, fmt.Errorf("gagal memperbarui status keluhan: %w", err)
+	}
+	return updated, nil
+}
+
+// AddReply menambahkan pesan ke percakapan keluhan dan mengirimkannya sebagai push ke pihak lain.
+// Balasan petugas mengubah status menjadi responded; balasan pasien mengembalikannya ke antrean.
+func (s *complaintService) AddReply(requesterID uint, requesterRole string, complaintID uint, message string) (models.ComplaintReply, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return models.ComplaintReply{}, errors.New("pesan balasan tidak boleh kosong")
+	}
+	complaint, err := s.findAccessibleComplaint(requesterID, requesterRole, complaintID)
+	if err != nil {
+		return models.ComplaintReply{}, err
+	}
+	if complaint.Status == models.ComplaintStatusClosed {
+		return models.ComplaintReply{}, ErrComplaintClosed
+	}
+
+	reply, err := s.complaintRepo.CreateReply(models.ComplaintReply{
+		ComplaintLogID: complaint.ID,
+		AuthorID:       requesterID,
+		AuthorRole:     requesterRole,
+		Message:        message,
+	})
+	if err != nil {
+		return models.ComplaintReply{}, fmt.Errorf("gagal menyimpan balasan: %w", err)
+	}
+
+	if isComplaintStaff(requesterRole) {
+		markComplaintAcknowledged(&complaint, requesterID)
+		if complaint.RespondedAt == nil {
+			now := time.Now()
+			complaint.RespondedAt = &now
+		}
+		complaint.Status = models.ComplaintStatusResponded
+	} else if complaint.Status == models.ComplaintStatusResponded {
+		complaint.Status = models.ComplaintStatusAcknowledged
+	}
+	if _, err := s.complaintRepo.Update(complaint); err != nil {
+		return models.ComplaintReply{}, fmt.Errorf("gagal memperbarui status keluhan: %w", err)
+	}
+
+	s.publishReply(reply)
+	return reply, nil
+}
+
+// Close menutup keluhan; bisa dilakukan pasien pemilik maupun petugas.
+func (s *complaintService) Close(requesterID uint, requesterRole string, complaintID uint) (models.ComplaintLog, error) {
+	complaint, err := s.findAccessibleComplaint(requesterID, requesterRole, complaintID)
+	if err != nil {
+		return models.ComplaintLog{}, err
+	}
+	if complaint.Status == models.ComplaintStatusClosed {
+		return complaint, nil
+	}
+
+	now := time.Now()
+	complaint.Status = models.ComplaintStatusClosed
+	complaint.ClosedAt = &now
+	updated, err := s.complaintRepo.Update(complaint)
+	if err != nil {
+		return models.ComplaintLog{}, fmt.Errorf("gagal menutup keluhan: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *complaintService) findAccessibleComplaint(requesterID uint, requesterRole string, complaintID uint) (models.ComplaintLog, error) {
+	complaint, err := s.complaintRepo.FindByID(complaintID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ComplaintLog{}, ErrComplaintNotFound
+		}
+		return models.ComplaintLog{}, fmt.Errorf("gagal mencari keluhan: %w", err)
+	}
+	if err := authorizePatientAccess(s.careTeamRepo, requesterID, requesterRole, complaint.UserID); err != nil {
+		if errors.Is(err, ErrPatientAccessDenied) {
+			// Jangan bocorkan keberadaan keluhan milik pasien lain
+			return models.ComplaintLog{}, ErrComplaintNotFound
+		}
+		return models.ComplaintLog{}, err
+	}
+	return complaint, nil
+}
+
+func (s *complaintService) publishReply(reply models.ComplaintReply) {
+	if s.queueService == nil {
+		return
+	}
+	payload := ReminderMessage{ScheduleType: ScheduleTypeComplaintReply, ScheduleID: reply.ID}
+	if err := s.queueService.PublishMessage(payload); err != nil {
+		log.Printf("WARNING: Failed to publish complaint reply ID %d: %v", reply.ID, err)
+	}
+}
+
+func markComplaintAcknowledged(complaint *models.ComplaintLog, staffID uint) {
+	if complaint.AcknowledgedAt == nil {
+		now := time.Now()
+		complaint.AcknowledgedAt = &now
+		complaint.AcknowledgedBy = &staffID
+	}
+	if complaint.Status == models.ComplaintStatusOpen {
+		complaint.Status = models.ComplaintStatusAcknowledged
+	}
+}
+
+func isComplaintStaff(role string) bool {
+	return role == models.RoleAdmin || role == models.RoleClinician
+}
+
+func isValidComplaintStatus(status string) bool {
+	switch status {
+	case models.ComplaintStatusOpen, models.ComplaintStatusAcknowledged, models.ComplaintStatusResponded, models.ComplaintStatusClosed:
+		return true
+	}
+	return false
+}
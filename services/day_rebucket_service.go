@@ -0,0 +1,212 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/utils"
+)
+
+// RebucketReport merangkum hasil pemindahan data harian satu user ke tanggal lokalnya.
+type RebucketReport struct {
+	UserID              uint
+	Timezone            string
+	FluidEntriesMoved   int
+	FluidLogsDeleted    int
+	MonitoringMoved     int
+	MonitoringKept      int // Tanggal diisi pasien (backdate), tidak dipindah
+	MonitoringConflicts []string
+}
+
+// DayRebucketService dipakai sekali jalan (cmd/rebucket) untuk memindahkan data lama
+// yang tanggalnya dihitung di waktu server ke tanggal lokal pasien.
+type DayRebucketService interface {
+	RebucketUser(user models.User, dryRun bool) (RebucketReport, error)
+}
+
+type dayRebucketService struct {
+	fluid          *fluidBalanceService
+	monitoringRepo repositories.HemodialysisMonitoringRepository
+}
+
+func NewDayRebucketService(fluidRepo repositories.FluidBalanceRepository, userRepo repositories.UserRepository, prescriptionRepo repositories.FluidPrescriptionRepository, entryRepo repositories.FluidEntryRepository, presetRepo repositories.FluidContainerPresetRepository, monitoringRepo repositories.HemodialysisMonitoringRepository) DayRebucketService {
+	return &dayRebucketService{
+		fluid: &fluidBalanceService{
+			repo:             fluidRepo,
+			userRepo:         userRepo,
+			prescriptionRepo: prescriptionRepo,
+			entryRepo:        entryRepo,
+			presetRepo:       presetRepo,
+		},
+		monitoringRepo: monitoringRepo,
+	}
+}
+
+func (s *dayRebucketService) RebucketUser(user models.User, dryRun bool) (RebucketReport, error) {
+	location := utils.LoadUserLocation(user.Timezone)
+	report := RebucketReport{UserID: user.ID, Timezone: location.String()}
+
+	if err := s.rebucketFluid(user.ID, location, dryRun, &report); err != nil {
+		return report, err
+	}
+	if err := s.rebucketMonitoring(user.ID, location, dryRun, &report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// rebucketFluid memindahkan setiap entry ke tanggal lokal dari RecordedAt, lalu menghitung
+// ulang log harian yang terdampak. Log lama tanpa entry dikonversi dulu (waktu = CreatedAt).
+func (s *dayRebucketService) rebucketFluid(userID uint, location *time.Location, dryRun bool, report *RebucketReport) error {
+	logs, err := s.fluid.repo.FindAllByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil log cairan user %d: %w", userID, err)
+	}
+
+	if dryRun {
+		entries, err := s.fluid.entryRepo.FindAllByUserID(userID)
+		if err != nil {
+			return fmt.Errorf("gagal mengambil catatan cairan user %d: %w", userID, err)
+		}
+		itemizedLogs := make(map[uint]bool)
+		for _, entry := range entries {
+			itemizedLogs[entry.FluidBalanceLogID] = true
+			if !utils.LocalDate(entry.RecordedAt, location).Equal(entry.LogDate) {
+				report.FluidEntriesMoved++
+			}
+		}
+		for _, dailyLog := range logs {
+			if itemizedLogs[dailyLog.ID] || (dailyLog.IntakeCC == 0 && dailyLog.OutputCC == 0) {
+				continue
+			}
+			if !utils.LocalDate(dailyLog.CreatedAt, location).Equal(dailyLog.LogDate) {
+				report.FluidEntriesMoved++
+			}
+		}
+		return nil
+	}
+
+	for _, dailyLog := range logs {
+		if _, err := s.fluid.ensureDailyLog(userID, dailyLog.LogDate); err != nil {
+			return err
+		}
+	}
+
+	entries, err := s.fluid.entryRepo.FindAllByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil catatan cairan user %d: %w", userID, err)
+	}
+	affected := make(map[time.Time]bool)
+	for _, entry := range entries {
+		target := utils.LocalDate(entry.RecordedAt, location)
+		if target.Equal(entry.LogDate) {
+			continue
+		}
+		targetLog, err := s.fluid.ensureDailyLog(userID, target)
+		if err != nil {
+			return err
+		}
+		affected[entry.LogDate] = true
+		affected[target] = true
+
+		entry.LogDate = target
+		entry.FluidBalanceLogID = targetLog.ID
+		if _, err := s.fluid.entryRepo.Update(entry); err != nil {
+			return fmt.Errorf("gagal memindahkan catatan cairan %d: %w", entry.ID, err)
+		}
+		report.FluidEntriesMoved++
+	}
+
+	for date := range affected {
+		dailyLog, err := s.fluid.repo.FindByUserAndDate(userID, date)
+		if err != nil {
+			return fmt.Errorf("gagal mencari log cairan %s: %w", date.Format("2006-01-02"), err)
+		}
+		count, err := s.fluid.entryRepo.CountByLogID(dailyLog.ID)
+		if err != nil {
+			return fmt.Errorf("gagal menghitung catatan cairan: %w", err)
+		}
+		if count == 0 {
+			if err := s.fluid.repo.Delete(dailyLog.ID); err != nil {
+				return fmt.Errorf("gagal menghapus log cairan kosong %d: %w", dailyLog.ID, err)
+			}
+			report.FluidLogsDeleted++
+			continue
+		}
+		if _, _, err := s.fluid.recomputeDailyLog(dailyLog); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebucketMonitoring memindahkan monitoring ke tanggal lokal dari CreatedAt. Hanya baris yang
+// tanggalnya berasal dari perhitungan lama (lihat legacyMonitoringDates) yang dipindah; tanggal
+// yang dipilih pasien saat backdate dibiarkan. Jika tanggal tujuan sudah terisi (unique
+// user+tanggal), baris dilewati dan dilaporkan sebagai konflik.
+func (s *dayRebucketService) rebucketMonitoring(userID uint, location *time.Location, dryRun bool, report *RebucketReport) error {
+	monitorings, err := s.monitoringRepo.FindAllByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil monitoring user %d: %w", userID, err)
+	}
+
+	occupied := make(map[time.Time]bool)
+	for _, monitoring := range monitorings {
+		occupied[monitoring.MonitoringDate] = true
+	}
+
+	// Timezone pasien di depan UTC menggeser tanggal ke depan, jadi proses dari yang
+	// terbaru agar slot tujuan sudah kosong sebelum ditempati.
+	sort.Slice(monitorings, func(i, j int) bool {
+		return monitorings[i].MonitoringDate.After(monitorings[j].MonitoringDate)
+	})
+	for _, monitoring := range monitorings {
+		target := utils.LocalDate(monitoring.CreatedAt, location)
+		if target.Equal(monitoring.MonitoringDate) {
+			continue
+		}
+		if !legacyMonitoringDates(monitoring.CreatedAt)[monitoring.MonitoringDate] {
+			report.MonitoringKept++
+			continue
+		}
+		if occupied[target] {
+			report.MonitoringConflicts = append(report.MonitoringConflicts, fmt.Sprintf("monitoring %d: %s -> %s sudah terisi", monitoring.ID, monitoring.MonitoringDate.Format("2006-01-02"), target.Format("2006-01-02")))
+			continue
+		}
+
+		delete(occupied, monitoring.MonitoringDate)
+		occupied[target] = true
+		report.MonitoringMoved++
+		if dryRun {
+			continue
+		}
+
+		monitoring.MonitoringDate = target
+		if _, err := s.monitoringRepo.Update(monitoring); err != nil {
+			return fmt.Errorf("gagal memindahkan monitoring %d: %w", monitoring.ID, err)
+		}
+		log.Printf("Monitoring ID %d moved to %s", monitoring.ID, target.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// legacyMonitoringDates mengembalikan tanggal yang mungkin ditulis kode lama untuk monitoring yang
+// dibuat pada createdAt: tanggal UTC saat pencatatan, yang dikirim driver dengan loc=Local sehingga
+// bisa bergeser sehari mengikuti timezone server. created_at juga ditulis dengan loc=Local, jadi
+// jam dinding server ikut diperhitungkan.
+func legacyMonitoringDates(createdAt time.Time) map[time.Time]bool {
+	wallClock := time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(),
+		createdAt.Hour(), createdAt.Minute(), createdAt.Second(), createdAt.Nanosecond(), time.Local)
+
+	dates := make(map[time.Time]bool)
+	for _, instant := range []time.Time{createdAt, wallClock} {
+		utcDate := utils.LocalDate(instant, time.UTC)
+		dates[utcDate] = true
+		dates[utils.LocalDate(utcDate, time.Local)] = true
+	}
+	return dates
+}
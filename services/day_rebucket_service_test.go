@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLegacyMonitoringDates(t *testing.T) {
+	originalLocal := time.Local
+	t.Cleanup(func() { time.Local = originalLocal })
+
+	date := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name      string
+		serverTZ  *time.Location
+		createdAt time.Time
+		stored    time.Time
+		want      bool
+	}{
+		{"server UTC, stored UTC date", time.UTC, time.Date(2024, 3, 10, 20, 0, 0, 0, time.UTC), date("2024-03-10"), true},
+		{"server UTC, backdated", time.UTC, time.Date(2024, 3, 10, 20, 0, 0, 0, time.UTC), date("2024-03-08"), false},
+		{"server behind UTC shifts back a day", time.FixedZone("UTC-5", -5*3600), time.Date(2024, 3, 10, 20, 0, 0, 0, time.UTC), date("2024-03-09"), true},
+		{"server ahead of UTC, created_at as wall clock", time.FixedZone("WITA", 8*3600), time.Date(2024, 3, 11, 2, 0, 0, 0, time.UTC), date("2024-03-10"), true},
+		{"server ahead of UTC, backdated", time.FixedZone("WITA", 8*3600), time.Date(2024, 3, 11, 2, 0, 0, 0, time.UTC), date("2024-03-05"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			time.Local = tt.serverTZ
+			if got := legacyMonitoringDates(tt.createdAt)[tt.stored]; got != tt.want {
+				t.Errorf("legacyMonitoringDates(%s)[%s] = %v, want %v", tt.createdAt, tt.stored.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
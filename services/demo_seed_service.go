@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"gorm.io/gorm"
+)
+
+// Akun demo yang dibuat DemoSeedService.
+const (
+	DemoPatientEmail   = "pasien.demo@tirtaapp.local"
+	DemoClinicianEmail = "klinisi.demo@tirtaapp.local"
+	demoTimezone       = "Asia/Makassar"
+)
+
+// DemoSeedResult adalah akun demo hasil seeding. Created false berarti data demo sudah ada sebelumnya.
+type DemoSeedResult struct {
+	Patient   models.User
+	Clinician models.User
+	Created   bool
+}
+
+// DemoSeedService membuat satu pasien dan satu klinisi demo beserta data contoh (resep cairan,
+// catatan cairan, jadwal, hasil lab) lewat service yang sama dengan API, sehingga validasi dan
+// pesan pengingat ke queue ikut berjalan.
+type DemoSeedService interface {
+	Seed(password string) (DemoSeedResult, error)
+}
+
+type demoSeedService struct {
+	userRepo                    repositories.UserRepository
+	authService                 AuthService
+	careTeamService             CareTeamService
+	fluidPrescriptionService    FluidPrescriptionService
+	fluidBalanceService         FluidBalanceService
+	drugScheduleService         DrugScheduleService
+	controlScheduleService      ControlScheduleService
+	hemodialysisScheduleService HemodialysisScheduleService
+	medicationRefillService     MedicationRefillService
+	labResultService            LabResultService
+}
+
+func NewDemoSeedService(userRepo repositories.UserRepository, authService AuthService, careTeamService CareTeamService, fluidPrescriptionService FluidPrescriptionService, fluidBalanceService FluidBalanceService, drugScheduleService DrugScheduleService, controlScheduleService ControlScheduleService, hemodialysisScheduleService HemodialysisScheduleService, medicationRefillService MedicationRefillService, labResultService LabResultService) DemoSeedService {
+	return &demoSeedService{
+		userRepo:                    userRepo,
+		authService:                 authService,
+		careTeamService:             careTeamService,
+		fluidPrescriptionService:    fluidPrescriptionService,
+		fluidBalanceService:         fluidBalanceService,
+		drugScheduleService:         drugScheduleService,
+		controlScheduleService:      controlScheduleService,
+		hemodialysisScheduleService: hemodialysisScheduleService,
+		medicationRefillService:     medicationRefillService,
+		labResultService:            labResultService,
+	}
+}
+
+func (s *demoSeedService) Seed(password string) (DemoSeedResult, error) {
+	// Idempoten: jika pasien demo sudah ada, jangan buat data ganda
+	if patient, err := s.userRepo.FindByEmail(DemoPatientEmail); err == nil {
+		clinician, _ := s.userRepo.FindByEmail(DemoClinicianEmail)
+		return DemoSeedResult{Patient: patient, Clinician: clinician}, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return DemoSeedResult{}, fmt.Errorf("gagal mencari pasien demo: %w", err)
+	}
+
+	clinician, err := s.userRepo.FindByEmail(DemoClinicianEmail)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		clinician, err = s.authService.Register(dto.RegisterDTO{
+			Name: "Klinisi Demo", Email: DemoClinicianEmail, Role: models.RoleClinician,
+			PhoneNumber: "081200000001", Password: password, Timezone: demoTimezone,
+		})
+	}
+	if err != nil {
+		return DemoSeedResult{}, fmt.Errorf("gagal membuat klinisi demo: %w", err)
+	}
+	patient, err := s.authService.Register(dto.RegisterDTO{
+		Name: "Pasien Demo", Email: DemoPatientEmail, Role: models.RoleUser,
+		PhoneNumber: "081200000002", Password: password, Timezone: demoTimezone,
+	})
+	if err != nil {
+		return DemoSeedResult{}, fmt.Errorf("gagal membuat pasien demo: %w", err)
+	}
+	result := DemoSeedResult{Patient: patient, Clinician: clinician, Created: true}
+
+	if _, err := s.careTeamService.AssignClinician(patient.ID, clinician.ID); err != nil {
+		return result, fmt.Errorf("gagal menautkan klinisi demo: %w", err)
+	}
+
+	today := utils.TodayIn(utils.LoadUserLocation(demoTimezone))
+	date := func(days int) string { return today.AddDate(0, 0, days).Format("2006-01-02") }
+
+	dailyLimit := 800
+	if _, err := s.fluidPrescriptionService.Prescribe(clinician.ID, clinician.Role, patient.ID, dto.CreateFluidPrescriptionDTO{
+		DailyLimitCC: &dailyLimit, WarningPercent: 85, EffectiveDate: date(-7), Notes: "Data demo",
+	}); err != nil {
+		return result, fmt.Errorf("gagal membuat resep cairan demo: %w", err)
+	}
+
+	// Catatan cairan kemarin (jika masih dalam jendela pengisian susulan) dan hari ini
+	entries := []dto.FluidEntryDTO{
+		{Direction: models.FluidDirectionIntake, Category: models.FluidCategoryWater, VolumeCC: intPtr(200)},
+		{Direction: models.FluidDirectionIntake, Category: models.FluidCategoryTea, VolumeCC: intPtr(150)},
+		{Direction: models.FluidDirectionIntake, Category: models.FluidCategorySoup, VolumeCC: intPtr(250)},
+		{Direction: models.FluidDirectionOutput, Category: models.FluidCategoryUrine, VolumeCC: intPtr(100)},
+	}
+	for _, entry := range entries {
+		if utils.IsWithinBackdateWindow(today.AddDate(0, 0, -1), today) {
+			backdated := entry
+			backdated.LogDate = date(-1)
+			if _, err := s.fluidBalanceService.AddEntry(patient.ID, backdated); err != nil {
+				return result, fmt.Errorf("gagal membuat catatan cairan demo: %w", err)
+			}
+		}
+		if entry.Direction == models.FluidDirectionIntake && entry.Category == models.FluidCategorySoup {
+			continue // Hari ini belum makan malam
+		}
+		if _, err := s.fluidBalanceService.AddEntry(patient.ID, entry); err != nil {
+			return result, fmt.Errorf("gagal membuat catatan cairan demo: %w", err)
+		}
+	}
+
+	for _, days := range []int{0, 1} {
+		if _, err := s.drugScheduleService.Create(patient.ID, dto.CreateDrugScheduleDTO{
+			DrugName: "Asam Folat", Dose: "1 tablet", ScheduleDate: date(days), At12: true, At18: true,
+		}); err != nil {
+			return result, fmt.Errorf("gagal membuat jadwal obat demo: %w", err)
+		}
+	}
+	for _, days := range []int{2, 5} {
+		if _, err := s.hemodialysisScheduleService.Create(patient.ID, dto.CreateHemodialysisScheduleDTO{ScheduleDate: date(days)}); err != nil {
+			return result, fmt.Errorf("gagal membuat jadwal hemodialisa demo: %w", err)
+		}
+	}
+	if _, err := s.controlScheduleService.Create(patient.ID, dto.CreateControlScheduleDTO{ControlDate: date(14)}); err != nil {
+		return result, fmt.Errorf("gagal membuat jadwal kontrol demo: %w", err)
+	}
+	if _, err := s.medicationRefillService.Create(patient.ID, dto.CreateMedicationRefillDTO{RefillDate: date(7)}); err != nil {
+		return result, fmt.Errorf("gagal membuat jadwal ambil obat demo: %w", err)
+	}
+
+	if _, err := s.labResultService.RecordResults(clinician.ID, clinician.Role, patient.ID, dto.RecordLabResultsDTO{
+		SampleDate: date(-3),
+		Results: []dto.LabResultItemDTO{
+			{TestCode: "hb", Value: floatPtr(9.4)},
+			{TestCode: "potassium", Value: floatPtr(5.1)},
+			{TestCode: "albumin", Value: floatPtr(3.6)},
+			{TestCode: models.LabTestUreaPre, Value: floatPtr(142)},
+			{TestCode: models.LabTestUreaPost, Value: floatPtr(45)},
+		},
+		Notes: "Data demo",
+	}); err != nil {
+		return result, fmt.Errorf("gagal membuat hasil lab demo: %w", err)
+	}
+	return result, nil
+}
+
+func intPtr(v int) *int { return &v }
+
+func floatPtr(v float64) *float64 { return &v }
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// Default persentase tontonan yang otomatis dianggap selesai (EDUCATION_COMPLETION_PERCENT).
+const defaultEducationCompletionPercent = 90
+
+var (
+	ErrEducationNotFound  = errors.New("edukasi tidak ditemukan")
+	ErrEducationNotViewed = errors.New("edukasi belum pernah dibuka")
+)
+
+// EducationProgressItem adalah status satu konten edukasi untuk seorang pasien.
+type EducationProgressItem struct {
+	Education models.Education
+	Progress  *models.EducationProgress // nil jika belum pernah dibuka
+}
+
+// EducationProgressSummary adalah ringkasan "X dari Y modul selesai".
+type EducationProgressSummary struct {
+	UserID    uint
+	Completed int
+	Total     int
+	Items     []EducationProgressItem
+}
+
+// EducationItemAnalytics menambahkan tingkat penyelesaian ke agregat repository.
+type EducationItemAnalytics struct {
+	repositories.EducationAnalytics
+	CompletionRate float64 // Persentase penonton unik yang menyelesaikan
+}
+
+type EducationEngagementService interface {
+	RecordView(userID uint, educationID uint, input dto.RecordEducationViewDTO) (models.EducationProgress, error)
+	MarkCompleted(userID uint, educationID uint) (models.EducationProgress, error)
+	GetProgress(requesterID uint, requesterRole string, patientID uint) (EducationProgressSummary, error)
+	GetAnalytics() ([]EducationItemAnalytics, error)
+}
+
+type educationEngagementService struct {
+	repo          repositories.EducationEngagementRepository
+	educationRepo repositories.EducationRepository
+	careTeamRepo  repositories.CareTeamRepository
+}
+
+func NewEducationEngagementService(repo repositories.EducationEngagementRepository, educationRepo repositories.EducationRepository, careTeamRepo repositories.CareTeamRepository) EducationEngagementService {
+	return &educationEngagementService{repo: repo, educationRepo: educationRepo, careTeamRepo: careTeamRepo}
+}
+
+func educationCompletionPercent() int {
+	if viper.IsSet("EDUCATION_COMPLETION_PERCENT") {
+		if percent := viper.GetInt("EDUCATION_COMPLETION_PERCENT"); percent > 0 && percent <= 100 {
+			return percent
+		}
+	}
+	return defaultEducationCompletionPercent
+}
+
+// RecordView mencatat satu tayangan dan memperbarui ringkasan progres. Konten otomatis
+// ditandai selesai bila persentase tontonan mencapai EDUCATION_COMPLETION_PERCENT.
+func (s *educationEngagementService) RecordView(userID uint, educationID uint, input dto.RecordEducationViewDTO) (models.EducationProgress, error) {
+	if err := s.ensureEducationExists(educationID); err != nil {
+		return models.EducationProgress{}, err
+	}
+
+	progress, err := s.repo.RecordView(models.EducationView{
+		EducationID:     educationID,
+		UserID:          userID,
+		ViewedAt:        time.Now(),
+		DurationSeconds: input.DurationSeconds,
+		PercentWatched:  input.PercentWatched,
+	}, educationCompletionPercent())
+	if err != nil {
+		return models.EducationProgress{}, fmt.Errorf("gagal mencatat tayangan edukasi: %w", err)
+	}
+	return progress, nil
+}
+
+// MarkCompleted menandai konten selesai secara eksplisit (misal artikel yang dibaca sampai akhir).
+// Konten harus sudah pernah dibuka lewat RecordView.
+func (s *educationEngagementService) MarkCompleted(userID uint, educationID uint) (models.EducationProgress, error) {
+	if err := s.ensureEducationExists(educationID); err != nil {
+		return models.EducationProgress{}, err
+	}
+	progress, err := s.findProgress(userID, educationID)
+	if err != nil {
+		return models.EducationProgress{}, err
+	}
+	if progress.CompletedAt != nil {
+		return progress, nil
+	}
+
+	if err := s.repo.MarkCompleted(progress.ID, time.Now()); err != nil {
+		return models.EducationProgress{}, fmt.Errorf("gagal menyimpan progres edukasi: %w", err)
+	}
+	return s.findProgress(userID, educationID)
+}
+
+// GetProgress mengambil status semua konten edukasi untuk pasien (urutan sama dengan daftar edukasi).
+func (s *educationEngagementService) GetProgress(requesterID uint, requesterRole string, patientID uint) (EducationProgressSummary, error) {
+	if err := authorizePatientAccess(s.careTeamRepo, requesterID, requesterRole, patientID); err != nil {
+		return EducationProgressSummary{}, err
+	}
+
+	educations, _, err := s.educationRepo.FindAll(repositories.EducationFilter{Sort: repositories.EducationSortFeatured, Limit: -1})
+	if err != nil {
+		return EducationProgressSummary{}, fmt.Errorf("gagal mengambil daftar edukasi: %w", err)
+	}
+	progressRows, err := s.repo.FindProgressByUser(patientID)
+	if err != nil {
+		return EducationProgressSummary{}, fmt.Errorf("gagal mengambil progres edukasi: %w", err)
+	}
+	byEducation := make(map[uint]models.EducationProgress, len(progressRows))
+	for _, progress := range progressRows {
+		byEducation[progress.EducationID] = progress
+	}
+
+	summary := EducationProgressSummary{UserID: patientID, Total: len(educations)}
+	for _, education := range educations {
+		item := EducationProgressItem{Education: education}
+		if progress, ok := byEducation[education.ID]; ok {
+			item.Progress = &progress
+			if progress.CompletedAt != nil {
+				summary.Completed++
+			}
+		}
+		summary.Items = append(summary.Items, item)
+	}
+	return summary, nil
+}
+
+// GetAnalytics mengambil statistik keterlibatan per konten untuk admin.
+func (s *educationEngagementService) GetAnalytics() ([]EducationItemAnalytics, error) {
+	rows, err := s.repo.FindAnalytics()
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung statistik edukasi: %w", err)
+	}
+	analytics := make([]EducationItemAnalytics, 0, len(rows))
+	for _, row := range rows {
+		item := EducationItemAnalytics{EducationAnalytics: row}
+		if row.UniqueViewers > 0 {
+			item.CompletionRate = math.Round(float64(row.Completions)/float64(row.UniqueViewers)*1000) / 10
+		}
+		item.AvgPercentWatched = math.Round(row.AvgPercentWatched*10) / 10
+		analytics = append(analytics, item)
+	}
+	return analytics, nil
+}
+
+func (s *educationEngagementService) ensureEducationExists(educationID uint) error {
+	if _, err := s.educationRepo.FindByID(educationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEducationNotFound
+		}
+		return fmt.Errorf("gagal mencari edukasi: %w", err)
+	}
+	return nil
+}
+
+func (s *educationEngagementService) findProgress(userID uint, educationID uint) (models.EducationProgress, error) {
+	progress, err := s.repo.FindProgress(userID, educationID)
+	if err == nil {
+		return progress, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.EducationProgress{}, ErrEducationNotViewed
+	}
+	return models.EducationProgress{}, fmt.Errorf("gagal mengambil progres edukasi: %w", err)
+}
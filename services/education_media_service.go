@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/storage"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// Potongan upload bertahap ditampung di disk lokal API selama sesi berjalan; setelah lengkap
+// file dipindahkan ke BlobStore dengan prefix storage.PrefixEducationMedia (tidak dilayani
+// sebagai static publik, hanya lewat endpoint media yang memerlukan login).
+const educationUploadPartsDir = "./uploads/education_media_parts"
+
+// Batas bawaan, bisa di-override lewat EDUCATION_MAX_PDF_MB, EDUCATION_MAX_VIDEO_MB
+// dan EDUCATION_UPLOAD_CHUNK_MB.
+const (
+	defaultEducationMaxPDFMB      = 50
+	defaultEducationMaxVideoMB    = 1024
+	defaultEducationUploadChunkMB = 8
+	educationUploadTTL            = 24 * time.Hour
+	pdfThumbnailTimeout           = 30 * time.Second
+)
+
+// educationMediaTypes memetakan MIME type hasil sniffing yang diizinkan ke jenis media & ekstensi file.
+var educationMediaTypes = map[string]struct{ mediaType, ext string }{
+	"application/pdf": {models.EducationMediaPDF, ".pdf"},
+	"video/mp4":       {models.EducationMediaVideo, ".mp4"},
+	"video/webm":      {models.EducationMediaVideo, ".webm"},
+}
+
+var (
+	ErrEducationUploadNotFound   = errors.New("sesi upload tidak ditemukan atau sudah kedaluwarsa")
+	ErrEducationUploadOffset     = errors.New("offset potongan tidak sesuai dengan jumlah byte yang sudah diterima")
+	ErrEducationUploadOverflow   = errors.New("potongan melebihi ukuran total file yang dideklarasikan")
+	ErrEducationUploadIncomplete = errors.New("upload media belum selesai")
+	ErrEducationMediaUnsupported = errors.New("tipe file media tidak didukung (hanya PDF, MP4 dan WebM)")
+	ErrEducationMediaTooLarge    = errors.New("ukuran file media melebihi batas")
+)
+
+// EducationMedia adalah file PDF/video yang sudah tersimpan di BlobStore dan siap dipasang ke edukasi.
+type EducationMedia struct {
+	Type     string
+	Key      string
+	MimeType string
+	Size     int64
+}
+
+// EducationMediaMaxBytes mengembalikan batas ukuran file untuk jenis media tertentu.
+func EducationMediaMaxBytes(mediaType string) int64 {
+	key, megabytes := "EDUCATION_MAX_VIDEO_MB", defaultEducationMaxVideoMB
+	if mediaType == models.EducationMediaPDF {
+		key, megabytes = "EDUCATION_MAX_PDF_MB", defaultEducationMaxPDFMB
+	}
+	if viper.IsSet(key) {
+		if value := viper.GetInt(key); value > 0 {
+			megabytes = value
+		}
+	}
+	return int64(megabytes) * 1024 * 1024
+}
+
+// EducationUploadChunkBytes adalah ukuran potongan maksimal yang diterima per request upload bertahap.
+func EducationUploadChunkBytes() int64 {
+	megabytes := defaultEducationUploadChunkMB
+	if viper.IsSet("EDUCATION_UPLOAD_CHUNK_MB") {
+		if value := viper.GetInt("EDUCATION_UPLOAD_CHUNK_MB"); value > 0 {
+			megabytes = value
+		}
+	}
+	return int64(megabytes) * 1024 * 1024
+}
+
+type EducationMediaService interface {
+	SaveFile(file *multipart.FileHeader) (EducationMedia, error)
+	StartUpload(createdBy uint, input dto.StartEducationUploadDTO) (models.EducationMediaUpload, error)
+	GetUpload(createdBy uint, uploadID string) (models.EducationMediaUpload, error)
+	AppendChunk(createdBy uint, uploadID string, offset int64, chunk io.Reader) (models.EducationMediaUpload, error)
+	ClaimUpload(createdBy uint, uploadID string) (EducationMedia, error)
+	GeneratePDFThumbnail(mediaKey string) (string, error)
+	Discard(media EducationMedia)
+}
+
+type educationMediaService struct {
+	uploadRepo   repositories.EducationMediaUploadRepository
+	imageService ImageService
+	store        storage.BlobStore
+}
+
+func NewEducationMediaService(uploadRepo repositories.EducationMediaUploadRepository, imageService ImageService, store storage.BlobStore) EducationMediaService {
+	if err := os.MkdirAll(educationUploadPartsDir, os.ModePerm); err != nil {
+		log.Fatalf("FATAL: Tidak bisa membuat direktori upload media edukasi: %v", err)
+	}
+	return &educationMediaService{uploadRepo: uploadRepo, imageService: imageService, store: store}
+}
+
+func newEducationMediaKey(ext string) string {
+	return storage.Key(storage.PrefixEducationMedia, fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), utils.RandomToken(4), ext))
+}
+
+// SaveFile menyimpan media yang diunggah langsung (multipart) setelah memvalidasi isinya.
+func (s *educationMediaService) SaveFile(file *multipart.FileHeader) (EducationMedia, error) {
+	mimeType, err := utils.SniffContentType(file)
+	if err != nil {
+		return EducationMedia{}, err
+	}
+	fileType, ok := educationMediaTypes[mimeType]
+	if !ok {
+		return EducationMedia{}, ErrEducationMediaUnsupported
+	}
+	if file.Size > EducationMediaMaxBytes(fileType.mediaType) {
+		return EducationMedia{}, ErrEducationMediaTooLarge
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return EducationMedia{}, fmt.Errorf("gagal membuka file media: %w", err)
+	}
+	defer src.Close()
+
+	key := newEducationMediaKey(fileType.ext)
+	if err := s.store.Put(context.Background(), key, src, file.Size, mimeType); err != nil {
+		return EducationMedia{}, fmt.Errorf("gagal menyimpan file media: %w", err)
+	}
+	return EducationMedia{Type: fileType.mediaType, Key: key, MimeType: mimeType, Size: file.Size}, nil
+}
+
+// StartUpload membuka sesi upload bertahap. Tipe file belum diketahui di tahap ini,
+// sehingga ukuran dibatasi dengan batas terbesar dan diperiksa ulang setelah potongan pertama.
+func (s *educationMediaService) StartUpload(createdBy uint, input dto.StartEducationUploadDTO) (models.EducationMediaUpload, error) {
+	s.purgeExpiredUploads()
+
+	limit := EducationMediaMaxBytes(models.EducationMediaVideo)
+	if pdfLimit := EducationMediaMaxBytes(models.EducationMediaPDF); pdfLimit > limit {
+		limit = pdfLimit
+	}
+	if input.TotalBytes > limit {
+		return models.EducationMediaUpload{}, ErrEducationMediaTooLarge
+	}
+
+	id := utils.RandomToken(16)
+	upload := models.EducationMediaUpload{
+		ID:         id,
+		CreatedBy:  createdBy,
+		FileName:   filepath.Base(input.FileName),
+		TotalBytes: input.TotalBytes,
+		Status:     models.EducationUploadInProgress,
+		TempPath:   filepath.Join(educationUploadPartsDir, id+".part"),
+		ExpiresAt:  time.Now().Add(educationUploadTTL),
+	}
+	file, err := os.Create(upload.TempPath)
+	if err != nil {
+		return models.EducationMediaUpload{}, fmt.Errorf("gagal menyiapkan file upload: %w", err)
+	}
+	file.Close()
+
+	created, err := s.uploadRepo.Create(upload)
+	if err != nil {
+		os.Remove(upload.TempPath)
+		return models.EducationMediaUpload{}, fmt.Errorf("gagal membuat sesi upload: %w", err)
+	}
+	return created, nil
+}
+
+func (s *educationMediaService) GetUpload(createdBy uint, uploadID string) (models.EducationMediaUpload, error) {
+	upload, err := s.uploadRepo.FindByID(uploadID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.EducationMediaUpload{}, ErrEducationUploadNotFound
+		}
+		return models.EducationMediaUpload{}, fmt.Errorf("gagal mengambil sesi upload: %w", err)
+	}
+	// Sesi milik admin lain diperlakukan seperti tidak ada
+	if upload.CreatedBy != createdBy || time.Now().After(upload.ExpiresAt) {
+		return models.EducationMediaUpload{}, ErrEducationUploadNotFound
+	}
+	return upload, nil
+}
+
+// AppendChunk menulis satu potongan pada offset yang diminta. Offset harus sama dengan
+// ReceivedBytes; bila tidak, sesi dikembalikan bersama ErrEducationUploadOffset agar klien
+// bisa melanjutkan dari posisi yang benar. Byte yang sempat tertulis sebelum koneksi putus tetap disimpan.
+// Potongan ditulis dengan WriteAt lalu ReceivedBytes dimajukan lewat update bersyarat, sehingga dua
+// request paralel untuk offset yang sama tidak menggandakan data: hanya satu yang tercatat.
+func (s *educationMediaService) AppendChunk(createdBy uint, uploadID string, offset int64, chunk io.Reader) (models.EducationMediaUpload, error) {
+	upload, err := s.GetUpload(createdBy, uploadID)
+	if err != nil {
+		return models.EducationMediaUpload{}, err
+	}
+	if upload.Status != models.EducationUploadInProgress || offset != upload.ReceivedBytes {
+		return upload, ErrEducationUploadOffset
+	}
+
+	file, err := os.OpenFile(upload.TempPath, os.O_WRONLY, 0)
+	if err != nil {
+		return upload, fmt.Errorf("gagal membuka file upload: %w", err)
+	}
+	remaining := upload.TotalBytes - offset
+	written, copyErr := io.Copy(io.NewOffsetWriter(file, offset), io.LimitReader(chunk, remaining))
+	file.Close()
+	if copyErr == nil && written == remaining {
+		// Sisa byte di body berarti klien mengirim lebih dari ukuran yang dideklarasikan
+		if n, _ := chunk.Read(make([]byte, 1)); n > 0 {
+			copyErr = ErrEducationUploadOverflow
+		}
+	}
+
+	next := upload
+	next.ReceivedBytes = offset + written
+	if next.MimeType == "" && (next.ReceivedBytes >= 512 || next.ReceivedBytes == next.TotalBytes) {
+		if err := s.detectUploadType(&next); err != nil {
+			s.removeUpload(upload)
+			return models.EducationMediaUpload{}, err
+		}
+	}
+	if next.ReceivedBytes == next.TotalBytes {
+		next.Status = models.EducationUploadCompleted
+	}
+
+	if written > 0 {
+		advanced, err := s.uploadRepo.AdvanceReceived(next, offset)
+		if err != nil {
+			return upload, fmt.Errorf("gagal menyimpan progres upload: %w", err)
+		}
+		if !advanced {
+			// Request lain sudah memajukan sesi ini lebih dulu
+			current, err := s.GetUpload(createdBy, uploadID)
+			if err != nil {
+				return models.EducationMediaUpload{}, err
+			}
+			return current, ErrEducationUploadOffset
+		}
+	}
+	if copyErr != nil {
+		if errors.Is(copyErr, ErrEducationUploadOverflow) {
+			return next, copyErr
+		}
+		return next, fmt.Errorf("gagal menerima potongan upload: %w", copyErr)
+	}
+	return next, nil
+}
+
+// detectUploadType membaca awal file upload untuk memastikan tipe dan batas ukurannya.
+func (s *educationMediaService) detectUploadType(upload *models.EducationMediaUpload) error {
+	file, err := os.Open(upload.TempPath)
+	if err != nil {
+		return fmt.Errorf("gagal membaca file upload: %w", err)
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("gagal membaca file upload: %w", err)
+	}
+	mimeType := utils.DetectContentType(head[:n])
+	fileType, ok := educationMediaTypes[mimeType]
+	if !ok {
+		return ErrEducationMediaUnsupported
+	}
+	if upload.TotalBytes > EducationMediaMaxBytes(fileType.mediaType) {
+		return ErrEducationMediaTooLarge
+	}
+	upload.MimeType = mimeType
+	return nil
+}
+
+// ClaimUpload memindahkan file dari sesi yang sudah selesai ke BlobStore dan menghapus
+// sesinya, sehingga satu upload hanya bisa dipasang ke satu edukasi.
+func (s *educationMediaService) ClaimUpload(createdBy uint, uploadID string) (EducationMedia, error) {
+	upload, err := s.GetUpload(createdBy, uploadID)
+	if err != nil {
+		return EducationMedia{}, err
+	}
+	if upload.Status != models.EducationUploadCompleted {
+		return EducationMedia{}, ErrEducationUploadIncomplete
+	}
+	fileType := educationMediaTypes[upload.MimeType]
+
+	file, err := os.Open(upload.TempPath)
+	if err != nil {
+		return EducationMedia{}, fmt.Errorf("gagal membuka file upload: %w", err)
+	}
+	key := newEducationMediaKey(fileType.ext)
+	err = s.store.Put(context.Background(), key, file, upload.TotalBytes, upload.MimeType)
+	file.Close()
+	if err != nil {
+		return EducationMedia{}, fmt.Errorf("gagal memindahkan file upload: %w", err)
+	}
+	s.removeUpload(upload)
+
+	return EducationMedia{Type: fileType.mediaType, Key: key, MimeType: upload.MimeType, Size: upload.TotalBytes}, nil
+}
+
+// GeneratePDFThumbnail merender halaman pertama PDF menjadi PNG memakai pdftoppm (poppler-utils)
+// dan menyimpannya sebagai thumbnail edukasi. Perintah bisa diganti lewat PDF_THUMBNAIL_COMMAND.
+// PDF diunduh dulu ke file sementara karena pdftoppm membutuhkan file lokal.
+func (s *educationMediaService) GeneratePDFThumbnail(mediaKey string) (string, error) {
+	command := viper.GetString("PDF_THUMBNAIL_COMMAND")
+	if command == "" {
+		command = "pdftoppm"
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return "", fmt.Errorf("perintah thumbnail PDF '%s' tidak tersedia: %w", command, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pdfThumbnailTimeout)
+	defer cancel()
+
+	workDir, err := os.MkdirTemp("", "education-pdf-*")
+	if err != nil {
+		return "", fmt.Errorf("gagal membuat direktori sementara: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	pdfPath := filepath.Join(workDir, "source.pdf")
+	if err := s.downloadObject(ctx, mediaKey, pdfPath); err != nil {
+		return "", err
+	}
+
+	prefix := filepath.Join(workDir, "thumbnail")
+	cmd := exec.CommandContext(ctx, command, "-png", "-singlefile", "-f", "1", "-l", "1", "-scale-to", "640", pdfPath, prefix)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("gagal membuat thumbnail PDF: %w (%s)", err, output)
+	}
+
+	thumbnail, err := os.Open(prefix + ".png")
+	if err != nil {
+		return "", fmt.Errorf("gagal membaca thumbnail PDF: %w", err)
+	}
+	defer thumbnail.Close()
+
+	// Diproses seperti thumbnail unggahan agar punya varian ukuran yang sama
+	key, err := s.imageService.Store(ctx, storage.PrefixEducations, thumbnail)
+	if err != nil {
+		return "", fmt.Errorf("gagal menyimpan thumbnail PDF: %w", err)
+	}
+	return key, nil
+}
+
+func (s *educationMediaService) downloadObject(ctx context.Context, key string, dst string) error {
+	object, _, err := s.store.Open(ctx, key)
+	if err != nil {
+		return fmt.Errorf("gagal membuka media '%s': %w", key, err)
+	}
+	defer object.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("gagal membuat file sementara: %w", err)
+	}
+	_, err = io.Copy(out, object)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("gagal mengunduh media '%s': %w", key, err)
+	}
+	return nil
+}
+
+// Discard menghapus file media yang batal dipakai (misal simpan ke DB gagal).
+func (s *educationMediaService) Discard(media EducationMedia) {
+	if media.Key == "" {
+		return
+	}
+	if err := s.store.Delete(context.Background(), media.Key); err != nil {
+		log.Printf("WARNING: Gagal menghapus media edukasi '%s': %v", media.Key, err)
+	}
+}
+
+func (s *educationMediaService) removeUpload(upload models.EducationMediaUpload) {
+	if err := os.Remove(upload.TempPath); err != nil && !os.IsNotExist(err) {
+		log.Printf("WARNING: Gagal menghapus file upload '%s': %v", upload.TempPath, err)
+	}
+	if err := s.uploadRepo.Delete(upload.ID); err != nil {
+		log.Printf("WARNING: Gagal menghapus sesi upload %s: %v", upload.ID, err)
+	}
+}
+
+// purgeExpiredUploads membersihkan sesi yang ditinggalkan beserta file sementaranya.
+func (s *educationMediaService) purgeExpiredUploads() {
+	expired, err := s.uploadRepo.FindExpired(time.Now())
+	if err != nil {
+		log.Printf("WARNING: Gagal mengambil sesi upload kedaluwarsa: %v", err)
+		return
+	}
+	for _, upload := range expired {
+		s.removeUpload(upload)
+	}
+}
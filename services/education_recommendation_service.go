@@ -0,0 +1,237 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"gorm.io/gorm"
+)
+
+// Jendela data terbaru yang dipakai untuk rekomendasi.
+const (
+	recommendationFluidWindowDays      = 7
+	recommendationIDWGWindowDays       = 14
+	recommendationComplaintWindowDays  = 7
+	recommendationOnboardingWindowDays = 30
+	defaultRecommendationLimit         = 5
+)
+
+// EducationTriggerLabels adalah alasan rekomendasi yang ditampilkan ke pasien per jenis pemicu.
+var EducationTriggerLabels = map[string]string{
+	models.EducationTriggerFluidOverLimit: "Asupan cairan Anda melebihi batas harian",
+	models.EducationTriggerHighIDWG:       "Kenaikan berat badan antar-sesi HD Anda tinggi",
+	models.EducationTriggerSymptom:        "Sesuai keluhan yang Anda laporkan",
+	models.EducationTriggerOnboarding:     "Materi dasar untuk pasien baru",
+}
+
+// EducationContext adalah satu kondisi pasien yang dicocokkan dengan pemicu edukasi.
+type EducationContext struct {
+	TriggerType  string
+	SymptomCodes []string
+}
+
+// EducationRecommendation adalah konten edukasi yang disarankan beserta alasannya.
+type EducationRecommendation struct {
+	Education   models.Education
+	TriggerType string
+	SymptomCode string
+}
+
+type EducationRecommendationService interface {
+	GetTriggers(educationID uint) ([]models.EducationTrigger, error)
+	SetTriggers(educationID uint, input dto.SetEducationTriggersDTO) ([]models.EducationTrigger, error)
+	ForContexts(userID uint, contexts []EducationContext) ([]EducationRecommendation, error)
+	Recommend(userID uint) ([]EducationRecommendation, error)
+}
+
+type educationRecommendationService struct {
+	triggerRepo    repositories.EducationTriggerRepository
+	educationRepo  repositories.EducationRepository
+	engagementRepo repositories.EducationEngagementRepository
+	userRepo       repositories.UserRepository
+	fluidRepo      repositories.FluidBalanceRepository
+	monitoringRepo repositories.HemodialysisMonitoringRepository
+	complaintRepo  repositories.ComplaintRepository
+}
+
+func NewEducationRecommendationService(triggerRepo repositories.EducationTriggerRepository, educationRepo repositories.EducationRepository, engagementRepo repositories.EducationEngagementRepository, userRepo repositories.UserRepository, fluidRepo repositories.FluidBalanceRepository, monitoringRepo repositories.HemodialysisMonitoringRepository, complaintRepo repositories.ComplaintRepository) EducationRecommendationService {
+	return &educationRecommendationService{
+		triggerRepo:    triggerRepo,
+		educationRepo:  educationRepo,
+		engagementRepo: engagementRepo,
+		userRepo:       userRepo,
+		fluidRepo:      fluidRepo,
+		monitoringRepo: monitoringRepo,
+		complaintRepo:  complaintRepo,
+	}
+}
+
+func (s *educationRecommendationService) GetTriggers(educationID uint) ([]models.EducationTrigger, error) {
+	if err := s.ensureEducationExists(educationID); err != nil {
+		return nil, err
+	}
+	return s.triggerRepo.FindByEducationID(educationID)
+}
+
+// SetTriggers mengganti seluruh pemicu rekomendasi milik satu edukasi.
+func (s *educationRecommendationService) SetTriggers(educationID uint, input dto.SetEducationTriggersDTO) ([]models.EducationTrigger, error) {
+	if err := s.ensureEducationExists(educationID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	triggers := []models.EducationTrigger{}
+	for _, item := range input.Triggers {
+		code := strings.ToLower(strings.TrimSpace(item.SymptomCode))
+		if item.Type == models.EducationTriggerSymptom && code == "" {
+			return nil, errors.New("kode gejala wajib diisi untuk pemicu symptom")
+		}
+		if item.Type != models.EducationTriggerSymptom {
+			code = ""
+		}
+		key := item.Type + "|" + code
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		triggers = append(triggers, models.EducationTrigger{TriggerType: item.Type, SymptomCode: code})
+	}
+
+	saved, err := s.triggerRepo.ReplaceForEducation(educationID, triggers)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan pemicu edukasi: %w", err)
+	}
+	return saved, nil
+}
+
+// ForContexts mencari edukasi yang pemicunya cocok dengan kondisi pasien, sesuai urutan
+// kondisi. Konten yang sudah diselesaikan pasien tidak disarankan lagi.
+func (s *educationRecommendationService) ForContexts(userID uint, contexts []EducationContext) ([]EducationRecommendation, error) {
+	recommendations := []EducationRecommendation{}
+	if len(contexts) == 0 {
+		return recommendations, nil
+	}
+
+	completed := make(map[uint]bool)
+	progressRows, err := s.engagementRepo.FindProgressByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil progres edukasi: %w", err)
+	}
+	for _, progress := range progressRows {
+		if progress.CompletedAt != nil {
+			completed[progress.EducationID] = true
+		}
+	}
+
+	seen := make(map[uint]bool)
+	for _, context := range contexts {
+		triggers, err := s.triggerRepo.FindMatching(context.TriggerType, context.SymptomCodes)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mencari edukasi yang relevan: %w", err)
+		}
+		sort.SliceStable(triggers, func(i, j int) bool {
+			return educationDisplayBefore(triggers[i].Education, triggers[j].Education)
+		})
+		for _, trigger := range triggers {
+			if seen[trigger.EducationID] || completed[trigger.EducationID] {
+				continue
+			}
+			seen[trigger.EducationID] = true
+			recommendations = append(recommendations, EducationRecommendation{
+				Education:   trigger.Education,
+				TriggerType: trigger.TriggerType,
+				SymptomCode: trigger.SymptomCode,
+			})
+			if len(recommendations) >= defaultRecommendationLimit {
+				return recommendations, nil
+			}
+		}
+	}
+	return recommendations, nil
+}
+
+// Recommend menyusun kondisi pasien dari data terbaru (cairan, IDWG, keluhan, masa awal
+// terapi) lalu mencari edukasi yang relevan.
+func (s *educationRecommendationService) Recommend(userID uint) ([]EducationRecommendation, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+	today := utils.TodayIn(utils.LoadUserLocation(user.Timezone))
+
+	var contexts []EducationContext
+	logs, err := s.fluidRepo.FindHistoryByUserID(userID, recommendationFluidWindowDays)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat cairan: %w", err)
+	}
+	for _, fluidLog := range logs {
+		if !fluidLog.LogDate.Before(today.AddDate(0, 0, -recommendationFluidWindowDays)) &&
+			fluidLog.DailyLimitCC > 0 && fluidLog.BalanceCC > fluidLog.DailyLimitCC {
+			contexts = append(contexts, EducationContext{TriggerType: models.EducationTriggerFluidOverLimit})
+			break
+		}
+	}
+
+	monitorings, err := s.monitoringRepo.FindHistoryByUserID(userID, 1)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat pemantauan: %w", err)
+	}
+	if len(monitorings) > 0 && monitorings[0].IDWGExceeded &&
+		!monitorings[0].MonitoringDate.Before(today.AddDate(0, 0, -recommendationIDWGWindowDays)) {
+		contexts = append(contexts, EducationContext{TriggerType: models.EducationTriggerHighIDWG})
+	}
+
+	complaints, err := s.complaintRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat keluhan: %w", err)
+	}
+	var symptomCodes []string
+	since := time.Now().AddDate(0, 0, -recommendationComplaintWindowDays)
+	for _, complaint := range complaints {
+		if complaint.CreatedAt.Before(since) {
+			break // Terurut terbaru lebih dulu
+		}
+		var codes []string
+		if err := json.Unmarshal(complaint.Complaints, &codes); err == nil {
+			symptomCodes = append(symptomCodes, codes...)
+		}
+	}
+	if len(symptomCodes) > 0 {
+		contexts = append(contexts, EducationContext{TriggerType: models.EducationTriggerSymptom, SymptomCodes: symptomCodes})
+	}
+
+	if user.CreatedAt.After(time.Now().AddDate(0, 0, -recommendationOnboardingWindowDays)) {
+		contexts = append(contexts, EducationContext{TriggerType: models.EducationTriggerOnboarding})
+	}
+
+	return s.ForContexts(userID, contexts)
+}
+
+func (s *educationRecommendationService) ensureEducationExists(educationID uint) error {
+	if _, err := s.educationRepo.FindByID(educationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEducationNotFound
+		}
+		return fmt.Errorf("gagal mencari edukasi: %w", err)
+	}
+	return nil
+}
+
+// educationDisplayBefore mengikuti urutan default daftar edukasi: unggulan dulu, lalu terbaru.
+func educationDisplayBefore(a, b models.Education) bool {
+	if (a.FeaturedOrder == nil) != (b.FeaturedOrder == nil) {
+		return a.FeaturedOrder != nil
+	}
+	if a.FeaturedOrder != nil && *a.FeaturedOrder != *b.FeaturedOrder {
+		return *a.FeaturedOrder < *b.FeaturedOrder
+	}
+	return a.CreatedAt.After(b.CreatedAt)
+}
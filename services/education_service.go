@@ -1,39 +1,105 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
-	"os"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/darmawguna/tirtaapp.git/dto"
 	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/storage"
 	"gorm.io/gorm"
 )
 
+// EducationCategories adalah daftar kategori edukasi beserta labelnya, sesuai urutan tampil.
+var EducationCategories = []struct{ Code, Label string }{
+	{models.EducationCategoryDiet, "Diet & Nutrisi"},
+	{models.EducationCategoryFluid, "Pembatasan Cairan"},
+	{models.EducationCategoryMedication, "Obat-obatan"},
+	{models.EducationCategoryAccessCare, "Perawatan Akses Vaskular"},
+	{models.EducationCategoryExercise, "Aktivitas Fisik"},
+}
+
+const (
+	DefaultEducationPageSize = 20
+	MaxEducationPageSize     = 100
+	// Sesuai kolom education_tags.name (size:50)
+	maxEducationTagLength = 50
+)
+
+var (
+	ErrInvalidEducationCategory = errors.New("kategori edukasi tidak valid")
+	ErrInvalidEducationSort     = errors.New("urutan edukasi tidak valid")
+	ErrEducationTagTooLong      = fmt.Errorf("tag edukasi maksimal %d karakter", maxEducationTagLength)
+)
+
+// EducationListParams adalah parameter pencarian daftar edukasi dari query string.
+type EducationListParams struct {
+	Query    string
+	Category string
+	Tag      string
+	Sort     string
+	Page     int
+	PageSize int
+	// All mengembalikan seluruh edukasi yang cocok tanpa paginasi (Page & PageSize diabaikan).
+	All bool
+}
+
+type EducationPage struct {
+	Items    []models.Education
+	Page     int
+	PageSize int
+	Total    int64
+}
+
 type EducationService interface {
-	Create(input dto.CreateEducationDTO, createdBy uint, thumbnailPath string) (models.Education, error)
-	FindAll() ([]models.Education, error)
+	Create(input dto.CreateEducationDTO, createdBy uint, thumbnailPath string, media *EducationMedia) (models.Education, error)
+	FindAll(params EducationListParams) (EducationPage, error)
 	FindByID(id uint) (models.Education, error)
-	Update(id uint, input dto.UpdateEducationDTO, thumbnailPath *string) (models.Education, error)
+	Update(id uint, input dto.UpdateEducationDTO, thumbnailPath *string, media *EducationMedia) (models.Education, error)
 	Delete(id uint) error
+	SetFeaturedOrder(educationIDs []uint) error
+	FindAllTags() ([]models.EducationTag, error)
 }
 
 type educationService struct {
 	educationRepo repositories.EducationRepository
+	store         storage.BlobStore
 }
 
-func NewEducationService(educationRepo repositories.EducationRepository) EducationService {
-	return &educationService{educationRepo: educationRepo}
+func NewEducationService(educationRepo repositories.EducationRepository, store storage.BlobStore) EducationService {
+	return &educationService{educationRepo: educationRepo, store: store}
 }
 
-func (s *educationService) Create(input dto.CreateEducationDTO, createdBy uint, thumbnailPath string) (models.Education, error) {
+// Create membuat edukasi baru. Jika media diberikan, konten di-host sendiri dan Url dikosongkan.
+func (s *educationService) Create(input dto.CreateEducationDTO, createdBy uint, thumbnailPath string, media *EducationMedia) (models.Education, error) {
+	if !isValidEducationCategory(input.Category) {
+		return models.Education{}, ErrInvalidEducationCategory
+	}
+	tagNames, err := normalizeEducationTags(input.Tags)
+	if err != nil {
+		return models.Education{}, err
+	}
+	tags, err := s.educationRepo.FindOrCreateTags(tagNames)
+	if err != nil {
+		return models.Education{}, fmt.Errorf("gagal menyimpan tag edukasi: %w", err)
+	}
 	education := models.Education{
-		Name:      input.Name,
-		Url:       input.Url,
-		Thumbnail: thumbnailPath, // Simpan path/URL dari handler
-		CreatedBy: createdBy,
+		Name:        input.Name,
+		Description: input.Description,
+		Category:    input.Category,
+		Tags:        tags,
+		Url:         input.Url,
+		Thumbnail:   thumbnailPath, // Simpan path/URL dari handler
+		MediaType:   models.EducationMediaURL,
+		CreatedBy:   createdBy,
+	}
+	if media != nil {
+		applyEducationMedia(&education, *media)
 	}
 	created, err := s.educationRepo.Create(education)
 	if err != nil {
@@ -42,15 +108,57 @@ func (s *educationService) Create(input dto.CreateEducationDTO, createdBy uint,
 	return created, nil
 }
 
-func (s *educationService) FindAll() ([]models.Education, error) {
-	return s.educationRepo.FindAll()
+// FindAll mencari edukasi dengan filter kategori/tag, pencarian teks, urutan dan paginasi.
+// Tanpa sort eksplisit, hasil pencarian diurutkan berdasarkan relevansi dan daftar biasa
+// menampilkan edukasi unggulan lebih dulu.
+func (s *educationService) FindAll(params EducationListParams) (EducationPage, error) {
+	if !isValidEducationCategory(params.Category) {
+		return EducationPage{}, ErrInvalidEducationCategory
+	}
+	if params.Sort == "" {
+		params.Sort = repositories.EducationSortFeatured
+		if strings.TrimSpace(params.Query) != "" {
+			params.Sort = repositories.EducationSortRelevance
+		}
+	}
+	switch params.Sort {
+	case repositories.EducationSortFeatured, repositories.EducationSortNewest, repositories.EducationSortOldest,
+		repositories.EducationSortName, repositories.EducationSortRelevance:
+	default:
+		return EducationPage{}, ErrInvalidEducationSort
+	}
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 || params.PageSize > MaxEducationPageSize {
+		params.PageSize = DefaultEducationPageSize
+	}
+	offset, limit := (params.Page-1)*params.PageSize, params.PageSize
+	if params.All {
+		offset, limit = 0, -1
+	}
+
+	educations, total, err := s.educationRepo.FindAll(repositories.EducationFilter{
+		Query:    params.Query,
+		Category: params.Category,
+		Tag:      strings.TrimSpace(params.Tag),
+		Sort:     params.Sort,
+		Offset:   offset,
+		Limit:    limit,
+	})
+	if err != nil {
+		return EducationPage{}, fmt.Errorf("gagal mengambil daftar edukasi: %w", err)
+	}
+	return EducationPage{Items: educations, Page: params.Page, PageSize: params.PageSize, Total: total}, nil
 }
 
 func (s *educationService) FindByID(id uint) (models.Education, error) {
 	return s.educationRepo.FindByID(id)
 }
 
-func (s *educationService) Update(id uint, input dto.UpdateEducationDTO, thumbnailPath *string) (models.Education, error) {
+// Update memperbarui edukasi. Media baru menggantikan konten lama; Url yang diisi tanpa media
+// mengubah edukasi kembali menjadi tautan eksternal. File lama dihapus setelah DB berhasil diperbarui.
+func (s *educationService) Update(id uint, input dto.UpdateEducationDTO, thumbnailPath *string, media *EducationMedia) (models.Education, error) {
 	education, err := s.educationRepo.FindByID(id)
 	if err != nil {
 		return models.Education{}, fmt.Errorf("edukasi tidak ditemukan: %w", err)
@@ -59,8 +167,34 @@ func (s *educationService) Update(id uint, input dto.UpdateEducationDTO, thumbna
 	oldThumbnailPath := education.Thumbnail // Simpan path lama
 	shouldDeleteOld := false
 
+	if !isValidEducationCategory(input.Category) {
+		return models.Education{}, ErrInvalidEducationCategory
+	}
+	tagNames, err := normalizeEducationTags(input.Tags)
+	if err != nil {
+		return models.Education{}, err
+	}
+	tags, err := s.educationRepo.FindOrCreateTags(tagNames)
+	if err != nil {
+		return models.Education{}, fmt.Errorf("gagal menyimpan tag edukasi: %w", err)
+	}
+
 	education.Name = input.Name
-	education.Url = input.Url
+	education.Description = input.Description
+	education.Category = input.Category
+	education.Tags = tags
+
+	oldMediaPath := education.MediaPath
+	switch {
+	case media != nil:
+		applyEducationMedia(&education, *media)
+	case input.Url != "" || education.MediaType == models.EducationMediaURL || education.MediaType == "":
+		education.Url = input.Url
+		education.MediaType = models.EducationMediaURL
+		education.MediaPath = ""
+		education.MediaMimeType = ""
+		education.MediaSize = 0
+	}
 
 	// Update thumbnail hanya jika path baru diberikan
 	if thumbnailPath != nil {
@@ -77,16 +211,12 @@ func (s *educationService) Update(id uint, input dto.UpdateEducationDTO, thumbna
 		return models.Education{}, fmt.Errorf("gagal update edukasi di db: %w", err)
 	}
 
-	// Hapus file lama jika update DB berhasil dan path baru berbeda
+	// Hapus file lama jika update DB berhasil dan key baru berbeda (di background goroutine)
 	if shouldDeleteOld {
-		go func(pathToDelete string) { // Hapus di background goroutine
-			err := os.Remove(pathToDelete) // Perlu path absolut atau relatif dari CWD
-			if err != nil {
-				log.Printf("WARNING: Gagal menghapus thumbnail lama '%s': %v", pathToDelete, err)
-			} else {
-				log.Printf("Thumbnail lama '%s' berhasil dihapus.", pathToDelete)
-			}
-		}(oldThumbnailPath)
+		go s.removeObject("thumbnail lama", oldThumbnailPath)
+	}
+	if oldMediaPath != "" && oldMediaPath != updatedEducation.MediaPath {
+		go s.removeObject("media lama", oldMediaPath)
 	}
 
 	return updatedEducation, nil
@@ -102,6 +232,7 @@ func (s *educationService) Delete(id uint) error {
 	}
 
 	thumbnailPathToDelete := education.Thumbnail
+	mediaPathToDelete := education.MediaPath
 
 	// Hapus data dari database
 	err = s.educationRepo.Delete(id)
@@ -109,17 +240,92 @@ func (s *educationService) Delete(id uint) error {
 		return fmt.Errorf("gagal menghapus edukasi dari db: %w", err)
 	}
 
-	// Hapus file jika path ada dan delete DB berhasil
+	// Hapus file jika key ada dan delete DB berhasil
 	if thumbnailPathToDelete != "" {
-		go func(pathToDelete string) { // Hapus di background
-			err := os.Remove(pathToDelete)
-			if err != nil {
-				log.Printf("WARNING: Gagal menghapus thumbnail '%s' saat delete edukasi: %v", pathToDelete, err)
-			} else {
-				log.Printf("Thumbnail '%s' berhasil dihapus saat delete edukasi.", pathToDelete)
-			}
-		}(thumbnailPathToDelete)
+		go s.removeObject("thumbnail", thumbnailPathToDelete)
+	}
+	if mediaPathToDelete != "" {
+		go s.removeObject("media", mediaPathToDelete)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// applyEducationMedia menjadikan edukasi sebagai konten yang di-host sendiri.
+func applyEducationMedia(education *models.Education, media EducationMedia) {
+	education.Url = ""
+	education.MediaType = media.Type
+	education.MediaPath = media.Key
+	education.MediaMimeType = media.MimeType
+	education.MediaSize = media.Size
+}
+
+// removeObject menghapus file edukasi dari BlobStore. Thumbnail berupa URL eksternal dilewati,
+// nilai lama yang masih berupa path file dinormalisasi menjadi key, dan varian thumbnail ikut dihapus.
+func (s *educationService) removeObject(label string, key string) {
+	if strings.HasPrefix(key, "http") {
+		return
+	}
+	for _, objectKey := range ImageObjectKeys(key) {
+		if err := s.store.Delete(context.Background(), objectKey); err != nil {
+			log.Printf("WARNING: Gagal menghapus %s edukasi '%s': %v", label, objectKey, err)
+			return
+		}
+	}
+	log.Printf("File %s edukasi '%s' berhasil dihapus.", label, key)
+}
+
+// SetFeaturedOrder mengatur daftar edukasi unggulan sesuai urutan ID yang diberikan.
+func (s *educationService) SetFeaturedOrder(educationIDs []uint) error {
+	seen := make(map[uint]bool, len(educationIDs))
+	for _, id := range educationIDs {
+		if seen[id] {
+			return fmt.Errorf("edukasi %d disebut lebih dari sekali", id)
+		}
+		seen[id] = true
+	}
+	if err := s.educationRepo.SetFeaturedOrder(educationIDs); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("edukasi tidak ditemukan: %w", err)
+		}
+		return fmt.Errorf("gagal mengatur edukasi unggulan: %w", err)
+	}
+	return nil
+}
+
+func (s *educationService) FindAllTags() ([]models.EducationTag, error) {
+	return s.educationRepo.FindAllTags()
+}
+
+func isValidEducationCategory(category string) bool {
+	if category == "" {
+		return true
+	}
+	for _, c := range EducationCategories {
+		if c.Code == category {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeEducationTags memecah tag yang dipisah koma, mengubah ke huruf kecil dan membuang duplikat.
+// Tag yang lebih panjang dari maxEducationTagLength karakter ditolak, bukan dibuang diam-diam.
+func normalizeEducationTags(raw []string) ([]string, error) {
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, value := range raw {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || seen[tag] {
+				continue
+			}
+			if utf8.RuneCountInString(tag) > maxEducationTagLength {
+				return nil, fmt.Errorf("%w: %q", ErrEducationTagTooLong, tag)
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEducationTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []string
+		want    []string
+		wantErr error
+	}{
+		{"empty", nil, []string{}, nil},
+		{"comma separated", []string{"Diet, Garam ,cairan"}, []string{"diet", "garam", "cairan"}, nil},
+		{"duplicates across values", []string{"diet", "DIET,garam", " garam "}, []string{"diet", "garam"}, nil},
+		{"blank entries", []string{",, ,", ""}, []string{}, nil},
+		{"exactly 50 characters", []string{strings.Repeat("a", 50)}, []string{strings.Repeat("a", 50)}, nil},
+		{"51 characters", []string{strings.Repeat("a", 51)}, nil, ErrEducationTagTooLong},
+		{"50 multibyte runes", []string{strings.Repeat("é", 50)}, []string{strings.Repeat("é", 50)}, nil},
+		{"51 multibyte runes", []string{strings.Repeat("é", 51)}, nil, ErrEducationTagTooLong},
+		{"long tag after valid ones", []string{"diet", strings.Repeat("b", 60)}, nil, ErrEducationTagTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeEducationTags(tt.raw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeEducationTags() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeEducationTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,102 +4,656 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"time"
 
 	"github.com/darmawguna/tirtaapp.git/dto" // Sesuaikan path
 	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/utils"
 	"gorm.io/gorm"
 )
 
-// Definisikan batas cairan (bisa diambil dari config nantinya)
-const dailyIntakeLimit = 600
-const warningThreshold = 500
+var (
+	ErrFluidEntryNotFound  = errors.New("catatan cairan tidak ditemukan")
+	ErrFluidEntryForbidden = errors.New("tidak berwenang mengubah catatan cairan ini")
+	// ErrOutsideBackdateWindow dipakai juga oleh pemantauan HD
+	ErrOutsideBackdateWindow = errors.New("tanggal berada di luar batas waktu pengisian data")
+)
+
+// FluidEntryResult adalah hasil perubahan satu entry beserta total harian terbarunya.
+type FluidEntryResult struct {
+	Entry     models.FluidEntry
+	Log       models.FluidBalanceLog
+	Allowance FluidAllowance
+}
+
+// FluidTimeline berisi seluruh entry cairan dalam satu hari.
+type FluidTimeline struct {
+	Date      time.Time
+	Log       models.FluidBalanceLog // ID 0 jika belum ada catatan pada hari itu
+	Entries   []models.FluidEntry
+	Allowance FluidAllowance
+}
+
+// Batas rentang tanggal statistik & riwayat cairan.
+const (
+	defaultFluidRangeDays = 30
+	maxFluidRangeDays     = 366
+)
+
+// FluidStats adalah ringkasan kepatuhan batas cairan dalam satu rentang tanggal.
+type FluidStats struct {
+	From                   time.Time
+	To                     time.Time
+	Granularity            string
+	DaysInRange            int
+	PercentDaysLogged      float64
+	LongestCompliantStreak int
+	Summary                repositories.FluidAggregate
+	Periods                []repositories.FluidAggregate
+}
+
+// FluidHistoryPage adalah satu halaman riwayat log cairan harian.
+type FluidHistoryPage struct {
+	From     time.Time
+	To       time.Time
+	Logs     []models.FluidBalanceLog
+	Page     int
+	PageSize int
+	Total    int64
+}
 
 type FluidBalanceService interface {
-	CreateOrUpdateLog(userID uint, input dto.CreateOrUpdateFluidLogDTO) (models.FluidBalanceLog, error)
+	CreateOrUpdateLog(userID uint, input dto.CreateOrUpdateFluidLogDTO) (models.FluidBalanceLog, FluidAllowance, error)
 	GetUserHistory(userID uint) ([]models.FluidBalanceLog, error)
+	GetTodayAllowance(userID uint) (FluidAllowance, error)
+	AddEntry(userID uint, input dto.FluidEntryDTO) (FluidEntryResult, error)
+	UpdateEntry(userID, entryID uint, input dto.FluidEntryDTO) (FluidEntryResult, error)
+	DeleteEntry(userID, entryID uint) (FluidEntryResult, error)
+	GetTimeline(userID uint, date string) (FluidTimeline, error)
+	GetStats(userID uint, from, to, granularity string) (FluidStats, error)
+	GetHistoryRange(userID uint, from, to string, page, pageSize int) (FluidHistoryPage, error)
+	GetPatientStats(requesterID uint, requesterRole string, patientID uint, from, to, granularity string) (FluidStats, error)
+	GetPatientHistoryRange(requesterID uint, requesterRole string, patientID uint, from, to string, page, pageSize int) (FluidHistoryPage, error)
 }
 
 type fluidBalanceService struct {
-	repo     repositories.FluidBalanceRepository
-	userRepo repositories.UserRepository
+	repo             repositories.FluidBalanceRepository
+	userRepo         repositories.UserRepository
+	prescriptionRepo repositories.FluidPrescriptionRepository
+	entryRepo        repositories.FluidEntryRepository
+	presetRepo       repositories.FluidContainerPresetRepository
+	careTeamRepo     repositories.CareTeamRepository
+	queueService     QueueService // Boleh nil (misal cmd/rebucket): alert tidak dikirim
 }
 
-func NewFluidBalanceService(repo repositories.FluidBalanceRepository, userRepo repositories.UserRepository) FluidBalanceService {
-	return &fluidBalanceService{repo: repo, userRepo: userRepo}
+func NewFluidBalanceService(repo repositories.FluidBalanceRepository, userRepo repositories.UserRepository, prescriptionRepo repositories.FluidPrescriptionRepository, entryRepo repositories.FluidEntryRepository, presetRepo repositories.FluidContainerPresetRepository, careTeamRepo repositories.CareTeamRepository, queueService QueueService) FluidBalanceService {
+	return &fluidBalanceService{
+		repo:             repo,
+		userRepo:         userRepo,
+		prescriptionRepo: prescriptionRepo,
+		entryRepo:        entryRepo,
+		presetRepo:       presetRepo,
+		careTeamRepo:     careTeamRepo,
+		queueService:     queueService,
+	}
 }
 
-func (s *fluidBalanceService) CreateOrUpdateLog(userID uint, input dto.CreateOrUpdateFluidLogDTO) (models.FluidBalanceLog, error) {
-	nowUTC := time.Now().UTC()
-	todayUTC := time.Date(nowUTC.Year(), nowUTC.Month(), nowUTC.Day(), 0, 0, 0, 0, time.UTC)
+// ScheduleType untuk event peringatan cairan yang dikirim ke worker.
+const ScheduleTypeFluidAlert = "FLUID_ALERT"
 
-	// [LOGIKA BISNIS] Cek apakah log sudah ada untuk hari ini
-	existingLog, err := s.repo.FindByUserAndDate(userID, todayUTC)
+// fluidAlertLevel mengembalikan level peringatan ("" jika masih aman).
+func fluidAlertLevel(balanceCC int, allowance FluidAllowance) string {
+	if balanceCC > allowance.DailyLimitCC {
+		return models.FluidAlertLevelExceeded
+	}
+	if balanceCC >= allowance.WarningThresholdCC {
+		return models.FluidAlertLevelWarning
+	}
+	return ""
+}
 
-	var finalLog models.FluidBalanceLog
-	var repoErr error
+// CheckQueuedFluidAlert menghitung ulang level peringatan dari balance log saat ini dengan batas
+// yang berlaku pada tanggal log. stale bernilai true jika level tersebut sudah lebih rendah dari
+// queuedLevel, misalnya karena entry dikoreksi sebelum pesan antrean diproses worker.
+func CheckQueuedFluidAlert(prescriptionRepo repositories.FluidPrescriptionRepository, fluidLog models.FluidBalanceLog, queuedLevel string) (currentLevel string, stale bool, err error) {
+	allowance, err := resolveFluidAllowance(prescriptionRepo, fluidLog.UserID, fluidLog.LogDate)
+	if err != nil {
+		return "", false, err
+	}
+	currentLevel = fluidAlertLevel(fluidLog.BalanceCC, allowance)
+	return currentLevel, fluidAlertRank(currentLevel) < fluidAlertRank(queuedLevel), nil
+}
+
+// fluidAlertRank dipakai untuk mendeteksi kenaikan level (aman < warning < exceeded).
+func fluidAlertRank(level string) int {
+	switch level {
+	case models.FluidAlertLevelWarning:
+		return 1
+	case models.FluidAlertLevelExceeded:
+		return 2
+	}
+	return 0
+}
 
-	var intakeVal, outputVal int
-    if input.IntakeCC != nil { // Cek nil sebelum dereference
-        intakeVal = *input.IntakeCC
-    }
-    if input.OutputCC != nil { // Cek nil sebelum dereference
-        outputVal = *input.OutputCC
-    }
-	
+// buildFluidWarningMessage menghasilkan pesan peringatan sesuai batas pasien, kosong jika aman.
+// Edukasi terkait dikirim terpisah lewat recommended_educations pada response.
+func buildFluidWarningMessage(balanceCC int, allowance FluidAllowance) string {
+	switch fluidAlertLevel(balanceCC, allowance) {
+	case models.FluidAlertLevelExceeded:
+		return fmt.Sprintf("Peringatan!\n\nHalo Bapak/Ibu, total keseimbangan cairan Anda hari ini (%d cc) sudah melebihi batas maksimal harian (%d cc/24 jam). Kelebihan cairan bisa menimbulkan sesak napas dan bengkak. Mohon batasi asupan cairan Anda dan hubungi perawat bila muncul keluhan.", balanceCC, allowance.DailyLimitCC)
+	case models.FluidAlertLevelWarning:
+		return fmt.Sprintf("Peringatan!\n\nHalo Bapak/Ibu, total keseimbangan cairan Anda hari ini (%d cc) sudah mendekati batas maksimal harian (%d cc/24 jam). Ingat, kelebihan cairan bisa menimbulkan sesak napas dan bengkak. Mari jaga kesehatan dengan mematuhi batas cairan harian Anda.", balanceCC, allowance.DailyLimitCC)
+	}
+	return ""
+}
+
+// CreateOrUpdateLog mempertahankan endpoint lama (input total intake/output):
+// nilai yang dikirim dicatat sebagai entry kategori "other" lalu total harian dihitung ulang.
+func (s *fluidBalanceService) CreateOrUpdateLog(userID uint, input dto.CreateOrUpdateFluidLogDTO) (models.FluidBalanceLog, FluidAllowance, error) {
+	location, err := s.userLocation(userID)
 	if err != nil {
-		// Jika error BUKAN karena tidak ditemukan, kembalikan error
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return models.FluidBalanceLog{}, fmt.Errorf("gagal mencari log hari ini: %w", err)
+		return models.FluidBalanceLog{}, FluidAllowance{}, err
+	}
+
+	// Default hari ini waktu lokal pasien, atau tanggal lampau yang masih dalam jendela pengisian
+	logDate, recordedAt, err := resolveEntryDate(input.LogDate, "", location)
+	if err != nil {
+		return models.FluidBalanceLog{}, FluidAllowance{}, err
+	}
+
+	dailyLog, err := s.ensureDailyLog(userID, logDate)
+	if err != nil {
+		return models.FluidBalanceLog{}, FluidAllowance{}, err
+	}
+	amounts := map[string]*int{
+		models.FluidDirectionIntake: input.IntakeCC,
+		models.FluidDirectionOutput: input.OutputCC,
+	}
+	var changes repositories.FluidEntryChanges
+	for direction, amount := range amounts {
+		if amount == nil || *amount == 0 { // Cek nil sebelum dereference
+			continue
 		}
+		entry := models.FluidEntry{
+			UserID:            userID,
+			FluidBalanceLogID: dailyLog.ID,
+			LogDate:           logDate,
+			RecordedAt:        recordedAt,
+			Direction:         direction,
+			Category:          models.FluidCategoryOther,
+			VolumeCC:          *amount,
+		}
+		changes.Create = append(changes.Create, entry)
+	}
+
+	_, updatedLog, allowance, err := s.saveEntries(dailyLog, changes)
+	return updatedLog, allowance, err
+}
+
+func (s *fluidBalanceService) GetUserHistory(userID uint) ([]models.FluidBalanceLog, error) {
+	logs, err := s.repo.FindHistoryByUserID(userID, 7)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat cairan: %w", err)
+	}
+	return logs, nil
+}
 
-		// --- Kasus 1: Record belum ada -> Buat Baru ---
-		log.Println("No existing log found for today. Creating new one.")
-		newLog := models.FluidBalanceLog{
-			UserID:   userID,
-			LogDate:  todayUTC,
-			IntakeCC: intakeVal,
-			OutputCC: outputVal,
+// GetTodayAllowance mengembalikan batas cairan yang berlaku hari ini beserta sisanya.
+func (s *fluidBalanceService) GetTodayAllowance(userID uint) (FluidAllowance, error) {
+	location, err := s.userLocation(userID)
+	if err != nil {
+		return FluidAllowance{}, err
+	}
+	today := utils.TodayIn(location)
+
+	allowance, err := resolveFluidAllowance(s.prescriptionRepo, userID, today)
+	if err != nil {
+		return FluidAllowance{}, err
+	}
+
+	balance := 0
+	todayLog, err := s.repo.FindByUserAndDate(userID, today)
+	if err == nil {
+		balance = todayLog.BalanceCC
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return FluidAllowance{}, fmt.Errorf("gagal mencari log hari ini: %w", err)
+	}
+	return allowance.withBalance(balance), nil
+}
+
+// AddEntry mencatat satu item cairan masuk/keluar lalu menghitung ulang total harian.
+func (s *fluidBalanceService) AddEntry(userID uint, input dto.FluidEntryDTO) (FluidEntryResult, error) {
+	location, err := s.userLocation(userID)
+	if err != nil {
+		return FluidEntryResult{}, err
+	}
+
+	entry := models.FluidEntry{UserID: userID}
+	if err := s.applyEntryInput(&entry, input, location); err != nil {
+		return FluidEntryResult{}, err
+	}
+
+	dailyLog, err := s.ensureDailyLog(userID, entry.LogDate)
+	if err != nil {
+		return FluidEntryResult{}, err
+	}
+	entry.FluidBalanceLogID = dailyLog.ID
+
+	saved, updatedLog, allowance, err := s.saveEntries(dailyLog, repositories.FluidEntryChanges{Create: []models.FluidEntry{entry}})
+	if err != nil {
+		return FluidEntryResult{}, err
+	}
+	return FluidEntryResult{Entry: saved.Create[0], Log: updatedLog, Allowance: allowance}, nil
+}
+
+// UpdateEntry mengoreksi entry yang salah input. Entry tetap berada di tanggal yang sama.
+func (s *fluidBalanceService) UpdateEntry(userID, entryID uint, input dto.FluidEntryDTO) (FluidEntryResult, error) {
+	entry, location, err := s.findEditableEntry(userID, entryID)
+	if err != nil {
+		return FluidEntryResult{}, err
+	}
+
+	originalDate := entry.LogDate.Format("2006-01-02")
+	if err := s.applyEntryInput(&entry, input, location); err != nil {
+		return FluidEntryResult{}, err
+	}
+	if entry.LogDate.Format("2006-01-02") != originalDate {
+		return FluidEntryResult{}, errors.New("waktu catatan tidak boleh dipindah ke tanggal lain, hapus lalu buat catatan baru")
+	}
+
+	dailyLog, err := s.repo.FindByUserAndDate(userID, entry.LogDate)
+	if err != nil {
+		return FluidEntryResult{}, fmt.Errorf("gagal mencari log harian: %w", err)
+	}
+	saved, updatedLog, allowance, err := s.saveEntries(dailyLog, repositories.FluidEntryChanges{Update: []models.FluidEntry{entry}})
+	if err != nil {
+		return FluidEntryResult{}, err
+	}
+	return FluidEntryResult{Entry: saved.Update[0], Log: updatedLog, Allowance: allowance}, nil
+}
+
+// DeleteEntry menghapus entry lalu menghitung ulang total hariannya.
+func (s *fluidBalanceService) DeleteEntry(userID, entryID uint) (FluidEntryResult, error) {
+	entry, _, err := s.findEditableEntry(userID, entryID)
+	if err != nil {
+		return FluidEntryResult{}, err
+	}
+
+	dailyLog, err := s.repo.FindByUserAndDate(userID, entry.LogDate)
+	if err != nil {
+		return FluidEntryResult{}, fmt.Errorf("gagal mencari log harian: %w", err)
+	}
+	_, updatedLog, allowance, err := s.saveEntries(dailyLog, repositories.FluidEntryChanges{DeleteIDs: []uint{entry.ID}})
+	if err != nil {
+		return FluidEntryResult{}, err
+	}
+	return FluidEntryResult{Entry: entry, Log: updatedLog, Allowance: allowance}, nil
+}
+
+// GetTimeline mengembalikan seluruh catatan cairan pada satu tanggal (default hari ini).
+func (s *fluidBalanceService) GetTimeline(userID uint, date string) (FluidTimeline, error) {
+	location, err := s.userLocation(userID)
+	if err != nil {
+		return FluidTimeline{}, err
+	}
+
+	day := utils.TodayIn(location)
+	if date != "" {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return FluidTimeline{}, fmt.Errorf("format tanggal tidak valid: %w", err)
 		}
-		newLog.BalanceCC = newLog.IntakeCC - newLog.OutputCC
-		// Terapkan warning jika perlu
-		if newLog.BalanceCC >= warningThreshold {
-			newLog.WarningMessage = fmt.Sprintf("Peringatan!\n\nHalo Bapak/Ibu, total keseimbangan cairan Anda hari ini (%d cc) sudah mendekati batas maksimal harian (%d cc/24 jam). Ingat, kelebihan cairan bisa menimbulkan sesak napas dan bengkak. Mari jaga kesehatan dengan mematuhi batas cairan harian Anda. Informasi lengkap tentang pengelolaan cairan dapat dilihat di menu Edukasi.", newLog.BalanceCC, dailyIntakeLimit)
-			log.Printf("Warning triggered for user %d, accumulated balance: %d", userID, newLog.BalanceCC)
+		day = parsed
+	}
+
+	timeline := FluidTimeline{Date: day}
+	dailyLog, err := s.repo.FindByUserAndDate(userID, day)
+	if err == nil {
+		timeline.Log = dailyLog
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return FluidTimeline{}, fmt.Errorf("gagal mencari log harian: %w", err)
+	}
+
+	timeline.Entries, err = s.entryRepo.FindByUserAndDate(userID, day)
+	if err != nil {
+		return FluidTimeline{}, fmt.Errorf("gagal mengambil catatan cairan: %w", err)
+	}
+
+	allowance, err := resolveFluidAllowance(s.prescriptionRepo, userID, day)
+	if err != nil {
+		return FluidTimeline{}, err
+	}
+	timeline.Allowance = allowance.withBalance(timeline.Log.BalanceCC)
+	return timeline, nil
+}
+
+// GetStats menghitung agregat cairan per hari/minggu/bulan. Rentang default 30 hari terakhir.
+func (s *fluidBalanceService) GetStats(userID uint, from, to, granularity string) (FluidStats, error) {
+	if granularity == "" {
+		granularity = repositories.FluidGranularityDay
+	}
+	if granularity != repositories.FluidGranularityDay && granularity != repositories.FluidGranularityWeek && granularity != repositories.FluidGranularityMonth {
+		return FluidStats{}, errors.New("granularity harus day, week, atau month")
+	}
+
+	fromDate, toDate, err := s.resolveDateRange(userID, from, to)
+	if err != nil {
+		return FluidStats{}, err
+	}
+
+	summary, err := s.repo.AggregateSummary(userID, fromDate, toDate, defaultDailyIntakeLimit)
+	if err != nil {
+		return FluidStats{}, fmt.Errorf("gagal menghitung statistik cairan: %w", err)
+	}
+	periods, err := s.repo.AggregateByPeriod(userID, fromDate, toDate, granularity, defaultDailyIntakeLimit)
+	if err != nil {
+		return FluidStats{}, fmt.Errorf("gagal menghitung statistik cairan per periode: %w", err)
+	}
+	streak, err := s.repo.LongestCompliantStreak(userID, fromDate, toDate, defaultDailyIntakeLimit)
+	if err != nil {
+		return FluidStats{}, fmt.Errorf("gagal menghitung hari patuh berturut-turut: %w", err)
+	}
+
+	daysInRange := int(toDate.Sub(fromDate).Hours()/24) + 1
+	return FluidStats{
+		From:                   fromDate,
+		To:                     toDate,
+		Granularity:            granularity,
+		DaysInRange:            daysInRange,
+		PercentDaysLogged:      math.Round(float64(summary.DaysLogged)/float64(daysInRange)*1000) / 10,
+		LongestCompliantStreak: streak,
+		Summary:                summary,
+		Periods:                periods,
+	}, nil
+}
+
+// GetPatientStats sama dengan GetStats, tetapi hanya untuk admin atau klinisi di tim perawatan pasien.
+func (s *fluidBalanceService) GetPatientStats(requesterID uint, requesterRole string, patientID uint, from, to, granularity string) (FluidStats, error) {
+	if err := authorizePatientAccess(s.careTeamRepo, requesterID, requesterRole, patientID); err != nil {
+		return FluidStats{}, err
+	}
+	return s.GetStats(patientID, from, to, granularity)
+}
+
+// GetHistoryRange mengambil riwayat log cairan dalam rentang tanggal dengan paginasi.
+func (s *fluidBalanceService) GetHistoryRange(userID uint, from, to string, page, pageSize int) (FluidHistoryPage, error) {
+	fromDate, toDate, err := s.resolveDateRange(userID, from, to)
+	if err != nil {
+		return FluidHistoryPage{}, err
+	}
+
+	logs, total, err := s.repo.FindByUserAndDateRange(userID, fromDate, toDate, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return FluidHistoryPage{}, fmt.Errorf("gagal mengambil riwayat cairan: %w", err)
+	}
+	return FluidHistoryPage{From: fromDate, To: toDate, Logs: logs, Page: page, PageSize: pageSize, Total: total}, nil
+}
+
+// GetPatientHistoryRange sama dengan GetHistoryRange, tetapi hanya untuk admin atau klinisi di tim perawatan pasien.
+func (s *fluidBalanceService) GetPatientHistoryRange(requesterID uint, requesterRole string, patientID uint, from, to string, page, pageSize int) (FluidHistoryPage, error) {
+	if err := authorizePatientAccess(s.careTeamRepo, requesterID, requesterRole, patientID); err != nil {
+		return FluidHistoryPage{}, err
+	}
+	return s.GetHistoryRange(patientID, from, to, page, pageSize)
+}
+
+// --- Helper ---
+
+// resolveDateRange memvalidasi from/to (YYYY-MM-DD). Tanpa to: hari ini di timezone user,
+// tanpa from: 30 hari sebelum to. Juga memastikan user (pasien) ada.
+func (s *fluidBalanceService) resolveDateRange(userID uint, from, to string) (time.Time, time.Time, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, time.Time{}, ErrPatientNotFound
 		}
-		// Panggil repo.Create
-		finalLog, repoErr = s.repo.Create(newLog)
+		return time.Time{}, time.Time{}, fmt.Errorf("gagal mengambil data user: %w", err)
+	}
 
-	} else {
-		// --- Kasus 2: Record sudah ada -> Update ---
-		log.Println("Existing log found for today (ID:", existingLog.ID, "). Updating.")
-		// Akumulasi nilai
-		existingLog.IntakeCC += intakeVal
-		existingLog.OutputCC += outputVal
-		// Hitung ulang balance & warning
-		existingLog.BalanceCC = existingLog.IntakeCC - existingLog.OutputCC
-		existingLog.WarningMessage = "" // Reset warning
-		if existingLog.BalanceCC >= warningThreshold {
-			existingLog.WarningMessage = fmt.Sprintf("Peringatan!\n\nHalo Bapak/Ibu, total keseimbangan cairan Anda hari ini (%d cc) sudah mendekati batas maksimal harian (%d cc/24 jam). Ingat, kelebihan cairan bisa menimbulkan sesak napas dan bengkak. Mari jaga kesehatan dengan mematuhi batas cairan harian Anda. Informasi lengkap tentang pengelolaan cairan dapat dilihat di menu Edukasi.", existingLog.BalanceCC, dailyIntakeLimit)
-			log.Printf("Warning triggered...")
+	toDate := utils.TodayIn(utils.LoadUserLocation(user.Timezone))
+	if to != "" {
+		if toDate, err = time.Parse("2006-01-02", to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("format to tidak valid: %w", err)
+		}
+	}
+	fromDate := toDate.AddDate(0, 0, -(defaultFluidRangeDays - 1))
+	if from != "" {
+		if fromDate, err = time.Parse("2006-01-02", from); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("format from tidak valid: %w", err)
 		}
-		// Panggil repo.Update
-		finalLog, repoErr = s.repo.Update(existingLog)
 	}
 
-	// Tangani error dari operasi Create atau Update
-	if repoErr != nil {
-		return models.FluidBalanceLog{}, fmt.Errorf("gagal menyimpan log cairan: %w", repoErr)
+	if fromDate.After(toDate) {
+		return time.Time{}, time.Time{}, errors.New("from tidak boleh setelah to")
 	}
+	if toDate.Sub(fromDate).Hours()/24 >= maxFluidRangeDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("rentang tanggal maksimal %d hari", maxFluidRangeDays)
+	}
+	return fromDate, toDate, nil
+}
 
-	return finalLog, nil
+// userLocation mengambil timezone user; semua batas "hari ini" dihitung di waktu lokal pasien.
+func (s *fluidBalanceService) userLocation(userID uint) (*time.Location, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data user: %w", err)
+	}
+	return utils.LoadUserLocation(user.Timezone), nil
 }
 
-func (s *fluidBalanceService) GetUserHistory(userID uint) ([]models.FluidBalanceLog, error) {
-	logs, err := s.repo.FindHistoryByUserID(userID, 7)
+// findEditableEntry memastikan entry milik user dan tanggalnya masih dalam jendela koreksi.
+func (s *fluidBalanceService) findEditableEntry(userID, entryID uint) (models.FluidEntry, *time.Location, error) {
+	entry, err := s.entryRepo.FindByID(entryID)
 	if err != nil {
-		return nil, fmt.Errorf("gagal mengambil riwayat cairan: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.FluidEntry{}, nil, ErrFluidEntryNotFound
+		}
+		return models.FluidEntry{}, nil, fmt.Errorf("gagal mencari catatan cairan: %w", err)
+	}
+	if entry.UserID != userID {
+		return models.FluidEntry{}, nil, ErrFluidEntryForbidden
+	}
+
+	location, err := s.userLocation(userID)
+	if err != nil {
+		return models.FluidEntry{}, nil, err
+	}
+	if !utils.IsWithinBackdateWindow(entry.LogDate, utils.TodayIn(location)) {
+		return models.FluidEntry{}, nil, ErrOutsideBackdateWindow
+	}
+	return entry, location, nil
+}
+
+// resolveEntryDate menentukan tanggal log (waktu lokal) dan waktu pencatatan dari input opsional.
+// Tanpa input: hari ini & sekarang. Dengan logDate lampau tanpa recordedAt: pukul 12:00 lokal.
+func resolveEntryDate(logDate string, recordedAt string, location *time.Location) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := utils.TodayIn(location)
+
+	recorded := now
+	if recordedAt != "" {
+		parsed, err := time.Parse(time.RFC3339, recordedAt)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("format recorded_at tidak valid (gunakan RFC3339): %w", err)
+		}
+		if parsed.After(now.Add(5 * time.Minute)) {
+			return time.Time{}, time.Time{}, errors.New("waktu catatan tidak boleh di masa depan")
+		}
+		recorded = parsed
+	}
+	date := utils.LocalDate(recorded, location)
+
+	if logDate != "" {
+		parsed, err := time.Parse("2006-01-02", logDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("format log_date tidak valid: %w", err)
+		}
+		if recordedAt != "" && !parsed.Equal(date) {
+			return time.Time{}, time.Time{}, errors.New("log_date dan recorded_at harus berada di tanggal yang sama")
+		}
+		if recordedAt == "" && !parsed.Equal(today) {
+			recorded = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 12, 0, 0, 0, location)
+		}
+		date = parsed
+	}
+
+	if !utils.IsWithinBackdateWindow(date, today) {
+		return time.Time{}, time.Time{}, ErrOutsideBackdateWindow
+	}
+	return date, recorded, nil
+}
+
+// applyEntryInput mengisi entry dari DTO: volume dari input langsung atau dari preset wadah,
+// dan tanggal log dari waktu pencatatan di timezone pasien.
+func (s *fluidBalanceService) applyEntryInput(entry *models.FluidEntry, input dto.FluidEntryDTO, location *time.Location) error {
+	entry.Direction = input.Direction
+	entry.Category = input.Category
+	entry.Notes = input.Notes
+	entry.ContainerPresetID = nil
+
+	switch {
+	case input.VolumeCC != nil:
+		entry.VolumeCC = *input.VolumeCC
+	case input.ContainerPresetID != nil:
+		preset, err := s.presetRepo.FindByID(*input.ContainerPresetID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("wadah tidak ditemukan")
+			}
+			return fmt.Errorf("gagal mencari wadah: %w", err)
+		}
+		quantity := input.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		entry.VolumeCC = int(math.Round(float64(preset.VolumeCC) * quantity))
+		entry.ContainerPresetID = &preset.ID
+	default:
+		return errors.New("volume_cc atau container_preset_id wajib diisi")
+	}
+
+	// Koreksi tanpa waktu baru mempertahankan waktu pencatatan semula
+	if entry.ID != 0 && input.LogDate == "" && input.RecordedAt == "" {
+		return nil
+	}
+
+	logDate, recordedAt, err := resolveEntryDate(input.LogDate, input.RecordedAt, location)
+	if err != nil {
+		return err
+	}
+
+	entry.RecordedAt = recordedAt
+	entry.LogDate = logDate
+	return nil
+}
+
+// ensureDailyLog mengambil (atau membuat) baris FluidBalanceLog untuk satu hari.
+// Log lama yang dibuat sebelum ada pencatatan per item dikonversi menjadi entry
+// agar totalnya tidak hilang saat dihitung ulang.
+func (s *fluidBalanceService) ensureDailyLog(userID uint, date time.Time) (models.FluidBalanceLog, error) {
+	dailyLog, err := s.repo.FindByUserAndDate(userID, date)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.FluidBalanceLog{}, fmt.Errorf("gagal mencari log hari ini: %w", err)
+		}
+		log.Println("No existing log found for this date. Creating new one.")
+		dailyLog, err = s.repo.Create(models.FluidBalanceLog{UserID: userID, LogDate: date})
+		if err != nil {
+			return models.FluidBalanceLog{}, fmt.Errorf("gagal membuat log cairan: %w", err)
+		}
+		return dailyLog, nil
+	}
+
+	if dailyLog.IntakeCC == 0 && dailyLog.OutputCC == 0 {
+		return dailyLog, nil
+	}
+	count, err := s.entryRepo.CountByLogID(dailyLog.ID)
+	if err != nil {
+		return models.FluidBalanceLog{}, fmt.Errorf("gagal menghitung catatan cairan: %w", err)
+	}
+	if count > 0 {
+		return dailyLog, nil
+	}
+
+	log.Printf("Converting legacy fluid log ID %d into entries.", dailyLog.ID)
+	legacy := []struct {
+		direction string
+		volume    int
+	}{
+		{models.FluidDirectionIntake, dailyLog.IntakeCC},
+		{models.FluidDirectionOutput, dailyLog.OutputCC},
+	}
+	for _, item := range legacy {
+		if item.volume == 0 {
+			continue
+		}
+		_, err := s.entryRepo.Create(models.FluidEntry{
+			UserID:            userID,
+			FluidBalanceLogID: dailyLog.ID,
+			LogDate:           dailyLog.LogDate,
+			RecordedAt:        dailyLog.CreatedAt,
+			Direction:         item.direction,
+			Category:          models.FluidCategoryOther,
+			VolumeCC:          item.volume,
+			Notes:             "Total sebelum pencatatan per item",
+		})
+		if err != nil {
+			return models.FluidBalanceLog{}, fmt.Errorf("gagal mengonversi log lama: %w", err)
+		}
+	}
+	return dailyLog, nil
+}
+
+// recomputeDailyLog menghitung ulang total harian dari seluruh entry lalu menerapkan peringatan.
+func (s *fluidBalanceService) recomputeDailyLog(dailyLog models.FluidBalanceLog) (models.FluidBalanceLog, FluidAllowance, error) {
+	_, updatedLog, allowance, err := s.saveEntries(dailyLog, repositories.FluidEntryChanges{})
+	return updatedLog, allowance, err
+}
+
+// saveEntries menyimpan perubahan entry dan total harian yang dihitung ulang dalam satu transaksi,
+// lalu mengirim peringatan bila pasien naik level.
+func (s *fluidBalanceService) saveEntries(dailyLog models.FluidBalanceLog, changes repositories.FluidEntryChanges) (repositories.FluidEntryChanges, models.FluidBalanceLog, FluidAllowance, error) {
+	allowance, err := resolveFluidAllowance(s.prescriptionRepo, dailyLog.UserID, dailyLog.LogDate)
+	if err != nil {
+		return repositories.FluidEntryChanges{}, models.FluidBalanceLog{}, FluidAllowance{}, err
+	}
+
+	var previousLevel string
+	saved, updatedLog, err := s.entryRepo.ApplyChanges(dailyLog.ID, changes, func(current *models.FluidBalanceLog, intakeCC, outputCC int) {
+		previousLevel = fluidAlertLevel(current.BalanceCC, allowance)
+		current.IntakeCC = intakeCC
+		current.OutputCC = outputCC
+		current.BalanceCC = intakeCC - outputCC
+		current.DailyLimitCC = allowance.DailyLimitCC
+		current.WarningMessage = buildFluidWarningMessage(current.BalanceCC, allowance)
+	})
+	if err != nil {
+		return repositories.FluidEntryChanges{}, models.FluidBalanceLog{}, FluidAllowance{}, fmt.Errorf("gagal menyimpan catatan cairan: %w", err)
+	}
+	if updatedLog.WarningMessage != "" {
+		log.Printf("Warning triggered for user %d, accumulated balance: %d", updatedLog.UserID, updatedLog.BalanceCC)
+	}
+
+	if level := fluidAlertLevel(updatedLog.BalanceCC, allowance); fluidAlertRank(level) > fluidAlertRank(previousLevel) {
+		s.publishFluidAlert(updatedLog, level)
+	}
+	return saved, updatedLog, allowance.withBalance(updatedLog.BalanceCC), nil
+}
+
+// publishFluidAlert mengirim event ke worker saat pasien naik level peringatan.
+// Kegagalan publish tidak menggagalkan pencatatan cairan.
+func (s *fluidBalanceService) publishFluidAlert(dailyLog models.FluidBalanceLog, level string) {
+	if s.queueService == nil {
+		return
+	}
+	payload := ReminderMessage{
+		ScheduleType: ScheduleTypeFluidAlert,
+		ScheduleID:   dailyLog.ID,
+		AlertLevel:   level,
+	}
+	if err := s.queueService.PublishMessage(payload); err != nil {
+		log.Printf("WARNING: Failed to publish fluid alert for log ID %d: %v", dailyLog.ID, err)
 	}
-	return logs, nil
 }
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"gorm.io/gorm"
+)
+
+type stubFluidPrescriptionRepo struct {
+	repositories.FluidPrescriptionRepository
+	prescription *models.FluidPrescription
+}
+
+func (r stubFluidPrescriptionRepo) FindEffectiveForDate(userID uint, date time.Time) (models.FluidPrescription, error) {
+	if r.prescription == nil {
+		return models.FluidPrescription{}, gorm.ErrRecordNotFound
+	}
+	return *r.prescription, nil
+}
+
+func TestCheckQueuedFluidAlert(t *testing.T) {
+	// Resep 1000 cc dengan peringatan 80% (800 cc); tanpa resep berlaku batas bawaan 600/500 cc.
+	prescribed := stubFluidPrescriptionRepo{prescription: &models.FluidPrescription{ID: 1, DailyLimitCC: 1000, WarningPercent: 80}}
+	noPrescription := stubFluidPrescriptionRepo{}
+
+	tests := []struct {
+		name        string
+		repo        stubFluidPrescriptionRepo
+		balanceCC   int
+		queuedLevel string
+		wantLevel   string
+		wantStale   bool
+	}{
+		{"exceeded still exceeded", prescribed, 1100, models.FluidAlertLevelExceeded, models.FluidAlertLevelExceeded, false},
+		{"exceeded corrected to warning", prescribed, 900, models.FluidAlertLevelExceeded, models.FluidAlertLevelWarning, true},
+		{"exceeded corrected to safe", prescribed, 300, models.FluidAlertLevelExceeded, "", true},
+		{"warning still warning", prescribed, 850, models.FluidAlertLevelWarning, models.FluidAlertLevelWarning, false},
+		{"warning corrected below threshold", prescribed, 700, models.FluidAlertLevelWarning, "", true},
+		{"warning now exceeded", prescribed, 1200, models.FluidAlertLevelWarning, models.FluidAlertLevelExceeded, false},
+		{"default limits warning", noPrescription, 550, models.FluidAlertLevelWarning, models.FluidAlertLevelWarning, false},
+		{"default limits corrected", noPrescription, 450, models.FluidAlertLevelWarning, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fluidLog := models.FluidBalanceLog{UserID: 1, LogDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), BalanceCC: tt.balanceCC}
+			level, stale, err := CheckQueuedFluidAlert(tt.repo, fluidLog, tt.queuedLevel)
+			if err != nil {
+				t.Fatalf("CheckQueuedFluidAlert() error = %v", err)
+			}
+			if level != tt.wantLevel || stale != tt.wantStale {
+				t.Errorf("CheckQueuedFluidAlert() = (%q, %v), want (%q, %v)", level, stale, tt.wantLevel, tt.wantStale)
+			}
+		})
+	}
+}
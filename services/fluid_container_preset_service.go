@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"gorm.io/gorm"
+)
+
+// Preset bawaan yang dibuat jika tabel masih kosong.
+var defaultFluidContainerPresets = []models.FluidContainerPreset{
+	{Name: "Gelas", VolumeCC: 200, SortOrder: 1, IsActive: true},
+	{Name: "Botol kecil", VolumeCC: 600, SortOrder: 2, IsActive: true},
+	{Name: "Mangkuk", VolumeCC: 250, SortOrder: 3, IsActive: true},
+	{Name: "Sendok makan", VolumeCC: 15, SortOrder: 4, IsActive: true},
+}
+
+var ErrFluidContainerPresetNotFound = errors.New("wadah tidak ditemukan")
+
+type FluidContainerPresetService interface {
+	EnsureDefaults() error
+	FindAll(includeInactive bool) ([]models.FluidContainerPreset, error)
+	Create(input dto.FluidContainerPresetDTO) (models.FluidContainerPreset, error)
+	Update(id uint, input dto.FluidContainerPresetDTO) (models.FluidContainerPreset, error)
+	Delete(id uint) error
+}
+
+type fluidContainerPresetService struct {
+	repo repositories.FluidContainerPresetRepository
+}
+
+func NewFluidContainerPresetService(repo repositories.FluidContainerPresetRepository) FluidContainerPresetService {
+	return &fluidContainerPresetService{repo: repo}
+}
+
+// EnsureDefaults mengisi preset bawaan saat tabel preset masih kosong.
+func (s *fluidContainerPresetService) EnsureDefaults() error {
+	count, err := s.repo.Count()
+	if err != nil {
+		return fmt.Errorf("gagal menghitung preset wadah: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	for _, preset := range defaultFluidContainerPresets {
+		if _, err := s.repo.Create(preset); err != nil {
+			return fmt.Errorf("gagal membuat preset wadah %s: %w", preset.Name, err)
+		}
+	}
+	log.Printf("Seeded %d default fluid container presets.", len(defaultFluidContainerPresets))
+	return nil
+}
+
+func (s *fluidContainerPresetService) FindAll(includeInactive bool) ([]models.FluidContainerPreset, error) {
+	return s.repo.FindAll(!includeInactive)
+}
+
+func (s *fluidContainerPresetService) Create(input dto.FluidContainerPresetDTO) (models.FluidContainerPreset, error) {
+	preset := models.FluidContainerPreset{
+		Name:      input.Name,
+		VolumeCC:  input.VolumeCC,
+		SortOrder: input.SortOrder,
+		IsActive:  true,
+	}
+	if input.IsActive != nil {
+		preset.IsActive = *input.IsActive
+	}
+	created, err := s.repo.Create(preset)
+	if err != nil {
+		return models.FluidContainerPreset{}, fmt.Errorf("gagal membuat preset wadah: %w", err)
+	}
+	return created, nil
+}
+
+func (s *fluidContainerPresetService) Update(id uint, input dto.FluidContainerPresetDTO) (models.FluidContainerPreset, error) {
+	preset, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.FluidContainerPreset{}, ErrFluidContainerPresetNotFound
+		}
+		return models.FluidContainerPreset{}, fmt.Errorf("gagal mencari preset wadah: %w", err)
+	}
+
+	preset.Name = input.Name
+	preset.VolumeCC = input.VolumeCC
+	preset.SortOrder = input.SortOrder
+	if input.IsActive != nil {
+		preset.IsActive = *input.IsActive
+	}
+
+	updated, err := s.repo.Update(preset)
+	if err != nil {
+		return models.FluidContainerPreset{}, fmt.Errorf("gagal memperbarui preset wadah: %w", err)
+	}
+	return updated, nil
+}
+
+// Delete menghapus preset. Entry lama tetap menyimpan volume aslinya sehingga tidak terpengaruh.
+func (s *fluidContainerPresetService) Delete(id uint) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrFluidContainerPresetNotFound
+		}
+		return fmt.Errorf("gagal mencari preset wadah: %w", err)
+	}
+	return s.repo.Delete(id)
+}
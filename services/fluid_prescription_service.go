@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"gorm.io/gorm"
+)
+
+// Batas bawaan jika klinisi belum meresepkan batas cairan untuk pasien.
+const (
+	defaultDailyIntakeLimit = 600
+	defaultWarningThreshold = 500
+	// Dasar batas cairan (insensible water loss) yang ditambah urine residu pasien
+	baseFluidAllowanceCC  = 500
+	defaultWarningPercent = 85
+)
+
+// FluidAllowance adalah batas cairan yang berlaku untuk satu pasien pada satu hari.
+type FluidAllowance struct {
+	DailyLimitCC       int
+	WarningThresholdCC int
+	RemainingCC        int    // Sisa batas hari ini (tidak negatif)
+	PrescriptionID     *uint  // nil jika memakai batas bawaan
+	AlertLevel         string // Level peringatan untuk balance hari itu ("" jika aman)
+}
+
+// resolveFluidAllowance mengambil resep yang berlaku pada tanggal tertentu,
+// atau batas bawaan jika pasien belum memiliki resep.
+func resolveFluidAllowance(repo repositories.FluidPrescriptionRepository, userID uint, date time.Time) (FluidAllowance, error) {
+	prescription, err := repo.FindEffectiveForDate(userID, date)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return FluidAllowance{DailyLimitCC: defaultDailyIntakeLimit, WarningThresholdCC: defaultWarningThreshold}, nil
+		}
+		return FluidAllowance{}, fmt.Errorf("gagal mengambil resep cairan: %w", err)
+	}
+	id := prescription.ID
+	return FluidAllowance{
+		DailyLimitCC:       prescription.DailyLimitCC,
+		WarningThresholdCC: prescription.DailyLimitCC * prescription.WarningPercent / 100,
+		PrescriptionID:     &id,
+	}, nil
+}
+
+// withBalance menghitung sisa batas berdasarkan keseimbangan cairan hari ini.
+func (a FluidAllowance) withBalance(balanceCC int) FluidAllowance {
+	a.RemainingCC = a.DailyLimitCC - balanceCC
+	if a.RemainingCC < 0 {
+		a.RemainingCC = 0
+	}
+	a.AlertLevel = fluidAlertLevel(balanceCC, a)
+	return a
+}
+
+var ErrPatientNotFound = errors.New("pasien tidak ditemukan")
+
+type FluidPrescriptionService interface {
+	Prescribe(prescriberID uint, prescriberRole string, patientID uint, input dto.CreateFluidPrescriptionDTO) (models.FluidPrescription, error)
+	GetHistory(requesterID uint, requesterRole string, patientID uint) ([]models.FluidPrescription, error)
+}
+
+type fluidPrescriptionService struct {
+	repo         repositories.FluidPrescriptionRepository
+	userRepo     repositories.UserRepository
+	careTeamRepo repositories.CareTeamRepository
+}
+
+func NewFluidPrescriptionService(repo repositories.FluidPrescriptionRepository, userRepo repositories.UserRepository, careTeamRepo repositories.CareTeamRepository) FluidPrescriptionService {
+	return &fluidPrescriptionService{repo: repo, userRepo: userRepo, careTeamRepo: careTeamRepo}
+}
+
+// Prescribe menyimpan resep batas cairan baru. Jika sudah ada resep dengan tanggal berlaku
+// yang sama, resep tersebut diperbarui (riwayat tanggal lain tetap utuh). Klinisi hanya boleh meresepkan
+// untuk pasien di tim perawatannya.
+func (s *fluidPrescriptionService) Prescribe(prescriberID uint, prescriberRole string, patientID uint, input dto.CreateFluidPrescriptionDTO) (models.FluidPrescription, error) {
+	if _, err := findAccessiblePatient(s.careTeamRepo, s.userRepo, prescriberID, prescriberRole, patientID); err != nil {
+		return models.FluidPrescription{}, err
+	}
+
+	effectiveDate, err := time.Parse("2006-01-02", input.EffectiveDate)
+	if err != nil {
+		return models.FluidPrescription{}, fmt.Errorf("format effective_date tidak valid: %w", err)
+	}
+
+	// Batas eksplisit diutamakan, jika tidak ada dihitung dari 500 cc + urine residu
+	var dailyLimit int
+	switch {
+	case input.DailyLimitCC != nil:
+		dailyLimit = *input.DailyLimitCC
+	case input.ResidualUrineCC != nil:
+		dailyLimit = baseFluidAllowanceCC + *input.ResidualUrineCC
+	default:
+		return models.FluidPrescription{}, errors.New("daily_limit_cc atau residual_urine_cc wajib diisi")
+	}
+
+	warningPercent := input.WarningPercent
+	if warningPercent == 0 {
+		warningPercent = defaultWarningPercent
+	}
+
+	prescription, err := s.repo.FindByUserAndEffectiveDate(patientID, effectiveDate)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.FluidPrescription{}, fmt.Errorf("gagal mencari resep cairan: %w", err)
+	}
+
+	prescription.UserID = patientID
+	prescription.EffectiveDate = effectiveDate
+	prescription.DailyLimitCC = dailyLimit
+	prescription.ResidualUrineCC = input.ResidualUrineCC
+	prescription.WarningPercent = warningPercent
+	prescription.Notes = input.Notes
+	prescription.PrescribedBy = prescriberID
+
+	if prescription.ID == 0 {
+		prescription, err = s.repo.Create(prescription)
+	} else {
+		prescription, err = s.repo.Update(prescription)
+	}
+	if err != nil {
+		return models.FluidPrescription{}, fmt.Errorf("gagal menyimpan resep cairan: %w", err)
+	}
+	return prescription, nil
+}
+
+func (s *fluidPrescriptionService) GetHistory(requesterID uint, requesterRole string, patientID uint) ([]models.FluidPrescription, error) {
+	if _, err := findAccessiblePatient(s.careTeamRepo, s.userRepo, requesterID, requesterRole, patientID); err != nil {
+		return nil, err
+	}
+	prescriptions, err := s.repo.FindHistoryByUserID(patientID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat resep cairan: %w", err)
+	}
+	return prescriptions, nil
+}
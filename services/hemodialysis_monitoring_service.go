@@ -3,13 +3,16 @@ package services
 import (
 	"errors" // <-- Pastikan import ini ada
 	"fmt"
-
-	// "log" // Tidak perlu log di sini, error dikembalikan
+	"log"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/darmawguna/tirtaapp.git/dto"          // Adjust path
 	models "github.com/darmawguna/tirtaapp.git/model" // Adjust path
 	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/spf13/viper"
 	"gorm.io/gorm" // <-- Pastikan import ini ada
 )
 
@@ -18,31 +21,95 @@ type HemodialysisMonitoringService interface {
 	CreateOrUpdateMonitoringForToday(userID uint, input dto.CreateHemodialysisMonitoringDTO) (models.HemodialysisMonitoring, error)
 	GetMonitoringHistory(userID uint) ([]models.HemodialysisMonitoring, error)
 	GetMonitoringByID(userID, monitoringID uint) (models.HemodialysisMonitoring, error)
+	GetIDWGAlerts(requesterID uint, requesterRole string, days int) ([]models.HemodialysisMonitoring, error)
+	GetMonitoringBySchedule(userID, scheduleID uint) (models.HemodialysisMonitoring, error)
 	// GetMonitoringByUserIDAndDate jika diperlukan
 }
 
+var (
+	ErrHemodialysisScheduleNotFound  = errors.New("jadwal hemodialisa tidak ditemukan")
+	ErrHemodialysisScheduleForbidden = errors.New("tidak berwenang mengakses jadwal hemodialisa ini")
+	ErrMonitoringNotFound            = errors.New("data pemantauan tidak ditemukan")
+)
+
+// ScheduleType untuk event IDWG melewati ambang yang dikirim ke worker.
+const ScheduleTypeIDWGAlert = "IDWG_ALERT"
+
+// Default ambang IDWG (% berat kering) sebelum pasien & klinisi diberi tahu.
+const defaultIDWGAlertPercent = 4.0
+
+// Default jarak maksimal (hari) ke sesi sebelumnya agar IDWG masih dihitung.
+const defaultIDWGMaxGapDays = 7
+
 // Implementasi service
 type hemodialysisMonitoringService struct {
 	monitoringRepo repositories.HemodialysisMonitoringRepository
-	userRepo       repositories.UserRepository // Opsional, hanya jika perlu timezone user di sini
+	userRepo       repositories.UserRepository // Untuk timezone user
+	scheduleRepo   repositories.HemodialysisScheduleRepository
+	careTeamRepo   repositories.CareTeamRepository
+	queueService   QueueService
 }
 
 // Constructor
-func NewHemodialysisMonitoringService(monitoringRepo repositories.HemodialysisMonitoringRepository, userRepo repositories.UserRepository) HemodialysisMonitoringService {
+func NewHemodialysisMonitoringService(monitoringRepo repositories.HemodialysisMonitoringRepository, userRepo repositories.UserRepository, scheduleRepo repositories.HemodialysisScheduleRepository, careTeamRepo repositories.CareTeamRepository, queueService QueueService) HemodialysisMonitoringService {
 	return &hemodialysisMonitoringService{
 		monitoringRepo: monitoringRepo,
 		userRepo:       userRepo,
+		scheduleRepo:   scheduleRepo,
+		careTeamRepo:   careTeamRepo,
+		queueService:   queueService,
 	}
 }
 
+// IDWGAlertPercent membaca IDWG_ALERT_PERCENT (contoh 3 atau 4 persen dari berat kering).
+func IDWGAlertPercent() float64 {
+	if percent := viper.GetFloat64("IDWG_ALERT_PERCENT"); percent > 0 {
+		return percent
+	}
+	return defaultIDWGAlertPercent
+}
+
+// IDWGMaxGapDays membaca IDWG_MAX_GAP_DAYS: jarak maksimal ke sesi sebelumnya. Setelah sesi
+// terlewat atau data tidak diisi, selisih berat mencakup lebih dari satu jeda antarsesi sehingga
+// IDWG tidak dihitung agar tidak memicu peringatan palsu.
+func IDWGMaxGapDays() int {
+	if days := viper.GetInt("IDWG_MAX_GAP_DAYS"); days > 0 {
+		return days
+	}
+	return defaultIDWGMaxGapDays
+}
+
 // CreateOrUpdateMonitoringForToday: Logika bisnis utama
 func (s *hemodialysisMonitoringService) CreateOrUpdateMonitoringForToday(userID uint, input dto.CreateHemodialysisMonitoringDTO) (models.HemodialysisMonitoring, error) {
-	// Gunakan UTC untuk konsistensi tanggal
-	nowUTC := time.Now().UTC()
-	todayUTC := time.Date(nowUTC.Year(), nowUTC.Month(), nowUTC.Day(), 0, 0, 0, 0, time.UTC)
+	// Tanggal pemantauan mengikuti waktu lokal pasien, bukan UTC
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return models.HemodialysisMonitoring{}, fmt.Errorf("gagal mengambil data user: %w", err)
+	}
+	today := utils.TodayIn(utils.LoadUserLocation(user.Timezone))
+
+	monitoringDate := today
+	if input.MonitoringDate != "" {
+		monitoringDate, err = time.Parse("2006-01-02", input.MonitoringDate)
+		if err != nil {
+			return models.HemodialysisMonitoring{}, fmt.Errorf("format monitoring_date tidak valid: %w", err)
+		}
+	}
+
+	// Hubungkan ke sesi HD: dari schedule_id, atau jadwal aktif pada tanggal tersebut
+	schedule, err := s.resolveSchedule(userID, input.ScheduleID, monitoringDate, input.MonitoringDate != "")
+	if err != nil {
+		return models.HemodialysisMonitoring{}, err
+	}
+	if schedule != nil {
+		monitoringDate = schedule.ScheduleDate
+	}
+	if !utils.IsWithinBackdateWindow(monitoringDate, today) {
+		return models.HemodialysisMonitoring{}, ErrOutsideBackdateWindow
+	}
 
-	// Cari apakah data untuk hari ini sudah ada
-	existingMonitoring, err := s.monitoringRepo.FindByUserIDAndDate(userID, todayUTC)
+	// Cari apakah data untuk tanggal tersebut sudah ada
+	existingMonitoring, err := s.monitoringRepo.FindByUserIDAndDate(userID, monitoringDate)
 
 	var savedMonitoring models.HemodialysisMonitoring
 	var repoErr error
@@ -56,11 +123,16 @@ func (s *hemodialysisMonitoringService) CreateOrUpdateMonitoringForToday(userID
 		// --- BELUM ADA DATA HARI INI -> BUAT BARU ---
 		newMonitoring := models.HemodialysisMonitoring{
 			UserID:         userID,
-			MonitoringDate: todayUTC,
-			BPBefore:       input.BPBefore,
-			BPAfter:        input.BPAfter,
-			WeightBefore:   input.WeightBefore,
-			WeightAfter:    input.WeightAfter,
+			MonitoringDate: monitoringDate,
+		}
+		if schedule != nil {
+			newMonitoring.HemodialysisScheduleID = &schedule.ID
+		}
+		if err := applyMonitoringInput(&newMonitoring, input); err != nil {
+			return models.HemodialysisMonitoring{}, err
+		}
+		if err := s.computeIDWG(&newMonitoring); err != nil {
+			return models.HemodialysisMonitoring{}, err
 		}
 		savedMonitoring, repoErr = s.monitoringRepo.Create(newMonitoring)
 		if repoErr != nil {
@@ -69,10 +141,15 @@ func (s *hemodialysisMonitoringService) CreateOrUpdateMonitoringForToday(userID
 
 	} else {
 		// --- SUDAH ADA DATA HARI INI -> UPDATE ---
-		existingMonitoring.BPBefore = input.BPBefore
-		existingMonitoring.BPAfter = input.BPAfter
-		existingMonitoring.WeightBefore = input.WeightBefore
-		existingMonitoring.WeightAfter = input.WeightAfter
+		if err := applyMonitoringInput(&existingMonitoring, input); err != nil {
+			return models.HemodialysisMonitoring{}, err
+		}
+		if schedule != nil {
+			existingMonitoring.HemodialysisScheduleID = &schedule.ID
+		}
+		if err := s.computeIDWG(&existingMonitoring); err != nil {
+			return models.HemodialysisMonitoring{}, err
+		}
 
 		savedMonitoring, repoErr = s.monitoringRepo.Update(existingMonitoring)
 		if repoErr != nil {
@@ -80,6 +157,18 @@ func (s *hemodialysisMonitoringService) CreateOrUpdateMonitoringForToday(userID
 		}
 	}
 
+	if schedule != nil && schedule.MonitoringStatus != models.MonitoringStatusRecorded {
+		schedule.MonitoringStatus = models.MonitoringStatusRecorded
+		if _, err := s.scheduleRepo.Update(*schedule); err != nil {
+			return models.HemodialysisMonitoring{}, fmt.Errorf("gagal memperbarui status jadwal hemodialisa: %w", err)
+		}
+	}
+
+	s.publishIDWGAlertIfNeeded(savedMonitoring)
+	// Berat sesudah HD sesi ini menjadi dasar IDWG sesi berikutnya (pengisian susulan)
+	if err := s.refreshNextSessionIDWG(savedMonitoring); err != nil {
+		return models.HemodialysisMonitoring{}, err
+	}
 	return savedMonitoring, nil
 }
 
@@ -108,4 +197,182 @@ func (s *hemodialysisMonitoringService) GetMonitoringByID(userID, monitoringID u
 	}
 
 	return monitoring, nil
-}
\ No newline at end of file
+}
+// resolveBloodPressure mengambil BP terstruktur, atau mem-parse teks dari aplikasi lama.
+func resolveBloodPressure(structured *dto.BloodPressureDTO, text string, label string) (int, int, *int, error) {
+	if structured != nil {
+		if err := utils.ValidateBloodPressure(structured.Systolic, structured.Diastolic, structured.Pulse); err != nil {
+			return 0, 0, nil, fmt.Errorf("tekanan darah %s: %w", label, err)
+		}
+		return structured.Systolic, structured.Diastolic, structured.Pulse, nil
+	}
+	if strings.TrimSpace(text) == "" {
+		return 0, 0, nil, fmt.Errorf("tekanan darah %s: %w", label, utils.ErrBloodPressureRequired)
+	}
+	systolic, diastolic, pulse, err := utils.ParseBloodPressure(text)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("tekanan darah %s: %w", label, err)
+	}
+	return systolic, diastolic, pulse, nil
+}
+
+// applyMonitoringInput memvalidasi dan mengisi data sesi HD dari DTO.
+func applyMonitoringInput(monitoring *models.HemodialysisMonitoring, input dto.CreateHemodialysisMonitoringDTO) error {
+	sysBefore, diaBefore, pulseBefore, err := resolveBloodPressure(input.BloodPressureBefore, input.BPBefore, "sebelum HD")
+	if err != nil {
+		return err
+	}
+	sysAfter, diaAfter, pulseAfter, err := resolveBloodPressure(input.BloodPressureAfter, input.BPAfter, "sesudah HD")
+	if err != nil {
+		return err
+	}
+
+	monitoring.SystolicBefore, monitoring.DiastolicBefore, monitoring.PulseBefore = &sysBefore, &diaBefore, pulseBefore
+	monitoring.SystolicAfter, monitoring.DiastolicAfter, monitoring.PulseAfter = &sysAfter, &diaAfter, pulseAfter
+	monitoring.BPBefore = utils.FormatBloodPressure(sysBefore, diaBefore)
+	monitoring.BPAfter = utils.FormatBloodPressure(sysAfter, diaAfter)
+	monitoring.BPNeedsReview = false
+
+	monitoring.WeightBefore = input.WeightBefore
+	monitoring.WeightAfter = input.WeightAfter
+	monitoring.UFGoalML = input.UFGoalML
+	monitoring.UFAchievedML = input.UFAchievedML
+	monitoring.DurationMinutes = input.DurationMinutes
+	monitoring.DryWeight = input.DryWeight
+	monitoring.Symptoms = strings.Join(input.Symptoms, ",")
+	return nil
+}
+
+// GetIDWGAlerts mengambil sesi dengan IDWG melewati ambang dalam beberapa hari terakhir.
+// Klinisi hanya melihat pasien yang ditugaskan kepadanya, admin melihat semua.
+func (s *hemodialysisMonitoringService) GetIDWGAlerts(requesterID uint, requesterRole string, days int) ([]models.HemodialysisMonitoring, error) {
+	var patientIDs []uint
+	if requesterRole != models.RoleAdmin {
+		var err error
+		patientIDs, err = s.careTeamRepo.FindPatientIDsByMember(requesterID, models.CareRelationClinician)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil daftar pasien: %w", err)
+		}
+		if len(patientIDs) == 0 {
+			return []models.HemodialysisMonitoring{}, nil
+		}
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	monitorings, err := s.monitoringRepo.FindIDWGExceededSince(since, patientIDs)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil peringatan IDWG: %w", err)
+	}
+	return monitorings, nil
+}
+
+// computeIDWG menghitung IDWG dari berat sebelum HD dikurangi berat sesudah HD sesi sebelumnya,
+// serta persentasenya terhadap berat kering (dari sesi ini atau sesi terakhir yang mencatatnya).
+// Sesi sebelumnya yang lebih jauh dari IDWGMaxGapDays hari tidak dipakai sebagai pembanding.
+func (s *hemodialysisMonitoringService) computeIDWG(monitoring *models.HemodialysisMonitoring) error {
+	monitoring.IDWGKg, monitoring.IDWGPercent, monitoring.IDWGExceeded = nil, nil, false
+
+	previous, err := s.monitoringRepo.FindPreviousSession(monitoring.UserID, monitoring.MonitoringDate)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // Sesi pertama, belum ada pembanding
+		}
+		return fmt.Errorf("gagal mengambil sesi sebelumnya: %w", err)
+	}
+	if monitoring.MonitoringDate.Sub(previous.MonitoringDate) > time.Duration(IDWGMaxGapDays())*24*time.Hour {
+		return nil
+	}
+	idwg := math.Round((monitoring.WeightBefore-previous.WeightAfter)*100) / 100
+	monitoring.IDWGKg = &idwg
+
+	dryWeight := 0.0
+	if monitoring.DryWeight != nil {
+		dryWeight = *monitoring.DryWeight
+	} else if dryWeight, err = s.monitoringRepo.FindLatestDryWeight(monitoring.UserID, monitoring.MonitoringDate); err != nil {
+		return fmt.Errorf("gagal mengambil berat kering: %w", err)
+	}
+	if dryWeight <= 0 {
+		return nil
+	}
+	percent := math.Round(idwg/dryWeight*10000) / 100
+	monitoring.IDWGPercent = &percent
+	monitoring.IDWGExceeded = percent > IDWGAlertPercent()
+	return nil
+}
+
+// refreshNextSessionIDWG menghitung ulang IDWG sesi setelahnya jika data sesi ini diisi belakangan.
+func (s *hemodialysisMonitoringService) refreshNextSessionIDWG(monitoring models.HemodialysisMonitoring) error {
+	next, err := s.monitoringRepo.FindNextSession(monitoring.UserID, monitoring.MonitoringDate)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("gagal mengambil sesi berikutnya: %w", err)
+	}
+	if err := s.computeIDWG(&next); err != nil {
+		return err
+	}
+	saved, err := s.monitoringRepo.Update(next)
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui IDWG sesi berikutnya: %w", err)
+	}
+	s.publishIDWGAlertIfNeeded(saved)
+	return nil
+}
+
+// publishIDWGAlertIfNeeded meminta worker mengirim push IDWG (sekali per sesi).
+func (s *hemodialysisMonitoringService) publishIDWGAlertIfNeeded(monitoring models.HemodialysisMonitoring) {
+	if !monitoring.IDWGExceeded || monitoring.IDWGAlertSentAt != nil || s.queueService == nil {
+		return
+	}
+	payload := ReminderMessage{ScheduleType: ScheduleTypeIDWGAlert, ScheduleID: monitoring.ID}
+	if err := s.queueService.PublishMessage(payload); err != nil {
+		log.Printf("WARNING: Failed to publish IDWG alert for monitoring ID %d: %v", monitoring.ID, err)
+	}
+}
+
+// GetMonitoringBySchedule mengambil data pemantauan untuk satu sesi HD milik user.
+func (s *hemodialysisMonitoringService) GetMonitoringBySchedule(userID, scheduleID uint) (models.HemodialysisMonitoring, error) {
+	if _, err := s.resolveSchedule(userID, &scheduleID, time.Time{}, false); err != nil {
+		return models.HemodialysisMonitoring{}, err
+	}
+	monitoring, err := s.monitoringRepo.FindByScheduleID(scheduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.HemodialysisMonitoring{}, ErrMonitoringNotFound
+		}
+		return models.HemodialysisMonitoring{}, fmt.Errorf("gagal mencari data pemantauan: %w", err)
+	}
+	return monitoring, nil
+}
+
+// resolveSchedule mencari jadwal HD untuk pemantauan. Dengan scheduleID, jadwal harus milik user
+// dan (jika tanggal dikirim eksplisit) bertanggal sama. Tanpa scheduleID, jadwal aktif pada
+// tanggal pemantauan dipakai bila ada; nil berarti hari tanpa jadwal.
+func (s *hemodialysisMonitoringService) resolveSchedule(userID uint, scheduleID *uint, monitoringDate time.Time, explicitDate bool) (*models.HemodialysisSchedule, error) {
+	if scheduleID == nil {
+		schedule, err := s.scheduleRepo.FindActiveByUserAndDate(userID, monitoringDate)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("gagal mencari jadwal hemodialisa: %w", err)
+		}
+		return &schedule, nil
+	}
+
+	schedule, err := s.scheduleRepo.FindByID(*scheduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrHemodialysisScheduleNotFound
+		}
+		return nil, fmt.Errorf("gagal mencari jadwal hemodialisa: %w", err)
+	}
+	if schedule.UserID != userID {
+		return nil, ErrHemodialysisScheduleForbidden
+	}
+	if explicitDate && !schedule.ScheduleDate.Equal(monitoringDate) {
+		return nil, errors.New("monitoring_date tidak sesuai dengan tanggal jadwal hemodialisa")
+	}
+	return &schedule, nil
+}
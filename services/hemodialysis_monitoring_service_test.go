@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type stubMonitoringRepo struct {
+	repositories.HemodialysisMonitoringRepository
+	previous *models.HemodialysisMonitoring
+}
+
+func (r stubMonitoringRepo) FindPreviousSession(userID uint, before time.Time) (models.HemodialysisMonitoring, error) {
+	if r.previous == nil {
+		return models.HemodialysisMonitoring{}, gorm.ErrRecordNotFound
+	}
+	return *r.previous, nil
+}
+
+func (r stubMonitoringRepo) FindLatestDryWeight(userID uint, before time.Time) (float64, error) {
+	return 0, nil
+}
+
+func TestComputeIDWG(t *testing.T) {
+	date := func(day int) time.Time { return time.Date(2024, 5, day, 0, 0, 0, 0, time.UTC) }
+	previousOn := func(day int) *models.HemodialysisMonitoring {
+		return &models.HemodialysisMonitoring{MonitoringDate: date(day), WeightAfter: 60}
+	}
+
+	tests := []struct {
+		name        string
+		maxGapDays  int // 0 = bawaan
+		previous    *models.HemodialysisMonitoring
+		wantKg      *float64
+		wantPercent *float64
+		wantAlert   bool
+	}{
+		{"regular gap", 0, previousOn(18), floatPtr(3), floatPtr(5.08), true},
+		{"long weekend gap", 0, previousOn(17), floatPtr(3), floatPtr(5.08), true},
+		{"exactly seven days", 0, previousOn(14), floatPtr(3), floatPtr(5.08), true},
+		{"missed session", 0, previousOn(10), nil, nil, false},
+		{"configured shorter gap", 3, previousOn(17), nil, nil, false},
+		{"configured longer gap", 14, previousOn(10), floatPtr(3), floatPtr(5.08), true},
+		{"first session", 0, nil, nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			if tt.maxGapDays > 0 {
+				viper.Set("IDWG_MAX_GAP_DAYS", tt.maxGapDays)
+			}
+
+			service := &hemodialysisMonitoringService{monitoringRepo: stubMonitoringRepo{previous: tt.previous}}
+			monitoring := models.HemodialysisMonitoring{MonitoringDate: date(21), WeightBefore: 63, DryWeight: floatPtr(59)}
+			if err := service.computeIDWG(&monitoring); err != nil {
+				t.Fatalf("computeIDWG() error = %v", err)
+			}
+			if !equalFloatPtr(monitoring.IDWGKg, tt.wantKg) || !equalFloatPtr(monitoring.IDWGPercent, tt.wantPercent) {
+				t.Errorf("computeIDWG() kg = %v, percent = %v, want %v, %v", deref(monitoring.IDWGKg), deref(monitoring.IDWGPercent), deref(tt.wantKg), deref(tt.wantPercent))
+			}
+			if monitoring.IDWGExceeded != tt.wantAlert {
+				t.Errorf("computeIDWG() exceeded = %v, want %v", monitoring.IDWGExceeded, tt.wantAlert)
+			}
+		})
+	}
+}
+
+func equalFloatPtr(a, b *float64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func deref(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"path"
+
+	// Registrasi decoder untuk image.Decode/DecodeConfig
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/darmawguna/tirtaapp.git/storage"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// MaxImageUploadBytes adalah batas ukuran file gambar yang diunggah (foto profil & thumbnail).
+	// Gambar tetap diperkecil saat diproses, jadi foto langsung dari kamera ponsel masih diterima.
+	MaxImageUploadBytes = 10 * 1024 * 1024
+
+	// Batas jumlah piksel sebelum decode, untuk menolak "decompression bomb".
+	maxImagePixels = 50_000_000
+	// Sisi terpanjang gambar utama yang disimpan.
+	maxImageDimension = 1600
+	imageJPEGQuality  = 85
+
+	// Nama objek gambar utama di dalam direktori gambar terproses.
+	processedImageName = "full.jpg"
+)
+
+var (
+	ErrImageUnsupported = errors.New("file bukan gambar yang didukung (JPEG, PNG, GIF, atau WebP)")
+	ErrImageTooLarge    = errors.New("ukuran atau dimensi gambar melebihi batas")
+)
+
+// imageVariant adalah satu ukuran turunan yang dibuat untuk setiap gambar.
+type imageVariant struct {
+	Name   string
+	Width  int
+	Format string
+}
+
+// Varian dibuat dengan lebar tetap (tinggi mengikuti rasio). WebP dari encoder pure-Go bersifat
+// lossless sehingga pada ukuran besar justru lebih berat dari JPEG; karena itu hanya dibuat untuk 128px.
+var imageVariants = []imageVariant{
+	{Name: "128", Width: 128, Format: "jpeg"},
+	{Name: "512", Width: 512, Format: "jpeg"},
+	{Name: "128_webp", Width: 128, Format: "webp"},
+}
+
+// ImageService memproses gambar unggahan: decode (menolak file non-gambar berdasarkan isi),
+// memutar sesuai orientasi EXIF, membuang seluruh metadata (termasuk lokasi GPS) dengan
+// meng-encode ulang, lalu menyimpan gambar utama beserta variannya ke BlobStore.
+type ImageService interface {
+	// Store menyimpan gambar di bawah prefix dan mengembalikan key gambar utama.
+	Store(ctx context.Context, prefix string, src io.Reader) (string, error)
+}
+
+type imageService struct {
+	store storage.BlobStore
+}
+
+func NewImageService(store storage.BlobStore) ImageService {
+	return &imageService{store: store}
+}
+
+func (s *imageService) Store(ctx context.Context, prefix string, src io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(src, MaxImageUploadBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("gagal membaca gambar: %w", err)
+	}
+	if len(data) > MaxImageUploadBytes {
+		return "", ErrImageTooLarge
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrImageUnsupported
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return "", fmt.Errorf("%w: %dx%d piksel", ErrImageTooLarge, config.Width, config.Height)
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return "", ErrImageUnsupported
+	}
+	// Ratakan transparansi ke latar putih agar hasil JPEG tidak menjadi hitam
+	img = imaging.OverlayCenter(imaging.New(img.Bounds().Dx(), img.Bounds().Dy(), color.White), img, 1)
+	if img.Bounds().Dx() > maxImageDimension || img.Bounds().Dy() > maxImageDimension {
+		img = imaging.Fit(img, maxImageDimension, maxImageDimension, imaging.Lanczos)
+	}
+
+	// Setiap gambar mendapat direktori acak sendiri: <prefix>/<token>/full.jpg, 128.jpg, 512.jpg, 128.webp
+	dir := storage.Key(prefix, utils.RandomToken(16))
+	key := path.Join(dir, processedImageName)
+	if err := s.put(ctx, key, img, "jpeg"); err != nil {
+		return "", err
+	}
+
+	variantKeys := ImageVariantKeys(key)
+	for _, variant := range imageVariants {
+		resized := img
+		if img.Bounds().Dx() > variant.Width {
+			resized = imaging.Resize(img, variant.Width, 0, imaging.Lanczos)
+		}
+		if err := s.put(ctx, variantKeys[variant.Name], resized, variant.Format); err != nil {
+			// Jangan tinggalkan gambar setengah jadi
+			for _, objectKey := range ImageObjectKeys(key) {
+				s.store.Delete(context.Background(), objectKey)
+			}
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+func (s *imageService) put(ctx context.Context, key string, img image.Image, format string) error {
+	var buf bytes.Buffer
+	contentType := "image/jpeg"
+	var err error
+	if format == "webp" {
+		contentType = "image/webp"
+		err = nativewebp.Encode(&buf, img, nil)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: imageJPEGQuality})
+	}
+	if err != nil {
+		return fmt.Errorf("gagal meng-encode gambar '%s': %w", key, err)
+	}
+	if err := s.store.Put(ctx, key, &buf, int64(buf.Len()), contentType); err != nil {
+		return fmt.Errorf("gagal menyimpan gambar '%s': %w", key, err)
+	}
+	return nil
+}
+
+// ImageVariantKeys mengembalikan key varian (nama varian -> key) dari key gambar utama.
+// Gambar lama yang diunggah sebelum pipeline ini (file tunggal) tidak punya varian, hasilnya nil.
+func ImageVariantKeys(key string) map[string]string {
+	key = storage.NormalizeKey(key)
+	if path.Base(key) != processedImageName {
+		return nil
+	}
+	dir := path.Dir(key)
+	keys := make(map[string]string, len(imageVariants))
+	for _, variant := range imageVariants {
+		ext := ".jpg"
+		if variant.Format == "webp" {
+			ext = ".webp"
+		}
+		keys[variant.Name] = path.Join(dir, fmt.Sprintf("%d%s", variant.Width, ext))
+	}
+	return keys
+}
+
+// ImageObjectKeys mengembalikan semua key milik satu gambar (utama + varian), dipakai saat menghapus.
+func ImageObjectKeys(key string) []string {
+	keys := []string{storage.NormalizeKey(key)}
+	for _, variantKey := range ImageVariantKeys(key) {
+		keys = append(keys, variantKey)
+	}
+	return keys
+}
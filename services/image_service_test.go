@@ -0,0 +1,225 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/darmawguna/tirtaapp.git/storage"
+)
+
+// testImageGPSMarker ditulis sebagai GPSMapDatum agar terlihat jika metadata GPS ikut tersimpan.
+const testImageGPSMarker = "TIRTAGPS"
+
+// jpegWithEXIF membuat JPEG 300x150 (kiri merah, kanan biru) dengan segmen EXIF berisi
+// orientasi 6 (putar 90 derajat searah jarum jam) dan tag GPS.
+func jpegWithEXIF(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 300, 150))
+	for y := 0; y < 150; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 150 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+
+	// TIFF big-endian: IFD0 (Orientation, GPSInfo) di offset 8, IFD GPS di offset 38, teks di 68.
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	ifdEntry := func(tag, typ uint16, count, value uint32) []byte {
+		entry := make([]byte, 12)
+		binary.BigEndian.PutUint16(entry[0:], tag)
+		binary.BigEndian.PutUint16(entry[2:], typ)
+		binary.BigEndian.PutUint32(entry[4:], count)
+		binary.BigEndian.PutUint32(entry[8:], value)
+		return entry
+	}
+	tiff = append(tiff, 0x00, 0x02)
+	tiff = append(tiff, ifdEntry(0x0112, 3, 1, 6<<16)...) // Orientation = 6
+	tiff = append(tiff, ifdEntry(0x8825, 4, 1, 38)...)    // GPSInfo
+	tiff = append(tiff, 0, 0, 0, 0)
+	tiff = append(tiff, 0x00, 0x02)
+	tiff = append(tiff, ifdEntry(0x0001, 2, 2, uint32('S')<<24)...)                    // GPSLatitudeRef
+	tiff = append(tiff, ifdEntry(0x0012, 2, uint32(len(testImageGPSMarker)+1), 68)...) // GPSMapDatum
+	tiff = append(tiff, 0, 0, 0, 0)
+	tiff = append(tiff, testImageGPSMarker+"\x00"...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(payload)+2))
+	app1 = append(app1, payload...)
+
+	data := encoded.Bytes()
+	return append(append(append([]byte{}, data[:2]...), app1...), data[2:]...)
+}
+
+// pngHeader membuat PNG yang hanya berisi header IHDR dengan dimensi yang diminta.
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], width)
+	binary.BigEndian.PutUint32(ihdr[4:], height)
+	ihdr[8], ihdr[9] = 8, 2 // 8 bit, RGB
+	chunk := make([]byte, 4)
+	binary.BigEndian.PutUint32(chunk, uint32(len(ihdr)))
+	typed := append([]byte("IHDR"), ihdr...)
+	chunk = append(chunk, typed...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(typed))
+	return append([]byte("\x89PNG\r\n\x1a\n"), chunk...)
+}
+
+func readStoredObject(t *testing.T, store storage.BlobStore, key string) []byte {
+	t.Helper()
+	object, _, err := store.Open(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Open(%q) error = %v", key, err)
+	}
+	defer object.Close()
+	data, err := io.ReadAll(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestImageServiceStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"jpeg with exif orientation and gps", jpegWithEXIF(t), nil},
+		{"not an image", []byte("bukan gambar, hanya teks biasa"), ErrImageUnsupported},
+		{"over the pixel limit", pngHeader(10000, 6000), ErrImageTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			store, err := storage.NewLocalStore(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			key, err := NewImageService(store).Store(context.Background(), storage.PrefixProfiles, bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Store() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				stored := 0
+				filepath.WalkDir(root, func(_ string, entry fs.DirEntry, _ error) error {
+					if entry != nil && !entry.IsDir() {
+						stored++
+					}
+					return nil
+				})
+				if stored != 0 {
+					t.Errorf("Store() left %d objects after rejecting the upload", stored)
+				}
+				return
+			}
+
+			// Gambar utama diputar sesuai EXIF: 300x150 menjadi 150x300, sisi kiri (merah) di atas.
+			full := readStoredObject(t, store, key)
+			img, err := jpeg.Decode(bytes.NewReader(full))
+			if err != nil {
+				t.Fatalf("stored main image is not a JPEG: %v", err)
+			}
+			if got := img.Bounds().Size(); got != image.Pt(150, 300) {
+				t.Errorf("main image size = %v, want (150,300)", got)
+			}
+			if r, _, b, _ := img.At(75, 20).RGBA(); r < b {
+				t.Errorf("top of main image is not red after auto-orientation (r=%d, b=%d)", r, b)
+			}
+
+			wantSizes := map[string]image.Point{"128": image.Pt(128, 256), "512": image.Pt(150, 300), "128_webp": image.Pt(128, 256)}
+			for name, variantKey := range ImageVariantKeys(key) {
+				variant := readStoredObject(t, store, variantKey)
+				config, _, err := image.DecodeConfig(bytes.NewReader(variant))
+				if err != nil {
+					t.Fatalf("variant %s: %v", name, err)
+				}
+				if got := image.Pt(config.Width, config.Height); got != wantSizes[name] {
+					t.Errorf("variant %s size = %v, want %v", name, got, wantSizes[name])
+				}
+			}
+
+			for _, objectKey := range ImageObjectKeys(key) {
+				data := readStoredObject(t, store, objectKey)
+				if bytes.Contains(data, []byte("Exif")) || bytes.Contains(data, []byte(testImageGPSMarker)) {
+					t.Errorf("%s still contains EXIF/GPS metadata", objectKey)
+				}
+			}
+		})
+	}
+}
+
+func TestImageVariantKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want map[string]string
+	}{
+		{"processed image", "profiles/abc/full.jpg", map[string]string{
+			"128":      "profiles/abc/128.jpg",
+			"512":      "profiles/abc/512.jpg",
+			"128_webp": "profiles/abc/128.webp",
+		}},
+		{"legacy path to processed image", "./uploads/educations/abc/full.jpg", map[string]string{
+			"128":      "educations/abc/128.jpg",
+			"512":      "educations/abc/512.jpg",
+			"128_webp": "educations/abc/128.webp",
+		}},
+		{"legacy single file", "profiles/1700000000_foto.jpg", nil},
+		{"legacy single file path", "./uploads/profiles/foto.png", nil},
+		{"similar name", "profiles/abc/full.jpeg", nil},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ImageVariantKeys(tt.key); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImageVariantKeys(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageObjectKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		wantMain  string
+		wantCount int
+	}{
+		{"processed image has main and variants", "profiles/abc/full.jpg", "profiles/abc/full.jpg", 1 + len(imageVariants)},
+		{"legacy single file", "./uploads/profiles/foto.png", "profiles/foto.png", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := ImageObjectKeys(tt.key)
+			if len(keys) != tt.wantCount {
+				t.Fatalf("ImageObjectKeys(%q) = %v, want %d keys", tt.key, keys, tt.wantCount)
+			}
+			if keys[0] != tt.wantMain {
+				t.Errorf("ImageObjectKeys(%q)[0] = %q, want %q", tt.key, keys[0], tt.wantMain)
+			}
+		})
+	}
+}
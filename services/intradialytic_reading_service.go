@@ -0,0 +1,213 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// Default kriteria hipotensi intradialitik (IDH).
+const (
+	defaultIDHSystolicDrop  = 20 // Penurunan sistolik dari sebelum HD (mmHg)
+	defaultIDHNadirSystolic = 90 // Sistolik di bawah nilai ini dianggap hipotensi (mmHg)
+)
+
+// Sesi malam (HD nokturnal) bisa melewati tengah malam; pengukuran masih diterima hingga
+// sekian jam setelah tengah malam waktu lokal pasien.
+const readingOvernightHours = 12
+
+var (
+	ErrReadingNotFound        = errors.New("data pengukuran tidak ditemukan")
+	ErrMonitoringAccessDenied = errors.New("tidak berwenang mengakses data pemantauan ini")
+)
+
+// HypotensionCriteria adalah ambang IDH yang sedang berlaku.
+type HypotensionCriteria struct {
+	SystolicDrop  int
+	NadirSystolic int
+}
+
+// CurrentHypotensionCriteria membaca IDH_SYSTOLIC_DROP dan IDH_NADIR_SYSTOLIC.
+func CurrentHypotensionCriteria() HypotensionCriteria {
+	criteria := HypotensionCriteria{SystolicDrop: defaultIDHSystolicDrop, NadirSystolic: defaultIDHNadirSystolic}
+	if drop := viper.GetInt("IDH_SYSTOLIC_DROP"); drop > 0 {
+		criteria.SystolicDrop = drop
+	}
+	if nadir := viper.GetInt("IDH_NADIR_SYSTOLIC"); nadir > 0 {
+		criteria.NadirSystolic = nadir
+	}
+	return criteria
+}
+
+// Evaluate mengembalikan alasan hipotensi untuk satu pengukuran (kosong jika tidak memenuhi kriteria).
+func (c HypotensionCriteria) Evaluate(systolic int, baselineSystolic *int) []string {
+	reasons := []string{}
+	if baselineSystolic != nil && *baselineSystolic-systolic >= c.SystolicDrop {
+		reasons = append(reasons, fmt.Sprintf("sistolik turun %d mmHg dari sebelum HD", *baselineSystolic-systolic))
+	}
+	if systolic < c.NadirSystolic {
+		reasons = append(reasons, fmt.Sprintf("sistolik di bawah %d mmHg", c.NadirSystolic))
+	}
+	return reasons
+}
+
+// FlaggedReading adalah pengukuran beserta hasil evaluasi hipotensi.
+type FlaggedReading struct {
+	Reading            models.IntradialyticReading
+	HypotensionReasons []string
+}
+
+// IntradialyticSeries adalah deret waktu pengukuran satu sesi HD.
+type IntradialyticSeries struct {
+	Monitoring          models.HemodialysisMonitoring
+	Criteria            HypotensionCriteria
+	Readings            []FlaggedReading
+	HypotensionReadings int
+}
+
+type IntradialyticReadingService interface {
+	AddReading(requesterID uint, requesterRole string, monitoringID uint, input dto.CreateIntradialyticReadingDTO) (FlaggedReading, error)
+	GetSeries(requesterID uint, requesterRole string, monitoringID uint) (IntradialyticSeries, error)
+	DeleteReading(requesterID uint, requesterRole string, monitoringID, readingID uint) error
+}
+
+type intradialyticReadingService struct {
+	repo           repositories.IntradialyticReadingRepository
+	monitoringRepo repositories.HemodialysisMonitoringRepository
+	userRepo       repositories.UserRepository
+	careTeamRepo   repositories.CareTeamRepository
+}
+
+func NewIntradialyticReadingService(repo repositories.IntradialyticReadingRepository, monitoringRepo repositories.HemodialysisMonitoringRepository, userRepo repositories.UserRepository, careTeamRepo repositories.CareTeamRepository) IntradialyticReadingService {
+	return &intradialyticReadingService{repo: repo, monitoringRepo: monitoringRepo, userRepo: userRepo, careTeamRepo: careTeamRepo}
+}
+
+func (s *intradialyticReadingService) AddReading(requesterID uint, requesterRole string, monitoringID uint, input dto.CreateIntradialyticReadingDTO) (FlaggedReading, error) {
+	monitoring, err := s.findAccessibleMonitoring(requesterID, requesterRole, monitoringID, true)
+	if err != nil {
+		return FlaggedReading{}, err
+	}
+
+	recordedAt, err := time.Parse(time.RFC3339, input.RecordedAt)
+	if err != nil {
+		return FlaggedReading{}, fmt.Errorf("format recorded_at tidak valid (gunakan RFC3339): %w", err)
+	}
+	if recordedAt.After(time.Now().Add(5 * time.Minute)) {
+		return FlaggedReading{}, errors.New("waktu pengukuran tidak boleh di masa depan")
+	}
+	if input.Diastolic >= input.Systolic {
+		return FlaggedReading{}, errors.New("diastolik harus lebih kecil dari sistolik")
+	}
+
+	// Pengukuran harus berada pada hari sesi (waktu lokal pasien) atau lanjutan sesi setelah tengah malam
+	patient, err := s.userRepo.FindByID(monitoring.UserID)
+	if err != nil {
+		return FlaggedReading{}, fmt.Errorf("gagal mengambil data pasien: %w", err)
+	}
+	if !withinSessionWindow(recordedAt, monitoring.MonitoringDate, utils.LoadUserLocation(patient.Timezone)) {
+		return FlaggedReading{}, fmt.Errorf("waktu pengukuran harus pada tanggal sesi hemodialisa atau hingga %d jam setelah tengah malam", readingOvernightHours)
+	}
+
+	reading, err := s.repo.Create(models.IntradialyticReading{
+		HemodialysisMonitoringID: monitoring.ID,
+		RecordedAt:               recordedAt,
+		Systolic:                 input.Systolic,
+		Diastolic:                input.Diastolic,
+		Pulse:                    input.Pulse,
+		BloodFlowRate:            input.BloodFlowRate,
+		VenousPressure:           input.VenousPressure,
+		ArterialPressure:         input.ArterialPressure,
+		UFRate:                   input.UFRate,
+		Notes:                    input.Notes,
+		RecordedBy:               requesterID,
+	})
+	if err != nil {
+		return FlaggedReading{}, fmt.Errorf("gagal menyimpan data pengukuran: %w", err)
+	}
+	return FlaggedReading{
+		Reading:            reading,
+		HypotensionReasons: CurrentHypotensionCriteria().Evaluate(reading.Systolic, monitoring.SystolicBefore),
+	}, nil
+}
+
+// GetSeries mengembalikan seluruh pengukuran sesi. Flag hipotensi dihitung saat dibaca agar
+// selalu mengikuti ambang terbaru dan sistolik sebelum HD yang mungkin dikoreksi.
+func (s *intradialyticReadingService) GetSeries(requesterID uint, requesterRole string, monitoringID uint) (IntradialyticSeries, error) {
+	monitoring, err := s.findAccessibleMonitoring(requesterID, requesterRole, monitoringID, false)
+	if err != nil {
+		return IntradialyticSeries{}, err
+	}
+	readings, err := s.repo.FindByMonitoringID(monitoring.ID)
+	if err != nil {
+		return IntradialyticSeries{}, fmt.Errorf("gagal mengambil data pengukuran: %w", err)
+	}
+
+	series := IntradialyticSeries{Monitoring: monitoring, Criteria: CurrentHypotensionCriteria()}
+	for _, reading := range readings {
+		reasons := series.Criteria.Evaluate(reading.Systolic, monitoring.SystolicBefore)
+		if len(reasons) > 0 {
+			series.HypotensionReadings++
+		}
+		series.Readings = append(series.Readings, FlaggedReading{Reading: reading, HypotensionReasons: reasons})
+	}
+	return series, nil
+}
+
+func (s *intradialyticReadingService) DeleteReading(requesterID uint, requesterRole string, monitoringID, readingID uint) error {
+	if _, err := s.findAccessibleMonitoring(requesterID, requesterRole, monitoringID, true); err != nil {
+		return err
+	}
+	reading, err := s.repo.FindByID(readingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReadingNotFound
+		}
+		return fmt.Errorf("gagal mencari data pengukuran: %w", err)
+	}
+	if reading.HemodialysisMonitoringID != monitoringID {
+		return ErrReadingNotFound
+	}
+	if err := s.repo.Delete(reading.ID); err != nil {
+		return fmt.Errorf("gagal menghapus data pengukuran: %w", err)
+	}
+	return nil
+}
+
+// findAccessibleMonitoring memeriksa hak akses: admin selalu boleh, klinisi hanya untuk pasien
+// yang ditugaskan kepadanya, pasien hanya boleh membaca datanya sendiri.
+func (s *intradialyticReadingService) findAccessibleMonitoring(requesterID uint, requesterRole string, monitoringID uint, write bool) (models.HemodialysisMonitoring, error) {
+	monitoring, err := s.monitoringRepo.FindByID(monitoringID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.HemodialysisMonitoring{}, ErrMonitoringNotFound
+		}
+		return models.HemodialysisMonitoring{}, fmt.Errorf("gagal mencari data pemantauan: %w", err)
+	}
+
+	// Pasien hanya boleh membaca; pencatatan dilakukan klinisi/admin
+	if write && requesterRole != models.RoleAdmin && requesterRole != models.RoleClinician {
+		return models.HemodialysisMonitoring{}, ErrMonitoringAccessDenied
+	}
+	if err := authorizePatientAccess(s.careTeamRepo, requesterID, requesterRole, monitoring.UserID); err != nil {
+		if errors.Is(err, ErrPatientAccessDenied) {
+			return models.HemodialysisMonitoring{}, ErrMonitoringAccessDenied
+		}
+		return models.HemodialysisMonitoring{}, err
+	}
+	return monitoring, nil
+}
+
+// withinSessionWindow bernilai true jika recordedAt berada antara tengah malam awal tanggal sesi
+// dan readingOvernightHours jam setelah tengah malam berikutnya, menurut zona waktu pasien.
+func withinSessionWindow(recordedAt, sessionDate time.Time, loc *time.Location) bool {
+	start := time.Date(sessionDate.Year(), sessionDate.Month(), sessionDate.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1).Add(readingOvernightHours * time.Hour)
+	return !recordedAt.Before(start) && recordedAt.Before(end)
+}
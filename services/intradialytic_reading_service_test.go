@@ -0,0 +1,68 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHypotensionCriteriaEvaluate(t *testing.T) {
+	criteria := HypotensionCriteria{SystolicDrop: 20, NadirSystolic: 90}
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name     string
+		systolic int
+		baseline *int
+		want     []string
+	}{
+		{"stable", 130, intPtr(140), []string{}},
+		{"drop just below threshold", 121, intPtr(140), []string{}},
+		{"systolic drop", 120, intPtr(140), []string{"sistolik turun 20 mmHg dari sebelum HD"}},
+		{"low systolic", 85, intPtr(95), []string{"sistolik di bawah 90 mmHg"}},
+		{"at nadir is not low", 90, intPtr(100), []string{}},
+		{"drop and low systolic", 80, intPtr(130), []string{"sistolik turun 50 mmHg dari sebelum HD", "sistolik di bawah 90 mmHg"}},
+		{"nil baseline, normal systolic", 100, nil, []string{}},
+		{"nil baseline, low systolic", 85, nil, []string{"sistolik di bawah 90 mmHg"}},
+		{"systolic rising", 160, intPtr(140), []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := criteria.Evaluate(tt.systolic, tt.baseline); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate(%d) = %q, want %q", tt.systolic, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithinSessionWindow(t *testing.T) {
+	wita := time.FixedZone("WITA", 8*3600)
+	sessionDate := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 5, day, hour, minute, 0, 0, wita) }
+
+	tests := []struct {
+		name       string
+		recordedAt time.Time
+		want       bool
+	}{
+		{"start of session day", at(10, 0, 0), true},
+		{"evening session start", at(10, 20, 30), true},
+		{"after midnight", at(11, 1, 15), true},
+		{"nocturnal session end", at(11, 6, 0), true},
+		{"last minute of window", at(11, 11, 59), true},
+		{"window end", at(11, 12, 0), false},
+		{"day before", at(9, 23, 59), false},
+		{"two days later", at(12, 8, 0), false},
+		{"utc instant inside local window", time.Date(2024, 5, 10, 18, 0, 0, 0, time.UTC), true},
+		{"utc instant before local midnight", time.Date(2024, 5, 9, 15, 59, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinSessionWindow(tt.recordedAt, sessionDate, wita); got != tt.want {
+				t.Errorf("withinSessionWindow(%s) = %v, want %v", tt.recordedAt, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// Nama-nama job terjadwal yang dijalankan oleh worker.
+const (
+	JobMonitoringReminder = "monitoring_reminder"
+	JobMissedDoseSweep    = "missed_dose_sweep"
+	JobDeviceTokenCleanup = "device_token_cleanup"
+	JobDailyFluidReport   = "daily_fluid_report"
+	JobMonitoringMissing  = "monitoring_missing_sweep"
+	JobLabDueReminder     = "lab_due_reminder"
+	JobAccessSelfCheck    = "access_self_check_reminder"
+	JobComplaintSLA       = "complaint_sla_escalation"
+	JobEducationUploadGC  = "education_upload_cleanup"
+)
+
+// ScheduleType khusus untuk memicu job secara manual lewat RabbitMQ.
+const ScheduleTypeJob = "JOB"
+
+// JobDefinition mendeskripsikan satu job di registry.
+// Jika PerUserTimezone true, ekspresi cron dievaluasi di timezone masing-masing user,
+// jika tidak maka dievaluasi di SERVER_TIMEZONE.
+type JobDefinition struct {
+	Name            string
+	Description     string
+	CronSpec        string
+	PerUserTimezone bool
+}
+
+// JobStatus menggabungkan definisi job dengan eksekusi terakhirnya.
+type JobStatus struct {
+	Definition JobDefinition
+	LastRun    *models.JobRun
+}
+
+// defaultJobDefinitions adalah registry bawaan. CronSpec bisa di-override
+// lewat env JOB_<NAMA_JOB>_CRON, contoh: JOB_MONITORING_REMINDER_CRON="0 5 * * *".
+var defaultJobDefinitions = []JobDefinition{
+	{Name: JobMonitoringReminder, Description: "Pengingat pengisian pemantauan hemodialisa pada hari jadwal HD", CronSpec: "0 6 * * *", PerUserTimezone: true},
+	{Name: JobMissedDoseSweep, Description: "Menutup jadwal obat yang sudah lewat dan mencatat dosis yang tidak terkirim pengingatnya", CronSpec: "5 0 * * *", PerUserTimezone: true},
+	{Name: JobDeviceTokenCleanup, Description: "Menghapus token FCM yang sudah lama tidak diperbarui", CronSpec: "0 3 * * 0", PerUserTimezone: false},
+	{Name: JobDailyFluidReport, Description: "Ringkasan keseimbangan cairan harian untuk pasien", CronSpec: "0 20 * * *", PerUserTimezone: true},
+	{Name: JobMonitoringMissing, Description: "Menandai jadwal HD yang sudah lewat tanpa data pemantauan", CronSpec: "15 0 * * *", PerUserTimezone: true},
+	{Name: JobLabDueReminder, Description: "Pengingat pemeriksaan lab bulanan untuk pasien yang belum punya hasil lab terbaru", CronSpec: "0 8 1 * *", PerUserTimezone: true},
+	{Name: JobAccessSelfCheck, Description: "Pengingat pemeriksaan thrill harian untuk pasien dengan fistula/graft aktif", CronSpec: "0 9 * * *", PerUserTimezone: true},
+	{Name: JobEducationUploadGC, Description: "Menghapus sesi upload media edukasi yang kedaluwarsa beserta file potongannya", CronSpec: "30 3 * * *", PerUserTimezone: false},
+	{Name: JobComplaintSLA, Description: "Eskalasi keluhan urgent/darurat yang belum diterima petugas melewati batas SLA", CronSpec: "*/5 * * * *", PerUserTimezone: false},
+}
+
+// JobDefinitions mengembalikan registry job dengan CronSpec yang sudah di-resolve dari config.
+func JobDefinitions() []JobDefinition {
+	definitions := make([]JobDefinition, 0, len(defaultJobDefinitions))
+	for _, def := range defaultJobDefinitions {
+		if spec := viper.GetString("JOB_" + strings.ToUpper(def.Name) + "_CRON"); spec != "" {
+			def.CronSpec = spec
+		}
+		definitions = append(definitions, def)
+	}
+	return definitions
+}
+
+// FindJobDefinition mencari definisi job berdasarkan nama.
+func FindJobDefinition(name string) (JobDefinition, bool) {
+	for _, def := range JobDefinitions() {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return JobDefinition{}, false
+}
+
+var ErrJobNotFound = errors.New("job tidak ditemukan")
+
+type JobService interface {
+	ListJobs() ([]JobStatus, error)
+	GetRuns(jobName string, limit int) ([]models.JobRun, error)
+	TriggerJob(jobName string, timezone string) error
+}
+
+type jobService struct {
+	jobRunRepo   repositories.JobRunRepository
+	queueService QueueService
+}
+
+func NewJobService(jobRunRepo repositories.JobRunRepository, queueService QueueService) JobService {
+	return &jobService{jobRunRepo: jobRunRepo, queueService: queueService}
+}
+
+func (s *jobService) ListJobs() ([]JobStatus, error) {
+	var statuses []JobStatus
+	for _, def := range JobDefinitions() {
+		status := JobStatus{Definition: def}
+		lastRun, err := s.jobRunRepo.FindLatestByJobName(def.Name)
+		if err == nil {
+			status.LastRun = &lastRun
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("gagal mengambil eksekusi terakhir job %s: %w", def.Name, err)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s *jobService) GetRuns(jobName string, limit int) ([]models.JobRun, error) {
+	if _, ok := FindJobDefinition(jobName); !ok {
+		return nil, ErrJobNotFound
+	}
+	runs, err := s.jobRunRepo.FindByJobName(jobName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat job: %w", err)
+	}
+	return runs, nil
+}
+
+// TriggerJob meminta worker menjalankan job sekarang juga melalui RabbitMQ.
+// timezone opsional; jika kosong, job per-timezone dijalankan untuk semua timezone user.
+func (s *jobService) TriggerJob(jobName string, timezone string) error {
+	if _, ok := FindJobDefinition(jobName); !ok {
+		return ErrJobNotFound
+	}
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("timezone tidak valid: %w", err)
+		}
+	}
+	payload := ReminderMessage{
+		ScheduleType: ScheduleTypeJob,
+		JobName:      jobName,
+		Timezone:     timezone,
+	}
+	if err := s.queueService.PublishMessage(payload); err != nil {
+		return fmt.Errorf("gagal mengirim trigger job: %w", err)
+	}
+	log.Printf("Manual trigger published for job %s (timezone: %q)", jobName, timezone)
+	return nil
+}
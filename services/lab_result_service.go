@@ -0,0 +1,390 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// Lab umumnya diperiksa bulanan, jadi rentang bawaan lebih panjang dari data harian.
+const (
+	defaultLabRangeDays = 365
+	maxLabRangeDays     = 5 * 366
+)
+
+// Target adekuasi dialisis bawaan (KDOQI) untuk HD 3x seminggu.
+const (
+	defaultURRTargetPercent = 65
+	defaultKtVTarget        = 1.2
+)
+
+var (
+	ErrLabResultNotFound = errors.New("hasil lab tidak ditemukan")
+	ErrLabResultConflict = errors.New("hasil lab pada tanggal ini sudah diinput petugas atau user lain dan tidak dapat ditimpa")
+)
+
+// AdequacyTargets adalah target URR & Kt/V yang sedang berlaku.
+type AdequacyTargets struct {
+	URRPercent float64
+	KtV        float64
+}
+
+// CurrentAdequacyTargets membaca URR_TARGET_PERCENT dan KTV_TARGET.
+func CurrentAdequacyTargets() AdequacyTargets {
+	targets := AdequacyTargets{URRPercent: defaultURRTargetPercent, KtV: defaultKtVTarget}
+	if urr := viper.GetFloat64("URR_TARGET_PERCENT"); urr > 0 {
+		targets.URRPercent = urr
+	}
+	if ktv := viper.GetFloat64("KTV_TARGET"); ktv > 0 {
+		targets.KtV = ktv
+	}
+	return targets
+}
+
+// LabResultView adalah hasil lab beserta status terhadap rentang rujukan katalog saat ini.
+type LabResultView struct {
+	Result models.LabResult
+	Flag   string
+}
+
+// LabTrend adalah deret waktu satu tes lab.
+type LabTrend struct {
+	Test   models.LabTest
+	From   time.Time
+	To     time.Time
+	Points []LabResultView
+}
+
+// AdequacyPoint adalah URR & Kt/V untuk satu tanggal yang punya ureum pra dan pasca HD.
+type AdequacyPoint struct {
+	SampleDate   time.Time
+	UreaPre      float64
+	UreaPost     float64
+	URRPercent   float64
+	KtV          *float64
+	MonitoringID *uint
+	BelowTarget  bool
+}
+
+type AdequacyReport struct {
+	From    time.Time
+	To      time.Time
+	Targets AdequacyTargets
+	Points  []AdequacyPoint
+}
+
+type LabResultService interface {
+	RecordResults(requesterID uint, requesterRole string, patientID uint, input dto.RecordLabResultsDTO) ([]LabResultView, error)
+	GetResults(requesterID uint, requesterRole string, patientID uint, from, to string, flaggedOnly bool) ([]LabResultView, error)
+	GetTrend(requesterID uint, requesterRole string, patientID uint, testCode, from, to string) (LabTrend, error)
+	GetAdequacy(requesterID uint, requesterRole string, patientID uint, from, to string) (AdequacyReport, error)
+	DeleteResult(requesterID uint, requesterRole string, resultID uint) error
+}
+
+type labResultService struct {
+	repo           repositories.LabResultRepository
+	testRepo       repositories.LabTestRepository
+	monitoringRepo repositories.HemodialysisMonitoringRepository
+	userRepo       repositories.UserRepository
+	careTeamRepo   repositories.CareTeamRepository
+}
+
+func NewLabResultService(repo repositories.LabResultRepository, testRepo repositories.LabTestRepository, monitoringRepo repositories.HemodialysisMonitoringRepository, userRepo repositories.UserRepository, careTeamRepo repositories.CareTeamRepository) LabResultService {
+	return &labResultService{repo: repo, testRepo: testRepo, monitoringRepo: monitoringRepo, userRepo: userRepo, careTeamRepo: careTeamRepo}
+}
+
+// RecordResults menyimpan satu panel hasil lab secara utuh (semua atau tidak sama sekali). Tes yang sudah
+// ada pada tanggal yang sama ditimpa, kecuali pasien mencoba menimpa nilai yang diinput orang lain.
+func (s *labResultService) RecordResults(requesterID uint, requesterRole string, patientID uint, input dto.RecordLabResultsDTO) ([]LabResultView, error) {
+	patient, err := findAccessiblePatient(s.careTeamRepo, s.userRepo, requesterID, requesterRole, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	sampleDate, err := time.Parse("2006-01-02", input.SampleDate)
+	if err != nil {
+		return nil, fmt.Errorf("format sample_date tidak valid: %w", err)
+	}
+	if sampleDate.After(utils.TodayIn(utils.LoadUserLocation(patient.Timezone))) {
+		return nil, errors.New("tanggal sampel tidak boleh di masa depan")
+	}
+
+	// Validasi semua kode dulu agar panel tidak tersimpan setengah
+	tests := make([]models.LabTest, len(input.Results))
+	seen := make(map[string]bool)
+	for i, item := range input.Results {
+		if seen[item.TestCode] {
+			return nil, fmt.Errorf("tes %s diisi lebih dari sekali", item.TestCode)
+		}
+		seen[item.TestCode] = true
+		test, err := s.testRepo.FindByCode(item.TestCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("kode tes lab tidak dikenal: %s", item.TestCode)
+			}
+			return nil, fmt.Errorf("gagal mencari tes lab: %w", err)
+		}
+		if !test.IsActive {
+			return nil, fmt.Errorf("tes lab %s sudah tidak aktif", item.TestCode)
+		}
+		tests[i] = test
+	}
+
+	results := make([]models.LabResult, len(input.Results))
+	for i, item := range input.Results {
+		results[i] = models.LabResult{
+			UserID:     patient.ID,
+			LabTestID:  tests[i].ID,
+			SampleDate: sampleDate,
+			Value:      *item.Value,
+			EnteredBy:  requesterID,
+			Notes:      input.Notes,
+		}
+	}
+	saved, err := s.repo.SavePanel(results, func(existing models.LabResult) error {
+		// Nilai dari klinisi/admin (atau pasien lain) tidak boleh diganti pasien; klinisi boleh mengoreksi
+		if requesterRole == models.RoleUser && existing.EnteredBy != requesterID {
+			return ErrLabResultConflict
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrLabResultConflict) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("gagal menyimpan hasil lab: %w", err)
+	}
+
+	views := make([]LabResultView, 0, len(saved))
+	for _, result := range saved {
+		views = append(views, toLabResultView(result))
+	}
+	return views, nil
+}
+
+func (s *labResultService) GetResults(requesterID uint, requesterRole string, patientID uint, from, to string, flaggedOnly bool) ([]LabResultView, error) {
+	patient, err := findAccessiblePatient(s.careTeamRepo, s.userRepo, requesterID, requesterRole, patientID)
+	if err != nil {
+		return nil, err
+	}
+	fromDate, toDate, err := resolvePatientDateRange(patient, from, to, defaultLabRangeDays, maxLabRangeDays)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.repo.FindByUserAndDateRange(patient.ID, fromDate, toDate, 0)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil hasil lab: %w", err)
+	}
+
+	views := []LabResultView{}
+	for _, result := range results {
+		view := toLabResultView(result)
+		if flaggedOnly && view.Flag == models.LabFlagNormal {
+			continue
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+func (s *labResultService) GetTrend(requesterID uint, requesterRole string, patientID uint, testCode, from, to string) (LabTrend, error) {
+	patient, err := findAccessiblePatient(s.careTeamRepo, s.userRepo, requesterID, requesterRole, patientID)
+	if err != nil {
+		return LabTrend{}, err
+	}
+	test, err := s.testRepo.FindByCode(testCode)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return LabTrend{}, ErrLabTestNotFound
+		}
+		return LabTrend{}, fmt.Errorf("gagal mencari tes lab: %w", err)
+	}
+	fromDate, toDate, err := resolvePatientDateRange(patient, from, to, defaultLabRangeDays, maxLabRangeDays)
+	if err != nil {
+		return LabTrend{}, err
+	}
+	results, err := s.repo.FindByUserAndDateRange(patient.ID, fromDate, toDate, test.ID)
+	if err != nil {
+		return LabTrend{}, fmt.Errorf("gagal mengambil hasil lab: %w", err)
+	}
+
+	trend := LabTrend{Test: test, From: fromDate, To: toDate, Points: []LabResultView{}}
+	for _, result := range results {
+		trend.Points = append(trend.Points, toLabResultView(result))
+	}
+	return trend, nil
+}
+
+// GetAdequacy menghitung URR & Kt/V dari pasangan ureum pra/pasca HD pada tanggal yang sama.
+// Dihitung saat dibaca agar koreksi ureum atau data sesi HD yang dilengkapi belakangan ikut terpakai.
+func (s *labResultService) GetAdequacy(requesterID uint, requesterRole string, patientID uint, from, to string) (AdequacyReport, error) {
+	patient, err := findAccessiblePatient(s.careTeamRepo, s.userRepo, requesterID, requesterRole, patientID)
+	if err != nil {
+		return AdequacyReport{}, err
+	}
+	fromDate, toDate, err := resolvePatientDateRange(patient, from, to, defaultLabRangeDays, maxLabRangeDays)
+	if err != nil {
+		return AdequacyReport{}, err
+	}
+	results, err := s.repo.FindByUserAndDateRange(patient.ID, fromDate, toDate, 0)
+	if err != nil {
+		return AdequacyReport{}, fmt.Errorf("gagal mengambil hasil lab: %w", err)
+	}
+
+	preByDate := make(map[time.Time]float64)
+	postByDate := make(map[time.Time]float64)
+	var dates []time.Time
+	for _, result := range results {
+		switch result.LabTest.Code {
+		case models.LabTestUreaPre:
+			preByDate[result.SampleDate] = result.Value
+			dates = append(dates, result.SampleDate)
+		case models.LabTestUreaPost:
+			postByDate[result.SampleDate] = result.Value
+		}
+	}
+
+	report := AdequacyReport{From: fromDate, To: toDate, Targets: CurrentAdequacyTargets(), Points: []AdequacyPoint{}}
+	for _, date := range dates {
+		pre, post := preByDate[date], postByDate[date]
+		if pre <= 0 || post <= 0 || post >= pre {
+			continue
+		}
+		point := AdequacyPoint{
+			SampleDate: date,
+			UreaPre:    pre,
+			UreaPost:   post,
+			URRPercent: calculateURR(pre, post),
+		}
+
+		monitoring, err := s.monitoringRepo.FindByUserIDAndDate(patient.ID, date)
+		if err == nil {
+			point.MonitoringID = &monitoring.ID
+			point.KtV = calculateKtV(pre, post, monitoring)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return AdequacyReport{}, fmt.Errorf("gagal mengambil data sesi HD: %w", err)
+		}
+
+		point.BelowTarget = point.URRPercent < report.Targets.URRPercent ||
+			(point.KtV != nil && *point.KtV < report.Targets.KtV)
+		report.Points = append(report.Points, point)
+	}
+	return report, nil
+}
+
+// DeleteResult menghapus satu hasil lab. Pasien hanya boleh menghapus hasil yang ia input sendiri.
+func (s *labResultService) DeleteResult(requesterID uint, requesterRole string, resultID uint) error {
+	result, err := s.repo.FindByID(resultID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLabResultNotFound
+		}
+		return fmt.Errorf("gagal mencari hasil lab: %w", err)
+	}
+	if err := authorizePatientAccess(s.careTeamRepo, requesterID, requesterRole, result.UserID); err != nil {
+		return err
+	}
+	if requesterRole == models.RoleUser && result.EnteredBy != requesterID {
+		return ErrPatientAccessDenied
+	}
+	if err := s.repo.Delete(result.ID); err != nil {
+		return fmt.Errorf("gagal menghapus hasil lab: %w", err)
+	}
+	return nil
+}
+
+func toLabResultView(result models.LabResult) LabResultView {
+	return LabResultView{Result: result, Flag: classifyLabValue(result.LabTest, result.Value)}
+}
+
+// classifyLabValue membandingkan nilai dengan ambang katalog; ambang kritis didahulukan.
+func classifyLabValue(test models.LabTest, value float64) string {
+	switch {
+	case test.CriticalLow != nil && value < *test.CriticalLow:
+		return models.LabFlagCriticalLow
+	case test.CriticalHigh != nil && value > *test.CriticalHigh:
+		return models.LabFlagCriticalHigh
+	case test.RefLow != nil && value < *test.RefLow:
+		return models.LabFlagLow
+	case test.RefHigh != nil && value > *test.RefHigh:
+		return models.LabFlagHigh
+	}
+	return models.LabFlagNormal
+}
+
+// calculateURR menghitung urea reduction ratio (%) dibulatkan satu desimal: (1 - post/pre) x 100.
+func calculateURR(ureaPre, ureaPost float64) float64 {
+	return math.Round((1-ureaPost/ureaPre)*1000) / 10
+}
+
+// calculateKtV memakai rumus Daugirdas generasi kedua:
+// Kt/V = -ln(R - 0.008 x t) + (4 - 3.5 x R) x UF / W
+// dengan R = ureum pasca/pra, t = durasi (jam), UF = ultrafiltrasi (L), W = berat sesudah HD (kg).
+// Mengembalikan nil jika durasi atau berat sesudah HD belum tercatat.
+func calculateKtV(ureaPre, ureaPost float64, monitoring models.HemodialysisMonitoring) *float64 {
+	if monitoring.DurationMinutes == nil || *monitoring.DurationMinutes <= 0 || monitoring.WeightAfter <= 0 {
+		return nil
+	}
+	ratio := ureaPost / ureaPre
+	hours := float64(*monitoring.DurationMinutes) / 60
+	ufLiters := math.Max(monitoring.WeightBefore-monitoring.WeightAfter, 0)
+	if monitoring.UFAchievedML != nil {
+		ufLiters = float64(*monitoring.UFAchievedML) / 1000
+	}
+
+	inner := ratio - 0.008*hours
+	if inner <= 0 {
+		return nil
+	}
+	ktv := math.Round((-math.Log(inner)+(4-3.5*ratio)*ufLiters/monitoring.WeightAfter)*100) / 100
+	return &ktv
+}
+
+// findAccessiblePatient memeriksa hak akses (authorizePatientAccess) lalu mengambil data pasien.
+func findAccessiblePatient(careTeamRepo repositories.CareTeamRepository, userRepo repositories.UserRepository, requesterID uint, requesterRole string, patientID uint) (models.User, error) {
+	if err := authorizePatientAccess(careTeamRepo, requesterID, requesterRole, patientID); err != nil {
+		return models.User{}, err
+	}
+	patient, err := userRepo.FindByID(patientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.User{}, ErrPatientNotFound
+		}
+		return models.User{}, fmt.Errorf("gagal mengambil data pasien: %w", err)
+	}
+	return patient, nil
+}
+
+// resolvePatientDateRange mengembalikan rentang tanggal (inklusif); bawaan defaultDays hari terakhir
+// di waktu lokal pasien.
+func resolvePatientDateRange(patient models.User, from, to string, defaultDays, maxDays int) (time.Time, time.Time, error) {
+	var err error
+	toDate := utils.TodayIn(utils.LoadUserLocation(patient.Timezone))
+	if to != "" {
+		if toDate, err = time.Parse("2006-01-02", to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("format to tidak valid: %w", err)
+		}
+	}
+	fromDate := toDate.AddDate(0, 0, -(defaultDays - 1))
+	if from != "" {
+		if fromDate, err = time.Parse("2006-01-02", from); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("format from tidak valid: %w", err)
+		}
+	}
+
+	if fromDate.After(toDate) {
+		return time.Time{}, time.Time{}, errors.New("from tidak boleh setelah to")
+	}
+	if toDate.Sub(fromDate).Hours()/24 >= float64(maxDays) {
+		return time.Time{}, time.Time{}, fmt.Errorf("rentang tanggal maksimal %d hari", maxDays)
+	}
+	return fromDate, toDate, nil
+}
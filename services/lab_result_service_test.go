@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+)
+
+func TestCalculateURR(t *testing.T) {
+	tests := []struct {
+		name      string
+		pre, post float64
+		want      float64
+	}{
+		{"target reached", 100, 30, 70},
+		{"below target", 120, 50, 58.3},
+		{"rounded to one decimal", 90, 31, 65.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateURR(tt.pre, tt.post); got != tt.want {
+				t.Errorf("calculateURR(%v, %v) = %v, want %v", tt.pre, tt.post, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateKtV(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	monitoring := func(duration *int, weightBefore, weightAfter float64, ufML *int) models.HemodialysisMonitoring {
+		return models.HemodialysisMonitoring{DurationMinutes: duration, WeightBefore: weightBefore, WeightAfter: weightAfter, UFAchievedML: ufML}
+	}
+
+	tests := []struct {
+		name       string
+		pre, post  float64
+		monitoring models.HemodialysisMonitoring
+		want       *float64
+	}{
+		// Contoh Daugirdas II: R = 0.3, t = 4 jam, UF = 3 L, W = 70 kg -> 1.44
+		{"daugirdas reference", 100, 30, monitoring(intPtr(240), 73, 70, nil), floatPtr(1.44)},
+		{"uf achieved overrides weight difference", 100, 30, monitoring(intPtr(240), 73, 70, intPtr(2000)), floatPtr(1.4)},
+		{"weight gain counts as zero uf", 100, 30, monitoring(intPtr(240), 69, 70, nil), floatPtr(1.32)},
+		{"nil duration", 100, 30, monitoring(nil, 73, 70, nil), nil},
+		{"zero duration", 100, 30, monitoring(intPtr(0), 73, 70, nil), nil},
+		{"missing weight after", 100, 30, monitoring(intPtr(240), 73, 0, nil), nil},
+		{"log argument zero", 100, 3.2, monitoring(intPtr(240), 73, 70, nil), nil},
+		{"log argument negative", 100, 2, monitoring(intPtr(240), 73, 70, nil), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateKtV(tt.pre, tt.post, tt.monitoring)
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil:
+				t.Fatalf("calculateKtV() = %v, want %v", got, tt.want)
+			case *got != *tt.want:
+				t.Errorf("calculateKtV() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyLabValue(t *testing.T) {
+	potassium := models.LabTest{RefLow: floatPtr(3.5), RefHigh: floatPtr(5.0), CriticalLow: floatPtr(2.5), CriticalHigh: floatPtr(6.5)}
+	refOnly := models.LabTest{RefLow: floatPtr(3.5), RefHigh: floatPtr(5.0)}
+
+	tests := []struct {
+		name  string
+		test  models.LabTest
+		value float64
+		want  string
+	}{
+		{"normal", potassium, 4.2, models.LabFlagNormal},
+		{"on reference bounds", potassium, 5.0, models.LabFlagNormal},
+		{"low", potassium, 3.0, models.LabFlagLow},
+		{"high", potassium, 5.8, models.LabFlagHigh},
+		{"critical low wins over low", potassium, 2.0, models.LabFlagCriticalLow},
+		{"critical high wins over high", potassium, 7.1, models.LabFlagCriticalHigh},
+		{"no critical thresholds", refOnly, 8.0, models.LabFlagHigh},
+		{"no thresholds", models.LabTest{}, 100, models.LabFlagNormal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyLabValue(tt.test, tt.value); got != tt.want {
+				t.Errorf("classifyLabValue(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"gorm.io/gorm"
+)
+
+func labThreshold(value float64) *float64 {
+	return &value
+}
+
+// Katalog bawaan lab bulanan pasien hemodialisa. Rentang rujukan mengikuti target pasien
+// dialisis (bukan populasi umum) dan bisa diubah admin.
+var defaultLabTests = []models.LabTest{
+	{Code: "hb", Name: "Hemoglobin", Unit: "g/dL", RefLow: labThreshold(10), RefHigh: labThreshold(12), CriticalLow: labThreshold(7), SortOrder: 1, IsActive: true},
+	{Code: "potassium", Name: "Kalium", Unit: "mmol/L", RefLow: labThreshold(3.5), RefHigh: labThreshold(5.5), CriticalLow: labThreshold(2.5), CriticalHigh: labThreshold(6.5), SortOrder: 2, IsActive: true},
+	{Code: "phosphate", Name: "Fosfat", Unit: "mg/dL", RefLow: labThreshold(3.5), RefHigh: labThreshold(5.5), CriticalLow: labThreshold(1), CriticalHigh: labThreshold(9), SortOrder: 3, IsActive: true},
+	{Code: "calcium", Name: "Kalsium", Unit: "mg/dL", RefLow: labThreshold(8.4), RefHigh: labThreshold(9.5), CriticalLow: labThreshold(6.5), CriticalHigh: labThreshold(13), SortOrder: 4, IsActive: true},
+	{Code: "albumin", Name: "Albumin", Unit: "g/dL", RefLow: labThreshold(3.5), RefHigh: labThreshold(5), CriticalLow: labThreshold(2), SortOrder: 5, IsActive: true},
+	{Code: models.LabTestUreaPre, Name: "Ureum pra-HD", Unit: "mg/dL", SortOrder: 6, IsActive: true},
+	{Code: models.LabTestUreaPost, Name: "Ureum pasca-HD", Unit: "mg/dL", SortOrder: 7, IsActive: true},
+	{Code: "creatinine", Name: "Kreatinin", Unit: "mg/dL", SortOrder: 8, IsActive: true},
+}
+
+var ErrLabTestNotFound = errors.New("tes lab tidak ditemukan")
+
+type LabTestService interface {
+	EnsureDefaults() error
+	FindAll(includeInactive bool) ([]models.LabTest, error)
+	Create(input dto.CreateLabTestDTO) (models.LabTest, error)
+	Update(id uint, input dto.LabTestDTO) (models.LabTest, error)
+	Delete(id uint) error
+}
+
+type labTestService struct {
+	repo repositories.LabTestRepository
+}
+
+func NewLabTestService(repo repositories.LabTestRepository) LabTestService {
+	return &labTestService{repo: repo}
+}
+
+// EnsureDefaults mengisi katalog bawaan saat tabel tes lab masih kosong.
+func (s *labTestService) EnsureDefaults() error {
+	count, err := s.repo.Count()
+	if err != nil {
+		return fmt.Errorf("gagal menghitung katalog tes lab: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	for _, test := range defaultLabTests {
+		if _, err := s.repo.Create(test); err != nil {
+			return fmt.Errorf("gagal membuat tes lab %s: %w", test.Code, err)
+		}
+	}
+	log.Printf("Seeded %d default lab tests.", len(defaultLabTests))
+	return nil
+}
+
+func (s *labTestService) FindAll(includeInactive bool) ([]models.LabTest, error) {
+	return s.repo.FindAll(!includeInactive)
+}
+
+func (s *labTestService) Create(input dto.CreateLabTestDTO) (models.LabTest, error) {
+	if _, err := s.repo.FindByCode(input.Code); err == nil {
+		return models.LabTest{}, fmt.Errorf("kode tes lab %s sudah digunakan", input.Code)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.LabTest{}, fmt.Errorf("gagal memeriksa kode tes lab: %w", err)
+	}
+
+	test := models.LabTest{Code: input.Code, IsActive: true}
+	if err := applyLabTestInput(&test, input.LabTestDTO); err != nil {
+		return models.LabTest{}, err
+	}
+	created, err := s.repo.Create(test)
+	if err != nil {
+		return models.LabTest{}, fmt.Errorf("gagal membuat tes lab: %w", err)
+	}
+	return created, nil
+}
+
+func (s *labTestService) Update(id uint, input dto.LabTestDTO) (models.LabTest, error) {
+	test, err := s.findByID(id)
+	if err != nil {
+		return models.LabTest{}, err
+	}
+	if err := applyLabTestInput(&test, input); err != nil {
+		return models.LabTest{}, err
+	}
+	updated, err := s.repo.Update(test)
+	if err != nil {
+		return models.LabTest{}, fmt.Errorf("gagal memperbarui tes lab: %w", err)
+	}
+	return updated, nil
+}
+
+// Delete hanya menonaktifkan tes agar hasil lab lama tetap bisa ditampilkan.
+func (s *labTestService) Delete(id uint) error {
+	test, err := s.findByID(id)
+	if err != nil {
+		return err
+	}
+	test.IsActive = false
+	if _, err := s.repo.Update(test); err != nil {
+		return fmt.Errorf("gagal menonaktifkan tes lab: %w", err)
+	}
+	return nil
+}
+
+func (s *labTestService) findByID(id uint) (models.LabTest, error) {
+	test, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.LabTest{}, ErrLabTestNotFound
+		}
+		return models.LabTest{}, fmt.Errorf("gagal mencari tes lab: %w", err)
+	}
+	return test, nil
+}
+
+// applyLabTestInput menyalin input ke model setelah memastikan urutan ambang masuk akal:
+// kritis rendah <= rujukan rendah <= rujukan tinggi <= kritis tinggi.
+func applyLabTestInput(test *models.LabTest, input dto.LabTestDTO) error {
+	ordered := []*float64{input.CriticalLow, input.RefLow, input.RefHigh, input.CriticalHigh}
+	var previous *float64
+	for _, threshold := range ordered {
+		if threshold == nil {
+			continue
+		}
+		if previous != nil && *threshold < *previous {
+			return errors.New("ambang harus berurutan: critical_low <= ref_low <= ref_high <= critical_high")
+		}
+		previous = threshold
+	}
+
+	test.Name = input.Name
+	test.Unit = input.Unit
+	test.RefLow = input.RefLow
+	test.RefHigh = input.RefHigh
+	test.CriticalLow = input.CriticalLow
+	test.CriticalHigh = input.CriticalHigh
+	test.SortOrder = input.SortOrder
+	if input.IsActive != nil {
+		test.IsActive = *input.IsActive
+	}
+	return nil
+}
@@ -2,31 +2,33 @@ package services
 
 import (
 	"errors" // Tambahkan import errors
+	"context"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/darmawguna/tirtaapp.git/dto" // Sesuaikan path module Anda
 	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/storage"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // ProfileService mendefinisikan interface untuk operasi profil.
 type ProfileService interface {
 	GetProfile(userID uint) (models.User, error)
-	UpdateProfile(userID uint, input dto.UpdateProfileDTO, profilePicturePath *string) (models.User, error)
+	UpdateProfile(userID uint, input dto.UpdateProfileDTO, profilePictureKey *string) (models.User, error)
 	CountRegularUsers() (int64, error)
 }
 
 // profileService adalah implementasi dari ProfileService.
 type profileService struct {
 	userRepo repositories.UserRepository
+	store    storage.BlobStore
 }
 
 // NewProfileService adalah constructor untuk profileService.
-func NewProfileService(userRepo repositories.UserRepository) ProfileService {
-	return &profileService{userRepo: userRepo}
+func NewProfileService(userRepo repositories.UserRepository, store storage.BlobStore) ProfileService {
+	return &profileService{userRepo: userRepo, store: store}
 }
 
 // GetProfile mengambil data profil pengguna berdasarkan ID.
@@ -40,14 +42,15 @@ func (s *profileService) GetProfile(userID uint) (models.User, error) {
 }
 
 // UpdateProfile memperbarui data profil pengguna (nama dan/atau password).
-func (s *profileService) UpdateProfile(userID uint, input dto.UpdateProfileDTO, profilePicturePath *string) (models.User, error) {
+// profilePictureKey adalah key objek di BlobStore (lihat storage.PrefixProfiles).
+func (s *profileService) UpdateProfile(userID uint, input dto.UpdateProfileDTO, profilePictureKey *string) (models.User, error) {
 	// 1. Ambil data user saat ini
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return models.User{}, errors.New("user profile not found")
 	}
 
-	oldProfilePicturePath := user.ProfilePicture // Simpan path lama
+	oldProfilePictureKey := user.ProfilePicture // Simpan key lama
 	shouldDeleteOld := false
 
 	// 2. Perbarui nama jika ada di input
@@ -64,11 +67,11 @@ func (s *profileService) UpdateProfile(userID uint, input dto.UpdateProfileDTO,
 		user.Password = string(hashedPassword)
 	}
 
-	// 4. [PEMBARUAN] Perbarui foto profil jika path baru diberikan
-	if profilePicturePath != nil {
-		user.ProfilePicture = *profilePicturePath // Simpan path file baru
+	// 4. [PEMBARUAN] Perbarui foto profil jika key baru diberikan
+	if profilePictureKey != nil {
+		user.ProfilePicture = *profilePictureKey // Simpan key objek baru
 		// Tandai file lama untuk dihapus jika pathnya ada dan berbeda
-		if oldProfilePicturePath != "" && oldProfilePicturePath != *profilePicturePath {
+		if oldProfilePictureKey != "" && oldProfilePictureKey != *profilePictureKey {
 			shouldDeleteOld = true
 		}
 	}
@@ -82,15 +85,17 @@ func (s *profileService) UpdateProfile(userID uint, input dto.UpdateProfileDTO,
 
 	// 6. [PEMBARUAN] Hapus file lama jika update DB berhasil
 	if shouldDeleteOld {
-		go func(pathToDelete string) { // Hapus di background
-			log.Printf("Attempting to delete old profile picture: %s", pathToDelete)
-			err := os.Remove(pathToDelete) // Pastikan path ini absolut atau relatif dari CWD API
-			if err != nil {
-				log.Printf("WARNING: Failed to delete old profile picture '%s': %v", pathToDelete, err)
-			} else {
-				log.Printf("Old profile picture '%s' deleted successfully.", pathToDelete)
+		go func(keyToDelete string) { // Hapus di background
+			log.Printf("Attempting to delete old profile picture: %s", keyToDelete)
+			// Hapus gambar utama beserta variannya; nilai lama bisa masih berupa path file sebelum migrasi storage
+			for _, objectKey := range ImageObjectKeys(keyToDelete) {
+				if err := s.store.Delete(context.Background(), objectKey); err != nil {
+					log.Printf("WARNING: Failed to delete old profile picture '%s': %v", objectKey, err)
+					return
+				}
 			}
-		}(oldProfilePicturePath)
+			log.Printf("Old profile picture '%s' deleted successfully.", keyToDelete)
+		}(oldProfilePictureKey)
 	}
 
 	return updatedUser, nil
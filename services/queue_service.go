@@ -24,11 +24,22 @@ type ReminderMessage struct {
 	ScheduleType string `json:"schedule_type"`
 	ScheduleID   uint   `json:"schedule_id"`
 	TimeSlot     int    `json:"time_slot,omitempty"`
+	JobName      string `json:"job_name,omitempty"`    // Hanya untuk ScheduleType "JOB" (trigger manual)
+	Timezone     string `json:"timezone,omitempty"`    // Opsional untuk ScheduleType "JOB"
+	AlertLevel   string `json:"alert_level,omitempty"` // Hanya untuk ScheduleType "FLUID_ALERT"
+}
+
+// QueueStats adalah jumlah pesan siap kirim dan consumer aktif pada satu queue.
+type QueueStats struct {
+	Name      string
+	Messages  int
+	Consumers int
 }
 
 type QueueService interface {
 	Connect() error
 	PublishMessage(payload ReminderMessage) error
+	Inspect() ([]QueueStats, error)
 	Close()
 	GetChannel() *amqp091.Channel
 }
@@ -123,6 +134,20 @@ func (s *queueService) PublishMessage(payload ReminderMessage) error {
 	return nil
 }
 
+// Inspect membaca status queue utama dan DLQ tanpa mengubah deklarasinya (QueueDeclarePassive).
+func (s *queueService) Inspect() ([]QueueStats, error) {
+	if s.channel == nil || s.conn.IsClosed() {
+		if err := s.Connect(); err != nil { return nil, err }
+	}
+	var stats []QueueStats
+	for _, name := range []string{MainQueue, DeadLetterQueue} {
+		queue, err := s.channel.QueueDeclarePassive(name, true, false, false, false, nil)
+		if err != nil { return nil, fmt.Errorf("failed to inspect queue %s: %w", name, err) }
+		stats = append(stats, QueueStats{Name: queue.Name, Messages: queue.Messages, Consumers: queue.Consumers})
+	}
+	return stats, nil
+}
+
 func (s *queueService) Close() {
 	if s.channel != nil { s.channel.Close() }
 	if s.conn != nil { s.conn.Close() }
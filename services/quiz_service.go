@@ -1,32 +1,57 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
 	"github.com/darmawguna/tirtaapp.git/dto"
 	models "github.com/darmawguna/tirtaapp.git/model"
 	"github.com/darmawguna/tirtaapp.git/repositories"
+	"gorm.io/gorm"
 )
 
+const defaultQuizPassingScore = 70
+
+var (
+	ErrQuizNotFound    = errors.New("kuis tidak ditemukan")
+	ErrQuizNotNative   = errors.New("kuis ini berupa tautan eksternal dan tidak bisa dikerjakan di aplikasi")
+	ErrQuizHasAttempts = errors.New("kuis sudah pernah dikerjakan pasien sehingga tidak bisa dihapus")
+)
+
+// QuizHistory merangkum riwayat pengerjaan satu kuis oleh satu pasien.
+type QuizHistory struct {
+	Quiz           models.Quiz
+	Attempts       []models.QuizAttempt
+	BestPercentage float64
+	Passed         bool
+}
+
 type QuizService interface {
 	Create(input dto.CreateQuizDTO, createdBy uint) (models.Quiz, error)
 	FindAll() ([]models.Quiz, error)
-	FindByID(id uint) (models.Quiz, error)
+	FindByID(id uint, includeAnswerKey bool) (models.Quiz, error)
 	Update(id uint, input dto.UpdateQuizDTO) (models.Quiz, error)
 	Delete(id uint) error
+	SubmitAttempt(userID uint, quizID uint, input dto.SubmitQuizAttemptDTO) (models.QuizAttempt, models.Quiz, error)
+	GetHistory(requesterID uint, requesterRole string, patientID uint, quizID uint) ([]QuizHistory, error)
 }
 
 type quizService struct {
-	quizRepo repositories.QuizRepository
+	quizRepo     repositories.QuizRepository
+	careTeamRepo repositories.CareTeamRepository
 }
 
-func NewQuizService(quizRepo repositories.QuizRepository) QuizService {
-	return &quizService{quizRepo: quizRepo}
+func NewQuizService(quizRepo repositories.QuizRepository, careTeamRepo repositories.CareTeamRepository) QuizService {
+	return &quizService{quizRepo: quizRepo, careTeamRepo: careTeamRepo}
 }
 
 func (s *quizService) Create(input dto.CreateQuizDTO, createdBy uint) (models.Quiz, error) {
-	quiz := models.Quiz{
-		Name:      input.Name,
-		Url:       input.Url,
-		CreatedBy: createdBy,
+	quiz := models.Quiz{CreatedBy: createdBy}
+	if err := applyQuizInput(&quiz, input.Name, input.Type, input.Url, input.Description, input.PassingScore, input.Questions); err != nil {
+		return models.Quiz{}, err
 	}
 	return s.quizRepo.Create(quiz)
 }
@@ -35,20 +60,263 @@ func (s *quizService) FindAll() ([]models.Quiz, error) {
 	return s.quizRepo.FindAll()
 }
 
-func (s *quizService) FindByID(id uint) (models.Quiz, error) {
-	return s.quizRepo.FindByID(id)
+// FindByID mengambil kuis beserta soalnya. Tanpa includeAnswerKey, kunci jawaban dan
+// pembahasan dikosongkan agar tidak terlihat sebelum pasien mengirim jawaban.
+func (s *quizService) FindByID(id uint, includeAnswerKey bool) (models.Quiz, error) {
+	quiz, err := s.findQuiz(id)
+	if err != nil {
+		return models.Quiz{}, err
+	}
+	if !includeAnswerKey {
+		for i := range quiz.Questions {
+			quiz.Questions[i].Explanation = ""
+			for j := range quiz.Questions[i].Options {
+				quiz.Questions[i].Options[j].IsCorrect = false
+			}
+		}
+	}
+	return quiz, nil
 }
 
 func (s *quizService) Update(id uint, input dto.UpdateQuizDTO) (models.Quiz, error) {
-	quiz, err := s.quizRepo.FindByID(id)
+	quiz, err := s.findQuiz(id)
 	if err != nil {
 		return models.Quiz{}, err
 	}
-	quiz.Name = input.Name
-	quiz.Url = input.Url
+	if err := applyQuizInput(&quiz, input.Name, input.Type, input.Url, input.Description, input.PassingScore, input.Questions); err != nil {
+		return models.Quiz{}, err
+	}
 	return s.quizRepo.Update(quiz)
 }
 
+// Delete menghapus kuis yang belum pernah dikerjakan. Kuis dengan riwayat pengerjaan ditolak
+// agar nilai pasien tidak ikut terhapus (foreign key quiz_attempts juga RESTRICT).
 func (s *quizService) Delete(id uint) error {
-	return s.quizRepo.Delete(id)
-}
\ No newline at end of file
+	if _, err := s.findQuiz(id); err != nil {
+		return err
+	}
+	count, err := s.quizRepo.CountAttempts(id)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa riwayat kuis: %w", err)
+	}
+	if count > 0 {
+		return ErrQuizHasAttempts
+	}
+	if err := s.quizRepo.Delete(id); err != nil {
+		return fmt.Errorf("gagal menghapus kuis: %w", err)
+	}
+	return nil
+}
+
+// SubmitAttempt menilai jawaban di server. Soal pilihan tunggal & benar/salah benar jika
+// pilihan yang dipilih tepat kunci jawaban; pilihan ganda benar jika semua pilihan benar
+// dipilih tanpa pilihan salah. Soal yang tidak dijawab dihitung salah.
+func (s *quizService) SubmitAttempt(userID uint, quizID uint, input dto.SubmitQuizAttemptDTO) (models.QuizAttempt, models.Quiz, error) {
+	quiz, err := s.findQuiz(quizID)
+	if err != nil {
+		return models.QuizAttempt{}, models.Quiz{}, err
+	}
+	if quiz.Type != models.QuizTypeNative || len(quiz.Questions) == 0 {
+		return models.QuizAttempt{}, models.Quiz{}, ErrQuizNotNative
+	}
+
+	selected := make(map[uint][]uint, len(input.Answers))
+	for _, answer := range input.Answers {
+		if _, duplicate := selected[answer.QuestionID]; duplicate {
+			return models.QuizAttempt{}, models.Quiz{}, fmt.Errorf("soal %d dijawab lebih dari sekali", answer.QuestionID)
+		}
+		selected[answer.QuestionID] = answer.OptionIDs
+	}
+
+	attempt := models.QuizAttempt{QuizID: quiz.ID, UserID: userID, MaxScore: len(quiz.Questions)}
+	for _, question := range quiz.Questions {
+		optionIDs := selected[question.ID]
+		delete(selected, question.ID)
+
+		isCorrect, err := scoreQuizQuestion(question, optionIDs)
+		if err != nil {
+			return models.QuizAttempt{}, models.Quiz{}, err
+		}
+		if optionIDs == nil {
+			optionIDs = []uint{}
+		}
+		optionIDsJSON, err := json.Marshal(optionIDs)
+		if err != nil {
+			return models.QuizAttempt{}, models.Quiz{}, err
+		}
+		attempt.Answers = append(attempt.Answers, models.QuizAttemptAnswer{
+			QuestionID:        question.ID,
+			SelectedOptionIDs: optionIDsJSON,
+			IsCorrect:         isCorrect,
+		})
+		if isCorrect {
+			attempt.Score++
+		}
+	}
+	for questionID := range selected {
+		return models.QuizAttempt{}, models.Quiz{}, fmt.Errorf("soal %d bukan bagian dari kuis ini", questionID)
+	}
+
+	attempt.Percentage = math.Round(float64(attempt.Score)/float64(attempt.MaxScore)*10000) / 100
+	attempt.Passed = attempt.Percentage >= float64(quiz.PassingScore)
+
+	saved, err := s.quizRepo.CreateAttempt(attempt)
+	if err != nil {
+		return models.QuizAttempt{}, models.Quiz{}, fmt.Errorf("gagal menyimpan hasil kuis: %w", err)
+	}
+	return saved, quiz, nil
+}
+
+// GetHistory mengambil riwayat pengerjaan per kuis (quizID 0 = semua kuis) beserta nilai terbaik.
+func (s *quizService) GetHistory(requesterID uint, requesterRole string, patientID uint, quizID uint) ([]QuizHistory, error) {
+	if err := authorizePatientAccess(s.careTeamRepo, requesterID, requesterRole, patientID); err != nil {
+		return nil, err
+	}
+	attempts, err := s.quizRepo.FindAttemptsByUser(patientID, quizID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat kuis: %w", err)
+	}
+
+	histories := []QuizHistory{}
+	index := make(map[uint]int)
+	for _, attempt := range attempts {
+		i, ok := index[attempt.QuizID]
+		if !ok {
+			i = len(histories)
+			index[attempt.QuizID] = i
+			histories = append(histories, QuizHistory{Quiz: attempt.Quiz})
+		}
+		history := &histories[i]
+		history.Attempts = append(history.Attempts, attempt)
+		if attempt.Percentage > history.BestPercentage {
+			history.BestPercentage = attempt.Percentage
+		}
+		if attempt.Passed {
+			history.Passed = true
+		}
+	}
+	return histories, nil
+}
+
+func (s *quizService) findQuiz(id uint) (models.Quiz, error) {
+	quiz, err := s.quizRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Quiz{}, ErrQuizNotFound
+		}
+		return models.Quiz{}, fmt.Errorf("gagal mencari kuis: %w", err)
+	}
+	return quiz, nil
+}
+
+// applyQuizInput mengisi kuis dari input admin dan memvalidasi soal sesuai jenisnya.
+func applyQuizInput(quiz *models.Quiz, name, quizType, url, description string, passingScore *int, questions []dto.QuizQuestionDTO) error {
+	if quizType == "" {
+		quizType = models.QuizTypeURL
+	}
+	quiz.Name = name
+	quiz.Type = quizType
+	quiz.Description = description
+	quiz.PassingScore = defaultQuizPassingScore
+	if passingScore != nil {
+		quiz.PassingScore = *passingScore
+	}
+
+	if quizType == models.QuizTypeURL {
+		if url == "" {
+			return errors.New("url wajib diisi untuk kuis bertipe url")
+		}
+		if len(questions) > 0 {
+			return errors.New("kuis bertipe url tidak boleh memiliki soal")
+		}
+		quiz.Url = url
+		quiz.Questions = nil
+		return nil
+	}
+
+	if len(questions) == 0 {
+		return errors.New("kuis native harus memiliki minimal satu soal")
+	}
+	quiz.Url = ""
+	quiz.Questions = make([]models.QuizQuestion, 0, len(questions))
+	for i, input := range questions {
+		question, err := buildQuizQuestion(input, i+1)
+		if err != nil {
+			return err
+		}
+		quiz.Questions = append(quiz.Questions, question)
+	}
+	return nil
+}
+
+func buildQuizQuestion(input dto.QuizQuestionDTO, position int) (models.QuizQuestion, error) {
+	question := models.QuizQuestion{
+		Position:    position,
+		Type:        input.Type,
+		Text:        strings.TrimSpace(input.Text),
+		Explanation: input.Explanation,
+	}
+
+	if input.Type == models.QuestionTypeTrueFalse {
+		if input.Answer == nil {
+			return models.QuizQuestion{}, fmt.Errorf("soal %d: jawaban benar/salah wajib diisi", position)
+		}
+		question.Options = []models.QuizOption{
+			{Position: 1, Text: "Benar", IsCorrect: *input.Answer},
+			{Position: 2, Text: "Salah", IsCorrect: !*input.Answer},
+		}
+		return question, nil
+	}
+
+	if len(input.Options) < 2 {
+		return models.QuizQuestion{}, fmt.Errorf("soal %d: minimal dua pilihan jawaban", position)
+	}
+	correct := 0
+	for j, option := range input.Options {
+		question.Options = append(question.Options, models.QuizOption{Position: j + 1, Text: option.Text, IsCorrect: option.IsCorrect})
+		if option.IsCorrect {
+			correct++
+		}
+	}
+	if input.Type == models.QuestionTypeSingleChoice && correct != 1 {
+		return models.QuizQuestion{}, fmt.Errorf("soal %d: pilihan tunggal harus memiliki tepat satu jawaban benar", position)
+	}
+	if correct == 0 {
+		return models.QuizQuestion{}, fmt.Errorf("soal %d: minimal satu jawaban benar", position)
+	}
+	return question, nil
+}
+
+// scoreQuizQuestion menilai satu soal; error jika pilihan bukan milik soal tersebut.
+func scoreQuizQuestion(question models.QuizQuestion, optionIDs []uint) (bool, error) {
+	if question.Type != models.QuestionTypeMultipleChoice && len(optionIDs) > 1 {
+		return false, fmt.Errorf("soal %d hanya boleh dijawab dengan satu pilihan", question.ID)
+	}
+
+	chosen := make(map[uint]bool, len(optionIDs))
+	for _, optionID := range optionIDs {
+		chosen[optionID] = true
+	}
+	isCorrect := len(optionIDs) > 0
+	for _, option := range question.Options {
+		if option.IsCorrect != chosen[option.ID] {
+			isCorrect = false
+		}
+		delete(chosen, option.ID)
+	}
+	if len(chosen) > 0 {
+		return false, fmt.Errorf("pilihan jawaban tidak valid untuk soal %d", question.ID)
+	}
+	return isCorrect, nil
+}
+
+// QuizCorrectOptionIDs mengembalikan ID pilihan yang benar untuk sebuah soal.
+func QuizCorrectOptionIDs(question models.QuizQuestion) []uint {
+	ids := []uint{}
+	for _, option := range question.Options {
+		if option.IsCorrect {
+			ids = append(ids, option.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+)
+
+func TestScoreQuizQuestion(t *testing.T) {
+	single := models.QuizQuestion{ID: 1, Type: models.QuestionTypeSingleChoice, Options: []models.QuizOption{
+		{ID: 10, IsCorrect: true}, {ID: 11}, {ID: 12},
+	}}
+	trueFalse := models.QuizQuestion{ID: 2, Type: models.QuestionTypeTrueFalse, Options: []models.QuizOption{
+		{ID: 20}, {ID: 21, IsCorrect: true},
+	}}
+	multiple := models.QuizQuestion{ID: 3, Type: models.QuestionTypeMultipleChoice, Options: []models.QuizOption{
+		{ID: 30, IsCorrect: true}, {ID: 31, IsCorrect: true}, {ID: 32},
+	}}
+
+	tests := []struct {
+		name      string
+		question  models.QuizQuestion
+		optionIDs []uint
+		want      bool
+		wantErr   bool
+	}{
+		{"single correct", single, []uint{10}, true, false},
+		{"single wrong", single, []uint{11}, false, false},
+		{"single unanswered", single, nil, false, false},
+		{"single two options", single, []uint{10, 11}, false, true},
+		{"single foreign option", single, []uint{30}, false, true},
+		{"true/false correct", trueFalse, []uint{21}, true, false},
+		{"true/false wrong", trueFalse, []uint{20}, false, false},
+		{"multiple all correct", multiple, []uint{31, 30}, true, false},
+		{"multiple partially correct", multiple, []uint{30}, false, false},
+		{"multiple with a wrong option", multiple, []uint{30, 31, 32}, false, false},
+		{"multiple duplicate selections", multiple, []uint{30, 30, 31}, true, false},
+		{"multiple unanswered", multiple, []uint{}, false, false},
+		{"multiple foreign option", multiple, []uint{30, 31, 10}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scoreQuizQuestion(tt.question, tt.optionIDs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scoreQuizQuestion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("scoreQuizQuestion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/utils"
+)
+
+// Jenis pengingat yang bisa dikirim ulang (sama dengan ReminderMessage.ScheduleType).
+var RepublishableReminderTypes = []string{"DRUG", "KONTROL", "HEMODIALISA", "OBAT_HABIS"}
+
+// RepublishReport merangkum pesan yang dikirim ulang per jenis pengingat.
+type RepublishReport struct {
+	Published map[string]int
+	Failed    int
+}
+
+// ReminderRepublishService mengirim ulang pesan pengingat yang belum terkirim ke queue, misalnya
+// setelah queue RabbitMQ dikosongkan atau hilang. Worker mengabaikan pesan ganda lewat flag *Sent,
+// jadi aman dijalankan berulang.
+type ReminderRepublishService interface {
+	// Republish mengirim ulang pengingat aktif yang belum terkirim dan tanggalnya belum lewat.
+	// scheduleType kosong berarti semua jenis; userID 0 berarti semua user.
+	Republish(scheduleType string, userID uint, dryRun bool) (RepublishReport, error)
+}
+
+type reminderRepublishService struct {
+	userRepo             repositories.UserRepository
+	drugRepo             repositories.DrugScheduleRepository
+	controlRepo          repositories.ControlScheduleRepository
+	hemodialysisRepo     repositories.HemodialysisScheduleRepository
+	medicationRefillRepo repositories.MedicationRefillRepository
+	queueService         QueueService
+}
+
+func NewReminderRepublishService(userRepo repositories.UserRepository, drugRepo repositories.DrugScheduleRepository, controlRepo repositories.ControlScheduleRepository, hemodialysisRepo repositories.HemodialysisScheduleRepository, medicationRefillRepo repositories.MedicationRefillRepository, queueService QueueService) ReminderRepublishService {
+	return &reminderRepublishService{
+		userRepo:             userRepo,
+		drugRepo:             drugRepo,
+		controlRepo:          controlRepo,
+		hemodialysisRepo:     hemodialysisRepo,
+		medicationRefillRepo: medicationRefillRepo,
+		queueService:         queueService,
+	}
+}
+
+func (s *reminderRepublishService) Republish(scheduleType string, userID uint, dryRun bool) (RepublishReport, error) {
+	if scheduleType != "" && !isRepublishableType(scheduleType) {
+		return RepublishReport{}, fmt.Errorf("jenis pengingat '%s' tidak dikenal", scheduleType)
+	}
+
+	var users []models.User
+	if userID != 0 {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return RepublishReport{}, fmt.Errorf("gagal mencari user %d: %w", userID, err)
+		}
+		users = append(users, user)
+	} else {
+		var err error
+		users, err = s.userRepo.FindAll()
+		if err != nil {
+			return RepublishReport{}, fmt.Errorf("gagal memuat user: %w", err)
+		}
+	}
+
+	report := RepublishReport{Published: map[string]int{}}
+	for _, user := range users {
+		messages, err := s.pendingMessages(user, scheduleType)
+		if err != nil {
+			return report, err
+		}
+		for _, message := range messages {
+			if !dryRun {
+				if err := s.queueService.PublishMessage(message); err != nil {
+					log.Printf("ERROR: Failed to republish %s reminder for schedule %d: %v", message.ScheduleType, message.ScheduleID, err)
+					report.Failed++
+					continue
+				}
+			}
+			report.Published[message.ScheduleType]++
+		}
+	}
+	return report, nil
+}
+
+// pendingMessages menyusun pesan untuk jadwal milik user yang masih menunggu notifikasi.
+// Tanggal dibandingkan dengan hari ini di timezone user, sama seperti pengecekan di worker.
+func (s *reminderRepublishService) pendingMessages(user models.User, scheduleType string) ([]ReminderMessage, error) {
+	today := utils.TodayIn(utils.LoadUserLocation(user.Timezone))
+	upcoming := func(date time.Time) bool {
+		return !utils.LocalDate(date, time.UTC).Before(today)
+	}
+	wants := func(t string) bool { return scheduleType == "" || scheduleType == t }
+
+	var messages []ReminderMessage
+	if wants("DRUG") {
+		schedules, err := s.drugRepo.FindAllByUserID(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat jadwal obat user %d: %w", user.ID, err)
+		}
+		for _, schedule := range schedules {
+			if !schedule.IsActive || !upcoming(schedule.ScheduleDate) {
+				continue
+			}
+			slots := []struct {
+				hour    int
+				enabled bool
+				sent    bool
+			}{
+				{6, schedule.At06, schedule.At06Sent},
+				{12, schedule.At12, schedule.At12Sent},
+				{18, schedule.At18, schedule.At18Sent},
+			}
+			for _, slot := range slots {
+				if slot.enabled && !slot.sent {
+					messages = append(messages, ReminderMessage{ScheduleType: "DRUG", ScheduleID: schedule.ID, TimeSlot: slot.hour})
+				}
+			}
+		}
+	}
+	if wants("KONTROL") {
+		schedules, err := s.controlRepo.FindAllByUserID(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat jadwal kontrol user %d: %w", user.ID, err)
+		}
+		for _, schedule := range schedules {
+			if schedule.IsActive && !schedule.NotificationSent && upcoming(schedule.ControlDate) {
+				messages = append(messages, ReminderMessage{ScheduleType: "KONTROL", ScheduleID: schedule.ID})
+			}
+		}
+	}
+	if wants("HEMODIALISA") {
+		schedules, err := s.hemodialysisRepo.FindAllByUserID(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat jadwal hemodialisa user %d: %w", user.ID, err)
+		}
+		for _, schedule := range schedules {
+			if schedule.IsActive && !schedule.NotificationSent && upcoming(schedule.ScheduleDate) {
+				messages = append(messages, ReminderMessage{ScheduleType: "HEMODIALISA", ScheduleID: schedule.ID})
+			}
+		}
+	}
+	if wants("OBAT_HABIS") {
+		schedules, err := s.medicationRefillRepo.FindAllByUserID(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat jadwal ambil obat user %d: %w", user.ID, err)
+		}
+		for _, schedule := range schedules {
+			if schedule.IsActive && !schedule.NotificationSent && upcoming(schedule.RefillDate) {
+				messages = append(messages, ReminderMessage{ScheduleType: "OBAT_HABIS", ScheduleID: schedule.ID})
+			}
+		}
+	}
+	return messages, nil
+}
+
+func isRepublishableType(scheduleType string) bool {
+	for _, t := range RepublishableReminderTypes {
+		if t == scheduleType {
+			return true
+		}
+	}
+	return false
+}
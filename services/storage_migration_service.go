@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/storage"
+)
+
+// StorageMigrationReport merangkum hasil migrasi file upload ke BlobStore.
+type StorageMigrationReport struct {
+	Checked   int // Referensi yang diperiksa
+	Copied    int // File yang disalin ke BlobStore
+	Rewritten int // Nilai DB yang diubah dari path file menjadi key
+	Missing   int // File sumber tidak ditemukan
+	Skipped   int // URL eksternal
+	Failed    int
+}
+
+// StorageMigrationService memindahkan file lama di disk lokal ke BlobStore yang aktif dan
+// mengubah kolom DB dari path file ("./uploads/profiles/x.jpg") menjadi key ("profiles/x.jpg").
+// Aman dijalankan berulang: objek yang sudah ada tidak disalin ulang.
+type StorageMigrationService interface {
+	Migrate(sourceRoot string, dryRun bool) (StorageMigrationReport, error)
+}
+
+type storageMigrationService struct {
+	refRepo repositories.UploadReferenceRepository
+	store   storage.BlobStore
+}
+
+func NewStorageMigrationService(refRepo repositories.UploadReferenceRepository, store storage.BlobStore) StorageMigrationService {
+	return &storageMigrationService{refRepo: refRepo, store: store}
+}
+
+func (s *storageMigrationService) Migrate(sourceRoot string, dryRun bool) (StorageMigrationReport, error) {
+	var report StorageMigrationReport
+	refs, err := s.refRepo.FindAll()
+	if err != nil {
+		return report, fmt.Errorf("gagal membaca referensi file: %w", err)
+	}
+
+	ctx := context.Background()
+	for _, ref := range refs {
+		report.Checked++
+		label := fmt.Sprintf("%s.%s id=%d", ref.Table, ref.Column, ref.ID)
+		if strings.HasPrefix(ref.Value, "http") {
+			report.Skipped++
+			continue
+		}
+
+		key := storage.NormalizeKey(ref.Value)
+		exists, err := s.store.Exists(ctx, key)
+		if err != nil {
+			log.Printf("ERROR: %s: %v", label, err)
+			report.Failed++
+			continue
+		}
+		if !exists {
+			copied, err := s.copyFromDisk(ctx, sourceRoot, key, dryRun)
+			if err != nil {
+				log.Printf("ERROR: %s: %v", label, err)
+				report.Failed++
+				continue
+			}
+			if !copied {
+				log.Printf("MISSING: %s: file '%s' not found under %s", label, key, sourceRoot)
+				report.Missing++
+				continue
+			}
+			log.Printf("COPY: %s -> %s", label, key)
+			report.Copied++
+		}
+
+		if ref.Value != key {
+			if !dryRun {
+				if err := s.refRepo.UpdateValue(ref, key); err != nil {
+					log.Printf("ERROR: %s: gagal mengubah referensi: %v", label, err)
+					report.Failed++
+					continue
+				}
+			}
+			report.Rewritten++
+		}
+	}
+	return report, nil
+}
+
+// copyFromDisk menyalin <sourceRoot>/<key> ke BlobStore. Mengembalikan false jika file sumber tidak ada.
+func (s *storageMigrationService) copyFromDisk(ctx context.Context, sourceRoot string, key string, dryRun bool) (bool, error) {
+	file, err := os.Open(filepath.Join(sourceRoot, filepath.FromSlash(key)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+	if dryRun {
+		return true, nil
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if err := s.store.Put(ctx, key, file, stat.Size(), mime.TypeByExtension(path.Ext(key))); err != nil {
+		return false, err
+	}
+	return true, nil
+}
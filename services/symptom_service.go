@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"gorm.io/gorm"
+)
+
+// Katalog gejala bawaan pasien hemodialisa, dibuat jika tabel masih kosong.
+var defaultSymptoms = []models.Symptom{
+	{Code: "chest_pain", Name: "Nyeri dada", Aliases: "sakit dada,dada sakit,dada nyeri", Severity: models.SymptomSeveritySevere, IsRedFlag: true, SortOrder: 1, IsActive: true},
+	{Code: "shortness_of_breath", Name: "Sesak napas", Aliases: "sesak,sesak nafas,susah napas,sulit bernapas", Severity: models.SymptomSeveritySevere, IsRedFlag: true, SortOrder: 2, IsActive: true},
+	{Code: "fainting", Name: "Pingsan atau penurunan kesadaran", Aliases: "pingsan,tidak sadar,hilang kesadaran", Severity: models.SymptomSeveritySevere, IsRedFlag: true, SortOrder: 3, IsActive: true},
+	{Code: "seizure", Name: "Kejang", Severity: models.SymptomSeveritySevere, IsRedFlag: true, SortOrder: 4, IsActive: true},
+	{Code: "access_bleeding", Name: "Perdarahan dari akses HD", Aliases: "perdarahan akses,akses berdarah,cimino berdarah", Severity: models.SymptomSeveritySevere, IsRedFlag: true, SortOrder: 5, IsActive: true},
+	{Code: "fever", Name: "Demam atau menggigil", Aliases: "demam,menggigil,meriang,panas badan", Severity: models.SymptomSeverityModerate, SortOrder: 6, IsActive: true},
+	{Code: "edema", Name: "Bengkak di kaki atau wajah", Aliases: "bengkak,sembab", Severity: models.SymptomSeverityModerate, SortOrder: 7, IsActive: true},
+	{Code: "palpitations", Name: "Jantung berdebar", Aliases: "berdebar,deg degan", Severity: models.SymptomSeverityModerate, SortOrder: 8, IsActive: true},
+	{Code: "vomiting", Name: "Muntah", Severity: models.SymptomSeverityModerate, SortOrder: 9, IsActive: true},
+	{Code: "dizziness", Name: "Pusing berat", Aliases: "pusing,pening,kliyengan", Severity: models.SymptomSeverityModerate, SortOrder: 10, IsActive: true},
+	{Code: "nausea", Name: "Mual", Aliases: "eneg,enek", Severity: models.SymptomSeverityMild, SortOrder: 11, IsActive: true},
+	{Code: "muscle_cramp", Name: "Kram otot", Aliases: "kram,keram", Severity: models.SymptomSeverityMild, SortOrder: 12, IsActive: true},
+	{Code: "itching", Name: "Kulit gatal", Aliases: "gatal", Severity: models.SymptomSeverityMild, SortOrder: 13, IsActive: true},
+	{Code: "fatigue", Name: "Lemas atau mudah lelah", Aliases: "lemas,lelah,capek", Severity: models.SymptomSeverityMild, SortOrder: 14, IsActive: true},
+	{Code: "headache", Name: "Sakit kepala", Aliases: "nyeri kepala,kepala sakit", Severity: models.SymptomSeverityMild, SortOrder: 15, IsActive: true},
+	{Code: "insomnia", Name: "Sulit tidur", Aliases: "susah tidur,tidak bisa tidur", Severity: models.SymptomSeverityMild, SortOrder: 16, IsActive: true},
+}
+
+var ErrSymptomNotFound = errors.New("gejala tidak ditemukan")
+
+type SymptomService interface {
+	EnsureDefaults() error
+	FindAll(includeInactive bool) ([]models.Symptom, error)
+	Create(input dto.CreateSymptomDTO) (models.Symptom, error)
+	Update(id uint, input dto.SymptomDTO) (models.Symptom, error)
+	Delete(id uint) error
+}
+
+type symptomService struct {
+	repo repositories.SymptomRepository
+}
+
+func NewSymptomService(repo repositories.SymptomRepository) SymptomService {
+	return &symptomService{repo: repo}
+}
+
+// EnsureDefaults mengisi katalog gejala bawaan saat tabel masih kosong.
+func (s *symptomService) EnsureDefaults() error {
+	count, err := s.repo.Count()
+	if err != nil {
+		return fmt.Errorf("gagal menghitung katalog gejala: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	for _, symptom := range defaultSymptoms {
+		if _, err := s.repo.Create(symptom); err != nil {
+			return fmt.Errorf("gagal membuat gejala %s: %w", symptom.Code, err)
+		}
+	}
+	log.Printf("Seeded %d default symptoms.", len(defaultSymptoms))
+	return nil
+}
+
+func (s *symptomService) FindAll(includeInactive bool) ([]models.Symptom, error) {
+	return s.repo.FindAll(!includeInactive)
+}
+
+func (s *symptomService) Create(input dto.CreateSymptomDTO) (models.Symptom, error) {
+	if _, err := s.repo.FindByCode(input.Code); err == nil {
+		return models.Symptom{}, fmt.Errorf("kode gejala %s sudah digunakan", input.Code)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Symptom{}, fmt.Errorf("gagal memeriksa kode gejala: %w", err)
+	}
+
+	symptom := models.Symptom{Code: input.Code, IsActive: true}
+	applySymptomInput(&symptom, input.SymptomDTO)
+	created, err := s.repo.Create(symptom)
+	if err != nil {
+		return models.Symptom{}, fmt.Errorf("gagal membuat gejala: %w", err)
+	}
+	return created, nil
+}
+
+func (s *symptomService) Update(id uint, input dto.SymptomDTO) (models.Symptom, error) {
+	symptom, err := s.findByID(id)
+	if err != nil {
+		return models.Symptom{}, err
+	}
+	applySymptomInput(&symptom, input)
+	updated, err := s.repo.Update(symptom)
+	if err != nil {
+		return models.Symptom{}, fmt.Errorf("gagal memperbarui gejala: %w", err)
+	}
+	return updated, nil
+}
+
+// Delete hanya menonaktifkan gejala agar riwayat keluhan yang memakai kodenya tetap terbaca.
+func (s *symptomService) Delete(id uint) error {
+	symptom, err := s.findByID(id)
+	if err != nil {
+		return err
+	}
+	symptom.IsActive = false
+	if _, err := s.repo.Update(symptom); err != nil {
+		return fmt.Errorf("gagal menonaktifkan gejala: %w", err)
+	}
+	return nil
+}
+
+func (s *symptomService) findByID(id uint) (models.Symptom, error) {
+	symptom, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Symptom{}, ErrSymptomNotFound
+		}
+		return models.Symptom{}, fmt.Errorf("gagal mencari gejala: %w", err)
+	}
+	return symptom, nil
+}
+
+func applySymptomInput(symptom *models.Symptom, input dto.SymptomDTO) {
+	symptom.Name = input.Name
+	symptom.Aliases = strings.Join(normalizeSymptomAliases(input.Aliases), ",")
+	symptom.Severity = input.Severity
+	symptom.IsRedFlag = input.IsRedFlag
+	symptom.SortOrder = input.SortOrder
+	if input.IsActive != nil {
+		symptom.IsActive = *input.IsActive
+	}
+}
+
+// normalizeSymptomAliases mengubah alias ke huruf kecil, membuang yang kosong dan duplikat.
+func normalizeSymptomAliases(raw []string) []string {
+	seen := make(map[string]bool)
+	aliases := []string{}
+	for _, alias := range raw {
+		alias = strings.Join(strings.Fields(strings.ToLower(alias)), " ")
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"gorm.io/gorm"
+)
+
+// Pesan saran yang dipakai aturan bawaan dan fallback.
+const (
+	triageMessageEmergency   = "Segera ke IGD terdekat atau hubungi 119. Keluhan bapak/ibu termasuk tanda bahaya yang perlu penanganan segera."
+	triageMessageUrgent      = "Segera konsultasikan keluhan bapak/ibu ke poliklinik atau faskes terdekat"
+	triageMessageRoutine     = "Konsultasikan keluhan bapak/ibu kepada dokter/perawat yang bertugas atau hubungi petugas pada link TANYA PETUGAS"
+	triageMessageNeedsReview = "Keluhan bapak/ibu akan ditinjau oleh perawat/dokter yang bertugas. Bila keluhan memberat, hubungi petugas pada link TANYA PETUGAS atau segera ke faskes terdekat."
+	triageMessageSelfCare    = "Keluhan bapak/ibu tergolong ringan. Catat keluhan dan sampaikan ke perawat saat jadwal HD berikutnya, atau hubungi petugas pada link TANYA PETUGAS bila memberat."
+)
+
+// Aturan triase bawaan, dibuat jika tabel aturan masih kosong.
+var defaultTriageRules = []models.TriageRule{
+	{Name: "Gejala tanda bahaya", Priority: 10, TriageLevel: models.TriageLevelEmergency, Message: triageMessageEmergency, RequireRedFlag: true, IsActive: true},
+	{Name: "Muntah disertai pusing berat", Priority: 20, TriageLevel: models.TriageLevelUrgent, Message: triageMessageUrgent, SymptomCodes: "vomiting,dizziness", IsActive: true},
+	{Name: "Keluhan sedang disertai keluhan lain", Priority: 30, TriageLevel: models.TriageLevelUrgent, Message: triageMessageUrgent, MinSeverity: models.SymptomSeverityModerate, MinCount: 2, IsActive: true},
+	{Name: "Tiga keluhan atau lebih", Priority: 40, TriageLevel: models.TriageLevelUrgent, Message: triageMessageUrgent, MinCount: 3, IsActive: true},
+	{Name: "Keluhan sedang", Priority: 50, TriageLevel: models.TriageLevelRoutine, Message: triageMessageRoutine, MinSeverity: models.SymptomSeverityModerate, IsActive: true},
+	{Name: "Keluhan ringan", Priority: 90, TriageLevel: models.TriageLevelSelfCare, Message: triageMessageSelfCare, MinCount: 1, IsActive: true},
+}
+
+var ErrTriageRuleNotFound = errors.New("aturan triase tidak ditemukan")
+
+// TriageOutcome adalah hasil triase atas daftar keluhan.
+type TriageOutcome struct {
+	Level        string
+	Message      string
+	Rule         *models.TriageRule // Nil jika tidak ada aturan yang cocok (fallback)
+	Symptoms     []models.Symptom
+	Unrecognized []string // Teks bebas yang tidak ada di katalog
+}
+
+type TriageService interface {
+	EnsureDefaults() error
+	FindAllRules(includeInactive bool) ([]models.TriageRule, error)
+	CreateRule(input dto.TriageRuleDTO) (models.TriageRule, error)
+	UpdateRule(id uint, input dto.TriageRuleDTO) (models.TriageRule, error)
+	DeleteRule(id uint) error
+	Triage(complaints []string) (TriageOutcome, error)
+}
+
+type triageService struct {
+	ruleRepo    repositories.TriageRuleRepository
+	symptomRepo repositories.SymptomRepository
+}
+
+func NewTriageService(ruleRepo repositories.TriageRuleRepository, symptomRepo repositories.SymptomRepository) TriageService {
+	return &triageService{ruleRepo: ruleRepo, symptomRepo: symptomRepo}
+}
+
+// EnsureDefaults mengisi aturan triase bawaan saat tabel aturan masih kosong.
+func (s *triageService) EnsureDefaults() error {
+	count, err := s.ruleRepo.Count()
+	if err != nil {
+		return fmt.Errorf("gagal menghitung aturan triase: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	for _, rule := range defaultTriageRules {
+		if _, err := s.ruleRepo.Create(rule); err != nil {
+			return fmt.Errorf("gagal membuat aturan triase %s: %w", rule.Name, err)
+		}
+	}
+	log.Printf("Seeded %d default triage rules.", len(defaultTriageRules))
+	return nil
+}
+
+func (s *triageService) FindAllRules(includeInactive bool) ([]models.TriageRule, error) {
+	return s.ruleRepo.FindAll(!includeInactive)
+}
+
+func (s *triageService) CreateRule(input dto.TriageRuleDTO) (models.TriageRule, error) {
+	rule := models.TriageRule{IsActive: true}
+	if err := s.applyRuleInput(&rule, input); err != nil {
+		return models.TriageRule{}, err
+	}
+	created, err := s.ruleRepo.Create(rule)
+	if err != nil {
+		return models.TriageRule{}, fmt.Errorf("gagal membuat aturan triase: %w", err)
+	}
+	return created, nil
+}
+
+func (s *triageService) UpdateRule(id uint, input dto.TriageRuleDTO) (models.TriageRule, error) {
+	rule, err := s.findRule(id)
+	if err != nil {
+		return models.TriageRule{}, err
+	}
+	if err := s.applyRuleInput(&rule, input); err != nil {
+		return models.TriageRule{}, err
+	}
+	updated, err := s.ruleRepo.Update(rule)
+	if err != nil {
+		return models.TriageRule{}, fmt.Errorf("gagal memperbarui aturan triase: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteRule menghapus aturan. Keluhan lama tetap menyimpan nama aturan yang cocok saat itu.
+func (s *triageService) DeleteRule(id uint) error {
+	if _, err := s.findRule(id); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Delete(id); err != nil {
+		return fmt.Errorf("gagal menghapus aturan triase: %w", err)
+	}
+	return nil
+}
+
+// Triage mencocokkan keluhan dengan katalog gejala, lalu mengevaluasi aturan aktif sesuai
+// prioritas. Keluhan di luar katalog ikut dihitung sebagai jumlah keluhan dan membuat hasil
+// minimal "needs_review" agar dibaca petugas, bukan dianggap keluhan rutin.
+func (s *triageService) Triage(complaints []string) (TriageOutcome, error) {
+	catalog, err := s.symptomRepo.FindAll(true)
+	if err != nil {
+		return TriageOutcome{}, fmt.Errorf("gagal mengambil katalog gejala: %w", err)
+	}
+
+	outcome := TriageOutcome{}
+	outcome.Symptoms, outcome.Unrecognized = matchSymptoms(catalog, complaints)
+
+	rules, err := s.ruleRepo.FindAll(true)
+	if err != nil {
+		return TriageOutcome{}, fmt.Errorf("gagal mengambil aturan triase: %w", err)
+	}
+	total := len(outcome.Symptoms) + len(outcome.Unrecognized)
+	for i := range rules {
+		if matchesTriageRule(rules[i], outcome.Symptoms, total) {
+			outcome.Level = rules[i].TriageLevel
+			outcome.Message = rules[i].Message
+			outcome.Rule = &rules[i]
+			break
+		}
+	}
+
+	if len(outcome.Unrecognized) > 0 && triageLevelRank(outcome.Level) < triageLevelRank(models.TriageLevelNeedsReview) {
+		outcome.Level = models.TriageLevelNeedsReview
+		outcome.Message = triageMessageNeedsReview
+		outcome.Rule = nil
+	} else if outcome.Rule == nil {
+		outcome.Level = models.TriageLevelRoutine
+		outcome.Message = triageMessageRoutine
+	}
+	return outcome, nil
+}
+
+// matchSymptoms mencocokkan setiap keluhan (tanpa membedakan huruf besar/kecil) dengan kode,
+// nama, atau alias gejala. Keluhan yang persis sama dengan kata kunci langsung dipakai; teks
+// bebas dicari kata kuncinya sebagai kata utuh sehingga "dada saya nyeri dan sesak" cocok
+// dengan dua gejala. Keluhan tanpa kecocokan dikembalikan sebagai unrecognized.
+func matchSymptoms(catalog []models.Symptom, complaints []string) ([]models.Symptom, []string) {
+	type keyword struct {
+		phrase  string
+		symptom models.Symptom
+	}
+	byKey := make(map[string]models.Symptom, len(catalog)*3)
+	keywords := []keyword{}
+	for _, symptom := range catalog {
+		byKey[strings.ToLower(symptom.Code)] = symptom
+		for _, phrase := range append([]string{symptom.Name}, SplitTriageCodes(symptom.Aliases)...) {
+			if phrase = normalizeComplaintText(phrase); phrase != "" {
+				byKey[phrase] = symptom
+				keywords = append(keywords, keyword{phrase: phrase, symptom: symptom})
+			}
+		}
+	}
+
+	symptoms := []models.Symptom{}
+	unrecognized := []string{}
+	seen := make(map[string]bool)
+	add := func(symptom models.Symptom) {
+		if !seen[symptom.Code] {
+			seen[symptom.Code] = true
+			symptoms = append(symptoms, symptom)
+		}
+	}
+	seenText := make(map[string]bool)
+	for _, complaint := range complaints {
+		complaint = strings.TrimSpace(complaint)
+		text := normalizeComplaintText(complaint)
+		if text == "" {
+			continue
+		}
+		if symptom, ok := byKey[strings.ToLower(complaint)]; ok {
+			add(symptom)
+			continue
+		}
+		if symptom, ok := byKey[text]; ok {
+			add(symptom)
+			continue
+		}
+		matched := false
+		padded := " " + text + " "
+		for _, kw := range keywords {
+			if strings.Contains(padded, " "+kw.phrase+" ") {
+				matched = true
+				add(kw.symptom)
+			}
+		}
+		if !matched && !seenText[text] {
+			seenText[text] = true
+			unrecognized = append(unrecognized, complaint)
+		}
+	}
+	return symptoms, unrecognized
+}
+
+// normalizeComplaintText mengubah teks ke huruf kecil dan menyisakan kata (huruf/angka)
+// yang dipisah satu spasi.
+func normalizeComplaintText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+func (s *triageService) findRule(id uint) (models.TriageRule, error) {
+	rule, err := s.ruleRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.TriageRule{}, ErrTriageRuleNotFound
+		}
+		return models.TriageRule{}, fmt.Errorf("gagal mencari aturan triase: %w", err)
+	}
+	return rule, nil
+}
+
+// applyRuleInput memvalidasi aturan: minimal satu kondisi, dan kode gejala harus ada di katalog.
+func (s *triageService) applyRuleInput(rule *models.TriageRule, input dto.TriageRuleDTO) error {
+	codes := []string{}
+	for _, code := range input.SymptomCodes {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		if _, err := s.symptomRepo.FindByCode(code); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("kode gejala tidak dikenal: %s", code)
+			}
+			return fmt.Errorf("gagal memeriksa kode gejala: %w", err)
+		}
+		codes = append(codes, code)
+	}
+	if !input.RequireRedFlag && input.MinSeverity == "" && len(codes) == 0 && input.MinCount == 0 {
+		return errors.New("aturan triase harus memiliki minimal satu kondisi")
+	}
+	if input.MinCodeMatches > len(codes) {
+		return errors.New("min_code_matches tidak boleh melebihi jumlah symptom_codes")
+	}
+
+	rule.Name = input.Name
+	rule.Priority = input.Priority
+	rule.TriageLevel = input.TriageLevel
+	rule.Message = input.Message
+	rule.RequireRedFlag = input.RequireRedFlag
+	rule.MinSeverity = input.MinSeverity
+	rule.SymptomCodes = strings.Join(codes, ",")
+	rule.MinCodeMatches = input.MinCodeMatches
+	rule.MinCount = input.MinCount
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+	return nil
+}
+
+// matchesTriageRule bernilai true jika semua kondisi aturan yang diisi terpenuhi.
+func matchesTriageRule(rule models.TriageRule, symptoms []models.Symptom, total int) bool {
+	conditions := 0
+	if rule.RequireRedFlag {
+		conditions++
+		found := false
+		for _, symptom := range symptoms {
+			if symptom.IsRedFlag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if rule.MinSeverity != "" {
+		conditions++
+		highest := 0
+		for _, symptom := range symptoms {
+			if rank := symptomSeverityRank(symptom.Severity); rank > highest {
+				highest = rank
+			}
+		}
+		if highest < symptomSeverityRank(rule.MinSeverity) {
+			return false
+		}
+	}
+	if codes := SplitTriageCodes(rule.SymptomCodes); len(codes) > 0 {
+		conditions++
+		present := make(map[string]bool, len(symptoms))
+		for _, symptom := range symptoms {
+			present[symptom.Code] = true
+		}
+		matches := 0
+		for _, code := range codes {
+			if present[code] {
+				matches++
+			}
+		}
+		required := rule.MinCodeMatches
+		if required <= 0 || required > len(codes) {
+			required = len(codes)
+		}
+		if matches < required {
+			return false
+		}
+	}
+	if rule.MinCount > 0 {
+		conditions++
+		if total < rule.MinCount {
+			return false
+		}
+	}
+	return conditions > 0
+}
+
+// SplitTriageCodes memecah kolom SymptomCodes (dipisah koma).
+func SplitTriageCodes(codes string) []string {
+	result := []string{}
+	for _, code := range strings.Split(codes, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			result = append(result, code)
+		}
+	}
+	return result
+}
+
+func symptomSeverityRank(severity string) int {
+	switch severity {
+	case models.SymptomSeverityMild:
+		return 1
+	case models.SymptomSeverityModerate:
+		return 2
+	case models.SymptomSeveritySevere:
+		return 3
+	}
+	return 0
+}
+
+func triageLevelRank(level string) int {
+	switch level {
+	case models.TriageLevelSelfCare:
+		return 1
+	case models.TriageLevelRoutine:
+		return 2
+	case models.TriageLevelNeedsReview:
+		return 3
+	case models.TriageLevelUrgent:
+		return 4
+	case models.TriageLevelEmergency:
+		return 5
+	}
+	return 0
+}
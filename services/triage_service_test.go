@@ -0,0 +1,108 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+)
+
+type stubSymptomRepo struct {
+	repositories.SymptomRepository
+	symptoms []models.Symptom
+}
+
+func (r stubSymptomRepo) FindAll(activeOnly bool) ([]models.Symptom, error) {
+	return r.symptoms, nil
+}
+
+type stubTriageRuleRepo struct {
+	repositories.TriageRuleRepository
+	rules []models.TriageRule
+}
+
+func (r stubTriageRuleRepo) FindAll(activeOnly bool) ([]models.TriageRule, error) {
+	return append([]models.TriageRule(nil), r.rules...), nil
+}
+
+func TestMatchSymptoms(t *testing.T) {
+	catalog := []models.Symptom{
+		{Code: "chest_pain", Name: "Nyeri dada", Aliases: "sakit dada,dada sakit"},
+		{Code: "shortness_of_breath", Name: "Sesak napas", Aliases: "sesak"},
+		{Code: "dizziness", Name: "Pusing berat", Aliases: "pusing"},
+		{Code: "itching", Name: "Kulit gatal", Aliases: "gatal"},
+	}
+
+	tests := []struct {
+		name             string
+		complaints       []string
+		wantCodes        []string
+		wantUnrecognized []string
+	}{
+		{"code", []string{"chest_pain"}, []string{"chest_pain"}, []string{}},
+		{"name in other case", []string{"NYERI DADA"}, []string{"chest_pain"}, []string{}},
+		{"alias", []string{"Sesak"}, []string{"shortness_of_breath"}, []string{}},
+		{"keywords in free text", []string{"Dada saya sakit sekali, nyeri dada dan sesak sejak pagi"}, []string{"chest_pain", "shortness_of_breath"}, []string{}},
+		{"punctuation around keyword", []string{"pusing!!"}, []string{"dizziness"}, []string{}},
+		{"keyword must be a whole word", []string{"kepusingan"}, []string{}, []string{"kepusingan"}},
+		{"duplicates collapse", []string{"gatal", "kulit gatal", "Gatal-gatal"}, []string{"itching"}, []string{}},
+		{"unmatched free text", []string{"perut kembung", "  ", "Perut kembung"}, []string{}, []string{"perut kembung"}},
+		{"mixed", []string{"sesak", "telinga berdenging"}, []string{"shortness_of_breath"}, []string{"telinga berdenging"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symptoms, unrecognized := matchSymptoms(catalog, tt.complaints)
+			codes := []string{}
+			for _, symptom := range symptoms {
+				codes = append(codes, symptom.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("matched codes = %q, want %q", codes, tt.wantCodes)
+			}
+			if !reflect.DeepEqual(unrecognized, tt.wantUnrecognized) {
+				t.Errorf("unrecognized = %q, want %q", unrecognized, tt.wantUnrecognized)
+			}
+		})
+	}
+}
+
+func TestTriageLevels(t *testing.T) {
+	service := NewTriageService(stubTriageRuleRepo{rules: defaultTriageRules}, stubSymptomRepo{symptoms: defaultSymptoms})
+
+	tests := []struct {
+		name       string
+		complaints []string
+		wantLevel  string
+		wantRule   bool
+	}{
+		{"red flag", []string{"nyeri dada"}, models.TriageLevelEmergency, true},
+		{"red flag in free text", []string{"Tiba-tiba sesak napas waktu tidur"}, models.TriageLevelEmergency, true},
+		{"single mild", []string{"Kulit gatal"}, models.TriageLevelSelfCare, true},
+		{"single moderate", []string{"demam"}, models.TriageLevelRoutine, true},
+		{"unmatched free text only", []string{"perut kembung"}, models.TriageLevelNeedsReview, false},
+		{"mild plus unmatched text", []string{"gatal", "perut kembung"}, models.TriageLevelNeedsReview, false},
+		{"moderate plus unmatched text escalates by count", []string{"demam", "perut kembung"}, models.TriageLevelUrgent, true},
+		{"red flag plus unmatched text", []string{"kejang", "perut kembung"}, models.TriageLevelEmergency, true},
+		{"nothing", []string{" "}, models.TriageLevelRoutine, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, err := service.Triage(tt.complaints)
+			if err != nil {
+				t.Fatalf("Triage() error = %v", err)
+			}
+			if outcome.Level != tt.wantLevel {
+				t.Errorf("Triage() level = %q, want %q", outcome.Level, tt.wantLevel)
+			}
+			if (outcome.Rule != nil) != tt.wantRule {
+				t.Errorf("Triage() rule = %v, want rule matched = %v", outcome.Rule, tt.wantRule)
+			}
+			if outcome.Message == "" {
+				t.Error("Triage() message is empty")
+			}
+		})
+	}
+}
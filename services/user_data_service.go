@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user tidak ditemukan")
+	ErrUserEmailExists = errors.New("email sudah dipakai user lain")
+	// ErrReferencedUsersMissing: peresep/pencatat di file tidak ada di database tujuan.
+	ErrReferencedUsersMissing = errors.New("user yang direferensikan tidak ada di database tujuan")
+)
+
+// UserDataService mengekspor seluruh data satu user ke bundle dan mengimpornya sebagai user baru,
+// misalnya untuk memindahkan pasien antar instalasi atau menyalin kasus ke lingkungan staging.
+type UserDataService interface {
+	Export(email string) (repositories.UserDataBundle, error)
+	// Import membuat user dari bundle. Jika email tidak kosong, dipakai menggantikan email di bundle.
+	// User lain yang direferensikan (peresep, pencatat) dicocokkan lewat email; jika ada yang tidak
+	// ditemukan impor gagal, kecuali attributeMissingTo diisi email user yang menggantikannya.
+	Import(bundle repositories.UserDataBundle, email string, attributeMissingTo string) (models.User, error)
+}
+
+type userDataService struct {
+	repo     repositories.UserDataRepository
+	userRepo repositories.UserRepository
+}
+
+func NewUserDataService(repo repositories.UserDataRepository, userRepo repositories.UserRepository) UserDataService {
+	return &userDataService{repo: repo, userRepo: userRepo}
+}
+
+func (s *userDataService) Export(email string) (repositories.UserDataBundle, error) {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repositories.UserDataBundle{}, ErrUserNotFound
+		}
+		return repositories.UserDataBundle{}, fmt.Errorf("gagal mencari user: %w", err)
+	}
+	bundle, err := s.repo.Export(user.ID)
+	if err != nil {
+		return repositories.UserDataBundle{}, fmt.Errorf("gagal mengekspor data user: %w", err)
+	}
+	return bundle, nil
+}
+
+func (s *userDataService) Import(bundle repositories.UserDataBundle, email string, attributeMissingTo string) (models.User, error) {
+	if email != "" {
+		bundle.User.Email = email
+	}
+	if bundle.User.Email == "" {
+		return models.User{}, errors.New("email user di file ekspor kosong")
+	}
+	if _, err := s.userRepo.FindByEmail(bundle.User.Email); err == nil {
+		return models.User{}, ErrUserEmailExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.User{}, fmt.Errorf("gagal mencari user: %w", err)
+	}
+
+	userIDs, err := s.resolveReferencedUsers(bundle, attributeMissingTo)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.repo.Import(bundle, userIDs)
+	if err != nil {
+		return models.User{}, fmt.Errorf("gagal mengimpor data user: %w", err)
+	}
+	return user, nil
+}
+
+// resolveReferencedUsers memetakan ID user lain di bundle ke ID di database ini lewat email.
+// User yang tidak ditemukan dipetakan ke attributeMissingTo bila diisi; jika tidak, semua email
+// yang hilang dilaporkan sekaligus agar bisa dibuat dulu di database tujuan.
+func (s *userDataService) resolveReferencedUsers(bundle repositories.UserDataBundle, attributeMissingTo string) (map[uint]uint, error) {
+	var fallbackID uint
+	if attributeMissingTo != "" {
+		fallback, err := s.userRepo.FindByEmail(attributeMissingTo)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("user pengganti %s tidak ditemukan", attributeMissingTo)
+			}
+			return nil, fmt.Errorf("gagal mencari user: %w", err)
+		}
+		fallbackID = fallback.ID
+	}
+
+	userIDs := map[uint]uint{}
+	seen := map[uint]bool{bundle.User.ID: true} // User yang diimpor dipetakan oleh repository
+	var missing []string
+	for _, oldID := range bundle.ReferencedUserIDs() {
+		if seen[oldID] {
+			continue
+		}
+		seen[oldID] = true
+
+		email, ok := bundle.ReferencedUsers[oldID]
+		if ok {
+			user, err := s.userRepo.FindByEmail(email)
+			if err == nil {
+				userIDs[oldID] = user.ID
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("gagal mencari user: %w", err)
+			}
+		} else {
+			email = fmt.Sprintf("user ID %d (email tidak ada di file)", oldID)
+		}
+		if fallbackID != 0 {
+			userIDs[oldID] = fallbackID
+			continue
+		}
+		missing = append(missing, email)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w: %s", ErrReferencedUsersMissing, strings.Join(missing, ", "))
+	}
+	return userIDs, nil
+}
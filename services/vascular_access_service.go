@@ -0,0 +1,289 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/darmawguna/tirtaapp.git/dto"
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/repositories"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ScheduleType khusus untuk eskalasi hasil pemeriksaan akses vaskular ke klinisi.
+const ScheduleTypeAccessAlert = "ACCESS_ALERT"
+
+const (
+	defaultAccessCheckRangeDays = 30
+	maxAccessCheckRangeDays     = 366
+)
+
+var ErrVascularAccessNotFound = errors.New("akses vaskular tidak ditemukan")
+
+// Masalah yang bisa dilaporkan dari pemeriksaan mandiri.
+const (
+	accessProblemNoThrill = "Thrill akses tidak teraba"
+	accessProblemRedness  = "Kemerahan di area akses"
+	accessProblemSwelling = "Bengkak di area akses"
+	accessProblemPain     = "Nyeri di area akses"
+)
+
+// AccessCheckResult adalah pemeriksaan mandiri beserta hasil evaluasinya.
+type AccessCheckResult struct {
+	Check    models.AccessSelfCheck
+	Problems []string
+	Advice   string
+}
+
+type VascularAccessService interface {
+	GetAccesses(requesterID uint, requesterRole string, patientID uint) ([]models.VascularAccess, error)
+	CreateAccess(requesterID uint, requesterRole string, patientID uint, input dto.VascularAccessDTO) (models.VascularAccess, error)
+	UpdateAccess(requesterID uint, requesterRole string, accessID uint, input dto.VascularAccessDTO) (models.VascularAccess, error)
+	SubmitSelfCheck(userID, accessID uint, input dto.AccessSelfCheckDTO) (AccessCheckResult, error)
+	GetSelfChecks(requesterID uint, requesterRole string, accessID uint, from, to string) ([]AccessCheckResult, error)
+}
+
+type vascularAccessService struct {
+	repo         repositories.VascularAccessRepository
+	userRepo     repositories.UserRepository
+	careTeamRepo repositories.CareTeamRepository
+	queueService QueueService
+}
+
+func NewVascularAccessService(repo repositories.VascularAccessRepository, userRepo repositories.UserRepository, careTeamRepo repositories.CareTeamRepository, queueService QueueService) VascularAccessService {
+	return &vascularAccessService{repo: repo, userRepo: userRepo, careTeamRepo: careTeamRepo, queueService: queueService}
+}
+
+func (s *vascularAccessService) GetAccesses(requesterID uint, requesterRole string, patientID uint) ([]models.VascularAccess, error) {
+	patient, err := findAccessiblePatient(s.careTeamRepo, s.userRepo, requesterID, requesterRole, patientID)
+	if err != nil {
+		return nil, err
+	}
+	accesses, err := s.repo.FindByUserID(patient.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil akses vaskular: %w", err)
+	}
+	return accesses, nil
+}
+
+func (s *vascularAccessService) CreateAccess(requesterID uint, requesterRole string, patientID uint, input dto.VascularAccessDTO) (models.VascularAccess, error) {
+	patient, err := findAccessiblePatient(s.careTeamRepo, s.userRepo, requesterID, requesterRole, patientID)
+	if err != nil {
+		return models.VascularAccess{}, err
+	}
+	access := models.VascularAccess{UserID: patient.ID, IsActive: true}
+	if err := applyVascularAccessInput(&access, input, patient); err != nil {
+		return models.VascularAccess{}, err
+	}
+	created, err := s.repo.Create(access)
+	if err != nil {
+		return models.VascularAccess{}, fmt.Errorf("gagal menyimpan akses vaskular: %w", err)
+	}
+	return created, nil
+}
+
+func (s *vascularAccessService) UpdateAccess(requesterID uint, requesterRole string, accessID uint, input dto.VascularAccessDTO) (models.VascularAccess, error) {
+	access, patient, err := s.findAccessibleAccess(requesterID, requesterRole, accessID)
+	if err != nil {
+		return models.VascularAccess{}, err
+	}
+	if err := applyVascularAccessInput(&access, input, patient); err != nil {
+		return models.VascularAccess{}, err
+	}
+	updated, err := s.repo.Update(access)
+	if err != nil {
+		return models.VascularAccess{}, fmt.Errorf("gagal memperbarui akses vaskular: %w", err)
+	}
+	return updated, nil
+}
+
+// SubmitSelfCheck menyimpan pemeriksaan mandiri (satu per akses per hari; kirim ulang menimpa).
+// Thrill tidak teraba atau tanda infeksi dicatat sebagai keluhan dan dieskalasi ke klinisi sekali per pemeriksaan.
+func (s *vascularAccessService) SubmitSelfCheck(userID, accessID uint, input dto.AccessSelfCheckDTO) (AccessCheckResult, error) {
+	access, err := s.repo.FindByID(accessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return AccessCheckResult{}, ErrVascularAccessNotFound
+		}
+		return AccessCheckResult{}, fmt.Errorf("gagal mencari akses vaskular: %w", err)
+	}
+	if access.UserID != userID {
+		return AccessCheckResult{}, ErrVascularAccessNotFound
+	}
+	if !access.IsActive {
+		return AccessCheckResult{}, errors.New("akses vaskular sudah tidak aktif")
+	}
+	if accessHasThrill(access) && input.ThrillPresent == nil {
+		return AccessCheckResult{}, errors.New("thrill_present wajib diisi untuk fistula/graft")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return AccessCheckResult{}, fmt.Errorf("gagal mengambil data user: %w", err)
+	}
+	today := utils.TodayIn(utils.LoadUserLocation(user.Timezone))
+	checkDate := today
+	if input.CheckDate != "" {
+		if checkDate, err = time.Parse("2006-01-02", input.CheckDate); err != nil {
+			return AccessCheckResult{}, fmt.Errorf("format check_date tidak valid: %w", err)
+		}
+	}
+	if !utils.IsWithinBackdateWindow(checkDate, today) {
+		return AccessCheckResult{}, ErrOutsideBackdateWindow
+	}
+
+	check, err := s.repo.FindCheckByAccessAndDate(access.ID, checkDate)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return AccessCheckResult{}, fmt.Errorf("gagal mencari pemeriksaan akses: %w", err)
+	}
+	check.VascularAccessID = access.ID
+	check.UserID = userID
+	check.CheckDate = checkDate
+	check.ThrillPresent = nil
+	if accessHasThrill(access) {
+		check.ThrillPresent = input.ThrillPresent
+	}
+	check.Swelling = input.Swelling
+	check.Redness = input.Redness
+	check.Pain = input.Pain
+	check.Notes = input.Notes
+
+	result := evaluateAccessCheck(access, check)
+	var complaint *models.ComplaintLog
+	if accessNeedsEscalation(access, check) && check.ComplaintLogID == nil {
+		complaintsJSON, err := json.Marshal(result.Problems)
+		if err != nil {
+			return AccessCheckResult{}, err
+		}
+		complaint = &models.ComplaintLog{
+			UserID:         userID,
+			Complaints:     datatypes.JSON(complaintsJSON),
+			Message:        result.Advice,
+			TriageLevel:    models.TriageLevelUrgent,
+			TriageRuleName: "Pemeriksaan akses vaskular",
+		}
+	}
+
+	// Keluhan eskalasi dan pemeriksaan disimpan dalam satu transaksi
+	saved, err := s.repo.SaveCheck(check, complaint)
+	if err != nil {
+		return AccessCheckResult{}, fmt.Errorf("gagal menyimpan pemeriksaan akses: %w", err)
+	}
+	result.Check = saved
+	if saved.ComplaintLogID != nil && saved.EscalatedAt == nil {
+		s.publishAccessAlert(saved)
+	}
+	return result, nil
+}
+
+func (s *vascularAccessService) GetSelfChecks(requesterID uint, requesterRole string, accessID uint, from, to string) ([]AccessCheckResult, error) {
+	access, patient, err := s.findAccessibleAccess(requesterID, requesterRole, accessID)
+	if err != nil {
+		return nil, err
+	}
+	fromDate, toDate, err := resolvePatientDateRange(patient, from, to, defaultAccessCheckRangeDays, maxAccessCheckRangeDays)
+	if err != nil {
+		return nil, err
+	}
+	checks, err := s.repo.FindChecksByAccessAndDateRange(access.ID, fromDate, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil pemeriksaan akses: %w", err)
+	}
+
+	results := []AccessCheckResult{}
+	for _, check := range checks {
+		result := evaluateAccessCheck(access, check)
+		result.Check = check
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *vascularAccessService) findAccessibleAccess(requesterID uint, requesterRole string, accessID uint) (models.VascularAccess, models.User, error) {
+	access, err := s.repo.FindByID(accessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.VascularAccess{}, models.User{}, ErrVascularAccessNotFound
+		}
+		return models.VascularAccess{}, models.User{}, fmt.Errorf("gagal mencari akses vaskular: %w", err)
+	}
+	patient, err := findAccessiblePatient(s.careTeamRepo, s.userRepo, requesterID, requesterRole, access.UserID)
+	if err != nil {
+		return models.VascularAccess{}, models.User{}, err
+	}
+	return access, patient, nil
+}
+
+func (s *vascularAccessService) publishAccessAlert(check models.AccessSelfCheck) {
+	if s.queueService == nil {
+		return
+	}
+	payload := ReminderMessage{ScheduleType: ScheduleTypeAccessAlert, ScheduleID: check.ID}
+	if err := s.queueService.PublishMessage(payload); err != nil {
+		log.Printf("WARNING: Failed to publish access alert for self-check ID %d: %v", check.ID, err)
+	}
+}
+
+func applyVascularAccessInput(access *models.VascularAccess, input dto.VascularAccessDTO, patient models.User) error {
+	createdOn, err := time.Parse("2006-01-02", input.CreatedOn)
+	if err != nil {
+		return fmt.Errorf("format created_on tidak valid: %w", err)
+	}
+	if createdOn.After(utils.TodayIn(utils.LoadUserLocation(patient.Timezone))) {
+		return errors.New("tanggal pembuatan akses tidak boleh di masa depan")
+	}
+	access.AccessType = input.AccessType
+	access.Site = input.Site
+	access.CreatedOn = createdOn
+	access.Notes = input.Notes
+	if input.IsActive != nil {
+		access.IsActive = *input.IsActive
+	}
+	return nil
+}
+
+// accessHasThrill menandakan akses yang getarannya (thrill) perlu diperiksa setiap hari.
+func accessHasThrill(access models.VascularAccess) bool {
+	return access.AccessType == models.AccessTypeAVF || access.AccessType == models.AccessTypeAVG
+}
+
+// accessNeedsEscalation: thrill hilang (kemungkinan trombosis) atau kemerahan/bengkak (tanda infeksi).
+// Nyeri saja dicatat tetapi tidak dieskalasi.
+func accessNeedsEscalation(access models.VascularAccess, check models.AccessSelfCheck) bool {
+	thrillMissing := accessHasThrill(access) && check.ThrillPresent != nil && !*check.ThrillPresent
+	return thrillMissing || check.Redness || check.Swelling
+}
+
+func evaluateAccessCheck(access models.VascularAccess, check models.AccessSelfCheck) AccessCheckResult {
+	result := AccessCheckResult{Problems: []string{}}
+	thrillMissing := accessHasThrill(access) && check.ThrillPresent != nil && !*check.ThrillPresent
+	if thrillMissing {
+		result.Problems = append(result.Problems, accessProblemNoThrill)
+	}
+	if check.Redness {
+		result.Problems = append(result.Problems, accessProblemRedness)
+	}
+	if check.Swelling {
+		result.Problems = append(result.Problems, accessProblemSwelling)
+	}
+	if check.Pain {
+		result.Problems = append(result.Problems, accessProblemPain)
+	}
+
+	switch {
+	case thrillMissing:
+		result.Advice = "Thrill akses tidak teraba. Segera hubungi unit hemodialisa Anda hari ini karena akses mungkin tersumbat dan perlu ditangani secepatnya."
+	case check.Redness || check.Swelling:
+		result.Advice = "Ada tanda infeksi pada akses. Jaga area tetap bersih dan kering, jangan ditekan, dan segera hubungi unit hemodialisa Anda."
+	case check.Pain:
+		result.Advice = "Catat keluhan nyeri dan sampaikan ke perawat saat HD berikutnya. Hubungi unit hemodialisa bila nyeri bertambah."
+	default:
+		result.Advice = "Akses dalam kondisi baik. Terima kasih sudah memeriksa hari ini."
+	}
+	return result
+}
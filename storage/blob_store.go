@@ -0,0 +1,113 @@
+// Package storage menyediakan penyimpanan file upload (foto profil, thumbnail & media edukasi,
+// lampiran keluhan) di balik interface BlobStore, sehingga API tidak terikat pada satu volume disk.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Prefix key untuk setiap jenis upload. Key disimpan di DB, misal "profiles/1700000000_foto.jpg".
+const (
+	PrefixProfiles       = "profiles"
+	PrefixEducations     = "educations"      // Thumbnail edukasi (publik)
+	PrefixEducationMedia = "education_media" // PDF/video edukasi (hanya lewat endpoint media)
+	PrefixComplaints     = "complaints"      // Lampiran keluhan (privat)
+)
+
+// legacyUploadRoot adalah direktori yang dulu disimpan sebagai bagian path file di DB.
+const legacyUploadRoot = "uploads"
+
+var (
+	ErrObjectNotFound = errors.New("objek tidak ditemukan")
+	ErrInvalidKey     = errors.New("key objek tidak valid")
+)
+
+// ObjectInfo adalah metadata objek yang dibutuhkan untuk menyajikannya lewat HTTP.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// BlobStore menyimpan dan membaca objek berdasarkan key relatif (tanpa "./uploads/").
+// Objek yang dibuka mendukung Seek agar bisa dilayani dengan range request.
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// NewBlobStoreFromConfig membuat BlobStore sesuai STORAGE_DRIVER ("local" bawaan, atau "s3").
+func NewBlobStoreFromConfig() (BlobStore, error) {
+	switch driver := strings.ToLower(viper.GetString("STORAGE_DRIVER")); driver {
+	case "", "local":
+		root := viper.GetString("STORAGE_LOCAL_ROOT")
+		if root == "" {
+			root = "./" + legacyUploadRoot
+		}
+		return NewLocalStore(root)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  viper.GetString("S3_ENDPOINT"),
+			Region:    viper.GetString("S3_REGION"),
+			Bucket:    viper.GetString("S3_BUCKET"),
+			AccessKey: viper.GetString("S3_ACCESS_KEY"),
+			SecretKey: viper.GetString("S3_SECRET_KEY"),
+			UseSSL:    viper.GetBool("S3_USE_SSL"),
+		})
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER '%s' tidak dikenal (gunakan local atau s3)", driver)
+	}
+}
+
+// Key menggabungkan prefix dan nama file menjadi key objek.
+func Key(prefix string, filename string) string {
+	return path.Join(prefix, filename)
+}
+
+// NormalizeKey mengubah nilai lama berupa path file ("./uploads/profiles/x.jpg") menjadi key
+// ("profiles/x.jpg"). Key yang sudah benar dan nilai kosong dikembalikan apa adanya.
+func NormalizeKey(value string) string {
+	if value == "" {
+		return ""
+	}
+	key := strings.TrimPrefix(path.Clean(strings.ReplaceAll(value, "\\", "/")), "./")
+	return strings.TrimPrefix(key, legacyUploadRoot+"/")
+}
+
+// IsLegacyPath menandai nilai DB yang masih berupa path file sebelum migrasi ke key.
+func IsLegacyPath(value string) bool {
+	return value != "" && NormalizeKey(value) != value
+}
+
+// cleanKey memvalidasi key agar tidak keluar dari root penyimpanan.
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean(key)
+	if key == "" || cleaned == "." || strings.HasPrefix(cleaned, "/") || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// PublicURL membangun URL untuk objek publik (foto profil & thumbnail). Jika STORAGE_PUBLIC_URL
+// diisi (misal bucket/CDN publik), URL langsung ke sana; selain itu dilayani API di /static.
+func PublicURL(key string) string {
+	key = NormalizeKey(key)
+	if publicURL := strings.TrimRight(viper.GetString("STORAGE_PUBLIC_URL"), "/"); publicURL != "" {
+		return publicURL + "/" + key
+	}
+	baseUrl := viper.GetString("BASE_URL")
+	if baseUrl == "" {
+		baseUrl = "http://localhost:8080"
+	}
+	return fmt.Sprintf("%s/static/%s", baseUrl, key)
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		want       string
+		wantLegacy bool
+	}{
+		{"key", "profiles/1.jpg", "profiles/1.jpg", false},
+		{"legacy relative path", "./uploads/profiles/1.jpg", "profiles/1.jpg", true},
+		{"legacy path without dot", "uploads/educations/2.png", "educations/2.png", true},
+		{"legacy windows path", `uploads\complaints\3.m4a`, "complaints/3.m4a", true},
+		{"redundant separators", "profiles//1.jpg", "profiles/1.jpg", true},
+		{"prefix only matches whole directory", "uploads_old/1.jpg", "uploads_old/1.jpg", false},
+		{"absolute path kept", "/uploads/1.jpg", "/uploads/1.jpg", false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeKey(tt.value); got != tt.want {
+				t.Errorf("NormalizeKey(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+			if got := IsLegacyPath(tt.value); got != tt.wantLegacy {
+				t.Errorf("IsLegacyPath(%q) = %v, want %v", tt.value, got, tt.wantLegacy)
+			}
+		})
+	}
+}
+
+func TestCleanKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr error
+	}{
+		{"key", "profiles/1.jpg", "profiles/1.jpg", nil},
+		{"inner dot segments", "profiles/./a/../1.jpg", "profiles/1.jpg", nil},
+		{"dotdot inside name", "profiles/..1.jpg", "profiles/..1.jpg", nil},
+		{"empty", "", "", ErrInvalidKey},
+		{"dot", ".", "", ErrInvalidKey},
+		{"absolute", "/etc/passwd", "", ErrInvalidKey},
+		{"parent", "..", "", ErrInvalidKey},
+		{"escapes root", "../secret.txt", "", ErrInvalidKey},
+		{"escapes root after clean", "profiles/../../secret.txt", "", ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cleanKey(tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("cleanKey(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("cleanKey(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config berisi koneksi ke layanan S3-compatible (AWS S3, MinIO, dsb).
+type S3Config struct {
+	Endpoint  string // host[:port] tanpa skema, misal "s3.amazonaws.com" atau "minio:9000"
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+// S3Store menyimpan objek di bucket S3-compatible. Untuk pengembangan lokal bisa memakai MinIO.
+type S3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Store membuat client dan memastikan bucket tersedia (dibuat jika belum ada).
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("S3_ENDPOINT dan S3_BUCKET wajib diisi untuk STORAGE_DRIVER=s3")
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat client S3: %w", err)
+	}
+
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memeriksa bucket '%s': %w", cfg.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("gagal membuat bucket '%s': %w", cfg.Bucket, err)
+		}
+	}
+	return &S3Store{client: client, bucket: cfg.Bucket}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	if _, err := s.client.PutObject(ctx, s.bucket, cleaned, body, size, minio.PutObjectOptions{ContentType: contentType}); err != nil {
+		return fmt.Errorf("gagal mengunggah objek '%s': %w", key, err)
+	}
+	return nil
+}
+
+// Open mengembalikan minio.Object yang mendukung Seek; setiap Seek+Read menjadi GET dengan header Range.
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	object, err := s.client.GetObject(ctx, s.bucket, cleaned, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("gagal membuka objek '%s': %w", key, err)
+	}
+	stat, err := object.Stat()
+	if err != nil {
+		object.Close()
+		if isS3NotFound(err) {
+			return nil, ObjectInfo{}, ErrObjectNotFound
+		}
+		return nil, ObjectInfo{}, fmt.Errorf("gagal membaca objek '%s': %w", key, err)
+	}
+	return object, ObjectInfo{Size: stat.Size, ContentType: stat.ContentType, ModTime: stat.LastModified}, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	if err := s.client.RemoveObject(ctx, s.bucket, cleaned, minio.RemoveObjectOptions{}); err != nil && !isS3NotFound(err) {
+		return fmt.Errorf("gagal menghapus objek '%s': %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3Store) Exists(ctx context.Context, key string) (bool, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err := s.client.StatObject(ctx, s.bucket, cleaned, minio.StatObjectOptions{}); err != nil {
+		if isS3NotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("gagal memeriksa objek '%s': %w", key, err)
+	}
+	return true, nil
+}
+
+func isS3NotFound(err error) bool {
+	return minio.ToErrorResponse(err).Code == minio.NoSuchKey
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Default masa berlaku URL bertanda tangan (MEDIA_URL_TTL_MINUTES).
+const defaultSignedURLTTLMinutes = 60
+
+var (
+	ErrSignatureInvalid     = errors.New("tanda tangan URL tidak valid")
+	ErrSignatureExpired     = errors.New("URL sudah kedaluwarsa")
+	ErrSigningSecretMissing = errors.New("MEDIA_URL_SECRET atau JWT_SECRET_KEY wajib diisi untuk menandatangani URL media")
+)
+
+// SignedURLTTL mengembalikan masa berlaku URL media privat.
+func SignedURLTTL() time.Duration {
+	minutes := defaultSignedURLTTLMinutes
+	if viper.IsSet("MEDIA_URL_TTL_MINUTES") {
+		if value := viper.GetInt("MEDIA_URL_TTL_MINUTES"); value > 0 {
+			minutes = value
+		}
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// SignedURL membangun URL /media/<key>?expires=..&signature=.. untuk objek privat (misal foto profil).
+// Waktu kedaluwarsa dibulatkan ke jendela TTL sehingga URL yang sama dipakai ulang selama satu
+// jendela dan bisa di-cache klien; sisa masa berlaku selalu minimal satu TTL.
+func SignedURL(key string) string {
+	key = NormalizeKey(key)
+	ttl := SignedURLTTL()
+	expires := time.Now().Truncate(ttl).Add(2 * ttl).Unix()
+
+	baseUrl := viper.GetString("BASE_URL")
+	if baseUrl == "" {
+		baseUrl = "http://localhost:8080"
+	}
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", sign(key, expires))
+	return fmt.Sprintf("%s/media/%s?%s", baseUrl, key, query.Encode())
+}
+
+// VerifySignature memeriksa parameter expires & signature dari URL hasil SignedURL.
+// Mengembalikan sisa masa berlaku untuk header Cache-Control.
+func VerifySignature(key string, expiresParam string, signature string) (time.Duration, error) {
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil {
+		return 0, ErrSignatureInvalid
+	}
+	expected, err := hex.DecodeString(sign(key, expires))
+	if err != nil {
+		return 0, ErrSignatureInvalid
+	}
+	given, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, given) {
+		return 0, ErrSignatureInvalid
+	}
+	remaining := time.Until(time.Unix(expires, 0))
+	if remaining <= 0 {
+		return 0, ErrSignatureExpired
+	}
+	return remaining, nil
+}
+
+func sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, signingSecret())
+	fmt.Fprintf(mac, "%s\n%d", key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckSigningSecret dipanggil saat startup: tanpa MEDIA_URL_SECRET maupun JWT_SECRET_KEY,
+// URL media akan ditandatangani dengan kunci kosong yang bisa dipalsukan siapa saja.
+func CheckSigningSecret() error {
+	if viper.GetString("MEDIA_URL_SECRET") == "" && viper.GetString("JWT_SECRET_KEY") == "" {
+		return ErrSigningSecretMissing
+	}
+	return nil
+}
+
+// signingSecret memakai MEDIA_URL_SECRET; jika kosong diturunkan dari JWT_SECRET_KEY agar
+// deploy lama tetap jalan tanpa konfigurasi baru.
+func signingSecret() []byte {
+	if secret := viper.GetString("MEDIA_URL_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	mac := hmac.New(sha256.New, []byte(viper.GetString("JWT_SECRET_KEY")))
+	mac.Write([]byte("media-url"))
+	return mac.Sum(nil)
+}
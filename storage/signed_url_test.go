@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func setConfig(t *testing.T, values map[string]interface{}) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+}
+
+func TestSignedURL(t *testing.T) {
+	setConfig(t, map[string]interface{}{"MEDIA_URL_SECRET": "rahasia", "MEDIA_URL_TTL_MINUTES": 30, "BASE_URL": "https://api.example.com"})
+
+	signed, err := url.Parse(SignedURL("uploads/profiles/1.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.Path != "/media/profiles/1.jpg" {
+		t.Errorf("path = %q, want /media/profiles/1.jpg", signed.Path)
+	}
+
+	remaining, err := VerifySignature("profiles/1.jpg", signed.Query().Get("expires"), signed.Query().Get("signature"))
+	if err != nil {
+		t.Fatalf("VerifySignature() error = %v", err)
+	}
+	ttl := 30 * time.Minute
+	if remaining < ttl || remaining > 2*ttl {
+		t.Errorf("remaining = %v, want between %v and %v", remaining, ttl, 2*ttl)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	setConfig(t, map[string]interface{}{"MEDIA_URL_SECRET": "rahasia"})
+
+	const key = "profiles/1.jpg"
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Minute).Unix()
+	valid := sign(key, future)
+
+	tests := []struct {
+		name      string
+		key       string
+		expires   string
+		signature string
+		wantErr   error
+	}{
+		{"valid", key, strconv.FormatInt(future, 10), valid, nil},
+		{"expired", key, strconv.FormatInt(past, 10), sign(key, past), ErrSignatureExpired},
+		{"other key", "profiles/2.jpg", strconv.FormatInt(future, 10), valid, ErrSignatureInvalid},
+		{"extended expiry", key, strconv.FormatInt(future+3600, 10), valid, ErrSignatureInvalid},
+		{"tampered signature", key, strconv.FormatInt(future, 10), strings.Repeat("0", len(valid)), ErrSignatureInvalid},
+		{"non-hex signature", key, strconv.FormatInt(future, 10), "zz", ErrSignatureInvalid},
+		{"empty signature", key, strconv.FormatInt(future, 10), "", ErrSignatureInvalid},
+		{"invalid expires", key, "besok", valid, ErrSignatureInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining, err := VerifySignature(tt.key, tt.expires, tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifySignature() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && remaining <= 0 {
+				t.Errorf("remaining = %v, want > 0", remaining)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureSecretRotation(t *testing.T) {
+	setConfig(t, map[string]interface{}{"MEDIA_URL_SECRET": "lama"})
+	expires := time.Now().Add(time.Hour).Unix()
+	signature := sign("profiles/1.jpg", expires)
+
+	viper.Set("MEDIA_URL_SECRET", "baru")
+	if _, err := VerifySignature("profiles/1.jpg", strconv.FormatInt(expires, 10), signature); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("VerifySignature() error = %v, want %v", err, ErrSignatureInvalid)
+	}
+}
+
+func TestCheckSigningSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr error
+	}{
+		{"media secret", map[string]interface{}{"MEDIA_URL_SECRET": "rahasia"}, nil},
+		{"jwt fallback", map[string]interface{}{"JWT_SECRET_KEY": "jwt"}, nil},
+		{"both set", map[string]interface{}{"MEDIA_URL_SECRET": "rahasia", "JWT_SECRET_KEY": "jwt"}, nil},
+		{"both empty", map[string]interface{}{"MEDIA_URL_SECRET": "", "JWT_SECRET_KEY": ""}, ErrSigningSecretMissing},
+		{"unset", map[string]interface{}{}, ErrSigningSecretMissing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfig(t, tt.config)
+			if err := CheckSigningSecret(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckSigningSecret() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
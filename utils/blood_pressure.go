@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Rentang fisiologis yang diterima untuk tekanan darah & nadi.
+const (
+	MinSystolic  = 60
+	MaxSystolic  = 260
+	MinDiastolic = 30
+	MaxDiastolic = 160
+	MinPulse     = 30
+	MaxPulse     = 200
+)
+
+// bloodPressurePattern menerima seluruh teks (diapit ^...$) dalam format umum: "120/80", "120-80",
+// "120 / 80 mmHg", "TD 120/80 N 72", "120/80, nadi: 72x/menit" atau "120/80/72". Nadi hanya diambil
+// jika dipisah "/" langsung setelah diastolik atau diberi label (N, nadi, HR, P), sehingga teks
+// seperti "120/80 (jam 10)" atau "1200/80" ditolak, bukan diurai sebagian.
+var bloodPressurePattern = regexp.MustCompile(`(?i)^\s*(?:TD\s*:?\s*)?(\d{2,3})\s*[/\\\-]\s*(\d{2,3})(?:\s*/\s*(\d{2,3}))?(?:\s*mm\s*Hg)?(?:\s*[,;]?\s*(?:nadi|HR|N|P)\s*[:=]?\s*(\d{2,3})(?:\s*(?:x/menit|x/mnt|kali/menit|bpm|x))?)?\s*$`)
+
+// ErrInvalidBloodPressure cocok (errors.Is) dengan semua error format maupun rentang tekanan darah.
+var ErrInvalidBloodPressure = errors.New("tekanan darah tidak valid")
+
+var (
+	ErrBloodPressureFormat   = invalidBloodPressure("format tekanan darah tidak dikenali")
+	ErrBloodPressureRequired = invalidBloodPressure("wajib diisi")
+)
+
+// bloodPressureError mempertahankan pesan validasi apa adanya sambil tetap cocok dengan ErrInvalidBloodPressure.
+type bloodPressureError struct{ message string }
+
+func (e bloodPressureError) Error() string { return e.message }
+
+func (e bloodPressureError) Is(target error) bool { return target == ErrInvalidBloodPressure }
+
+func invalidBloodPressure(format string, args ...interface{}) error {
+	return bloodPressureError{message: fmt.Sprintf(format, args...)}
+}
+
+// ParseBloodPressure mengurai teks tekanan darah bebas menjadi sistolik, diastolik dan nadi (opsional).
+func ParseBloodPressure(text string) (systolic int, diastolic int, pulse *int, err error) {
+	match := bloodPressurePattern.FindStringSubmatch(text)
+	if match == nil || (match[3] != "" && match[4] != "") {
+		return 0, 0, nil, ErrBloodPressureFormat
+	}
+	systolic, _ = strconv.Atoi(match[1])
+	diastolic, _ = strconv.Atoi(match[2])
+	for _, value := range match[3:] {
+		if value != "" {
+			parsed, _ := strconv.Atoi(value)
+			pulse = &parsed
+		}
+	}
+	if err := ValidateBloodPressure(systolic, diastolic, pulse); err != nil {
+		return 0, 0, nil, err
+	}
+	return systolic, diastolic, pulse, nil
+}
+
+// ValidateBloodPressure memeriksa rentang fisiologis dan sistolik > diastolik.
+func ValidateBloodPressure(systolic, diastolic int, pulse *int) error {
+	if systolic < MinSystolic || systolic > MaxSystolic {
+		return invalidBloodPressure("sistolik harus antara %d dan %d mmHg", MinSystolic, MaxSystolic)
+	}
+	if diastolic < MinDiastolic || diastolic > MaxDiastolic {
+		return invalidBloodPressure("diastolik harus antara %d dan %d mmHg", MinDiastolic, MaxDiastolic)
+	}
+	if diastolic >= systolic {
+		return invalidBloodPressure("diastolik harus lebih kecil dari sistolik")
+	}
+	if pulse != nil && (*pulse < MinPulse || *pulse > MaxPulse) {
+		return invalidBloodPressure("nadi harus antara %d dan %d kali/menit", MinPulse, MaxPulse)
+	}
+	return nil
+}
+
+// FormatBloodPressure menghasilkan format tampilan "120/80".
+func FormatBloodPressure(systolic, diastolic int) string {
+	return fmt.Sprintf("%d/%d", systolic, diastolic)
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestParseBloodPressure(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		systolic  int
+		diastolic int
+		pulse     *int
+		wantErr   error
+	}{
+		{name: "slash", text: "120/80", systolic: 120, diastolic: 80},
+		{name: "dash", text: "120-80", systolic: 120, diastolic: 80},
+		{name: "backslash", text: `120\80`, systolic: 120, diastolic: 80},
+		{name: "spaces and unit", text: " 120 / 80 mmHg ", systolic: 120, diastolic: 80},
+		{name: "unit without space", text: "120/80mmhg", systolic: 120, diastolic: 80},
+		{name: "TD prefix", text: "TD: 130/85", systolic: 130, diastolic: 85},
+		{name: "pulse after slash", text: "120/80/72", systolic: 120, diastolic: 80, pulse: intPtr(72)},
+		{name: "pulse with N label", text: "TD 120/80 N 72", systolic: 120, diastolic: 80, pulse: intPtr(72)},
+		{name: "pulse with nadi label and unit", text: "120/80 mmHg, nadi: 88x/menit", systolic: 120, diastolic: 80, pulse: intPtr(88)},
+		{name: "pulse with HR label", text: "110/70 HR 64 bpm", systolic: 110, diastolic: 70, pulse: intPtr(64)},
+		{name: "two-digit systolic", text: "95/60", systolic: 95, diastolic: 60},
+
+		{name: "empty", text: "", wantErr: ErrBloodPressureFormat},
+		{name: "free text", text: "normal", wantErr: ErrBloodPressureFormat},
+		{name: "four-digit systolic is not truncated", text: "1200/80", wantErr: ErrBloodPressureFormat},
+		{name: "four-digit diastolic", text: "120/8000", wantErr: ErrBloodPressureFormat},
+		{name: "trailing note is not a pulse", text: "120/80 (jam 10)", wantErr: ErrBloodPressureFormat},
+		{name: "unlabelled number is not a pulse", text: "120/80 72", wantErr: ErrBloodPressureFormat},
+		{name: "two pulses", text: "120/80/72 N 70", wantErr: ErrBloodPressureFormat},
+		{name: "leading text", text: "kemarin 120/80", wantErr: ErrBloodPressureFormat},
+
+		{name: "systolic out of range", text: "300/80", wantErr: ErrInvalidBloodPressure},
+		{name: "diastolic out of range", text: "120/20", wantErr: ErrInvalidBloodPressure},
+		{name: "diastolic above systolic", text: "90/100", wantErr: ErrInvalidBloodPressure},
+		{name: "pulse out of range", text: "120/80/250", wantErr: ErrInvalidBloodPressure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			systolic, diastolic, pulse, err := ParseBloodPressure(tt.text)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseBloodPressure(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+				}
+				if !errors.Is(err, ErrInvalidBloodPressure) {
+					t.Errorf("ParseBloodPressure(%q) error %v does not match ErrInvalidBloodPressure", tt.text, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBloodPressure(%q) unexpected error: %v", tt.text, err)
+			}
+			if systolic != tt.systolic || diastolic != tt.diastolic {
+				t.Errorf("ParseBloodPressure(%q) = %d/%d, want %d/%d", tt.text, systolic, diastolic, tt.systolic, tt.diastolic)
+			}
+			switch {
+			case tt.pulse == nil && pulse != nil:
+				t.Errorf("ParseBloodPressure(%q) pulse = %d, want none", tt.text, *pulse)
+			case tt.pulse != nil && (pulse == nil || *pulse != *tt.pulse):
+				t.Errorf("ParseBloodPressure(%q) pulse = %v, want %d", tt.text, pulse, *tt.pulse)
+			}
+		})
+	}
+}
+
+func TestValidateBloodPressure(t *testing.T) {
+	tests := []struct {
+		name      string
+		systolic  int
+		diastolic int
+		pulse     *int
+		wantErr   bool
+	}{
+		{"normal", 120, 80, nil, false},
+		{"lower bounds", MinSystolic, MinDiastolic, intPtr(MinPulse), false},
+		{"upper bounds", MaxSystolic, MaxDiastolic, intPtr(MaxPulse), false},
+		{"systolic too low", MinSystolic - 1, 40, nil, true},
+		{"systolic too high", MaxSystolic + 1, 80, nil, true},
+		{"diastolic too low", 120, MinDiastolic - 1, nil, true},
+		{"diastolic too high", 250, MaxDiastolic + 1, nil, true},
+		{"equal values", 100, 100, nil, true},
+		{"pulse too low", 120, 80, intPtr(MinPulse - 1), true},
+		{"pulse too high", 120, 80, intPtr(MaxPulse + 1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBloodPressure(tt.systolic, tt.diastolic, tt.pulse)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBloodPressure(%d, %d) error = %v, wantErr %v", tt.systolic, tt.diastolic, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidBloodPressure) {
+				t.Errorf("error %v does not match ErrInvalidBloodPressure", err)
+			}
+		})
+	}
+}
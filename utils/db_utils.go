@@ -14,15 +14,40 @@ func ClearAllData(db *gorm.DB) error {
 
 	// Delete in an order that respects foreign key constraints (generally, delete dependent data first)
 	modelsToDelete := []interface{}{
+		&models.IntradialyticReading{},   // Depends on HemodialysisMonitoring
 		&models.HemodialysisMonitoring{}, // Depends on HemodialysisSchedule
+		&models.AccessSelfCheck{},      // Depends on VascularAccess
+		&models.VascularAccess{},       // Depends on User
+		&models.LabResult{},            // Depends on LabTest & User
+		&models.LabTest{},              // Independent
+		&models.FluidEntry{},           // Depends on FluidBalanceLog & User
 		&models.FluidBalanceLog{},      // Depends on User
+		&models.FluidPrescription{},    // Depends on User
+		&models.FluidAlert{},           // Depends on User
+		&models.CareTeamMember{},       // Depends on User
+		&models.JobRun{},               // Independent
+		&models.FluidContainerPreset{}, // Independent
 		&models.Device{},               // Depends on User
-		&models.ComplaintLog{},         // Depends on User
+		&models.ComplaintAttachment{},  // Depends on ComplaintLog
+		&models.ComplaintReply{},       // Depends on ComplaintLog & User
+		&models.ComplaintLog{},         // Depends on User & TriageRule
+		&models.TriageRule{},           // Independent
+		&models.Symptom{},              // Independent
 		&models.DrugSchedule{},         // Depends on User
 		&models.ControlSchedule{},      // Depends on User
 		&models.HemodialysisSchedule{}, // Depends on User
+		&models.MedicationRefillSchedule{}, // Depends on User
+		&models.QuizAttemptAnswer{},    // Depends on QuizAttempt
+		&models.QuizAttempt{},          // Depends on Quiz & User
+		&models.QuizOption{},           // Depends on QuizQuestion
+		&models.QuizQuestion{},         // Depends on Quiz
 		&models.Quiz{},                 // Depends on User (CreatedBy)
-		&models.Education{},            // Depends on User (CreatedBy)
+		&models.EducationTrigger{},     // Depends on Education
+		&models.EducationMediaUpload{}, // Depends on User (CreatedBy)
+		&models.EducationView{},        // Depends on Education & User
+		&models.EducationProgress{},    // Depends on Education & User
+		&models.Education{},            // Depends on User (CreatedBy); tag links cascade
+		&models.EducationTag{},         // Independent
 		&models.User{},                 // Base table
 	}
 
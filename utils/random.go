@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// RandomToken menghasilkan string hex acak sepanjang 2*n karakter, dipakai untuk ID sesi
+// upload dan nama file yang tidak boleh mudah ditebak.
+func RandomToken(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand praktis tidak pernah gagal; fallback tetap unik per nanodetik
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
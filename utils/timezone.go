@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Default jendela (hari) untuk mengisi data tanggal yang sudah lewat.
+const defaultBackdateWindowDays = 2
+
+// LoadUserLocation mengubah string timezone user (contoh "Asia/Makassar") menjadi *time.Location.
+// Jika tidak valid, fallback ke UTC seperti di worker.
+func LoadUserLocation(timezone string) *time.Location {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Printf("WARNING: Invalid user timezone %q, falling back to UTC", timezone)
+		return time.UTC
+	}
+	return location
+}
+
+// LocalDate mengembalikan tanggal kalender t di location tertentu sebagai kunci tanggal
+// (00:00 UTC), sehingga aman disimpan ke kolom DATE tanpa bergeser hari.
+func LocalDate(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// TodayIn mengembalikan tanggal hari ini di location tertentu (lihat LocalDate).
+func TodayIn(location *time.Location) time.Time {
+	return LocalDate(time.Now(), location)
+}
+
+// BackdateWindowDays membaca ENTRY_BACKDATE_WINDOW_DAYS: berapa hari ke belakang
+// pasien masih boleh mengisi atau mengoreksi catatan harian.
+func BackdateWindowDays() int {
+	days := viper.GetInt("ENTRY_BACKDATE_WINDOW_DAYS")
+	if days < 0 {
+		return 0
+	}
+	if days == 0 && !viper.IsSet("ENTRY_BACKDATE_WINDOW_DAYS") {
+		return defaultBackdateWindowDays
+	}
+	return days
+}
+
+// IsWithinBackdateWindow memeriksa apakah date (kunci tanggal) berada di antara
+// today - BackdateWindowDays() dan today.
+func IsWithinBackdateWindow(date, today time.Time) bool {
+	earliest := today.AddDate(0, 0, -BackdateWindowDays())
+	return !date.Before(earliest) && !date.After(today)
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// SniffContentType menentukan MIME type file upload dari isinya (512 byte pertama),
+// bukan dari header Content-Type atau ekstensi yang dikirim klien.
+func SniffContentType(file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("gagal membuka file '%s': %w", file.Filename, err)
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("gagal membaca file '%s': %w", file.Filename, err)
+	}
+	contentType := DetectContentType(head[:n])
+	// Rekaman suara sebagian ponsel memakai brand umum MP4 (mp42/isom) yang sama dengan video
+	if contentType == "video/mp4" && mp4HasOnlyAudio(src, file.Size) {
+		return "audio/mp4", nil
+	}
+	return contentType, nil
+}
+
+// DetectContentType menentukan MIME type dari byte awal sebuah file (idealnya 512 byte).
+// Dipakai juga untuk upload bertahap, di mana isi file dibaca dari potongan pertama.
+func DetectContentType(head []byte) string {
+	// Format rekaman suara ponsel yang tidak dikenali http.DetectContentType
+	if len(head) >= 12 && bytes.Equal(head[4:8], []byte("ftyp")) {
+		switch {
+		case bytes.HasPrefix(head[8:12], []byte("M4A")):
+			return "audio/mp4"
+		case bytes.HasPrefix(head[8:12], []byte("3gp")):
+			return "audio/3gpp"
+		case bytes.HasPrefix(head[8:12], []byte("mp4")), bytes.Equal(head[8:12], []byte("isom")):
+			return "video/mp4" // Bisa berisi audio saja, lihat SniffContentType
+		}
+	}
+	if len(head) >= 2 && head[0] == 0xFF && (head[1]&0xF6) == 0xF0 {
+		return "audio/aac" // ADTS
+	}
+
+	switch contentType := http.DetectContentType(head); contentType {
+	case "application/ogg":
+		return "audio/ogg"
+	case "audio/wave":
+		return "audio/wav"
+	default:
+		return contentType
+	}
+}
+
+// mp4HasOnlyAudio memeriksa handler setiap track di box moov. Hasilnya true bila ada track
+// dan semuanya bertipe suara ("soun").
+func mp4HasOnlyAudio(r io.ReaderAt, size int64) bool {
+	handlers := mp4TrackHandlers(r, 0, size, 0)
+	if len(handlers) == 0 {
+		return false
+	}
+	for _, handler := range handlers {
+		if handler != "soun" {
+			return false
+		}
+	}
+	return true
+}
+
+// mp4TrackHandlers menelusuri box moov > trak > mdia > hdlr di rentang [start, end) dan
+// mengembalikan handler_type tiap track ("soun", "vide", ...).
+func mp4TrackHandlers(r io.ReaderAt, start, end int64, depth int) []string {
+	var handlers []string
+	header := make([]byte, 16)
+	for offset := start; offset+8 <= end; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			break
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header[:4]))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+		switch boxSize {
+		case 0: // Box terakhir, sampai akhir file
+			boxSize = end - offset
+		case 1: // Ukuran 64-bit
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return handlers
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if boxSize < headerSize || boxSize > end-offset {
+			break
+		}
+
+		switch boxType {
+		case "moov", "trak", "mdia":
+			if depth < 3 {
+				handlers = append(handlers, mp4TrackHandlers(r, offset+headerSize, offset+boxSize, depth+1)...)
+			}
+		case "hdlr":
+			// version+flags (4 byte) dan pre_defined (4 byte) sebelum handler_type
+			handlerType := make([]byte, 4)
+			if boxSize >= headerSize+12 {
+				if _, err := r.ReadAt(handlerType, offset+headerSize+8); err == nil {
+					handlers = append(handlers, string(handlerType))
+				}
+			}
+		}
+		offset += boxSize
+	}
+	return handlers
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"mime/multipart"
+	"testing"
+)
+
+// mp4Box menyusun satu box MP4 (ukuran 32-bit + tipe + isi).
+func mp4Box(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	box := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(box[:4], uint32(8+len(body)))
+	copy(box[4:8], boxType)
+	return append(box, body...)
+}
+
+func mp4Ftyp(majorBrand string, compatible ...string) []byte {
+	payload := []byte(majorBrand + "\x00\x00\x00\x00")
+	for _, brand := range compatible {
+		payload = append(payload, brand...)
+	}
+	return mp4Box("ftyp", payload)
+}
+
+func mp4Track(handlerType string) []byte {
+	hdlr := append(make([]byte, 8), handlerType...)
+	hdlr = append(hdlr, make([]byte, 13)...) // reserved + nama kosong
+	return mp4Box("trak", mp4Box("tkhd", make([]byte, 84)), mp4Box("mdia", mp4Box("mdhd", make([]byte, 24)), mp4Box("hdlr", hdlr)))
+}
+
+func mp4File(ftyp []byte, tracks ...string) []byte {
+	moov := [][]byte{mp4Box("mvhd", make([]byte, 100))}
+	for _, track := range tracks {
+		moov = append(moov, mp4Track(track))
+	}
+	// mdat di depan moov, seperti rekaman yang belum di-"faststart"
+	return bytes.Join([][]byte{ftyp, mp4Box("mdat", make([]byte, 600)), mp4Box("moov", moov...)}, nil)
+}
+
+func testFileHeader(t *testing.T, data []byte) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "upload.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+func TestSniffContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"m4a brand", mp4File(mp4Ftyp("M4A ", "M4A ", "mp42", "isom"), "soun"), "audio/mp4"},
+		{"mp42 audio only", mp4File(mp4Ftyp("mp42", "mp42", "isom"), "soun"), "audio/mp4"},
+		{"isom audio only", mp4File(mp4Ftyp("isom", "isom", "iso2"), "soun"), "audio/mp4"},
+		{"mp42 video with audio", mp4File(mp4Ftyp("mp42", "mp42", "isom"), "vide", "soun"), "video/mp4"},
+		{"isom video only", mp4File(mp4Ftyp("isom", "isom", "avc1"), "vide"), "video/mp4"},
+		{"mp42 without moov", append(mp4Ftyp("mp42", "mp42"), mp4Box("mdat", make([]byte, 64))...), "video/mp4"},
+		{"truncated moov", append(mp4Ftyp("mp42", "mp42"), 0x00, 0x00, 0x10, 0x00, 'm', 'o', 'o', 'v'), "video/mp4"},
+		{"3gpp", mp4File(mp4Ftyp("3gp4", "3gp4"), "soun"), "audio/3gpp"},
+		{"adts aac", []byte{0xFF, 0xF1, 0x50, 0x80, 0x02, 0x1F, 0xFC}, "audio/aac"},
+		{"ogg", append([]byte("OggS\x00\x02"), make([]byte, 40)...), "audio/ogg"},
+		{"wav", append([]byte("RIFF\x24\x00\x00\x00WAVEfmt "), make([]byte, 24)...), "audio/wav"},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png"},
+		{"plain text", []byte("bukan file media"), "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SniffContentType(testFileHeader(t, tt.data))
+			if err != nil {
+				t.Fatalf("SniffContentType() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SniffContentType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"gorm.io/gorm"
+)
+
+// handleAccessAlert mengirim push ke klinisi pasien saat pemeriksaan mandiri akses vaskular
+// melaporkan thrill tidak teraba atau tanda infeksi.
+func (w *Worker) handleAccessAlert(msg services.ReminderMessage) error {
+	check, err := w.vascularAccessRepo.FindCheckByID(msg.ScheduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Discarding access alert: self-check ID %d not found", msg.ScheduleID)
+			return nil
+		}
+		return fmt.Errorf("loading self-check %d: %w", msg.ScheduleID, err)
+	}
+	if check.ComplaintLogID == nil || check.EscalatedAt != nil {
+		log.Printf("Discarding access alert for self-check ID %d: no longer pending", check.ID)
+		return nil
+	}
+
+	user, err := w.userRepo.FindByID(check.UserID)
+	if err != nil {
+		log.Printf("Discarding access alert: user %d not found: %v", check.UserID, err)
+		return nil
+	}
+
+	var problems []string
+	if check.ThrillPresent != nil && !*check.ThrillPresent {
+		problems = append(problems, "thrill tidak teraba")
+	}
+	if check.Redness {
+		problems = append(problems, "kemerahan")
+	}
+	if check.Swelling {
+		problems = append(problems, "bengkak")
+	}
+	if check.Pain {
+		problems = append(problems, "nyeri")
+	}
+
+	clinicians, err := w.careTeamRepo.FindByPatientAndRelation(user.ID, models.CareRelationClinician)
+	if err != nil {
+		return fmt.Errorf("loading clinicians for user %d: %w", user.ID, err)
+	}
+	if len(clinicians) == 0 {
+		log.Printf("Access alert for self-check ID %d: user %d has no assigned clinician", check.ID, user.ID)
+	}
+	body := fmt.Sprintf("%s melaporkan %s pada %s (%s) tanggal %s.", user.Name, strings.Join(problems, ", "), check.VascularAccess.AccessType, check.VascularAccess.Site, formatDateID(check.CheckDate))
+	for _, clinician := range clinicians {
+		devices, err := w.deviceRepo.FindAllByUserID(clinician.MemberID)
+		if err != nil {
+			log.Printf("ERROR: Loading devices for clinician %d: %v", clinician.MemberID, err)
+			continue
+		}
+		w.sendToDevices(devices, "🚨 Masalah Akses Vaskular Pasien", body)
+	}
+
+	marked, err := w.vascularAccessRepo.MarkCheckEscalated(check.ID, time.Now())
+	if err != nil {
+		return fmt.Errorf("marking access alert sent for self-check %d: %w", check.ID, err)
+	}
+	if !marked {
+		log.Printf("Access alert for self-check ID %d was already marked as sent", check.ID)
+	}
+	return nil
+}
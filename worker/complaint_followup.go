@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"gorm.io/gorm"
+)
+
+// handleComplaintReply meneruskan balasan keluhan sebagai push: balasan petugas ke pasien,
+// balasan pasien ke petugas yang menerima keluhan (atau seluruh klinisi pasien jika belum ada).
+func (w *Worker) handleComplaintReply(msg services.ReminderMessage) error {
+	reply, err := w.complaintRepo.FindReplyByID(msg.ScheduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Discarding complaint reply: reply ID %d not found", msg.ScheduleID)
+			return nil
+		}
+		return fmt.Errorf("loading complaint reply %d: %w", msg.ScheduleID, err)
+	}
+	complaint, err := w.complaintRepo.FindByID(reply.ComplaintLogID)
+	if err != nil {
+		log.Printf("Discarding complaint reply ID %d: complaint %d not found: %v", reply.ID, reply.ComplaintLogID, err)
+		return nil
+	}
+
+	body := truncateMessage(reply.Message, 150)
+	if reply.AuthorRole == models.RoleAdmin || reply.AuthorRole == models.RoleClinician {
+		devices, err := w.deviceRepo.FindAllByUserID(complaint.UserID)
+		if err != nil {
+			return fmt.Errorf("loading devices for user %d: %w", complaint.UserID, err)
+		}
+		w.sendToDevices(devices, "💬 Balasan Perawat untuk Keluhan Anda", body)
+		return nil
+	}
+
+	var recipients []uint
+	if complaint.AcknowledgedBy != nil {
+		recipients = append(recipients, *complaint.AcknowledgedBy)
+	} else {
+		clinicians, err := w.careTeamRepo.FindByPatientAndRelation(complaint.UserID, models.CareRelationClinician)
+		if err != nil {
+			return fmt.Errorf("loading clinicians for user %d: %w", complaint.UserID, err)
+		}
+		for _, clinician := range clinicians {
+			recipients = append(recipients, clinician.MemberID)
+		}
+	}
+	if len(recipients) == 0 {
+		log.Printf("Complaint reply ID %d: user %d has no clinician to notify", reply.ID, complaint.UserID)
+	}
+
+	patient, err := w.userRepo.FindByID(complaint.UserID)
+	if err != nil {
+		log.Printf("Discarding complaint reply ID %d: user %d not found: %v", reply.ID, complaint.UserID, err)
+		return nil
+	}
+	title := fmt.Sprintf("💬 Balasan Keluhan dari %s", patient.Name)
+	for _, memberID := range recipients {
+		devices, err := w.deviceRepo.FindAllByUserID(memberID)
+		if err != nil {
+			log.Printf("ERROR: Loading devices for user %d: %v", memberID, err)
+			continue
+		}
+		w.sendToDevices(devices, title, body)
+	}
+	return nil
+}
+
+// escalateComplaintSLA mengirim push ke klinisi pasien dan seluruh admin untuk keluhan
+// darurat/urgent yang belum diterima petugas melewati batas SLA (lihat services.ComplaintSLA).
+// Setiap keluhan hanya dieskalasi sekali.
+func (w *Worker) escalateComplaintSLA(jc *JobContext) error {
+	admins, err := w.userRepo.FindByRole(models.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("loading admins: %w", err)
+	}
+
+	for _, level := range []string{models.TriageLevelEmergency, models.TriageLevelUrgent} {
+		sla := services.ComplaintSLA(level)
+		if sla <= 0 {
+			continue
+		}
+		complaints, err := w.complaintRepo.FindUnacknowledgedBefore(level, time.Now().Add(-sla))
+		if err != nil {
+			return fmt.Errorf("loading overdue %s complaints: %w", level, err)
+		}
+
+		for _, complaint := range complaints {
+			recipients := make(map[uint]bool)
+			for _, admin := range admins {
+				recipients[admin.ID] = true
+			}
+			clinicians, err := w.careTeamRepo.FindByPatientAndRelation(complaint.UserID, models.CareRelationClinician)
+			if err != nil {
+				jc.RecordError("loading clinicians for user %d: %v", complaint.UserID, err)
+				continue
+			}
+			for _, clinician := range clinicians {
+				recipients[clinician.MemberID] = true
+			}
+
+			// Klaim dulu dengan update bersyarat agar acknowledge yang masuk bersamaan tidak tertimpa
+			// dan push tidak terkirim untuk keluhan yang sudah diterima petugas
+			claimed, err := w.complaintRepo.MarkEscalated(complaint.ID, time.Now())
+			if err != nil {
+				jc.RecordError("marking complaint %d escalated: %v", complaint.ID, err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+
+			title := "⏰ Keluhan Belum Ditanggapi"
+			body := fmt.Sprintf("Keluhan %s dari %s belum diterima petugas selama lebih dari %d menit.",
+				level, complaint.User.Name, int(sla.Minutes()))
+			if complaint.TriageRuleName != "" {
+				body += " Aturan triase: " + complaint.TriageRuleName + "."
+			}
+			for memberID := range recipients {
+				devices, err := w.deviceRepo.FindAllByUserID(memberID)
+				if err != nil {
+					jc.RecordError("loading devices for user %d: %v", memberID, err)
+					continue
+				}
+				w.sendToDevices(devices, title, body)
+			}
+
+			jc.ItemsProcessed++
+		}
+	}
+	return nil
+}
+
+// truncateMessage memotong isi pesan agar muat di notifikasi push.
+func truncateMessage(message string, limit int) string {
+	runes := []rune(message)
+	if len(runes) <= limit {
+		return message
+	}
+	return string(runes[:limit]) + "…"
+}
@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/darmawguna/tirtaapp.git/utils"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// Default jumlah hari berturut-turut melebihi batas sebelum klinisi diberi tahu.
+const defaultFluidEscalationDays = 2
+
+// fluidEscalationDays membaca FLUID_ALERT_ESCALATION_DAYS; 0 menonaktifkan eskalasi ke klinisi.
+func fluidEscalationDays() int {
+	if !viper.IsSet("FLUID_ALERT_ESCALATION_DAYS") {
+		return defaultFluidEscalationDays
+	}
+	return viper.GetInt("FLUID_ALERT_ESCALATION_DAYS")
+}
+
+// handleFluidAlert mengirim push peringatan cairan ke pasien dan pendampingnya,
+// maksimal sekali per level per hari, lalu mengeskalasi ke klinisi jika perlu.
+func (w *Worker) handleFluidAlert(msg services.ReminderMessage) error {
+	fluidLog, err := w.fluidBalanceRepo.FindByID(msg.ScheduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Discarding fluid alert: log ID %d not found", msg.ScheduleID)
+			return nil
+		}
+		return fmt.Errorf("loading fluid log %d: %w", msg.ScheduleID, err)
+	}
+	// Entry bisa saja sudah dikoreksi sebelum pesan diproses
+	currentLevel, stale, err := services.CheckQueuedFluidAlert(w.fluidPrescriptionRepo, fluidLog, msg.AlertLevel)
+	if err != nil {
+		return fmt.Errorf("rechecking fluid alert for log %d: %w", fluidLog.ID, err)
+	}
+	if stale {
+		log.Printf("Discarding stale %s fluid alert for log ID %d (balance %d cc, now %q)", msg.AlertLevel, fluidLog.ID, fluidLog.BalanceCC, currentLevel)
+		return nil
+	}
+
+	user, err := w.userRepo.FindByID(fluidLog.UserID)
+	if err != nil {
+		log.Printf("Discarding fluid alert: user %d not found: %v", fluidLog.UserID, err)
+		return nil
+	}
+
+	alert, created, err := w.fluidAlertRepo.CreateIfNotExists(models.FluidAlert{
+		UserID:       fluidLog.UserID,
+		LogDate:      fluidLog.LogDate,
+		Level:        msg.AlertLevel,
+		BalanceCC:    fluidLog.BalanceCC,
+		DailyLimitCC: fluidLog.DailyLimitCC,
+	})
+	if err != nil {
+		return fmt.Errorf("recording fluid alert: %w", err)
+	}
+	if !created {
+		log.Printf("Fluid alert %s for user %d on %s already sent, skipping.", msg.AlertLevel, user.ID, fluidLog.LogDate.Format("2006-01-02"))
+		return nil
+	}
+
+	// Pengisian susulan untuk hari lalu tetap dicatat (untuk eskalasi) tanpa push ke pasien
+	if fluidLog.LogDate.Equal(utils.TodayIn(utils.LoadUserLocation(user.Timezone))) {
+		w.notifyPatientAndCaregivers(user, fluidLog, msg.AlertLevel)
+	}
+
+	if msg.AlertLevel == models.FluidAlertLevelExceeded {
+		if err := w.escalateFluidAlert(user, alert); err != nil {
+			log.Printf("ERROR: Fluid alert escalation for user %d failed: %v", user.ID, err)
+		}
+	}
+	return nil
+}
+
+func (w *Worker) notifyPatientAndCaregivers(user models.User, fluidLog models.FluidBalanceLog, level string) {
+	title := "💧 Batas Cairan Hampir Tercapai"
+	body := fmt.Sprintf("Keseimbangan cairan Anda hari ini %d cc dari batas %d cc. Yuk, batasi minum hingga besok.", fluidLog.BalanceCC, fluidLog.DailyLimitCC)
+	caregiverBody := fmt.Sprintf("Keseimbangan cairan %s hari ini %d cc, mendekati batas %d cc.", user.Name, fluidLog.BalanceCC, fluidLog.DailyLimitCC)
+	if level == models.FluidAlertLevelExceeded {
+		title = "⚠️ Batas Cairan Terlampaui"
+		body = fmt.Sprintf("Keseimbangan cairan Anda hari ini %d cc, melebihi batas %d cc. Mohon hentikan asupan cairan dan hubungi perawat bila sesak atau bengkak.", fluidLog.BalanceCC, fluidLog.DailyLimitCC)
+		caregiverBody = fmt.Sprintf("Keseimbangan cairan %s hari ini %d cc, melebihi batas %d cc. Mohon bantu awasi asupan cairannya.", user.Name, fluidLog.BalanceCC, fluidLog.DailyLimitCC)
+	}
+
+	devices, err := w.deviceRepo.FindAllByUserID(user.ID)
+	if err != nil {
+		log.Printf("ERROR: Loading devices for user %d: %v", user.ID, err)
+	} else {
+		w.sendToDevices(devices, title, body)
+	}
+
+	caregivers, err := w.careTeamRepo.FindByPatientAndRelation(user.ID, models.CareRelationCaregiver)
+	if err != nil {
+		log.Printf("ERROR: Loading caregivers for user %d: %v", user.ID, err)
+		return
+	}
+	for _, caregiver := range caregivers {
+		devices, err := w.deviceRepo.FindAllByUserID(caregiver.MemberID)
+		if err != nil {
+			log.Printf("ERROR: Loading devices for caregiver %d: %v", caregiver.MemberID, err)
+			continue
+		}
+		w.sendToDevices(devices, title, caregiverBody)
+	}
+}
+
+// escalateFluidAlert memberi tahu klinisi jika batas terlampaui beberapa hari berturut-turut.
+func (w *Worker) escalateFluidAlert(user models.User, alert models.FluidAlert) error {
+	days := fluidEscalationDays()
+	if days <= 0 {
+		return nil
+	}
+	for i := 1; i < days; i++ {
+		exceeded, err := w.fluidAlertRepo.ExistsForDate(user.ID, alert.LogDate.AddDate(0, 0, -i), models.FluidAlertLevelExceeded)
+		if err != nil {
+			return fmt.Errorf("checking previous fluid alerts: %w", err)
+		}
+		if !exceeded {
+			return nil
+		}
+	}
+
+	clinicians, err := w.careTeamRepo.FindByPatientAndRelation(user.ID, models.CareRelationClinician)
+	if err != nil {
+		return fmt.Errorf("loading clinicians: %w", err)
+	}
+	if len(clinicians) == 0 {
+		log.Printf("User %d exceeded fluid limit %d days in a row but has no assigned clinician.", user.ID, days)
+		return nil
+	}
+
+	title := "🚨 Pasien Melebihi Batas Cairan"
+	body := fmt.Sprintf("%s melebihi batas cairan %d hari berturut-turut (terakhir %s: %d cc dari batas %d cc).", user.Name, days, formatDateID(alert.LogDate), alert.BalanceCC, alert.DailyLimitCC)
+	for _, clinician := range clinicians {
+		devices, err := w.deviceRepo.FindAllByUserID(clinician.MemberID)
+		if err != nil {
+			log.Printf("ERROR: Loading devices for clinician %d: %v", clinician.MemberID, err)
+			continue
+		}
+		w.sendToDevices(devices, title, body)
+	}
+
+	now := time.Now()
+	alert.EscalatedAt = &now
+	if _, err := w.fluidAlertRepo.Update(alert); err != nil {
+		return fmt.Errorf("marking fluid alert escalated: %w", err)
+	}
+	return nil
+}
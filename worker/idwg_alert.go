@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"gorm.io/gorm"
+)
+
+// handleIDWGAlert mengirim push ke pasien dan klinisinya saat IDWG melewati ambang.
+func (w *Worker) handleIDWGAlert(msg services.ReminderMessage) error {
+	monitoring, err := w.monitoringRepo.FindByID(msg.ScheduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Discarding IDWG alert: monitoring ID %d not found", msg.ScheduleID)
+			return nil
+		}
+		return fmt.Errorf("loading monitoring %d: %w", msg.ScheduleID, err)
+	}
+	// Data bisa sudah dikoreksi, atau push sudah terkirim dari pesan sebelumnya
+	if !monitoring.IDWGExceeded || monitoring.IDWGAlertSentAt != nil || monitoring.IDWGKg == nil || monitoring.IDWGPercent == nil {
+		log.Printf("Discarding IDWG alert for monitoring ID %d: no longer pending", monitoring.ID)
+		return nil
+	}
+
+	user, err := w.userRepo.FindByID(monitoring.UserID)
+	if err != nil {
+		log.Printf("Discarding IDWG alert: user %d not found: %v", monitoring.UserID, err)
+		return nil
+	}
+
+	devices, err := w.deviceRepo.FindAllByUserID(user.ID)
+	if err != nil {
+		return fmt.Errorf("loading devices for user %d: %w", user.ID, err)
+	}
+	title := "⚖️ Kenaikan Berat Badan Antar-HD Tinggi"
+	body := fmt.Sprintf("Berat badan Anda naik %.1f kg (%.1f%% dari berat kering) sejak HD terakhir. Mohon batasi cairan dan garam, serta hubungi perawat bila sesak atau bengkak.", *monitoring.IDWGKg, *monitoring.IDWGPercent)
+	w.sendToDevices(devices, title, body)
+
+	clinicians, err := w.careTeamRepo.FindByPatientAndRelation(user.ID, models.CareRelationClinician)
+	if err != nil {
+		log.Printf("ERROR: Loading clinicians for user %d: %v", user.ID, err)
+	}
+	clinicianBody := fmt.Sprintf("IDWG %s pada %s: %.1f kg (%.1f%% berat kering), melewati ambang %.1f%%.", user.Name, formatDateID(monitoring.MonitoringDate), *monitoring.IDWGKg, *monitoring.IDWGPercent, services.IDWGAlertPercent())
+	for _, clinician := range clinicians {
+		clinicianDevices, err := w.deviceRepo.FindAllByUserID(clinician.MemberID)
+		if err != nil {
+			log.Printf("ERROR: Loading devices for clinician %d: %v", clinician.MemberID, err)
+			continue
+		}
+		w.sendToDevices(clinicianDevices, "🚨 IDWG Pasien Melewati Ambang", clinicianBody)
+	}
+
+	marked, err := w.monitoringRepo.MarkIDWGAlertSent(monitoring.ID, time.Now())
+	if err != nil {
+		return fmt.Errorf("marking IDWG alert sent for monitoring %d: %w", monitoring.ID, err)
+	}
+	if !marked {
+		log.Printf("IDWG alert for monitoring ID %d was already marked as sent", monitoring.ID)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// Default umur maksimum token FCM tanpa pembaruan (Firebase menganggap token
+// yang tidak aktif 270 hari sudah kedaluwarsa).
+const defaultDeviceTokenStaleDays = 270
+
+// sendMonitoringReminders mengingatkan pasien mengisi pemantauan HD pada hari jadwal HD.
+func (w *Worker) sendMonitoringReminders(jc *JobContext) error {
+	today := jc.Today()
+	schedules, err := w.hemodialysisScheduleRepo.FindSchedulesForDateAndNotNotifiedByTimezone(today, jc.Timezone)
+	if err != nil {
+		return fmt.Errorf("checking daily schedules: %w", err)
+	}
+	if len(schedules) == 0 {
+		log.Printf("Job: No monitoring reminders to send today (%s, %s).", today, jc.Timezone)
+		return nil
+	}
+
+	for _, schedule := range schedules {
+		devices, err := w.deviceRepo.FindAllByUserID(schedule.UserID)
+		if err != nil {
+			jc.RecordError("loading devices for user %d: %v", schedule.UserID, err)
+			continue
+		}
+		if len(devices) == 0 {
+			continue
+		}
+
+		title := "🩸 Pengingat Pemantauan Hemodialisa"
+		body := "Jangan lupa untuk mengisi data pemantauan hemodialisis hari ini, ya."
+
+		log.Printf("Job: Sending monitoring reminder for schedule ID %d...", schedule.ID)
+		w.sendToDevices(devices, title, body)
+
+		schedule.MonitoringNotificationSent = true
+		if _, err = w.hemodialysisScheduleRepo.Update(schedule); err != nil {
+			jc.RecordError("updating monitoring status for schedule %d: %v", schedule.ID, err)
+			continue
+		}
+		jc.ItemsProcessed++
+	}
+	return nil
+}
+
+// sweepMissedDoses menutup jadwal obat yang tanggalnya sudah lewat. Slot yang aktif
+// tetapi pengingatnya tidak pernah terkirim ditandai sebagai dosis terlewat.
+func (w *Worker) sweepMissedDoses(jc *JobContext) error {
+	schedules, err := w.drugScheduleRepo.FindActiveBeforeDateByTimezone(jc.Today(), jc.Timezone)
+	if err != nil {
+		return fmt.Errorf("loading past drug schedules: %w", err)
+	}
+
+	for _, schedule := range schedules {
+		missed := 0
+		for _, slot := range []int{6, 12, 18} {
+			if isDrugSlotEnabled(schedule, slot) && !isDrugNotificationSent(schedule, slot) {
+				markDrugSlotMissed(&schedule, slot)
+				missed++
+			}
+		}
+		if missed > 0 {
+			log.Printf("Job: Drug schedule ID %d (user %d) closed with %d missed reminder slot(s).", schedule.ID, schedule.UserID, missed)
+		}
+
+		schedule.IsActive = false
+		if _, err := w.drugScheduleRepo.Update(schedule); err != nil {
+			jc.RecordError("closing drug schedule %d: %v", schedule.ID, err)
+			continue
+		}
+		jc.ItemsProcessed++
+	}
+	return nil
+}
+
+// markDrugSlotMissed menandai slot jadwal obat sebagai dosis terlewat.
+func markDrugSlotMissed(schedule *models.DrugSchedule, timeSlot int) {
+	switch timeSlot {
+	case 6:
+		schedule.At06Missed = true
+	case 12:
+		schedule.At12Missed = true
+	case 18:
+		schedule.At18Missed = true
+	}
+}
+
+// cleanupDeviceTokens menghapus token FCM yang sudah lama tidak diperbarui.
+func (w *Worker) cleanupDeviceTokens(jc *JobContext) error {
+	staleDays := viper.GetInt("DEVICE_TOKEN_STALE_DAYS")
+	if staleDays <= 0 {
+		staleDays = defaultDeviceTokenStaleDays
+	}
+	cutoff := time.Now().AddDate(0, 0, -staleDays)
+
+	deleted, err := w.deviceRepo.DeleteNotUpdatedSince(cutoff)
+	if err != nil {
+		return fmt.Errorf("deleting stale device tokens: %w", err)
+	}
+	jc.ItemsProcessed = int(deleted)
+	return nil
+}
+
+// purgeExpiredEducationUploads menghapus sesi upload bertahap yang ditinggalkan admin beserta
+// file potongannya (volume uploads dipakai bersama API).
+func (w *Worker) purgeExpiredEducationUploads(jc *JobContext) error {
+	expired, err := w.educationUploadRepo.FindExpired(time.Now())
+	if err != nil {
+		return fmt.Errorf("loading expired education uploads: %w", err)
+	}
+
+	for _, upload := range expired {
+		if err := os.Remove(upload.TempPath); err != nil && !os.IsNotExist(err) {
+			jc.RecordError("removing upload file %s: %v", upload.TempPath, err)
+			continue
+		}
+		if err := w.educationUploadRepo.Delete(upload.ID); err != nil {
+			jc.RecordError("deleting upload session %s: %v", upload.ID, err)
+			continue
+		}
+		jc.ItemsProcessed++
+	}
+	return nil
+}
+
+// sendDailyFluidReports mengirim ringkasan keseimbangan cairan hari ini ke pasien yang mengisi log.
+func (w *Worker) sendDailyFluidReports(jc *JobContext) error {
+	logs, err := w.fluidBalanceRepo.FindByDateAndTimezone(jc.Today(), jc.Timezone)
+	if err != nil {
+		return fmt.Errorf("loading fluid logs: %w", err)
+	}
+
+	for _, fluidLog := range logs {
+		devices, err := w.deviceRepo.FindAllByUserID(fluidLog.UserID)
+		if err != nil {
+			jc.RecordError("loading devices for user %d: %v", fluidLog.UserID, err)
+			continue
+		}
+		if len(devices) == 0 {
+			continue
+		}
+
+		title := "💧 Ringkasan Cairan Hari Ini"
+		body := fmt.Sprintf("Cairan masuk %d cc, cairan keluar %d cc, keseimbangan %d cc. Tetap jaga batas cairan harian Anda, ya.", fluidLog.IntakeCC, fluidLog.OutputCC, fluidLog.BalanceCC)
+		w.sendToDevices(devices, title, body)
+		jc.ItemsProcessed++
+	}
+	return nil
+}
+
+// markMissingMonitoring menutup status pemantauan jadwal HD yang tanggalnya sudah lewat:
+// "recorded" jika ternyata ada data pada tanggal itu (data lama belum terhubung), selain itu "missing".
+// Pasien masih bisa mengisi susulan dalam jendela ENTRY_BACKDATE_WINDOW_DAYS.
+func (w *Worker) markMissingMonitoring(jc *JobContext) error {
+	schedules, err := w.hemodialysisScheduleRepo.FindPendingMonitoringBeforeDateByTimezone(jc.Today(), jc.Timezone)
+	if err != nil {
+		return fmt.Errorf("loading past HD schedules: %w", err)
+	}
+
+	for _, schedule := range schedules {
+		schedule.MonitoringStatus = models.MonitoringStatusMissing
+		monitoring, err := w.monitoringRepo.FindByUserIDAndDate(schedule.UserID, schedule.ScheduleDate)
+		if err == nil {
+			schedule.MonitoringStatus = models.MonitoringStatusRecorded
+			if monitoring.HemodialysisScheduleID == nil {
+				monitoring.HemodialysisScheduleID = &schedule.ID
+				if _, err := w.monitoringRepo.Update(monitoring); err != nil {
+					jc.RecordError("linking monitoring %d to schedule %d: %v", monitoring.ID, schedule.ID, err)
+					continue
+				}
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			jc.RecordError("checking monitoring for schedule %d: %v", schedule.ID, err)
+			continue
+		}
+
+		if _, err := w.hemodialysisScheduleRepo.Update(schedule); err != nil {
+			jc.RecordError("updating monitoring status for schedule %d: %v", schedule.ID, err)
+			continue
+		}
+		jc.ItemsProcessed++
+	}
+	return nil
+}
+
+// Default jarak minimal dari hasil lab terakhir sebelum pasien diingatkan lagi.
+const defaultLabDueIntervalDays = 28
+
+// sendLabDueReminders mengingatkan pasien yang belum punya hasil lab dalam LAB_DUE_INTERVAL_DAYS
+// terakhir untuk melakukan pemeriksaan lab bulanan.
+func (w *Worker) sendLabDueReminders(jc *JobContext) error {
+	intervalDays := viper.GetInt("LAB_DUE_INTERVAL_DAYS")
+	if intervalDays <= 0 {
+		intervalDays = defaultLabDueIntervalDays
+	}
+	since := time.Now().In(jc.Location).AddDate(0, 0, -intervalDays).Format("2006-01-02")
+
+	patients, err := w.labResultRepo.FindPatientsDueByTimezone(jc.Timezone, since)
+	if err != nil {
+		return fmt.Errorf("loading patients due for labs: %w", err)
+	}
+
+	for _, patient := range patients {
+		devices, err := w.deviceRepo.FindAllByUserID(patient.ID)
+		if err != nil {
+			jc.RecordError("loading devices for user %d: %v", patient.ID, err)
+			continue
+		}
+		if len(devices) == 0 {
+			continue
+		}
+
+		title := "🧪 Waktunya Pemeriksaan Lab"
+		body := "Sudah waktunya pemeriksaan laboratorium bulanan (Hb, kalium, fosfat, kalsium, albumin, ureum). Catat hasilnya di aplikasi setelah keluar, ya."
+		w.sendToDevices(devices, title, body)
+		jc.ItemsProcessed++
+	}
+	return nil
+}
+
+// sendAccessSelfCheckReminders mengingatkan pasien dengan fistula/graft aktif yang belum
+// memeriksa thrill hari ini.
+func (w *Worker) sendAccessSelfCheckReminders(jc *JobContext) error {
+	accesses, err := w.vascularAccessRepo.FindDueSelfCheckByTimezone(jc.Today(), jc.Timezone)
+	if err != nil {
+		return fmt.Errorf("loading vascular accesses due for self-check: %w", err)
+	}
+
+	for _, access := range accesses {
+		devices, err := w.deviceRepo.FindAllByUserID(access.UserID)
+		if err != nil {
+			jc.RecordError("loading devices for user %d: %v", access.UserID, err)
+			continue
+		}
+		if len(devices) == 0 {
+			continue
+		}
+
+		title := "🤚 Periksa Akses HD Anda"
+		body := fmt.Sprintf("Raba getaran (thrill) %s di %s dan perhatikan bengkak, kemerahan, atau nyeri. Catat hasilnya di aplikasi, ya.", access.AccessType, access.Site)
+		w.sendToDevices(devices, title, body)
+		jc.ItemsProcessed++
+	}
+	return nil
+}
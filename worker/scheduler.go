@@ -0,0 +1,311 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	models "github.com/darmawguna/tirtaapp.git/model"
+	"github.com/darmawguna/tirtaapp.git/services"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+)
+
+const (
+	JobTriggerCron   = "cron"
+	JobTriggerManual = "manual"
+
+	jobStatusRunning = "running"
+	jobStatusSuccess = "success"
+	jobStatusFailed  = "failed"
+
+	// Batas jumlah pesan error yang disimpan per eksekusi
+	maxRecordedJobErrors = 20
+)
+
+// JobContext dibawa ke setiap eksekusi job: timezone yang sedang diproses
+// dan penghitung hasil yang nantinya disimpan ke JobRun.
+type JobContext struct {
+	Timezone       string
+	Location       *time.Location
+	ItemsProcessed int
+	errs           []string
+}
+
+// RecordError mencatat error per item tanpa menghentikan job.
+func (jc *JobContext) RecordError(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	log.Printf("Job ERROR [%s]: %s", jc.Timezone, msg)
+	jc.errs = append(jc.errs, msg)
+}
+
+// Today mengembalikan tanggal hari ini (YYYY-MM-DD) di timezone job.
+func (jc *JobContext) Today() string {
+	return time.Now().In(jc.Location).Format("2006-01-02")
+}
+
+// JobFunc adalah implementasi satu job. Error yang dikembalikan menandai job gagal total.
+type JobFunc func(jc *JobContext) error
+
+// jobRegistry memetakan nama job (lihat services.JobDefinitions) ke implementasinya.
+func (w *Worker) jobRegistry() map[string]JobFunc {
+	return map[string]JobFunc{
+		services.JobMonitoringReminder: w.sendMonitoringReminders,
+		services.JobMissedDoseSweep:    w.sweepMissedDoses,
+		services.JobDeviceTokenCleanup: w.cleanupDeviceTokens,
+		services.JobDailyFluidReport:   w.sendDailyFluidReports,
+		services.JobMonitoringMissing:  w.markMissingMonitoring,
+		services.JobLabDueReminder:     w.sendLabDueReminders,
+		services.JobAccessSelfCheck:    w.sendAccessSelfCheckReminders,
+		services.JobComplaintSLA:       w.escalateComplaintSLA,
+		services.JobEducationUploadGC:  w.purgeExpiredEducationUploads,
+	}
+}
+
+// dispatchManualJob menjalankan trigger manual dari admin di goroutine terpisah
+// sehingga pesan pengingat lain tetap diproses selama job berjalan.
+func (w *Worker) dispatchManualJob(name string, timezone string) {
+	if _, ok := services.FindJobDefinition(name); !ok {
+		log.Printf("Discarding job trigger: unknown job: %s", name)
+		return
+	}
+
+	w.manualJobs.Add(1)
+	go func() {
+		defer w.manualJobs.Done()
+		if err := w.RunJob(name, timezone, JobTriggerManual); err != nil {
+			log.Printf("Manual job %s failed: %v", name, err)
+		}
+	}()
+}
+
+// WaitManualJobs menunggu semua job manual yang sedang berjalan selesai.
+func (w *Worker) WaitManualJobs() {
+	w.manualJobs.Wait()
+}
+
+// RunJob menjalankan job berdasarkan nama. Untuk job per-timezone dengan timezone kosong,
+// job dijalankan sekali untuk setiap timezone user yang ada.
+func (w *Worker) RunJob(name string, timezone string, trigger string) error {
+	def, ok := services.FindJobDefinition(name)
+	if !ok {
+		return fmt.Errorf("unknown job: %s", name)
+	}
+
+	if !def.PerUserTimezone {
+		location := serverLocation()
+		w.runJobOnce(def, location.String(), location, trigger)
+		return nil
+	}
+
+	timezones := []string{timezone}
+	if timezone == "" {
+		var err error
+		timezones, err = w.userRepo.FindDistinctTimezones()
+		if err != nil {
+			return fmt.Errorf("failed to load user timezones: %w", err)
+		}
+	}
+	for _, tz := range timezones {
+		location, err := time.LoadLocation(tz)
+		if err != nil {
+			log.Printf("WARNING: Skipping job %s for invalid timezone %q: %v", name, tz, err)
+			continue
+		}
+		w.runJobOnce(def, tz, location, trigger)
+	}
+	return nil
+}
+
+func (w *Worker) runJobOnce(def services.JobDefinition, timezone string, location *time.Location, trigger string) {
+	fn, ok := w.jobRegistry()[def.Name]
+	if !ok {
+		log.Printf("ERROR: Job %s has no implementation", def.Name)
+		return
+	}
+
+	// Cegah eksekusi ganda untuk job+timezone yang sama (misal cron & trigger manual bersamaan)
+	key := def.Name + "|" + timezone
+	if !w.acquireJob(key) {
+		log.Printf("Job %s (%s) is still running, skipping this trigger.", def.Name, timezone)
+		return
+	}
+	defer w.releaseJob(key)
+
+	run, err := w.jobRunRepo.Create(models.JobRun{
+		JobName:   def.Name,
+		Timezone:  timezone,
+		Trigger:   trigger,
+		Status:    jobStatusRunning,
+		StartedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to record start of job %s: %v", def.Name, err)
+	}
+
+	log.Printf("Job %s started (timezone: %s, trigger: %s)", def.Name, timezone, trigger)
+	jc := &JobContext{Timezone: timezone, Location: location}
+	jobErr := fn(jc)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.ItemsProcessed = jc.ItemsProcessed
+	run.ErrorCount = len(jc.errs)
+	run.Status = jobStatusSuccess
+	recordedErrs := jc.errs
+	if len(recordedErrs) > maxRecordedJobErrors {
+		recordedErrs = recordedErrs[:maxRecordedJobErrors]
+	}
+	if jobErr != nil {
+		run.Status = jobStatusFailed
+		run.ErrorCount++
+		recordedErrs = append([]string{jobErr.Error()}, recordedErrs...)
+	}
+	run.ErrorMessage = strings.Join(recordedErrs, "\n")
+
+	if run.ID != 0 {
+		if _, err := w.jobRunRepo.Update(run); err != nil {
+			log.Printf("ERROR: Failed to record end of job %s: %v", def.Name, err)
+		}
+	}
+	log.Printf("Job %s finished (timezone: %s, status: %s, items: %d, errors: %d)", def.Name, timezone, run.Status, run.ItemsProcessed, run.ErrorCount)
+}
+
+func (w *Worker) acquireJob(key string) bool {
+	w.runningMu.Lock()
+	defer w.runningMu.Unlock()
+	if w.runningJobs == nil {
+		w.runningJobs = make(map[string]bool)
+	}
+	if w.runningJobs[key] {
+		return false
+	}
+	w.runningJobs[key] = true
+	return true
+}
+
+func (w *Worker) releaseJob(key string) {
+	w.runningMu.Lock()
+	defer w.runningMu.Unlock()
+	delete(w.runningJobs, key)
+}
+
+// serverLocation membaca SERVER_TIMEZONE untuk job yang tidak per-user.
+func serverLocation() *time.Location {
+	serverTimezone := viper.GetString("SERVER_TIMEZONE")
+	if serverTimezone == "" {
+		serverTimezone = "Asia/Makassar"
+	}
+	location, err := time.LoadLocation(serverTimezone)
+	if err != nil {
+		log.Printf("WARNING: Invalid SERVER_TIMEZONE %q, falling back to UTC", serverTimezone)
+		return time.UTC
+	}
+	return location
+}
+
+// --- Scheduler: mendaftarkan job ke cron ---
+
+// Scheduler mengelola entry cron untuk setiap job di registry. Job per-timezone
+// mendapat satu entry per timezone user (CRON_TZ=<tz> <spec>), sehingga "06:00"
+// berarti 06:00 waktu lokal pasien, bukan waktu server.
+type Scheduler struct {
+	worker  *Worker
+	cron    *cron.Cron
+	mu      sync.Mutex
+	entries map[string]cron.EntryID // key: nama job|timezone
+}
+
+// Interval sinkronisasi daftar timezone user ke entry cron.
+const timezoneSyncInterval = "@every 30m"
+
+func NewScheduler(w *Worker) *Scheduler {
+	return &Scheduler{
+		worker:  w,
+		cron:    cron.New(),
+		entries: make(map[string]cron.EntryID),
+	}
+}
+
+// Start mendaftarkan semua job lalu menjalankan cron.
+func (s *Scheduler) Start() error {
+	if err := s.syncEntries(); err != nil {
+		return err
+	}
+	// Timezone baru (user baru dari daerah lain) ikut terdaftar tanpa restart worker
+	if _, err := s.cron.AddFunc(timezoneSyncInterval, func() {
+		if err := s.syncEntries(); err != nil {
+			log.Printf("Scheduler ERROR: failed to sync job entries: %v", err)
+		}
+	}); err != nil {
+		return fmt.Errorf("failed to add timezone sync entry: %w", err)
+	}
+	s.cron.Start()
+	return nil
+}
+
+// Stop menghentikan cron; context selesai saat job yang sedang berjalan rampung.
+func (s *Scheduler) Stop() context.Context {
+	return s.cron.Stop()
+}
+
+func (s *Scheduler) syncEntries() error {
+	timezones, err := s.worker.userRepo.FindDistinctTimezones()
+	if err != nil {
+		return fmt.Errorf("failed to load user timezones: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[string]bool)
+	for _, def := range services.JobDefinitions() {
+		def := def
+		var jobTimezones []string
+		if def.PerUserTimezone {
+			jobTimezones = timezones
+		} else {
+			jobTimezones = []string{serverLocation().String()}
+		}
+
+		for _, tz := range jobTimezones {
+			if _, err := time.LoadLocation(tz); err != nil {
+				log.Printf("WARNING: Skipping invalid timezone %q for job %s", tz, def.Name)
+				continue
+			}
+			key := def.Name + "|" + tz
+			wanted[key] = true
+			if _, exists := s.entries[key]; exists {
+				continue
+			}
+
+			tz := tz
+			spec := fmt.Sprintf("CRON_TZ=%s %s", tz, def.CronSpec)
+			id, err := s.cron.AddFunc(spec, func() {
+				if def.PerUserTimezone {
+					s.worker.RunJob(def.Name, tz, JobTriggerCron)
+				} else {
+					s.worker.RunJob(def.Name, "", JobTriggerCron)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("invalid cron spec %q for job %s: %w", def.CronSpec, def.Name, err)
+			}
+			s.entries[key] = id
+			log.Printf("Scheduler: job %s scheduled at %q (%s)", def.Name, def.CronSpec, tz)
+		}
+	}
+
+	// Hapus entry untuk timezone yang sudah tidak dipakai user mana pun
+	for key, id := range s.entries {
+		if !wanted[key] {
+			s.cron.Remove(id)
+			delete(s.entries, key)
+			log.Printf("Scheduler: removed entry %s", key)
+		}
+	}
+	return nil
+}
@@ -5,13 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/darmawguna/tirtaapp.git/config"       // Adjust path if needed
 	models "github.com/darmawguna/tirtaapp.git/model" // Adjust path if needed
 	"github.com/darmawguna/tirtaapp.git/repositories"
 	"github.com/darmawguna/tirtaapp.git/services"
-	"github.com/spf13/viper"
 	"gorm.io/gorm"
 )
 
@@ -25,6 +25,20 @@ type Worker struct {
 	controlScheduleRepo      repositories.ControlScheduleRepository
 	hemodialysisScheduleRepo repositories.HemodialysisScheduleRepository
 	medicationRefillRepo     repositories.MedicationRefillRepository
+	fluidBalanceRepo         repositories.FluidBalanceRepository
+	fluidPrescriptionRepo    repositories.FluidPrescriptionRepository
+	jobRunRepo               repositories.JobRunRepository
+	fluidAlertRepo           repositories.FluidAlertRepository
+	careTeamRepo             repositories.CareTeamRepository
+	monitoringRepo           repositories.HemodialysisMonitoringRepository
+	labResultRepo            repositories.LabResultRepository
+	vascularAccessRepo       repositories.VascularAccessRepository
+	complaintRepo            repositories.ComplaintRepository
+	educationUploadRepo      repositories.EducationMediaUploadRepository
+
+	runningMu   sync.Mutex
+	runningJobs map[string]bool // Job+timezone yang sedang berjalan
+	manualJobs  sync.WaitGroup  // Job manual yang berjalan di luar goroutine consumer
 }
 
 // Error khusus untuk memicu requeue via DLX
@@ -34,13 +48,9 @@ var monthsID = [...]string{"", "Januari", "Februari", "Maret", "April", "Mei", "
 
 // Constructor untuk Worker
 func NewWorker() (*Worker, error) {
-	// Koneksi DB & Migrasi
+	// Koneksi DB & cek versi skema (migrasi dijalankan lewat cmd/migrate)
 	db := config.ConnectDB()
-	config.RunMigration(db,
-		&models.User{}, &models.Device{}, &models.DrugSchedule{},
-		&models.ControlSchedule{}, &models.HemodialysisSchedule{}, &models.HemodialysisMonitoring{},
-		&models.MedicationRefillSchedule{},
-	)
+	config.RequireMigratedSchema(db)
 
 	// Inisialisasi Firebase
 	firebaseSvc := services.NewFirebaseService()
@@ -58,6 +68,16 @@ func NewWorker() (*Worker, error) {
 		controlScheduleRepo:      repositories.NewControlScheduleRepository(db),
 		hemodialysisScheduleRepo: repositories.NewHemodialysisScheduleRepository(db),
 		medicationRefillRepo:     repositories.NewMedicationRefillRepository(db),
+		fluidBalanceRepo:         repositories.NewFluidBalanceRepository(db),
+		fluidPrescriptionRepo:    repositories.NewFluidPrescriptionRepository(db),
+		jobRunRepo:               repositories.NewJobRunRepository(db),
+		fluidAlertRepo:           repositories.NewFluidAlertRepository(db),
+		careTeamRepo:             repositories.NewCareTeamRepository(db),
+		monitoringRepo:           repositories.NewHemodialysisMonitoringRepository(db),
+		labResultRepo:            repositories.NewLabResultRepository(db),
+		vascularAccessRepo:       repositories.NewVascularAccessRepository(db),
+		complaintRepo:            repositories.NewComplaintRepository(db),
+		educationUploadRepo:      repositories.NewEducationMediaUploadRepository(db),
 	}
 	log.Println("Worker dependencies initialized.")
 	return w, nil
@@ -70,6 +90,27 @@ func (w *Worker) MessageHandler(body []byte) error {
 		return fmt.Errorf("could not unmarshal message body: %w", err)
 	}
 
+	// Trigger manual job dari endpoint admin, tidak terkait jadwal tertentu.
+	// Job dijalankan di goroutine terpisah agar consumer (QoS=1) tidak tertahan.
+	if msg.ScheduleType == services.ScheduleTypeJob {
+		w.dispatchManualJob(msg.JobName, msg.Timezone)
+		return nil
+	}
+
+	// Event peringatan cairan dari API, bukan pengingat jadwal
+	if msg.ScheduleType == services.ScheduleTypeFluidAlert {
+		return w.handleFluidAlert(msg)
+	}
+	if msg.ScheduleType == services.ScheduleTypeIDWGAlert {
+		return w.handleIDWGAlert(msg)
+	}
+	if msg.ScheduleType == services.ScheduleTypeAccessAlert {
+		return w.handleAccessAlert(msg)
+	}
+	if msg.ScheduleType == services.ScheduleTypeComplaintReply {
+		return w.handleComplaintReply(msg)
+	}
+
 	user, location, scheduleDate, err := w.getUserAndTimezone(msg)
 	if err != nil {
 		log.Printf("Discarding message: %v", err)
@@ -190,59 +231,6 @@ func (w *Worker) MessageHandler(body []byte) error {
 	return nil // Sukses// Sukses
 }
 
-// --- Logika Cron Job ---
-func (w *Worker) SendDailyMonitoringReminders() {
-	log.Println("Cron Job: Running daily check for Hemodialysis monitoring reminders...")
-
-	serverTimezone := viper.GetString("SERVER_TIMEZONE")
-	if serverTimezone == "" {
-		serverTimezone = "Asia/Makassar"
-		log.Println("WARNING: SERVER_TIMEZONE not set...")
-	}
-	location, err := time.LoadLocation(serverTimezone)
-	if err != nil {
-		log.Printf("Cron Job ERROR: Invalid SERVER_TIMEZONE...")
-		location, _ = time.LoadLocation("UTC")
-	}
-
-	now := time.Now().In(location)
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
-
-	schedules, err := w.hemodialysisScheduleRepo.FindSchedulesForDateAndNotNotified(today)
-	if err != nil {
-		log.Printf("Cron Job ERROR: checking daily schedules: %v", err)
-		return
-	}
-	if len(schedules) == 0 {
-		log.Println("Cron Job: No monitoring reminders to send today.")
-		return
-	}
-
-	log.Printf("Cron Job: Found %d schedules...", len(schedules))
-	for _, schedule := range schedules {
-		if !schedule.IsActive {
-			continue
-		}
-
-		devices, err := w.deviceRepo.FindAllByUserID(schedule.UserID)
-		if err != nil || len(devices) == 0 {
-			continue
-		}
-
-		title := "🩸 Pengingat Pemantauan Hemodialisa"
-		body := "Jangan lupa untuk mengisi data pemantauan hemodialisis hari ini, ya."
-
-		log.Printf("Cron Job: Sending monitoring reminder for schedule ID %d...", schedule.ID)
-		w.sendToDevices(devices, title, body)
-
-		schedule.MonitoringNotificationSent = true
-		if _, err = w.hemodialysisScheduleRepo.Update(schedule); err != nil {
-			log.Printf("Cron Job ERROR: updating monitoring status for schedule %d: %v", schedule.ID, err)
-		}
-	}
-	log.Printf("Cron Job: Finished sending %d daily monitoring reminders.", len(schedules))
-}
-
 // --- Helper Functions (Methods) ---
 func (w *Worker) getUserAndTimezone(msg services.ReminderMessage) (models.User, *time.Location, time.Time, error) {
 	var userID uint
@@ -346,6 +334,19 @@ func isDrugNotificationSent(schedule models.DrugSchedule, timeSlot int) bool {
 	}
 }
 
+func isDrugSlotEnabled(schedule models.DrugSchedule, timeSlot int) bool {
+	switch timeSlot {
+	case 6:
+		return schedule.At06
+	case 12:
+		return schedule.At12
+	case 18:
+		return schedule.At18
+	default:
+		return false
+	}
+}
+
 func formatDateID(t time.Time) string {
 	// Dapatkan nama hari dan bulan dalam bahasa Inggris
 	dayNameEN := t.Format("Monday")